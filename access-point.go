@@ -0,0 +1,201 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/minio/minio-go/v6/pkg/s3signer"
+	"github.com/minio/minio-go/v6/pkg/s3utils"
+)
+
+// accessPointARN - an S3 access point addressed by its ARN in place of
+// a bucket name, e.g.
+// 'arn:aws:s3:us-west-2:123456789012:accesspoint/my-access-point', an
+// Object Lambda access point, e.g.
+// 'arn:aws:s3-object-lambda:us-west-2:123456789012:accesspoint/my-olap',
+// an access point of a bucket on an Outpost, e.g.
+// 'arn:aws:s3-outposts:us-west-2:123456789012:outpost/op-01ac5d28a6a232904/accesspoint/my-ap',
+// or a Multi-Region Access Point, which has no region, named by its
+// alias, e.g. 'arn:aws:s3::123456789012:accesspoint/mfzwi23gnjvgw.mrap'.
+type accessPointARN struct {
+	partition string
+	service   string
+	region    string
+	accountID string
+	outpostID string
+	name      string
+}
+
+var (
+	validAccountID       = regexp.MustCompile(`^[0-9]{12}$`)
+	validOutpostID       = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+	validAccessPointName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,48}[a-z0-9]$`)
+	validMultiRegionName = regexp.MustCompile(`^[a-z0-9]+\.mrap$`)
+)
+
+// isAccessPointARN - returns true if the bucket name is an ARN, which
+// are only accepted for access points.
+func isAccessPointARN(bucketName string) bool {
+	return strings.HasPrefix(bucketName, "arn:")
+}
+
+// isMultiRegion - returns true for Multi-Region Access Points.
+func (ap accessPointARN) isMultiRegion() bool {
+	return ap.service == "s3" && ap.region == ""
+}
+
+// isMultiRegionARN - returns true if the bucket name is the ARN of a
+// Multi-Region Access Point.
+func isMultiRegionARN(bucketName string) bool {
+	if !isAccessPointARN(bucketName) {
+		return false
+	}
+	ap, err := parseAccessPointARN(bucketName)
+	return err == nil && ap.isMultiRegion()
+}
+
+// isObjectLambda - returns true for Object Lambda access points.
+func (ap accessPointARN) isObjectLambda() bool {
+	return ap.service == s3signer.ServiceS3ObjectLambda
+}
+
+// accessPointSigningName - returns the signing name of the service of
+// an access point ARN other than s3, empty for bucket names and the
+// ARNs of s3 access points.
+func accessPointSigningName(bucketName string) string {
+	if !isAccessPointARN(bucketName) {
+		return ""
+	}
+	ap, err := parseAccessPointARN(bucketName)
+	if err != nil || ap.service == "s3" {
+		return ""
+	}
+	return ap.service
+}
+
+// parseAccessPointARN - parses the ARN of an access point in the
+// forms 'arn:<partition>:<service>:<region>:<account-id>:accesspoint/<name>'
+// and 'arn:<partition>:<service>:<region>:<account-id>:accesspoint:<name>',
+// the service is either s3 or s3-object-lambda and the region is
+// empty for Multi-Region Access Points, or
+// 'arn:<partition>:s3-outposts:<region>:<account-id>:outpost/<outpost-id>/accesspoint/<name>'
+// for access points on Outposts.
+func parseAccessPointARN(arn string) (ap accessPointARN, err error) {
+	fields := strings.SplitN(arn, ":", 6)
+	if len(fields) != 6 || fields[0] != "arn" {
+		return ap, errors.New("Access point ARN " + arn + " is malformed")
+	}
+	ap = accessPointARN{
+		partition: fields[1],
+		service:   fields[2],
+		region:    fields[3],
+		accountID: fields[4],
+	}
+	resource := strings.FieldsFunc(fields[5], func(r rune) bool {
+		return r == '/' || r == ':'
+	})
+	switch ap.service {
+	case "s3", s3signer.ServiceS3ObjectLambda:
+		if len(resource) != 2 || resource[0] != "accesspoint" {
+			return ap, errors.New("ARN " + arn + " is not an ARN of an access point")
+		}
+		ap.name = resource[1]
+	case s3signer.ServiceS3Outposts:
+		if len(resource) == 4 && resource[0] == "outpost" && resource[2] == "bucket" {
+			return ap, errors.New("Outposts bucket ARN " + arn + " cannot address objects, use the ARN of an access point of the bucket")
+		}
+		if len(resource) != 4 || resource[0] != "outpost" || resource[2] != "accesspoint" {
+			return ap, errors.New("ARN " + arn + " is not an ARN of an Outposts access point")
+		}
+		ap.outpostID, ap.name = resource[1], resource[3]
+		if !validOutpostID.MatchString(ap.outpostID) {
+			return ap, errors.New("Access point ARN " + arn + " has no valid outpost id")
+		}
+	default:
+		return ap, errors.New("Access point ARN " + arn + " is not an ARN of the s3, s3-object-lambda or s3-outposts service")
+	}
+	switch {
+	case ap.isMultiRegion():
+		if !validAccountID.MatchString(ap.accountID) {
+			err = errors.New("Access point ARN " + arn + " has no valid account id")
+		} else if !validMultiRegionName.MatchString(ap.name) {
+			err = errors.New("Multi-Region Access Point ARN " + arn + " has no valid alias")
+		}
+	case !Region(ap.region).IsValid():
+		err = errors.New("Access point ARN " + arn + " has no valid region")
+	case ap.partition != s3utils.GetPartition(ap.region):
+		err = errors.New("Access point ARN " + arn + " has a region of another partition than " + ap.partition)
+	case !validAccountID.MatchString(ap.accountID):
+		err = errors.New("Access point ARN " + arn + " has no valid account id")
+	case !validAccessPointName.MatchString(ap.name):
+		err = errors.New("Access point ARN " + arn + " has no valid access point name")
+	}
+	return ap, err
+}
+
+// getAccessPointEndpoint - returns the endpoint of an access point,
+// e.g. 'my-access-point-123456789012.s3-accesspoint.dualstack.us-west-2.amazonaws.com',
+// 'my-olap-123456789012.s3-object-lambda.us-west-2.amazonaws.com' for
+// Object Lambda access points and
+// 'my-ap-123456789012.op-01ac5d28a6a232904.s3-outposts.us-west-2.amazonaws.com'
+// for access points on Outposts, which both have no dual-stack
+// endpoints. Access points are reached through FIPS endpoints from
+// clients of FIPS endpoints. Multi-Region Access Points are reached
+// through the global endpoint, e.g.
+// 'mfzwi23gnjvgw.mrap.accesspoint.s3-global.amazonaws.com', which
+// routes requests to the nearest region.
+func getAccessPointEndpoint(ap accessPointARN, endpointURL url.URL) string {
+	if ap.isMultiRegion() {
+		return ap.name + ".accesspoint.s3-global.amazonaws.com"
+	}
+	host := ap.name + "-" + ap.accountID
+	switch ap.service {
+	case s3signer.ServiceS3ObjectLambda:
+		host += "." + s3signer.ServiceS3ObjectLambda
+	case s3signer.ServiceS3Outposts:
+		host += "." + ap.outpostID + "." + s3signer.ServiceS3Outposts
+	default:
+		host += ".s3-accesspoint"
+	}
+	isFIPS := s3utils.IsAmazonFIPSEndpoint(endpointURL)
+	if isFIPS {
+		host += "-fips"
+	}
+	if ap.service == "s3" && (!isFIPS || strings.Contains(endpointURL.Host, ".dualstack.")) {
+		host += ".dualstack"
+	}
+	host += "." + ap.region + ".amazonaws.com"
+	if ap.partition == s3utils.PartitionAWSChina {
+		host += ".cn"
+	}
+	return host
+}
+
+// copySourcePath - returns the path of a source object in the
+// x-amz-copy-source header, objects of access points are addressed
+// below the ARN of the access point.
+func copySourcePath(bucketName, objectName string) string {
+	if isAccessPointARN(bucketName) {
+		return bucketName + "/object/" + objectName
+	}
+	return bucketName + "/" + objectName
+}
@@ -0,0 +1,244 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v6/pkg/s3signer"
+)
+
+// Tests the parsing of access point ARNs.
+func TestParseAccessPointARN(t *testing.T) {
+	testCases := []struct {
+		arn      string
+		endpoint string
+		host     string
+		valid    bool
+	}{
+		{"arn:aws:s3:us-west-2:123456789012:accesspoint/my-access-point", "s3.amazonaws.com",
+			"my-access-point-123456789012.s3-accesspoint.dualstack.us-west-2.amazonaws.com", true},
+		{"arn:aws:s3:us-east-1:123456789012:accesspoint:ap1", "s3-fips.us-east-1.amazonaws.com",
+			"ap1-123456789012.s3-accesspoint-fips.us-east-1.amazonaws.com", true},
+		{"arn:aws-cn:s3:cn-north-1:123456789012:accesspoint/ap1", "s3.cn-north-1.amazonaws.com.cn",
+			"ap1-123456789012.s3-accesspoint.dualstack.cn-north-1.amazonaws.com.cn", true},
+		{"arn:aws:s3:cn-north-1:123456789012:accesspoint/ap1", "", "", false},
+		{"arn:aws:s3:us-west-2:1234:accesspoint/ap1", "", "", false},
+		{"arn:aws:s3:us-west-2:123456789012:accesspoint/Ap1", "", "", false},
+		{"arn:aws:s3:us-west-2:123456789012:bucket/ap1", "", "", false},
+		{"arn:aws:iam::123456789012:accesspoint/ap1", "", "", false},
+		{"arn:aws:s3-object-lambda:us-west-2:123456789012:accesspoint/my-olap", "s3.amazonaws.com",
+			"my-olap-123456789012.s3-object-lambda.us-west-2.amazonaws.com", true},
+		{"arn:aws-us-gov:s3-object-lambda:us-gov-west-1:123456789012:accesspoint/my-olap", "s3-fips-us-gov-west-1.amazonaws.com",
+			"my-olap-123456789012.s3-object-lambda-fips.us-gov-west-1.amazonaws.com", true},
+		{"arn:aws:s3-outposts:us-west-2:123456789012:outpost/op-01ac5d28a6a232904/accesspoint/my-ap", "s3.amazonaws.com",
+			"my-ap-123456789012.op-01ac5d28a6a232904.s3-outposts.us-west-2.amazonaws.com", true},
+		{"arn:aws:s3-outposts:us-west-2:123456789012:outpost:op-01ac5d28a6a232904:accesspoint:my-ap", "s3.amazonaws.com",
+			"my-ap-123456789012.op-01ac5d28a6a232904.s3-outposts.us-west-2.amazonaws.com", true},
+		{"arn:aws:s3-outposts:us-west-2:123456789012:outpost/op-01ac5d28a6a232904/bucket/my-bucket", "", "", false},
+		{"arn:aws:s3-outposts:us-west-2:123456789012:accesspoint/my-ap", "", "", false},
+		{"arn:aws:s3:us-west-2:123456789012:outpost/op-01ac5d28a6a232904/accesspoint/my-ap", "", "", false},
+		{"arn:aws:s3::123456789012:accesspoint/mfzwi23gnjvgw.mrap", "s3.amazonaws.com",
+			"mfzwi23gnjvgw.mrap.accesspoint.s3-global.amazonaws.com", true},
+		{"arn:aws:s3::123456789012:accesspoint/my-access-point", "", "", false},
+		{"arn:aws:s3-object-lambda::123456789012:accesspoint/mfzwi23gnjvgw.mrap", "", "", false},
+		{"arn:aws:s3:us-west-2:123456789012", "", "", false},
+	}
+	for i, testCase := range testCases {
+		ap, err := parseAccessPointARN(testCase.arn)
+		if (err == nil) != testCase.valid {
+			t.Errorf("Test %d: expected valid %t, got %v", i+1, testCase.valid, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		c, err := New(testCase.endpoint, "access", "secret", true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if host := getAccessPointEndpoint(ap, *c.endpointURL); host != testCase.host {
+			t.Errorf("Test %d: expected host %s, got %s", i+1, testCase.host, host)
+		}
+	}
+}
+
+// Tests requests to access points are sent to their endpoints and
+// signed for their regions.
+func TestAccessPointRequest(t *testing.T) {
+	const arn = "arn:aws:s3:us-west-2:123456789012:accesspoint/my-access-point"
+
+	c, err := New("s3.amazonaws.com", "access", "secret", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := c.newRequest("GET", requestMetadata{bucketName: arn, objectName: "dir/object"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.URL.Host != "my-access-point-123456789012.s3-accesspoint.dualstack.us-west-2.amazonaws.com" || req.URL.Path != "/dir/object" {
+		t.Errorf("unexpected URL %s", req.URL)
+	}
+	if auth := req.Header.Get("Authorization"); !strings.Contains(auth, "/us-west-2/s3/aws4_request") {
+		t.Errorf("expected signature scope of us-west-2, got %s", auth)
+	}
+
+	if err = c.MakeBucket(arn, ""); ToErrorResponse(err).Code != "InvalidBucketName" {
+		t.Errorf("expected InvalidBucketName creating a bucket with an ARN, got %v", err)
+	}
+
+	src := NewSourceInfo(arn, "dir/object", nil)
+	if source := src.Headers.Get("X-Amz-Copy-Source"); !strings.HasSuffix(source, "accesspoint/my-access-point/object/dir/object") {
+		t.Errorf("unexpected copy source %s", source)
+	}
+
+	c, err = NewV2("s3.amazonaws.com", "access", "secret", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.newRequest("GET", requestMetadata{bucketName: arn, objectName: "object"}); ToErrorResponse(err).Code != "APINotSupported" {
+		t.Errorf("expected APINotSupported with signature V2, got %v", err)
+	}
+
+	c, err = New("localhost:9000", "access", "secret", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.newRequest("GET", requestMetadata{bucketName: arn, objectName: "object"}); ToErrorResponse(err).Code != "APINotSupported" {
+		t.Errorf("expected APINotSupported from other endpoints than Amazon S3, got %v", err)
+	}
+}
+
+// Tests requests to Object Lambda access points are sent to their
+// endpoints and signed for the s3-object-lambda service.
+func TestObjectLambdaRequest(t *testing.T) {
+	const arn = "arn:aws:s3-object-lambda:us-west-2:123456789012:accesspoint/my-olap"
+
+	c, err := New("s3.amazonaws.com", "access", "secret", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var traced s3signer.SignatureV4
+	c.SetSignatureHook(func(req *http.Request, signature s3signer.SignatureV4) {
+		traced = signature
+	})
+	req, err := c.newRequest("GET", requestMetadata{bucketName: arn, objectName: "object"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.URL.Host != "my-olap-123456789012.s3-object-lambda.us-west-2.amazonaws.com" || req.URL.Path != "/object" {
+		t.Errorf("unexpected URL %s", req.URL)
+	}
+	auth := req.Header.Get("Authorization")
+	if !strings.Contains(auth, "/us-west-2/s3-object-lambda/aws4_request") {
+		t.Errorf("expected signature scope of s3-object-lambda, got %s", auth)
+	}
+	if !strings.HasSuffix(auth, "Signature="+traced.Signature) {
+		t.Errorf("expected traced signature %s to sign the request, got %s", traced.Signature, auth)
+	}
+
+	u, err := c.PresignedGetObject(arn, "object", time.Hour, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if credential := u.Query().Get("X-Amz-Credential"); !strings.HasSuffix(credential, "/us-west-2/s3-object-lambda/aws4_request") {
+		t.Errorf("expected presigned credential of s3-object-lambda, got %s", credential)
+	}
+
+	if _, err = c.newRequest("PUT", requestMetadata{bucketName: arn, objectName: "object"}); ToErrorResponse(err).Code != "APINotSupported" {
+		t.Errorf("expected APINotSupported writing through Object Lambda, got %v", err)
+	}
+}
+
+// Tests requests to access points on Outposts are sent to the endpoint
+// of their Outpost and signed for the s3-outposts service.
+func TestOutpostsRequest(t *testing.T) {
+	const arn = "arn:aws:s3-outposts:us-west-2:123456789012:outpost/op-01ac5d28a6a232904/accesspoint/my-ap"
+
+	c, err := New("s3.amazonaws.com", "access", "secret", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := c.newRequest("PUT", requestMetadata{
+		bucketName:    arn,
+		objectName:    "object",
+		contentBody:   strings.NewReader("data"),
+		contentLength: 4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.URL.Host != "my-ap-123456789012.op-01ac5d28a6a232904.s3-outposts.us-west-2.amazonaws.com" || req.URL.Path != "/object" {
+		t.Errorf("unexpected URL %s", req.URL)
+	}
+	if auth := req.Header.Get("Authorization"); !strings.Contains(auth, "/us-west-2/s3-outposts/aws4_request") {
+		t.Errorf("expected signature scope of s3-outposts, got %s", auth)
+	}
+	if sha := req.Header.Get("X-Amz-Content-Sha256"); sha != unsignedPayload {
+		t.Errorf("expected an unsigned payload instead of a streaming signature, got %s", sha)
+	}
+
+	_, err = c.newRequest("GET", requestMetadata{
+		bucketName: "arn:aws:s3-outposts:us-west-2:123456789012:outpost/op-01ac5d28a6a232904/bucket/my-bucket",
+		objectName: "object",
+	})
+	if ToErrorResponse(err).Code != "InvalidBucketName" {
+		t.Errorf("expected InvalidBucketName for an Outposts bucket ARN, got %v", err)
+	}
+}
+
+// Tests requests to Multi-Region Access Points are sent to the global
+// endpoint and signed with signature V4A for all regions.
+func TestMultiRegionAccessPointRequest(t *testing.T) {
+	const arn = "arn:aws:s3::123456789012:accesspoint/mfzwi23gnjvgw.mrap"
+
+	c, err := New("s3.amazonaws.com", "access", "secret", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := c.newRequest("PUT", requestMetadata{
+		bucketName:    arn,
+		objectName:    "object",
+		contentBody:   strings.NewReader("data"),
+		contentLength: 4,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.URL.Host != "mfzwi23gnjvgw.mrap.accesspoint.s3-global.amazonaws.com" || req.URL.Path != "/object" {
+		t.Errorf("unexpected URL %s", req.URL)
+	}
+	if auth := req.Header.Get("Authorization"); !strings.HasPrefix(auth, "AWS4-ECDSA-P256-SHA256 Credential=access/") ||
+		!strings.Contains(auth, "/s3/aws4_request") {
+		t.Errorf("expected a signature V4A, got %s", auth)
+	}
+	if req.Header.Get("X-Amz-Region-Set") != "*" {
+		t.Errorf("expected region set *, got %q", req.Header.Get("X-Amz-Region-Set"))
+	}
+
+	u, err := c.PresignedGetObject(arn, "object", time.Hour, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Query().Get("X-Amz-Algorithm") != "AWS4-ECDSA-P256-SHA256" {
+		t.Errorf("expected a presigned signature V4A, got %s", u)
+	}
+}
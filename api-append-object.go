@@ -0,0 +1,133 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/minio/minio-go/v6/pkg/encrypt"
+)
+
+// amzWriteOffsetBytes - header of the offset data is appended at.
+const amzWriteOffsetBytes = "X-Amz-Write-Offset-Bytes"
+
+// AppendObjectOptions represents options specified by user for
+// AppendObject call.
+type AppendObjectOptions struct {
+	Progress             io.Reader
+	ServerSideEncryption encrypt.ServerSide
+	SendContentMd5       bool
+	DisableContentSha256 bool
+}
+
+// AppendObject - appends the data of the reader to an object of an
+// appendable bucket, without reading and rewriting the object. The
+// write offset must be equal to the current size of the object, or
+// zero to create it, otherwise the server rejects the request with
+// InvalidWriteOffset and the object is left unchanged. A write offset
+// of -1 uses the current size of the object fetched with StatObject,
+// which is only safe without concurrent appenders.
+//
+// Returns the offset for the next append, i.e. the new size of the
+// object. The size of the data must be known and each append is a
+// single PUT operation of up to 5GiB.
+func (c Client) AppendObject(bucketName, objectName string, reader io.Reader, objectSize, writeOffset int64,
+	opts AppendObjectOptions) (nextOffset int64, err error) {
+	return c.AppendObjectWithContext(context.Background(), bucketName, objectName, reader, objectSize, writeOffset, opts)
+}
+
+// AppendObjectWithContext - Identical to AppendObject call, but accepts
+// context to facilitate request cancellation.
+func (c Client) AppendObjectWithContext(ctx context.Context, bucketName, objectName string, reader io.Reader, objectSize, writeOffset int64,
+	opts AppendObjectOptions) (nextOffset int64, err error) {
+	// Input validation.
+	if err = c.validateBucketName(bucketName, false); err != nil {
+		return 0, err
+	}
+	if err = ValidateObjectKey(objectName); err != nil {
+		return 0, err
+	}
+	if objectSize < 0 {
+		return 0, ErrEntityTooSmall(objectSize, bucketName, objectName)
+	}
+	if objectSize > maxSinglePutObjectSize {
+		return 0, ErrEntityTooLarge(objectSize, maxSinglePutObjectSize, bucketName, objectName)
+	}
+	if writeOffset < -1 {
+		return 0, ErrInvalidArgument("Write offset cannot be negative.")
+	}
+
+	if writeOffset == -1 {
+		sopts := StatObjectOptions{GetObjectOptions{ServerSideEncryption: opts.ServerSideEncryption}}
+		objInfo, err := c.statObject(ctx, bucketName, objectName, sopts)
+		switch {
+		case err == nil:
+			writeOffset = objInfo.Size
+		case IsNotFound(err):
+			writeOffset = 0
+		default:
+			return 0, err
+		}
+	}
+
+	// Compute the MD5 sum of the data if requested.
+	var md5Base64 string
+	if opts.SendContentMd5 {
+		md5Base64, reader, err = contentMD5(reader, objectSize)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	customHeader := make(http.Header)
+	customHeader.Set(amzWriteOffsetBytes, strconv.FormatInt(writeOffset, 10))
+	if opts.ServerSideEncryption != nil {
+		opts.ServerSideEncryption.Marshal(customHeader)
+	}
+
+	reqMetadata := requestMetadata{
+		bucketName:       bucketName,
+		objectName:       objectName,
+		customHeader:     customHeader,
+		contentBody:      newHook(reader, opts.Progress),
+		contentLength:    objectSize,
+		contentMD5Base64: md5Base64,
+		unsignedPayload:  opts.DisableContentSha256,
+	}
+
+	// Execute PUT on objectName.
+	resp, err := c.executeMethod(ctx, "PUT", reqMetadata)
+	defer closeResponse(resp)
+	if err != nil {
+		return 0, err
+	}
+	if resp != nil {
+		if resp.StatusCode != http.StatusOK {
+			return 0, httpRespToErrorResponse(resp, bucketName, objectName)
+		}
+	}
+
+	// Prefer the size of the object reported by the server.
+	if size, err := strconv.ParseInt(resp.Header.Get("X-Amz-Object-Size"), 10, 64); err == nil {
+		return size, nil
+	}
+	return writeOffset + objectSize, nil
+}
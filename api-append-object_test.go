@@ -0,0 +1,108 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Tests appending to an object of a fake server validating offsets.
+func TestAppendObject(t *testing.T) {
+	var mutex sync.Mutex
+	var object []byte
+	exists := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		switch r.Method {
+		case http.MethodHead:
+			if !exists {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Length", strconv.Itoa(len(object)))
+			w.Header().Set("ETag", `"etag"`)
+			w.Header().Set("Last-Modified", "Wed, 02 Jan 2019 03:04:05 GMT")
+		case http.MethodPut:
+			data, _ := ioutil.ReadAll(r.Body)
+			offset, err := strconv.Atoi(r.Header.Get(amzWriteOffsetBytes))
+			if err != nil || offset != len(object) {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte("<Error><Code>InvalidWriteOffset</Code></Error>"))
+				return
+			}
+			object = append(object, data...)
+			exists = true
+			w.Header().Set("X-Amz-Object-Size", strconv.Itoa(len(object)))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := AppendObjectOptions{DisableContentSha256: true}
+
+	testCases := []struct {
+		data        string
+		writeOffset int64
+		nextOffset  int64
+		invalid     bool
+	}{
+		// Fetch the offset of an object which does not exist.
+		{"hello", -1, 5, false},
+		{", ", 5, 7, false},
+		// Offset of an earlier size.
+		{"world", 5, 0, true},
+		{"world", -1, 12, false},
+	}
+	for i, testCase := range testCases {
+		nextOffset, err := c.AppendObject("bucket", "log", strings.NewReader(testCase.data), int64(len(testCase.data)), testCase.writeOffset, opts)
+		if testCase.invalid {
+			if !IsInvalidWriteOffset(err) {
+				t.Errorf("Test %d: expected InvalidWriteOffset, got %v", i+1, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if nextOffset != testCase.nextOffset {
+			t.Errorf("Test %d: expected next offset %d, got %d", i+1, testCase.nextOffset, nextOffset)
+		}
+	}
+	if string(object) != "hello, world" {
+		t.Errorf("expected %q, got %q", "hello, world", object)
+	}
+
+	if _, err = c.AppendObject("bucket", "log", strings.NewReader(""), -1, 0, opts); err == nil {
+		t.Error("expected an error for an unknown size")
+	}
+	if _, err = c.AppendObject("bucket", "log", strings.NewReader(""), 0, -2, opts); err == nil {
+		t.Error("expected an error for an invalid write offset")
+	}
+}
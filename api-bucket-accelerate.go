@@ -0,0 +1,73 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"encoding/xml"
+	"net/url"
+)
+
+// AccelerateStatus - transfer acceleration status of a bucket.
+type AccelerateStatus string
+
+// Different transfer acceleration statuses.
+const (
+	AccelerateEnabled   AccelerateStatus = "Enabled"
+	AccelerateSuspended AccelerateStatus = "Suspended"
+)
+
+// accelerateConfiguration container for the transfer acceleration
+// configuration of a bucket.
+type accelerateConfiguration struct {
+	XMLName xml.Name         `xml:"AccelerateConfiguration"`
+	Status  AccelerateStatus `xml:"Status,omitempty"`
+}
+
+// SetBucketAccelerate - enables or suspends transfer acceleration of a
+// bucket. The accelerate endpoint set with SetS3TransferAccelerate can
+// only be used for buckets with acceleration enabled.
+func (c Client) SetBucketAccelerate(bucketName string, status AccelerateStatus) error {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return err
+	}
+	if status != AccelerateEnabled && status != AccelerateSuspended {
+		return ErrInvalidArgument("Unsupported transfer acceleration status ‘" + string(status) + "’.")
+	}
+
+	urlValues := make(url.Values)
+	urlValues.Set("accelerate", "")
+	return c.putBucketConfig(bucketName, urlValues, accelerateConfiguration{Status: status})
+}
+
+// GetBucketAccelerate - gets the transfer acceleration status of a
+// bucket, the status is empty if acceleration was never configured.
+func (c Client) GetBucketAccelerate(bucketName string) (AccelerateStatus, error) {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return "", err
+	}
+
+	urlValues := make(url.Values)
+	urlValues.Set("accelerate", "")
+	var config accelerateConfiguration
+	if err := c.getBucketConfig(bucketName, urlValues, &config); err != nil {
+		return "", err
+	}
+	return config.Status, nil
+}
@@ -0,0 +1,186 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"encoding/xml"
+	"net/url"
+)
+
+// Tag - key value pair of a tag filter.
+type Tag struct {
+	Key   string `xml:"Key"`
+	Value string `xml:"Value"`
+}
+
+// AnalyticsConfiguration - storage class analysis configuration of a
+// bucket, objects which match the filter are analyzed.
+type AnalyticsConfiguration struct {
+	XMLName              xml.Name             `xml:"AnalyticsConfiguration"`
+	ID                   string               `xml:"Id"`
+	Filter               *AnalyticsFilter     `xml:"Filter,omitempty"`
+	StorageClassAnalysis StorageClassAnalysis `xml:"StorageClassAnalysis"`
+}
+
+// AnalyticsFilter - objects analyzed by an analytics configuration,
+// only one of Prefix, Tag and And may be set.
+type AnalyticsFilter struct {
+	Prefix string                `xml:"Prefix,omitempty"`
+	Tag    *Tag                  `xml:"Tag,omitempty"`
+	And    *AnalyticsAndOperator `xml:"And,omitempty"`
+}
+
+// AnalyticsAndOperator - objects matching the prefix and all the tags.
+type AnalyticsAndOperator struct {
+	Prefix string `xml:"Prefix,omitempty"`
+	Tags   []Tag  `xml:"Tag"`
+}
+
+// StorageClassAnalysis - where the results of a storage class analysis
+// are exported to, if anywhere.
+type StorageClassAnalysis struct {
+	DataExport *AnalyticsDataExport `xml:"DataExport,omitempty"`
+}
+
+// AnalyticsDataExport - export of the results of a storage class
+// analysis.
+type AnalyticsDataExport struct {
+	// Version of the exported data, defaults to "V_1".
+	OutputSchemaVersion string                     `xml:"OutputSchemaVersion"`
+	Destination         AnalyticsExportDestination `xml:"Destination"`
+}
+
+// AnalyticsExportDestination - destination of exported analysis
+// results.
+type AnalyticsExportDestination struct {
+	S3BucketDestination AnalyticsS3BucketDestination `xml:"S3BucketDestination"`
+}
+
+// AnalyticsS3BucketDestination - bucket exported analysis results are
+// written to.
+type AnalyticsS3BucketDestination struct {
+	// Format of the exported data, defaults to "CSV".
+	Format string `xml:"Format"`
+	// Account owning the destination bucket, if any.
+	BucketAccountID string `xml:"BucketAccountId,omitempty"`
+	// ARN of the destination bucket, e.g. "arn:aws:s3:::mybucket".
+	Bucket string `xml:"Bucket"`
+	Prefix string `xml:"Prefix,omitempty"`
+}
+
+// listBucketAnalyticsResult container for the response of listing the
+// analytics configurations of a bucket.
+type listBucketAnalyticsResult struct {
+	AnalyticsConfigurations []AnalyticsConfiguration `xml:"AnalyticsConfiguration"`
+	IsTruncated             bool
+	NextContinuationToken   string
+}
+
+// SetBucketAnalytics - sets the analytics configuration of a bucket
+// with the ID of the configuration, replacing the configuration with
+// this ID if any.
+func (c Client) SetBucketAnalytics(bucketName string, config AnalyticsConfiguration) error {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return err
+	}
+	if config.ID == "" {
+		return ErrInvalidArgument("Analytics configuration ID cannot be empty.")
+	}
+	if config.StorageClassAnalysis.DataExport != nil {
+		export := *config.StorageClassAnalysis.DataExport
+		config.StorageClassAnalysis.DataExport = &export
+		if export.OutputSchemaVersion == "" {
+			export.OutputSchemaVersion = "V_1"
+		}
+		if export.Destination.S3BucketDestination.Format == "" {
+			export.Destination.S3BucketDestination.Format = "CSV"
+		}
+	}
+
+	urlValues := make(url.Values)
+	urlValues.Set("analytics", "")
+	urlValues.Set("id", config.ID)
+	return c.putBucketConfig(bucketName, urlValues, config)
+}
+
+// GetBucketAnalytics - gets the analytics configuration of a bucket
+// with the given ID.
+func (c Client) GetBucketAnalytics(bucketName, id string) (AnalyticsConfiguration, error) {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return AnalyticsConfiguration{}, err
+	}
+	if id == "" {
+		return AnalyticsConfiguration{}, ErrInvalidArgument("Analytics configuration ID cannot be empty.")
+	}
+
+	urlValues := make(url.Values)
+	urlValues.Set("analytics", "")
+	urlValues.Set("id", id)
+	var config AnalyticsConfiguration
+	if err := c.getBucketConfig(bucketName, urlValues, &config); err != nil {
+		return AnalyticsConfiguration{}, err
+	}
+	return config, nil
+}
+
+// ListBucketAnalytics - lists all analytics configurations of a
+// bucket.
+func (c Client) ListBucketAnalytics(bucketName string) ([]AnalyticsConfiguration, error) {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return nil, err
+	}
+
+	var configs []AnalyticsConfiguration
+	var continuationToken string
+	for {
+		urlValues := make(url.Values)
+		urlValues.Set("analytics", "")
+		if continuationToken != "" {
+			urlValues.Set("continuation-token", continuationToken)
+		}
+		var result listBucketAnalyticsResult
+		if err := c.getBucketConfig(bucketName, urlValues, &result); err != nil {
+			return nil, err
+		}
+		configs = append(configs, result.AnalyticsConfigurations...)
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return configs, nil
+		}
+		continuationToken = result.NextContinuationToken
+	}
+}
+
+// RemoveBucketAnalytics - removes the analytics configuration of a
+// bucket with the given ID.
+func (c Client) RemoveBucketAnalytics(bucketName, id string) error {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return err
+	}
+	if id == "" {
+		return ErrInvalidArgument("Analytics configuration ID cannot be empty.")
+	}
+
+	urlValues := make(url.Values)
+	urlValues.Set("analytics", "")
+	urlValues.Set("id", id)
+	return c.removeBucketConfig(bucketName, urlValues)
+}
@@ -0,0 +1,564 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// newBucketConfigServer - returns a server storing the configurations
+// sent to the subresources of a bucket, keyed by subresource and ID.
+// Configurations with IDs are listed two per page, stored error
+// documents are sent with 404 Not Found.
+func newBucketConfigServer(t *testing.T, configs map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		var subresource string
+		for key := range query {
+			if key != "id" && key != "continuation-token" {
+				subresource = key
+			}
+		}
+		key := subresource
+		if id := query.Get("id"); id != "" {
+			key += "/" + id
+		}
+		switch r.Method {
+		case "PUT":
+			body, _ := ioutil.ReadAll(r.Body)
+			sum := md5.Sum(body)
+			if r.Header.Get("Content-Md5") != base64.StdEncoding.EncodeToString(sum[:]) {
+				t.Errorf("expected Content-MD5 to be set for %s", key)
+			}
+			configs[key] = string(body)
+		case "DELETE":
+			delete(configs, key)
+			w.WriteHeader(http.StatusNoContent)
+		case "GET":
+			if config, ok := configs[key]; ok {
+				if strings.HasPrefix(config, "<Error>") {
+					w.WriteHeader(http.StatusNotFound)
+				}
+				fmt.Fprint(w, config)
+				return
+			}
+			if _, ok := query["id"]; ok {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, "<Error><Code>NoSuchConfiguration</Code><Message>The specified configuration does not exist.</Message></Error>")
+				return
+			}
+			var keys []string
+			for key := range configs {
+				if strings.HasPrefix(key, subresource+"/") {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+			start := 0
+			if token := query.Get("continuation-token"); token != "" {
+				fmt.Sscan(token, &start)
+			}
+			end := start + 2
+			truncated := end < len(keys)
+			if !truncated {
+				end = len(keys)
+			}
+			fmt.Fprintf(w, "<ListConfigurationsResult><IsTruncated>%t</IsTruncated><NextContinuationToken>%d</NextContinuationToken>", truncated, end)
+			for _, key := range keys[start:end] {
+				fmt.Fprint(w, configs[key])
+			}
+			fmt.Fprint(w, "</ListConfigurationsResult>")
+		}
+	}))
+}
+
+// Tests setting, getting, listing and removing analytics
+// configurations.
+func TestBucketAnalytics(t *testing.T) {
+	configs := make(map[string]string)
+	server := newBucketConfigServer(t, configs)
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	export := &AnalyticsDataExport{
+		Destination: AnalyticsExportDestination{
+			S3BucketDestination: AnalyticsS3BucketDestination{Bucket: "arn:aws:s3:::reports", Prefix: "analysis/"},
+		},
+	}
+	documents := AnalyticsConfiguration{
+		ID:                   "documents",
+		Filter:               &AnalyticsFilter{And: &AnalyticsAndOperator{Prefix: "documents/", Tags: []Tag{{Key: "class", Value: "a"}, {Key: "team", Value: "b"}}}},
+		StorageClassAnalysis: StorageClassAnalysis{DataExport: export},
+	}
+	if err = c.SetBucketAnalytics("bucket", documents); err != nil {
+		t.Fatal(err)
+	}
+	if export.OutputSchemaVersion != "" {
+		t.Error("expected the data export of the caller not to be modified")
+	}
+	for _, id := range []string{"images", "videos"} {
+		if err = c.SetBucketAnalytics("bucket", AnalyticsConfiguration{ID: id, Filter: &AnalyticsFilter{Prefix: id + "/"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config, err := c.GetBucketAnalytics("bucket", "documents")
+	if err != nil {
+		t.Fatal(err)
+	}
+	documents.StorageClassAnalysis.DataExport = &AnalyticsDataExport{
+		OutputSchemaVersion: "V_1",
+		Destination: AnalyticsExportDestination{
+			S3BucketDestination: AnalyticsS3BucketDestination{Format: "CSV", Bucket: "arn:aws:s3:::reports", Prefix: "analysis/"},
+		},
+	}
+	config.XMLName = xml.Name{}
+	if !reflect.DeepEqual(config, documents) {
+		t.Errorf("expected %+v, got %+v", documents, config)
+	}
+
+	list, err := c.ListBucketAnalytics("bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, config := range list {
+		ids = append(ids, config.ID)
+	}
+	if expected := []string{"documents", "images", "videos"}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("expected configurations %v, got %v", expected, ids)
+	}
+
+	if err = c.RemoveBucketAnalytics("bucket", "images"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.GetBucketAnalytics("bucket", "images"); ToErrorResponse(err).Code != "NoSuchConfiguration" {
+		t.Errorf("expected removed configuration to be missing, got %v", err)
+	}
+	if err = c.SetBucketAnalytics("bucket", AnalyticsConfiguration{}); err == nil {
+		t.Error("expected configuration without ID to be rejected")
+	}
+}
+
+// Tests setting, getting, listing and removing metrics configurations.
+func TestBucketMetrics(t *testing.T) {
+	configs := make(map[string]string)
+	server := newBucketConfigServer(t, configs)
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	all := MetricsConfiguration{ID: "all"}
+	tagged := MetricsConfiguration{ID: "tagged", Filter: &MetricsFilter{Tag: &Tag{Key: "team", Value: "b"}}}
+	for _, config := range []MetricsConfiguration{all, tagged} {
+		if err = c.SetBucketMetrics("bucket", config); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if expected := `<MetricsConfiguration><Id>tagged</Id><Filter><Tag><Key>team</Key><Value>b</Value></Tag></Filter></MetricsConfiguration>`; configs["metrics/tagged"] != expected {
+		t.Errorf("expected %s to be sent, got %s", expected, configs["metrics/tagged"])
+	}
+
+	config, err := c.GetBucketMetrics("bucket", "tagged")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.ID != "tagged" || !reflect.DeepEqual(config.Filter, tagged.Filter) {
+		t.Errorf("expected %+v, got %+v", tagged, config)
+	}
+
+	list, err := c.ListBucketMetrics("bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].ID != "all" || list[0].Filter != nil || list[1].ID != "tagged" {
+		t.Errorf("unexpected configurations %+v", list)
+	}
+
+	if err = c.RemoveBucketMetrics("bucket", "all"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := configs["metrics/all"]; ok {
+		t.Error("expected configuration to be removed")
+	}
+}
+
+// Tests setting and getting the access logging configuration.
+func TestBucketLogging(t *testing.T) {
+	configs := make(map[string]string)
+	server := newBucketConfigServer(t, configs)
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Response as sent by Amazon S3.
+	configs["logging"] = `<BucketLoggingStatus xmlns="http://doc.s3.amazonaws.com/2006-03-01">` +
+		`<LoggingEnabled><TargetBucket>logs</TargetBucket><TargetPrefix>access/</TargetPrefix><TargetGrants>` +
+		`<Grant><Grantee xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:type="Group"><URI>http://acs.amazonaws.com/groups/global/AllUsers</URI></Grantee><Permission>READ</Permission></Grant>` +
+		`</TargetGrants></LoggingEnabled></BucketLoggingStatus>`
+	config, err := c.GetBucketLogging("bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &LoggingEnabled{
+		TargetBucket: "logs",
+		TargetPrefix: "access/",
+		TargetGrants: []LoggingGrant{{Grantee: Grantee{Type: "Group", URI: "http://acs.amazonaws.com/groups/global/AllUsers"}, Permission: "READ"}},
+	}
+	if !reflect.DeepEqual(config.LoggingEnabled, expected) {
+		t.Errorf("expected %+v, got %+v", expected, config.LoggingEnabled)
+	}
+
+	// Configurations sent round trip.
+	if err = c.SetBucketLogging("bucket", config); err != nil {
+		t.Fatal(err)
+	}
+	config, err = c.GetBucketLogging("bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(config.LoggingEnabled, expected) {
+		t.Errorf("expected %+v, got %+v", expected, config.LoggingEnabled)
+	}
+
+	if err = c.SetBucketLogging("bucket", BucketLogging{}); err != nil {
+		t.Fatal(err)
+	}
+	if configs["logging"] != "<BucketLoggingStatus></BucketLoggingStatus>" {
+		t.Errorf("expected logging to be disabled, got %s", configs["logging"])
+	}
+	if err = c.SetBucketLogging("bucket", BucketLogging{LoggingEnabled: &LoggingEnabled{}}); err == nil {
+		t.Error("expected configuration without target bucket to be rejected")
+	}
+}
+
+// Tests setting, getting, listing and removing Intelligent-Tiering
+// configurations.
+func TestBucketIntelligentTiering(t *testing.T) {
+	configs := make(map[string]string)
+	server := newBucketConfigServer(t, configs)
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archive := IntelligentTieringConfiguration{
+		ID:     "archive",
+		Filter: &IntelligentTieringFilter{Prefix: "backups/"},
+		Tierings: []IntelligentTiering{
+			{AccessTier: ArchiveAccess, Days: 90},
+			{AccessTier: DeepArchiveAccess, Days: 180},
+		},
+	}
+	if err = c.SetBucketIntelligentTiering("bucket", archive); err != nil {
+		t.Fatal(err)
+	}
+	expected := `<IntelligentTieringConfiguration><Id>archive</Id><Filter><Prefix>backups/</Prefix></Filter><Status>Enabled</Status>` +
+		`<Tiering><AccessTier>ARCHIVE_ACCESS</AccessTier><Days>90</Days></Tiering>` +
+		`<Tiering><AccessTier>DEEP_ARCHIVE_ACCESS</AccessTier><Days>180</Days></Tiering></IntelligentTieringConfiguration>`
+	if configs["intelligent-tiering/archive"] != expected {
+		t.Errorf("expected %s to be sent, got %s", expected, configs["intelligent-tiering/archive"])
+	}
+	if err = c.SetBucketIntelligentTiering("bucket", IntelligentTieringConfiguration{
+		ID:       "tagged",
+		Status:   "Disabled",
+		Filter:   &IntelligentTieringFilter{Tag: &Tag{Key: "tier", Value: "cold"}},
+		Tierings: []IntelligentTiering{{AccessTier: DeepArchiveAccess, Days: 365}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := c.GetBucketIntelligentTiering("bucket", "archive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Status != "Enabled" || !reflect.DeepEqual(config.Tierings, archive.Tierings) || !reflect.DeepEqual(config.Filter, archive.Filter) {
+		t.Errorf("expected %+v, got %+v", archive, config)
+	}
+
+	list, err := c.ListBucketIntelligentTiering("bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].ID != "archive" || list[1].ID != "tagged" || list[1].Status != "Disabled" {
+		t.Errorf("unexpected configurations %+v", list)
+	}
+
+	if err = c.RemoveBucketIntelligentTiering("bucket", "archive"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := configs["intelligent-tiering/archive"]; ok {
+		t.Error("expected configuration to be removed")
+	}
+
+	for i, config := range []IntelligentTieringConfiguration{
+		{Tierings: archive.Tierings},
+		{ID: "none"},
+		{ID: "unknown", Tierings: []IntelligentTiering{{AccessTier: "GLACIER", Days: 90}}},
+	} {
+		if err = c.SetBucketIntelligentTiering("bucket", config); ToErrorResponse(err).Code != "InvalidArgument" {
+			t.Errorf("Test %d: expected invalid configuration to be rejected, got %v", i+1, err)
+		}
+	}
+}
+
+// Tests setting, getting and removing the public access block
+// configuration.
+func TestBucketPublicAccessBlock(t *testing.T) {
+	configs := map[string]string{
+		"publicAccessBlock": "<Error><Code>NoSuchPublicAccessBlockConfiguration</Code><Message>The public access block configuration was not found</Message></Error>",
+	}
+	server := newBucketConfigServer(t, configs)
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := c.GetBucketPublicAccessBlock("bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config != (PublicAccessBlockConfiguration{}) {
+		t.Errorf("expected nothing to be blocked, got %+v", config)
+	}
+
+	if err = c.SetBucketPublicAccessBlock("bucket", PublicAccessBlockConfiguration{BlockPublicAcls: true, BlockPublicPolicy: true}); err != nil {
+		t.Fatal(err)
+	}
+	expected := "<PublicAccessBlockConfiguration><BlockPublicAcls>true</BlockPublicAcls><IgnorePublicAcls>false</IgnorePublicAcls>" +
+		"<BlockPublicPolicy>true</BlockPublicPolicy><RestrictPublicBuckets>false</RestrictPublicBuckets></PublicAccessBlockConfiguration>"
+	if configs["publicAccessBlock"] != expected {
+		t.Errorf("expected %s to be sent, got %s", expected, configs["publicAccessBlock"])
+	}
+	config, err = c.GetBucketPublicAccessBlock("bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !config.BlockPublicAcls || config.IgnorePublicAcls || !config.BlockPublicPolicy || config.RestrictPublicBuckets {
+		t.Errorf("unexpected configuration %+v", config)
+	}
+
+	if err = c.RemoveBucketPublicAccessBlock("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := configs["publicAccessBlock"]; ok {
+		t.Error("expected configuration to be removed")
+	}
+}
+
+// Tests setting, getting and removing the ownership controls.
+func TestBucketOwnershipControls(t *testing.T) {
+	configs := map[string]string{
+		"ownershipControls": "<Error><Code>OwnershipControlsNotFoundError</Code><Message>The bucket ownership controls were not found</Message></Error>",
+	}
+	server := newBucketConfigServer(t, configs)
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ownership, err := c.GetBucketOwnershipControls("bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ownership != "" {
+		t.Errorf("expected no ownership controls, got %s", ownership)
+	}
+
+	if err = c.SetBucketOwnershipControls("bucket", OwnershipBucketOwnerEnforced); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "<OwnershipControls><Rule><ObjectOwnership>BucketOwnerEnforced</ObjectOwnership></Rule></OwnershipControls>"; configs["ownershipControls"] != expected {
+		t.Errorf("expected %s to be sent, got %s", expected, configs["ownershipControls"])
+	}
+	ownership, err = c.GetBucketOwnershipControls("bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ownership != OwnershipBucketOwnerEnforced {
+		t.Errorf("expected %s, got %s", OwnershipBucketOwnerEnforced, ownership)
+	}
+
+	if err = c.RemoveBucketOwnershipControls("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := configs["ownershipControls"]; ok {
+		t.Error("expected ownership controls to be removed")
+	}
+	if err = c.SetBucketOwnershipControls("bucket", "BucketOwner"); ToErrorResponse(err).Code != "InvalidArgument" {
+		t.Errorf("expected unknown ownership to be rejected, got %v", err)
+	}
+}
+
+// Tests setting and getting the transfer acceleration status.
+func TestBucketAccelerate(t *testing.T) {
+	configs := map[string]string{
+		"accelerate": `<AccelerateConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/"/>`,
+	}
+	server := newBucketConfigServer(t, configs)
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	status, err := c.GetBucketAccelerate("bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != "" {
+		t.Errorf("expected no status, got %s", status)
+	}
+
+	if err = c.SetBucketAccelerate("bucket", AccelerateEnabled); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "<AccelerateConfiguration><Status>Enabled</Status></AccelerateConfiguration>"; configs["accelerate"] != expected {
+		t.Errorf("expected %s to be sent, got %s", expected, configs["accelerate"])
+	}
+	status, err = c.GetBucketAccelerate("bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != AccelerateEnabled {
+		t.Errorf("expected %s, got %s", AccelerateEnabled, status)
+	}
+	if err = c.SetBucketAccelerate("bucket", ""); ToErrorResponse(err).Code != "InvalidArgument" {
+		t.Errorf("expected empty status to be rejected, got %v", err)
+	}
+}
+
+// Tests that transfer acceleration is configured through the regular
+// endpoint.
+func TestBucketAccelerateEndpoint(t *testing.T) {
+	c, err := NewWithRegion("s3.amazonaws.com", "access", "secret", true, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetS3TransferAccelerate("s3-accelerate.amazonaws.com")
+	u, err := c.makeTargetURL("bucket", "", "us-east-1", true, url.Values{"accelerate": {""}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Host != "bucket.s3.dualstack.us-east-1.amazonaws.com" {
+		t.Errorf("expected regular endpoint, got %s", u.Host)
+	}
+	u, err = c.makeTargetURL("bucket", "object", "us-east-1", true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Host != "bucket.s3-accelerate.amazonaws.com" {
+		t.Errorf("expected accelerate endpoint, got %s", u.Host)
+	}
+}
+
+// Tests setting and getting the object lock configuration.
+func TestBucketObjectLockConfig(t *testing.T) {
+	configs := map[string]string{
+		"object-lock": "<Error><Code>ObjectLockConfigurationNotFoundError</Code><Message>Object Lock configuration does not exist for this bucket</Message></Error>",
+	}
+	server := newBucketConfigServer(t, configs)
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objectLock, mode, validity, unit, err := c.GetBucketObjectLockConfig("bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objectLock != "" || mode != nil || validity != nil || unit != nil {
+		t.Errorf("expected object locking to be disabled, got %s %v %v %v", objectLock, mode, validity, unit)
+	}
+
+	governance, thirty, days := Governance, uint(30), Days
+	if err = c.SetBucketObjectLockConfig("bucket", &governance, &thirty, &days); err != nil {
+		t.Fatal(err)
+	}
+	expected := "<ObjectLockConfiguration><ObjectLockEnabled>Enabled</ObjectLockEnabled>" +
+		"<Rule><DefaultRetention><Mode>GOVERNANCE</Mode><Days>30</Days></DefaultRetention></Rule></ObjectLockConfiguration>"
+	if configs["object-lock"] != expected {
+		t.Errorf("expected %s to be sent, got %s", expected, configs["object-lock"])
+	}
+	objectLock, mode, validity, unit, err = c.GetBucketObjectLockConfig("bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objectLock != "Enabled" || mode == nil || *mode != Governance || validity == nil || *validity != 30 || unit == nil || *unit != Days {
+		t.Errorf("unexpected configuration %s %v %v %v", objectLock, mode, validity, unit)
+	}
+
+	if err = c.SetBucketObjectLockConfig("bucket", nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if expected = "<ObjectLockConfiguration><ObjectLockEnabled>Enabled</ObjectLockEnabled></ObjectLockConfiguration>"; configs["object-lock"] != expected {
+		t.Errorf("expected %s to be sent, got %s", expected, configs["object-lock"])
+	}
+	objectLock, mode, _, _, err = c.GetBucketObjectLockConfig("bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objectLock != "Enabled" || mode != nil {
+		t.Errorf("expected no default retention, got %s %v", objectLock, mode)
+	}
+
+	invalidMode, zero, years := RetentionMode("LEGAL"), uint(0), Years
+	for i, testCase := range []struct {
+		mode     *RetentionMode
+		validity *uint
+		unit     *ValidityUnit
+	}{
+		{&governance, nil, nil},
+		{&invalidMode, &thirty, &years},
+		{&governance, &zero, &years},
+	} {
+		if err = c.SetBucketObjectLockConfig("bucket", testCase.mode, testCase.validity, testCase.unit); ToErrorResponse(err).Code != "InvalidArgument" {
+			t.Errorf("Test %d: expected invalid configuration to be rejected, got %v", i+1, err)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"encoding/xml"
+	"net/url"
+)
+
+// IntelligentTieringAccessTier - archive access tier of the
+// Intelligent-Tiering storage class.
+type IntelligentTieringAccessTier string
+
+// Different archive access tiers of the Intelligent-Tiering storage
+// class.
+const (
+	// ArchiveAccess tier, objects not accessed for at least 90 days
+	// may be moved to it.
+	ArchiveAccess IntelligentTieringAccessTier = "ARCHIVE_ACCESS"
+	// DeepArchiveAccess tier, objects not accessed for at least 180
+	// days may be moved to it.
+	DeepArchiveAccess IntelligentTieringAccessTier = "DEEP_ARCHIVE_ACCESS"
+)
+
+// IntelligentTieringConfiguration - Intelligent-Tiering configuration
+// of a bucket, objects which match the filter are moved to the archive
+// access tiers after the given number of days without access.
+type IntelligentTieringConfiguration struct {
+	XMLName xml.Name                  `xml:"IntelligentTieringConfiguration"`
+	ID      string                    `xml:"Id"`
+	Filter  *IntelligentTieringFilter `xml:"Filter,omitempty"`
+	// Status is either "Enabled" or "Disabled", defaults to
+	// "Enabled".
+	Status   string               `xml:"Status"`
+	Tierings []IntelligentTiering `xml:"Tiering"`
+}
+
+// IntelligentTieringFilter - objects a configuration applies to, only
+// one of Prefix, Tag and And may be set.
+type IntelligentTieringFilter struct {
+	Prefix string                         `xml:"Prefix,omitempty"`
+	Tag    *Tag                           `xml:"Tag,omitempty"`
+	And    *IntelligentTieringAndOperator `xml:"And,omitempty"`
+}
+
+// IntelligentTieringAndOperator - objects matching the prefix and all
+// the tags.
+type IntelligentTieringAndOperator struct {
+	Prefix string `xml:"Prefix,omitempty"`
+	Tags   []Tag  `xml:"Tag"`
+}
+
+// IntelligentTiering - number of days without access after which
+// objects are moved to an archive access tier.
+type IntelligentTiering struct {
+	AccessTier IntelligentTieringAccessTier `xml:"AccessTier"`
+	Days       int                          `xml:"Days"`
+}
+
+// listBucketIntelligentTieringResult container for the response of
+// listing the Intelligent-Tiering configurations of a bucket.
+type listBucketIntelligentTieringResult struct {
+	IntelligentTieringConfigurations []IntelligentTieringConfiguration `xml:"IntelligentTieringConfiguration"`
+	IsTruncated                      bool
+	NextContinuationToken            string
+}
+
+// SetBucketIntelligentTiering - sets the Intelligent-Tiering
+// configuration of a bucket with the ID of the configuration,
+// replacing the configuration with this ID if any.
+func (c Client) SetBucketIntelligentTiering(bucketName string, config IntelligentTieringConfiguration) error {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return err
+	}
+	if config.ID == "" {
+		return ErrInvalidArgument("Intelligent-Tiering configuration ID cannot be empty.")
+	}
+	if len(config.Tierings) == 0 {
+		return ErrInvalidArgument("Intelligent-Tiering configuration requires at least one tiering.")
+	}
+	for _, tiering := range config.Tierings {
+		if tiering.AccessTier != ArchiveAccess && tiering.AccessTier != DeepArchiveAccess {
+			return ErrInvalidArgument("Unsupported Intelligent-Tiering access tier ‘" + string(tiering.AccessTier) + "’.")
+		}
+	}
+	if config.Status == "" {
+		config.Status = "Enabled"
+	}
+
+	urlValues := make(url.Values)
+	urlValues.Set("intelligent-tiering", "")
+	urlValues.Set("id", config.ID)
+	return c.putBucketConfig(bucketName, urlValues, config)
+}
+
+// GetBucketIntelligentTiering - gets the Intelligent-Tiering
+// configuration of a bucket with the given ID.
+func (c Client) GetBucketIntelligentTiering(bucketName, id string) (IntelligentTieringConfiguration, error) {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return IntelligentTieringConfiguration{}, err
+	}
+	if id == "" {
+		return IntelligentTieringConfiguration{}, ErrInvalidArgument("Intelligent-Tiering configuration ID cannot be empty.")
+	}
+
+	urlValues := make(url.Values)
+	urlValues.Set("intelligent-tiering", "")
+	urlValues.Set("id", id)
+	var config IntelligentTieringConfiguration
+	if err := c.getBucketConfig(bucketName, urlValues, &config); err != nil {
+		return IntelligentTieringConfiguration{}, err
+	}
+	return config, nil
+}
+
+// ListBucketIntelligentTiering - lists all Intelligent-Tiering
+// configurations of a bucket.
+func (c Client) ListBucketIntelligentTiering(bucketName string) ([]IntelligentTieringConfiguration, error) {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return nil, err
+	}
+
+	var configs []IntelligentTieringConfiguration
+	var continuationToken string
+	for {
+		urlValues := make(url.Values)
+		urlValues.Set("intelligent-tiering", "")
+		if continuationToken != "" {
+			urlValues.Set("continuation-token", continuationToken)
+		}
+		var result listBucketIntelligentTieringResult
+		if err := c.getBucketConfig(bucketName, urlValues, &result); err != nil {
+			return nil, err
+		}
+		configs = append(configs, result.IntelligentTieringConfigurations...)
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return configs, nil
+		}
+		continuationToken = result.NextContinuationToken
+	}
+}
+
+// RemoveBucketIntelligentTiering - removes the Intelligent-Tiering
+// configuration of a bucket with the given ID.
+func (c Client) RemoveBucketIntelligentTiering(bucketName, id string) error {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return err
+	}
+	if id == "" {
+		return ErrInvalidArgument("Intelligent-Tiering configuration ID cannot be empty.")
+	}
+
+	urlValues := make(url.Values)
+	urlValues.Set("intelligent-tiering", "")
+	urlValues.Set("id", id)
+	return c.removeBucketConfig(bucketName, urlValues)
+}
@@ -0,0 +1,95 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"encoding/xml"
+	"net/url"
+)
+
+// BucketLogging - access logging configuration of a bucket, access
+// logging is disabled if LoggingEnabled is not set.
+type BucketLogging struct {
+	XMLName        xml.Name        `xml:"BucketLoggingStatus"`
+	LoggingEnabled *LoggingEnabled `xml:"LoggingEnabled,omitempty"`
+}
+
+// LoggingEnabled - where access logs of a bucket are delivered to.
+type LoggingEnabled struct {
+	// Bucket access logs are written to.
+	TargetBucket string `xml:"TargetBucket"`
+	// Prefix of the names of the access log objects.
+	TargetPrefix string `xml:"TargetPrefix"`
+	// Permissions granted on the access log objects.
+	TargetGrants []LoggingGrant `xml:"TargetGrants>Grant,omitempty"`
+}
+
+// LoggingGrant - permission granted to a grantee on access log
+// objects.
+type LoggingGrant struct {
+	Grantee Grantee `xml:"Grantee"`
+	// Permission is one of "FULL_CONTROL", "READ" or "WRITE".
+	Permission string `xml:"Permission"`
+}
+
+// Grantee - the user or group a permission is granted to.
+type Grantee struct {
+	// Type is one of "CanonicalUser", "AmazonCustomerByEmail" or
+	// "Group", identified by ID, EmailAddress and URI respectively.
+	Type         string `xml:"http://www.w3.org/2001/XMLSchema-instance type,attr"`
+	ID           string `xml:"ID,omitempty"`
+	DisplayName  string `xml:"DisplayName,omitempty"`
+	EmailAddress string `xml:"EmailAddress,omitempty"`
+	URI          string `xml:"URI,omitempty"`
+}
+
+// SetBucketLogging - sets the access logging configuration of a
+// bucket, a configuration without LoggingEnabled disables access
+// logging.
+func (c Client) SetBucketLogging(bucketName string, config BucketLogging) error {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return err
+	}
+	if config.LoggingEnabled != nil {
+		if err := c.validateBucketName(config.LoggingEnabled.TargetBucket, false); err != nil {
+			return err
+		}
+	}
+
+	urlValues := make(url.Values)
+	urlValues.Set("logging", "")
+	return c.putBucketConfig(bucketName, urlValues, config)
+}
+
+// GetBucketLogging - gets the access logging configuration of a
+// bucket.
+func (c Client) GetBucketLogging(bucketName string) (BucketLogging, error) {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return BucketLogging{}, err
+	}
+
+	urlValues := make(url.Values)
+	urlValues.Set("logging", "")
+	var config BucketLogging
+	if err := c.getBucketConfig(bucketName, urlValues, &config); err != nil {
+		return BucketLogging{}, err
+	}
+	return config, nil
+}
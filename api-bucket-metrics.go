@@ -0,0 +1,140 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"encoding/xml"
+	"net/url"
+)
+
+// MetricsConfiguration - request metrics configuration of a bucket,
+// metrics are reported for requests to objects which match the
+// filter, or to all objects without a filter.
+type MetricsConfiguration struct {
+	XMLName xml.Name       `xml:"MetricsConfiguration"`
+	ID      string         `xml:"Id"`
+	Filter  *MetricsFilter `xml:"Filter,omitempty"`
+}
+
+// MetricsFilter - objects reported by a metrics configuration, only
+// one of Prefix, Tag, AccessPointArn and And may be set.
+type MetricsFilter struct {
+	Prefix         string              `xml:"Prefix,omitempty"`
+	Tag            *Tag                `xml:"Tag,omitempty"`
+	AccessPointArn string              `xml:"AccessPointArn,omitempty"`
+	And            *MetricsAndOperator `xml:"And,omitempty"`
+}
+
+// MetricsAndOperator - objects matching the prefix, the access point
+// and all the tags.
+type MetricsAndOperator struct {
+	Prefix         string `xml:"Prefix,omitempty"`
+	Tags           []Tag  `xml:"Tag"`
+	AccessPointArn string `xml:"AccessPointArn,omitempty"`
+}
+
+// listBucketMetricsResult container for the response of listing the
+// metrics configurations of a bucket.
+type listBucketMetricsResult struct {
+	MetricsConfigurations []MetricsConfiguration `xml:"MetricsConfiguration"`
+	IsTruncated           bool
+	NextContinuationToken string
+}
+
+// SetBucketMetrics - sets the metrics configuration of a bucket with
+// the ID of the configuration, replacing the configuration with this
+// ID if any.
+func (c Client) SetBucketMetrics(bucketName string, config MetricsConfiguration) error {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return err
+	}
+	if config.ID == "" {
+		return ErrInvalidArgument("Metrics configuration ID cannot be empty.")
+	}
+
+	urlValues := make(url.Values)
+	urlValues.Set("metrics", "")
+	urlValues.Set("id", config.ID)
+	return c.putBucketConfig(bucketName, urlValues, config)
+}
+
+// GetBucketMetrics - gets the metrics configuration of a bucket with
+// the given ID.
+func (c Client) GetBucketMetrics(bucketName, id string) (MetricsConfiguration, error) {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return MetricsConfiguration{}, err
+	}
+	if id == "" {
+		return MetricsConfiguration{}, ErrInvalidArgument("Metrics configuration ID cannot be empty.")
+	}
+
+	urlValues := make(url.Values)
+	urlValues.Set("metrics", "")
+	urlValues.Set("id", id)
+	var config MetricsConfiguration
+	if err := c.getBucketConfig(bucketName, urlValues, &config); err != nil {
+		return MetricsConfiguration{}, err
+	}
+	return config, nil
+}
+
+// ListBucketMetrics - lists all metrics configurations of a bucket.
+func (c Client) ListBucketMetrics(bucketName string) ([]MetricsConfiguration, error) {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return nil, err
+	}
+
+	var configs []MetricsConfiguration
+	var continuationToken string
+	for {
+		urlValues := make(url.Values)
+		urlValues.Set("metrics", "")
+		if continuationToken != "" {
+			urlValues.Set("continuation-token", continuationToken)
+		}
+		var result listBucketMetricsResult
+		if err := c.getBucketConfig(bucketName, urlValues, &result); err != nil {
+			return nil, err
+		}
+		configs = append(configs, result.MetricsConfigurations...)
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return configs, nil
+		}
+		continuationToken = result.NextContinuationToken
+	}
+}
+
+// RemoveBucketMetrics - removes the metrics configuration of a bucket
+// with the given ID.
+func (c Client) RemoveBucketMetrics(bucketName, id string) error {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return err
+	}
+	if id == "" {
+		return ErrInvalidArgument("Metrics configuration ID cannot be empty.")
+	}
+
+	urlValues := make(url.Values)
+	urlValues.Set("metrics", "")
+	urlValues.Set("id", id)
+	return c.removeBucketConfig(bucketName, urlValues)
+}
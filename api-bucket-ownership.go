@@ -0,0 +1,104 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"encoding/xml"
+	"net/url"
+)
+
+// ObjectOwnership - ownership of the objects uploaded to a bucket.
+type ObjectOwnership string
+
+// Different types of object ownership.
+const (
+	// OwnershipBucketOwnerEnforced disables ACLs, the bucket owner
+	// owns all objects.
+	OwnershipBucketOwnerEnforced ObjectOwnership = "BucketOwnerEnforced"
+	// OwnershipBucketOwnerPreferred makes the bucket owner own
+	// objects uploaded with the bucket-owner-full-control canned ACL.
+	OwnershipBucketOwnerPreferred ObjectOwnership = "BucketOwnerPreferred"
+	// OwnershipObjectWriter makes the uploading account own the objects.
+	OwnershipObjectWriter ObjectOwnership = "ObjectWriter"
+)
+
+// ownershipControls container for the ownership controls of a bucket.
+type ownershipControls struct {
+	XMLName xml.Name                `xml:"OwnershipControls"`
+	Rules   []ownershipControlsRule `xml:"Rule"`
+}
+
+// ownershipControlsRule - rule of the ownership controls of a bucket.
+type ownershipControlsRule struct {
+	ObjectOwnership ObjectOwnership `xml:"ObjectOwnership"`
+}
+
+// SetBucketOwnershipControls - sets the object ownership of a bucket.
+func (c Client) SetBucketOwnershipControls(bucketName string, ownership ObjectOwnership) error {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return err
+	}
+	switch ownership {
+	case OwnershipBucketOwnerEnforced, OwnershipBucketOwnerPreferred, OwnershipObjectWriter:
+	default:
+		return ErrInvalidArgument("Unsupported object ownership ‘" + string(ownership) + "’.")
+	}
+
+	controls := ownershipControls{Rules: []ownershipControlsRule{{ObjectOwnership: ownership}}}
+
+	urlValues := make(url.Values)
+	urlValues.Set("ownershipControls", "")
+	return c.putBucketConfig(bucketName, urlValues, controls)
+}
+
+// GetBucketOwnershipControls - gets the object ownership of a bucket,
+// the ownership is empty if the bucket has no ownership controls.
+func (c Client) GetBucketOwnershipControls(bucketName string) (ObjectOwnership, error) {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return "", err
+	}
+
+	urlValues := make(url.Values)
+	urlValues.Set("ownershipControls", "")
+	var controls ownershipControls
+	if err := c.getBucketConfig(bucketName, urlValues, &controls); err != nil {
+		if ToErrorResponse(err).Code == "OwnershipControlsNotFoundError" {
+			return "", nil
+		}
+		return "", err
+	}
+	if len(controls.Rules) == 0 {
+		return "", nil
+	}
+	return controls.Rules[0].ObjectOwnership, nil
+}
+
+// RemoveBucketOwnershipControls - removes the ownership controls of a
+// bucket.
+func (c Client) RemoveBucketOwnershipControls(bucketName string) error {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return err
+	}
+
+	urlValues := make(url.Values)
+	urlValues.Set("ownershipControls", "")
+	return c.removeBucketConfig(bucketName, urlValues)
+}
@@ -0,0 +1,87 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"encoding/xml"
+	"net/url"
+)
+
+// PublicAccessBlockConfiguration - public access block configuration
+// of a bucket.
+type PublicAccessBlockConfiguration struct {
+	XMLName xml.Name `xml:"PublicAccessBlockConfiguration"`
+	// BlockPublicAcls rejects requests setting public ACLs.
+	BlockPublicAcls bool `xml:"BlockPublicAcls"`
+	// IgnorePublicAcls ignores public ACLs of the bucket and its
+	// objects.
+	IgnorePublicAcls bool `xml:"IgnorePublicAcls"`
+	// BlockPublicPolicy rejects bucket policies granting public
+	// access.
+	BlockPublicPolicy bool `xml:"BlockPublicPolicy"`
+	// RestrictPublicBuckets restricts access to buckets with public
+	// policies to the account of the bucket owner.
+	RestrictPublicBuckets bool `xml:"RestrictPublicBuckets"`
+}
+
+// SetBucketPublicAccessBlock - sets the public access block
+// configuration of a bucket.
+func (c Client) SetBucketPublicAccessBlock(bucketName string, config PublicAccessBlockConfiguration) error {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return err
+	}
+
+	urlValues := make(url.Values)
+	urlValues.Set("publicAccessBlock", "")
+	return c.putBucketConfig(bucketName, urlValues, config)
+}
+
+// GetBucketPublicAccessBlock - gets the public access block
+// configuration of a bucket, nothing is blocked if the bucket has no
+// configuration.
+func (c Client) GetBucketPublicAccessBlock(bucketName string) (PublicAccessBlockConfiguration, error) {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return PublicAccessBlockConfiguration{}, err
+	}
+
+	urlValues := make(url.Values)
+	urlValues.Set("publicAccessBlock", "")
+	var config PublicAccessBlockConfiguration
+	if err := c.getBucketConfig(bucketName, urlValues, &config); err != nil {
+		if ToErrorResponse(err).Code == "NoSuchPublicAccessBlockConfiguration" {
+			return PublicAccessBlockConfiguration{}, nil
+		}
+		return PublicAccessBlockConfiguration{}, err
+	}
+	return config, nil
+}
+
+// RemoveBucketPublicAccessBlock - removes the public access block
+// configuration of a bucket.
+func (c Client) RemoveBucketPublicAccessBlock(bucketName string) error {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return err
+	}
+
+	urlValues := make(url.Values)
+	urlValues.Set("publicAccessBlock", "")
+	return c.removeBucketConfig(bucketName, urlValues)
+}
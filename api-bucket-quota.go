@@ -0,0 +1,140 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// minIOAdminPrefix - path prefix of the admin APIs of MinIO server.
+const minIOAdminPrefix = "/minio/admin/v3"
+
+// QuotaType - type of a bucket quota.
+type QuotaType string
+
+// Different types of bucket quotas.
+const (
+	// HardQuota rejects uploads which would exceed the quota.
+	HardQuota QuotaType = "hard"
+)
+
+// BucketQuota - quota configuration of a bucket, this is an extension
+// supported by MinIO server.
+type BucketQuota struct {
+	// Quota is the maximum size of the bucket in bytes, zero means
+	// no quota.
+	Quota uint64 `json:"quota"`
+
+	// Type of the quota.
+	Type QuotaType `json:"quotatype,omitempty"`
+}
+
+// SetBucketQuota - sets the quota of a bucket, a zero quota removes
+// the quota of the bucket. Requires admin credentials.
+func (c Client) SetBucketQuota(bucketName string, quota BucketQuota) error {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return err
+	}
+	if quota.Quota > 0 && quota.Type == "" {
+		quota.Type = HardQuota
+	}
+	data, err := json.Marshal(quota)
+	if err != nil {
+		return err
+	}
+
+	urlValues := make(url.Values)
+	urlValues.Set("bucket", bucketName)
+
+	resp, err := c.executeMethod(context.Background(), "PUT", requestMetadata{
+		adminAPI:         "set-bucket-quota",
+		queryValues:      urlValues,
+		contentBody:      bytes.NewReader(data),
+		contentLength:    int64(len(data)),
+		contentSHA256Hex: sum256Hex(data),
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return err
+	}
+	if resp != nil {
+		if resp.StatusCode != http.StatusOK {
+			return adminRespToErrorResponse(resp, bucketName)
+		}
+	}
+	return nil
+}
+
+// GetBucketQuota - gets the quota of a bucket, the quota is zero if
+// the bucket has none. Requires admin credentials.
+func (c Client) GetBucketQuota(bucketName string) (BucketQuota, error) {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return BucketQuota{}, err
+	}
+
+	urlValues := make(url.Values)
+	urlValues.Set("bucket", bucketName)
+
+	resp, err := c.executeMethod(context.Background(), "GET", requestMetadata{
+		adminAPI:         "get-bucket-quota",
+		queryValues:      urlValues,
+		contentSHA256Hex: emptySHA256Hex,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return BucketQuota{}, err
+	}
+	if resp != nil {
+		if resp.StatusCode != http.StatusOK {
+			err = adminRespToErrorResponse(resp, bucketName)
+			if ToErrorResponse(err).Code == "XMinioAdminNoSuchQuotaConfiguration" {
+				return BucketQuota{}, nil
+			}
+			return BucketQuota{}, err
+		}
+	}
+
+	var quota BucketQuota
+	if err = json.NewDecoder(resp.Body).Decode(&quota); err != nil {
+		return BucketQuota{}, err
+	}
+	return quota, nil
+}
+
+// adminRespToErrorResponse - returns the JSON error document sent by
+// an admin API, falling back to httpRespToErrorResponse.
+func adminRespToErrorResponse(resp *http.Response, bucketName string) error {
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	errResp := ErrorResponse{StatusCode: resp.StatusCode}
+	if err = json.Unmarshal(body, &errResp); err == nil && errResp.Code != "" {
+		errResp.BucketName = bucketName
+		return errResp
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return httpRespToErrorResponse(resp, bucketName, "")
+}
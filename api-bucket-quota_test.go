@@ -0,0 +1,91 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Tests setting and getting the quota of a bucket with the MinIO
+// admin API.
+func TestBucketQuota(t *testing.T) {
+	quotas := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bucket := r.URL.Query().Get("bucket")
+		switch {
+		case r.Method == "PUT" && r.URL.Path == "/minio/admin/v3/set-bucket-quota":
+			body, _ := ioutil.ReadAll(r.Body)
+			quotas[bucket] = string(body)
+		case r.Method == "GET" && r.URL.Path == "/minio/admin/v3/get-bucket-quota":
+			quota, ok := quotas[bucket]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"Code":"XMinioAdminNoSuchQuotaConfiguration","Message":"The quota configuration does not exist"}`)
+				return
+			}
+			fmt.Fprint(w, quota)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"Code":"XMinioAdminInvalidArgument","Message":"Invalid arguments specified."}`)
+		}
+	}))
+	defer server.Close()
+
+	c, err := New(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	quota, err := c.GetBucketQuota("bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quota != (BucketQuota{}) {
+		t.Errorf("expected no quota, got %+v", quota)
+	}
+
+	if err = c.SetBucketQuota("bucket", BucketQuota{Quota: 1 << 30}); err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"quota":1073741824,"quotatype":"hard"}`; quotas["bucket"] != expected {
+		t.Errorf("expected quota %s to be sent, got %s", expected, quotas["bucket"])
+	}
+	quota, err = c.GetBucketQuota("bucket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (BucketQuota{Quota: 1 << 30, Type: HardQuota}); quota != expected {
+		t.Errorf("expected quota %+v, got %+v", expected, quota)
+	}
+
+	quotas["bucket"] = "{"
+	if _, err = c.GetBucketQuota("bucket"); err == nil {
+		t.Error("expected malformed quota to fail")
+	}
+
+	c.endpointURL.Path = "/unknown"
+	err = c.SetBucketQuota("bucket", BucketQuota{})
+	if errResp := ToErrorResponse(err); errResp.Code != "XMinioAdminInvalidArgument" || errResp.StatusCode != http.StatusBadRequest || errResp.BucketName != "bucket" {
+		t.Errorf("expected admin error, got %#v", err)
+	}
+}
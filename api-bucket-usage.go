@@ -0,0 +1,191 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"sync"
+)
+
+// BucketUsage - number and total size of the objects under a prefix.
+type BucketUsage struct {
+	ObjectsCount int64
+	Size         int64
+}
+
+// BucketUsageOptions represents options specified by user for
+// GetBucketUsage call.
+type BucketUsageOptions struct {
+	// Only count objects whose names begin with Prefix.
+	Prefix string
+
+	// Number of prefixes listed concurrently, defaults to 4.
+	NumWorkers int
+
+	// Progress is called with the running totals after every listed
+	// page, calls are never concurrent.
+	Progress func(BucketUsage)
+}
+
+// usageCounter - accumulates the usage of concurrently listed pages.
+type usageCounter struct {
+	mutex    sync.Mutex
+	usage    BucketUsage
+	progress func(BucketUsage)
+}
+
+func (u *usageCounter) add(page BucketUsage) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	u.usage.ObjectsCount += page.ObjectsCount
+	u.usage.Size += page.Size
+	if u.progress != nil {
+		u.progress(u.usage)
+	}
+}
+
+// GetBucketUsage - computes the number and total size of the objects
+// under opts.Prefix. The prefixes directly under opts.Prefix are
+// listed concurrently.
+func (c Client) GetBucketUsage(bucketName string, opts BucketUsageOptions) (BucketUsage, error) {
+	return c.GetBucketUsageWithContext(context.Background(), bucketName, opts)
+}
+
+// GetBucketUsageWithContext - Identical to GetBucketUsage call, but accepts context to facilitate request cancellation.
+func (c Client) GetBucketUsageWithContext(ctx context.Context, bucketName string, opts BucketUsageOptions) (BucketUsage, error) {
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return BucketUsage{}, err
+	}
+
+	numWorkers := opts.NumWorkers
+	if numWorkers <= 0 {
+		numWorkers = totalWorkers
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	counter := &usageCounter{progress: opts.Progress}
+	var errOnce sync.Once
+	var firstErr error
+	setErr := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	// Workers list the prefixes found under opts.Prefix recursively.
+	prefixCh := make(chan string)
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for prefix := range prefixCh {
+				var page BucketUsage
+				err := c.listPages(ctx, bucketName, prefix, "", func(object ObjectInfo, isPrefix bool) bool {
+					page.ObjectsCount++
+					page.Size += object.Size
+					return true
+				}, func() {
+					counter.add(page)
+					page = BucketUsage{}
+				})
+				if err != nil {
+					setErr(err)
+				}
+			}
+		}()
+	}
+
+	// Objects directly under opts.Prefix are counted here.
+	var page BucketUsage
+	err := c.listPages(ctx, bucketName, opts.Prefix, "/", func(object ObjectInfo, isPrefix bool) bool {
+		if !isPrefix {
+			page.ObjectsCount++
+			page.Size += object.Size
+			return true
+		}
+		select {
+		case prefixCh <- object.Key:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}, func() {
+		counter.add(page)
+		page = BucketUsage{}
+	})
+	if err != nil {
+		setErr(err)
+	}
+	close(prefixCh)
+	wg.Wait()
+
+	if firstErr != nil {
+		return BucketUsage{}, firstErr
+	}
+	return counter.usage, nil
+}
+
+// listPages - pages through the listing of prefix, passing the objects
+// and common prefixes to fn and calling pageDone after every page.
+// Listing stops with the error of ctx if fn returns false.
+func (c Client) listPages(ctx context.Context, bucketName, prefix, delimiter string, fn listEntryFunc, pageDone func()) error {
+	var marker string
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var (
+			isTruncated bool
+			stopped     bool
+			err         error
+		)
+		if c.profile.listObjectsV1 {
+			var result ListBucketResult
+			result, stopped, err = c.listObjectsStream(bucketName, prefix, marker, delimiter, 1000, func(object ObjectInfo, isPrefix bool) bool {
+				// Save the marker.
+				if !isPrefix {
+					marker = object.Key
+				}
+				return fn(object, isPrefix)
+			})
+			if result.NextMarker != "" {
+				marker = result.NextMarker
+			}
+			isTruncated = result.IsTruncated
+		} else {
+			var result ListBucketV2Result
+			result, stopped, err = c.listObjectsV2Stream(bucketName, prefix, marker, false, delimiter, 1000, "", false, nil, fn)
+			marker = result.NextContinuationToken
+			isTruncated = result.IsTruncated
+		}
+		if err != nil {
+			return err
+		}
+		if stopped {
+			return ctx.Err()
+		}
+		pageDone()
+		if !isTruncated {
+			return nil
+		}
+	}
+}
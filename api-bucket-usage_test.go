@@ -0,0 +1,115 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// newListServer - returns a server listing objects with version 2 of
+// the listing, two keys per page.
+func newListServer(objects map[string]int64) *httptest.Server {
+	var keys []string
+	for key := range objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		prefix, delimiter := query.Get("prefix"), query.Get("delimiter")
+		start, _ := strconv.Atoi(query.Get("continuation-token"))
+		var entries []string
+		seen := make(map[string]bool)
+		for _, key := range keys {
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			if i := strings.Index(key[len(prefix):], delimiter); delimiter != "" && i >= 0 {
+				commonPrefix := key[:len(prefix)+i+1]
+				if !seen[commonPrefix] {
+					seen[commonPrefix] = true
+					entries = append(entries, "<CommonPrefixes><Prefix>"+commonPrefix+"</Prefix></CommonPrefixes>")
+				}
+				continue
+			}
+			entries = append(entries, fmt.Sprintf("<Contents><Key>%s</Key><Size>%d</Size></Contents>", key, objects[key]))
+		}
+		end := start + 2
+		truncated := end < len(entries)
+		if !truncated {
+			end = len(entries)
+		}
+		fmt.Fprintf(w, "<ListBucketResult><IsTruncated>%t</IsTruncated><NextContinuationToken>%d</NextContinuationToken>%s</ListBucketResult>",
+			truncated, end, strings.Join(entries[start:end], ""))
+	}))
+}
+
+// Tests computing the usage of a bucket and of a prefix.
+func TestGetBucketUsage(t *testing.T) {
+	server := newListServer(map[string]int64{
+		"a":       1,
+		"b":       2,
+		"c/":      0,
+		"c/1":     4,
+		"c/2":     8,
+		"c/d/3":   16,
+		"e/4":     32,
+		"e/5":     64,
+		"e/f/g/6": 128,
+	})
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		prefix   string
+		expected BucketUsage
+	}{
+		{"", BucketUsage{ObjectsCount: 9, Size: 255}},
+		{"c/", BucketUsage{ObjectsCount: 4, Size: 28}},
+		{"e/f", BucketUsage{ObjectsCount: 1, Size: 128}},
+		{"x", BucketUsage{}},
+	}
+	for i, testCase := range testCases {
+		var progress []BucketUsage
+		usage, err := c.GetBucketUsage("bucket", BucketUsageOptions{
+			Prefix:     testCase.prefix,
+			NumWorkers: 2,
+			Progress: func(usage BucketUsage) {
+				progress = append(progress, usage)
+			},
+		})
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if usage != testCase.expected {
+			t.Errorf("Test %d: expected %+v, got %+v", i+1, testCase.expected, usage)
+		}
+		if len(progress) == 0 || progress[len(progress)-1] != usage {
+			t.Errorf("Test %d: expected progress to end with %+v, got %+v", i+1, usage, progress)
+		}
+	}
+}
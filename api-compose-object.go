@@ -57,10 +57,10 @@ type DestinationInfo struct {
 // source is copied to the destination.
 func NewDestinationInfo(bucket, object string, sse encrypt.ServerSide, userMeta map[string]string) (d DestinationInfo, err error) {
 	// Input validation.
-	if err = s3utils.CheckValidBucketName(bucket); err != nil {
+	if err = ValidateBucketName(bucket, false); err != nil {
 		return d, err
 	}
-	if err = s3utils.CheckValidObjectName(object); err != nil {
+	if err = ValidateObjectKey(object); err != nil {
 		return d, err
 	}
 
@@ -137,7 +137,7 @@ func NewSourceInfo(bucket, object string, sse encrypt.ServerSide) SourceInfo {
 	}
 
 	// Set the source header
-	r.Headers.Set("x-amz-copy-source", s3utils.EncodePath(bucket+"/"+object))
+	r.Headers.Set("x-amz-copy-source", s3utils.EncodePath(copySourcePath(bucket, object)))
 	return r
 }
 
@@ -229,13 +229,14 @@ func (c Client) copyObjectDo(ctx context.Context, srcBucket, srcObject, destBuck
 	}
 
 	// Set the source header
-	headers.Set("x-amz-copy-source", s3utils.EncodePath(srcBucket+"/"+srcObject))
+	headers.Set("x-amz-copy-source", s3utils.EncodePath(copySourcePath(srcBucket, srcObject)))
 
 	// Send upload-part-copy request
 	resp, err := c.executeMethod(ctx, "PUT", requestMetadata{
 		bucketName:   destBucket,
 		objectName:   destObject,
 		customHeader: headers,
+		errorIn200:   true,
 	})
 	defer closeResponse(resp)
 	if err != nil {
@@ -267,7 +268,7 @@ func (c Client) copyObjectPartDo(ctx context.Context, srcBucket, srcObject, dest
 	headers := make(http.Header)
 
 	// Set source
-	headers.Set("x-amz-copy-source", s3utils.EncodePath(srcBucket+"/"+srcObject))
+	headers.Set("x-amz-copy-source", s3utils.EncodePath(copySourcePath(srcBucket, srcObject)))
 
 	if startOffset < 0 {
 		return p, ErrInvalidArgument("startOffset must be non-negative")
@@ -290,6 +291,7 @@ func (c Client) copyObjectPartDo(ctx context.Context, srcBucket, srcObject, dest
 		objectName:   destObject,
 		customHeader: headers,
 		queryValues:  queryValues,
+		errorIn200:   true,
 	})
 	defer closeResponse(resp)
 	if err != nil {
@@ -328,6 +330,7 @@ func (c Client) uploadPartCopy(ctx context.Context, bucket, object, uploadID str
 		objectName:   object,
 		customHeader: headers,
 		queryValues:  urlValues,
+		errorIn200:   true,
 	})
 	defer closeResponse(resp)
 	if err != nil {
@@ -355,15 +358,25 @@ func (c Client) uploadPartCopy(ctx context.Context, bucket, object, uploadID str
 // server-side copying operations. Optionally takes progress reader hook
 // for applications to look at current progress.
 func (c Client) ComposeObjectWithProgress(dst DestinationInfo, srcs []SourceInfo, progress io.Reader) error {
+	return c.composeObject(context.Background(), dst, srcs, progress)
+}
+
+func (c Client) composeObject(ctx context.Context, dst DestinationInfo, srcs []SourceInfo, progress io.Reader) (err error) {
 	if len(srcs) < 1 || len(srcs) > maxPartsCount {
 		return ErrInvalidArgument("There must be as least one and up to 10000 source objects.")
 	}
-	ctx := context.Background()
+	if err := c.validateBucketName(dst.bucket, false); err != nil {
+		return err
+	}
+	for _, src := range srcs {
+		if err := c.validateBucketName(src.bucket, false); err != nil {
+			return err
+		}
+	}
 	srcSizes := make([]int64, len(srcs))
 	var totalSize, size, totalParts int64
 	var srcUserMeta map[string]string
 	etags := make([]string, len(srcs))
-	var err error
 	for i, src := range srcs {
 		size, etags[i], srcUserMeta, err = src.getProps(c)
 		if err != nil {
@@ -419,10 +432,13 @@ func (c Client) ComposeObjectWithProgress(dst DestinationInfo, srcs []SourceInfo
 	// involved, it is being copied wholly and at most 5GiB in
 	// size, emptyfiles are also supported).
 	if (totalParts == 1 && srcs[0].start == -1 && totalSize <= maxPartSize) || (totalSize == 0) {
-		return c.CopyObjectWithProgress(dst, srcs[0], progress)
+		return c.copyObject(ctx, dst, srcs[0], progress)
 	}
 
 	// Now, handle multipart-copy cases.
+	if c.profile.noUploadPartCopy {
+		return ErrAPINotSupported("Composing objects from multiple parts is not supported by " + c.profile.name + ".")
+	}
 
 	// 1. Ensure that the object has not been changed while
 	//    we are copying data.
@@ -452,6 +468,14 @@ func (c Client) ComposeObjectWithProgress(dst DestinationInfo, srcs []SourceInfo
 		return err
 	}
 
+	// Abort the multipart upload if any part copy fails, to
+	// relinquish the storage of the parts already copied.
+	defer func() {
+		if err != nil {
+			c.cleanupMultipartUpload(dst.bucket, dst.object, uploadID)
+		}
+	}()
+
 	// 3. Perform copy part uploads
 	objParts := []CompletePart{}
 	partIndex := 1
@@ -492,7 +516,7 @@ func (c Client) ComposeObjectWithProgress(dst DestinationInfo, srcs []SourceInfo
 
 	// 4. Make final complete-multipart request.
 	_, err = c.completeMultipartUpload(ctx, dst.bucket, dst.object, uploadID,
-		completeMultipartUpload{Parts: objParts})
+		completeMultipartUpload{Parts: objParts}, PutObjectOptions{})
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,173 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/minio/minio-go/v6/pkg/encrypt"
+)
+
+// CopyPrefixOptions represents options specified by user for
+// CopyPrefix call.
+type CopyPrefixOptions struct {
+	// Number of objects copied concurrently, defaults to 4.
+	NumWorkers int
+
+	// Rename maps the name of a source object to the name of its
+	// copy. By default the source prefix of the name is replaced by
+	// the destination prefix.
+	Rename func(objectName string) string
+
+	// Server-side encryption of the source and of the copied
+	// objects.
+	SourceEncryption      encrypt.ServerSide
+	DestinationEncryption encrypt.ServerSide
+
+	// User metadata replacing the metadata of the copied objects,
+	// the metadata of the source objects is kept if empty.
+	UserMetadata map[string]string
+}
+
+// CopyPrefixResult - result of copying a single object of the prefix.
+type CopyPrefixResult struct {
+	// Name of the source object.
+	SourceObject string
+
+	// Name of the copied object in the destination bucket.
+	ObjectName string
+
+	// Size of the object.
+	Size int64
+
+	// Error is set if the object could not be listed or copied.
+	Err error
+}
+
+// CopyPrefix - copies all objects under srcPrefix of srcBucket to
+// dstBucket using server-side copies. Object names are mapped by
+// opts.Rename, or by replacing srcPrefix with dstPrefix. Objects are
+// copied concurrently and the result of each copy is sent on the
+// returned channel, which is closed once all the objects have been
+// processed.
+func (c Client) CopyPrefix(srcBucket, srcPrefix, dstBucket, dstPrefix string, opts CopyPrefixOptions) <-chan CopyPrefixResult {
+	return c.CopyPrefixWithContext(context.Background(), srcBucket, srcPrefix, dstBucket, dstPrefix, opts)
+}
+
+// CopyPrefixWithContext - Identical to CopyPrefix call, but accepts context to facilitate request cancellation.
+func (c Client) CopyPrefixWithContext(ctx context.Context, srcBucket, srcPrefix, dstBucket, dstPrefix string, opts CopyPrefixOptions) <-chan CopyPrefixResult {
+	resultCh := make(chan CopyPrefixResult, 1)
+
+	// Input validation.
+	if err := c.validateBucketName(srcBucket, false); err != nil {
+		defer close(resultCh)
+		resultCh <- CopyPrefixResult{Err: err}
+		return resultCh
+	}
+	if err := c.validateBucketName(dstBucket, false); err != nil {
+		defer close(resultCh)
+		resultCh <- CopyPrefixResult{Err: err}
+		return resultCh
+	}
+
+	rename := opts.Rename
+	if rename == nil {
+		rename = func(objectName string) string {
+			return dstPrefix + strings.TrimPrefix(objectName, srcPrefix)
+		}
+	}
+
+	numWorkers := opts.NumWorkers
+	if numWorkers <= 0 {
+		numWorkers = totalWorkers
+	}
+
+	// Results are dropped once ctx is done, so that the goroutines do
+	// not block when the caller stops receiving.
+	sendResult := func(result CopyPrefixResult) {
+		select {
+		case resultCh <- result:
+		case <-ctx.Done():
+		}
+	}
+
+	// List the objects and send them to be copied.
+	objectsCh := make(chan CopyPrefixResult)
+	go func() {
+		defer close(objectsCh)
+		doneCh := make(chan struct{})
+		defer close(doneCh)
+		for object := range c.ListObjectsV2(srcBucket, srcPrefix, true, doneCh) {
+			if object.Err != nil {
+				sendResult(CopyPrefixResult{Err: object.Err})
+				return
+			}
+			select {
+			case objectsCh <- CopyPrefixResult{
+				SourceObject: object.Key,
+				ObjectName:   rename(object.Key),
+				Size:         object.Size,
+			}:
+			case <-ctx.Done():
+				// Report the cancellation if the result fits in the
+				// buffer of the channel.
+				select {
+				case resultCh <- CopyPrefixResult{Err: ctx.Err()}:
+				default:
+				}
+				return
+			}
+		}
+	}()
+
+	// Copy the objects with a pool of workers.
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for object := range objectsCh {
+				object.Err = c.copyPrefixObject(ctx, srcBucket, dstBucket, object, opts)
+				sendResult(object)
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(resultCh)
+	}()
+
+	return resultCh
+}
+
+// copyPrefixObject - copies a single listed object, objects larger
+// than 5GiB are copied in parts.
+func (c Client) copyPrefixObject(ctx context.Context, srcBucket, dstBucket string, object CopyPrefixResult, opts CopyPrefixOptions) error {
+	dst, err := NewDestinationInfo(dstBucket, object.ObjectName, opts.DestinationEncryption, opts.UserMetadata)
+	if err != nil {
+		return err
+	}
+	src := NewSourceInfo(srcBucket, object.SourceObject, opts.SourceEncryption)
+	if object.Size <= maxPartSize {
+		return c.copyObject(ctx, dst, src, nil)
+	}
+	return c.composeObject(ctx, dst, []SourceInfo{src}, nil)
+}
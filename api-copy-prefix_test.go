@@ -0,0 +1,101 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Tests copying the objects of a prefix with the default and a custom
+// renaming of the objects.
+func TestCopyPrefix(t *testing.T) {
+	var mutex sync.Mutex
+	var copies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprint(w, "<ListBucketResult><IsTruncated>false</IsTruncated>")
+			for _, key := range []string{"src/a", "src/b/c", "src/denied"} {
+				if strings.HasPrefix(key, r.URL.Query().Get("prefix")) {
+					fmt.Fprintf(w, "<Contents><Key>%s</Key><Size>1</Size></Contents>", key)
+				}
+			}
+			fmt.Fprint(w, "</ListBucketResult>")
+		case http.MethodPut:
+			source := r.Header.Get("X-Amz-Copy-Source")
+			if strings.HasSuffix(source, "denied") {
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, "<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>")
+				return
+			}
+			mutex.Lock()
+			copies = append(copies, source+" "+r.URL.Path)
+			mutex.Unlock()
+			fmt.Fprint(w, "<CopyObjectResult><ETag>\"etag\"</ETag></CopyObjectResult>")
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		opts     CopyPrefixOptions
+		results  []string
+		expected []string
+	}{
+		{
+			CopyPrefixOptions{},
+			[]string{"src/a dst/a", "src/b/c dst/b/c", "src/denied dst/denied AccessDenied"},
+			[]string{"src/src/a /dst/dst/a", "src/src/b/c /dst/dst/b/c"},
+		},
+		{
+			CopyPrefixOptions{NumWorkers: 1, Rename: strings.ToUpper},
+			[]string{"src/a SRC/A", "src/b/c SRC/B/C", "src/denied SRC/DENIED AccessDenied"},
+			[]string{"src/src/a /dst/SRC/A", "src/src/b/c /dst/SRC/B/C"},
+		},
+	}
+	for i, testCase := range testCases {
+		copies = nil
+		var results []string
+		for result := range c.CopyPrefix("src", "src/", "dst", "dst/", testCase.opts) {
+			line := result.SourceObject + " " + result.ObjectName
+			if result.Err != nil {
+				line += " " + ToErrorResponse(result.Err).Code
+			}
+			results = append(results, line)
+		}
+		sort.Strings(results)
+		if !reflect.DeepEqual(results, testCase.results) {
+			t.Errorf("Test %d: Expected results %q, got %q", i+1, testCase.results, results)
+		}
+		sort.Strings(copies)
+		if !reflect.DeepEqual(copies, testCase.expected) {
+			t.Errorf("Test %d: Expected copies %q, got %q", i+1, testCase.expected, copies)
+		}
+	}
+}
@@ -19,6 +19,7 @@ package minio
 
 import (
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -28,6 +29,22 @@ type BucketInfo struct {
 	Name string `json:"name"`
 	// Date the bucket was created.
 	CreationDate time.Time `json:"creationDate"`
+	// Region of the bucket, only set by ListBucketsWithOptions when
+	// requested or if the server sends it along.
+	Region string `json:"region,omitempty" xml:"BucketRegion"`
+}
+
+// UserMetadata - user defined metadata of an object, the keys are
+// stored in canonical header form without the x-amz-meta- prefix.
+type UserMetadata map[string]string
+
+// Get - returns the value of a user metadata key, the key is matched
+// case-insensitively with or without the x-amz-meta- prefix.
+func (m UserMetadata) Get(key string) string {
+	if strings.HasPrefix(strings.ToLower(key), "x-amz-meta-") {
+		key = key[len("x-amz-meta-"):]
+	}
+	return m[http.CanonicalHeaderKey(key)]
 }
 
 // ObjectInfo container for object metadata.
@@ -47,6 +64,9 @@ type ObjectInfo struct {
 	// eg: x-amz-meta-*, content-encoding etc.
 	Metadata http.Header `json:"metadata" xml:"-"`
 
+	// User defined metadata on the object, set as x-amz-meta-* headers.
+	UserMetadata UserMetadata `json:"userMetadata,omitempty" xml:"-"`
+
 	// Owner name.
 	Owner struct {
 		DisplayName string `json:"name"`
@@ -56,6 +76,32 @@ type ObjectInfo struct {
 	// The class of storage used to store the object.
 	StorageClass string `json:"storageClass"`
 
+	// Number of parts of a multipart object, set by StatObject and
+	// GetObject when a part is requested with PartNumber.
+	PartsCount int `json:"partsCount,omitempty" xml:"-"`
+
+	// Additional checksums of the object, or of the requested part,
+	// set by StatObject and GetObject with VerifyChecksum. Checksums
+	// of multipart objects are of the form '<checksum>-<parts>'.
+	ChecksumCRC32  string `json:"checksumCRC32,omitempty" xml:"-"`
+	ChecksumCRC32C string `json:"checksumCRC32C,omitempty" xml:"-"`
+	ChecksumSHA1   string `json:"checksumSHA1,omitempty" xml:"-"`
+	ChecksumSHA256 string `json:"checksumSHA256,omitempty" xml:"-"`
+
+	// Server-side encryption of the object, 'AES256' for SSE-S3 and
+	// 'aws:kms' for SSE-KMS, and the id of the KMS key and the
+	// encryption context of objects encrypted with SSE-KMS, and
+	// whether they are encrypted with an S3 Bucket Key, set by
+	// StatObject and GetObject.
+	ServerSideEncryption    string            `json:"serverSideEncryption,omitempty" xml:"-"`
+	SSEKMSKeyID             string            `json:"sseKMSKeyID,omitempty" xml:"-"`
+	SSEKMSEncryptionContext map[string]string `json:"sseKMSEncryptionContext,omitempty" xml:"-"`
+	SSEKMSBucketKeyEnabled  bool              `json:"sseKMSBucketKeyEnabled,omitempty" xml:"-"`
+
+	// Set for the common prefixes of a listing which is not
+	// recursive, pseudo-directories named by Key.
+	IsPrefix bool `json:"isPrefix,omitempty" xml:"-"`
+
 	// Error
 	Err error `json:"-"`
 }
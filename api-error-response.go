@@ -18,9 +18,13 @@
 package minio
 
 import (
+	"bytes"
 	"encoding/xml"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+
+	"github.com/minio/minio-go/v6/pkg/s3utils"
 )
 
 /* **** SAMPLE ERROR RESPONSE ****
@@ -51,8 +55,16 @@ type ErrorResponse struct {
 	// only in HEAD bucket and ListObjects response.
 	Region string
 
+	// Endpoint to address the bucket at, returned in PermanentRedirect
+	// and TemporaryRedirect errors.
+	Endpoint string
+
 	// Underlying HTTP status code for the returned error
 	StatusCode int `xml:"-" json:"-"`
+
+	// Number of attempts made for the request, including all
+	// retries, before this error was returned.
+	Attempts int `xml:"-" json:"-"`
 }
 
 // ToErrorResponse - Returns parsed ErrorResponse struct from body and
@@ -89,6 +101,65 @@ func (e ErrorResponse) Error() string {
 	return e.Message
 }
 
+// IsNotFound - returns true if the error indicates that the bucket,
+// object, upload or version addressed by the request does not exist.
+// This is true for errors decoded from an XML error body as well as
+// for body-less responses such as a failed HEAD request.
+func IsNotFound(err error) bool {
+	errResp := ToErrorResponse(err)
+	switch errResp.Code {
+	case "NoSuchBucket", "NoSuchKey", "NoSuchUpload", "NoSuchVersion":
+		return true
+	}
+	return errResp.Code != "" && errResp.StatusCode == http.StatusNotFound
+}
+
+// IsAccessDenied - returns true if the error indicates that access
+// to the bucket or object was denied by the server.
+func IsAccessDenied(err error) bool {
+	errResp := ToErrorResponse(err)
+	switch errResp.Code {
+	case "AccessDenied", "AllAccessDisabled":
+		return true
+	}
+	return false
+}
+
+// IsBucketNotEmpty - returns true if the error indicates that the
+// bucket could not be removed because it still has objects in it.
+func IsBucketNotEmpty(err error) bool {
+	errResp := ToErrorResponse(err)
+	switch errResp.Code {
+	case "BucketNotEmpty":
+		return true
+	case "Conflict":
+		// Body-less response to a DELETE bucket request.
+		return errResp.StatusCode == http.StatusConflict
+	}
+	return false
+}
+
+// IsNotModified - returns true if the error indicates that a conditional
+// GET or HEAD request was not served because the object was not
+// modified, i.e. the server replied with 304 Not Modified.
+func IsNotModified(err error) bool {
+	return ToErrorResponse(err).Code == "NotModified"
+}
+
+// IsPreconditionFailed - returns true if the error indicates that one
+// of the conditions specified in the request did not hold, i.e. the
+// server replied with 412 Precondition Failed.
+func IsPreconditionFailed(err error) bool {
+	return ToErrorResponse(err).Code == "PreconditionFailed"
+}
+
+// IsInvalidWriteOffset - returns true if the error indicates that an
+// append was rejected because the write offset is not the current size
+// of the object.
+func IsInvalidWriteOffset(err error) bool {
+	return ToErrorResponse(err).Code == "InvalidWriteOffset"
+}
+
 // Common string for errors to report issue location in unexpected
 // cases.
 const (
@@ -143,14 +214,16 @@ func httpRespToErrorResponse(resp *http.Response, bucketName, objectName string)
 				Message:    "Bucket not empty.",
 				BucketName: bucketName,
 			}
-		case http.StatusPreconditionFailed:
+		case http.StatusNotModified:
 			errResp = ErrorResponse{
 				StatusCode: resp.StatusCode,
-				Code:       "PreconditionFailed",
-				Message:    s3ErrorResponseMap["PreconditionFailed"],
+				Code:       "NotModified",
+				Message:    s3ErrorResponseMap["NotModified"],
 				BucketName: bucketName,
 				Key:        objectName,
 			}
+		case http.StatusPreconditionFailed:
+			errResp = ErrPreconditionFailed(bucketName, objectName).(ErrorResponse)
 		default:
 			errResp = ErrorResponse{
 				StatusCode: resp.StatusCode,
@@ -172,6 +245,7 @@ func httpRespToErrorResponse(resp *http.Response, bucketName, objectName string)
 	if errResp.Region == "" {
 		errResp.Region = resp.Header.Get("x-amz-bucket-region")
 	}
+	errResp.Attempts = retryAttempt(resp.Request)
 	if errResp.Code == "InvalidRegion" && errResp.Region != "" {
 		errResp.Message = fmt.Sprintf("Region does not match, expecting region ‘%s’.", errResp.Region)
 	}
@@ -179,6 +253,43 @@ func httpRespToErrorResponse(resp *http.Response, bucketName, objectName string)
 	return errResp
 }
 
+// errorIn200Response - returns the error of a 200 OK response of an
+// operation which may fail after the response status was sent, S3 then
+// sends an error document as response body for CopyObject,
+// UploadPartCopy and CompleteMultipartUpload. The body of resp is
+// restored for the caller.
+func errorIn200Response(resp *http.Response, bucketName, objectName string) (errResp ErrorResponse, found bool, err error) {
+	body, err := ioutil.ReadAll(resp.Body)
+	closeResponse(resp)
+	if err != nil {
+		return ErrorResponse{}, false, err
+	}
+	defer func() {
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}()
+	if !isErrorDocument(body) {
+		return ErrorResponse{}, false, nil
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return ToErrorResponse(httpRespToErrorResponse(resp, bucketName, objectName)), true, nil
+}
+
+// isErrorDocument - returns true if the root element of the XML
+// document body is an Error element. S3 sends whitespace to keep the
+// connection alive until the result is known, which is skipped.
+func isErrorDocument(body []byte) bool {
+	d := xml.NewDecoder(bytes.NewReader(body))
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return false
+		}
+		if t, ok := token.(xml.StartElement); ok {
+			return t.Name.Local == "Error"
+		}
+	}
+}
+
 // ErrTransferAccelerationBucket - bucket name is invalid to be used with transfer acceleration.
 func ErrTransferAccelerationBucket(bucketName string) error {
 	return ErrorResponse{
@@ -189,6 +300,56 @@ func ErrTransferAccelerationBucket(bucketName string) error {
 	}
 }
 
+// ErrCrossPartitionBucket - bucket is located in a region of another
+// AWS partition than the endpoint, e.g. a China region with a global
+// endpoint, which cannot be accessed with the same credentials.
+func ErrCrossPartitionBucket(bucketName, region, partition string) error {
+	return ErrorResponse{
+		StatusCode: http.StatusBadRequest,
+		Code:       "InvalidRegion",
+		Message:    fmt.Sprintf("Region ‘%s’ is not part of the ‘%s’ partition of the endpoint, use an endpoint of the ‘%s’ partition.", region, partition, s3utils.GetPartition(region)),
+		BucketName: bucketName,
+		Region:     region,
+	}
+}
+
+// ErrPreconditionFailed - At least one of the conditions specified in
+// the request did not hold, e.g. the object already exists for a
+// PutObject with CreateOnly set.
+func ErrPreconditionFailed(bucketName, objectName string) error {
+	return ErrorResponse{
+		StatusCode: http.StatusPreconditionFailed,
+		Code:       "PreconditionFailed",
+		Message:    s3ErrorResponseMap["PreconditionFailed"],
+		BucketName: bucketName,
+		Key:        objectName,
+	}
+}
+
+// ErrObjectCorrupted - Downloaded data does not match the checksum of the object.
+func ErrObjectCorrupted(bucketName, objectName, expected, actual string) error {
+	msg := fmt.Sprintf("Data read has checksum ‘%s’ which does not match the checksum ‘%s’ of the object.", actual, expected)
+	return ErrorResponse{
+		StatusCode: http.StatusBadRequest,
+		Code:       "ObjectCorrupted",
+		Message:    msg,
+		BucketName: bucketName,
+		Key:        objectName,
+	}
+}
+
+// ErrCopyMismatch - Copy of an object does not match the source object.
+func ErrCopyMismatch(bucketName, objectName, source string) error {
+	msg := fmt.Sprintf("The copy does not match the source object ‘%s’.", source)
+	return ErrorResponse{
+		StatusCode: http.StatusConflict,
+		Code:       "CopyMismatch",
+		Message:    msg,
+		BucketName: bucketName,
+		Key:        objectName,
+	}
+}
+
 // ErrEntityTooLarge - Input size is larger than supported maximum.
 func ErrEntityTooLarge(totalSize, maxObjectSize int64, bucketName, objectName string) error {
 	msg := fmt.Sprintf("Your proposed upload size ‘%d’ exceeds the maximum allowed object size ‘%d’ for single PUT operation.", totalSize, maxObjectSize)
@@ -23,8 +23,10 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -301,3 +303,150 @@ func TestErrorResponseComparable(t *testing.T) {
 		t.Fatalf("ErrorResponse should be comparable")
 	}
 }
+
+// Tests if the attempt number saved on the request is reported
+// on the error response.
+func TestErrorResponseAttempts(t *testing.T) {
+	req, err := http.NewRequest("GET", "http://localhost:9000/bucket/object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := &http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Status:     "503 Service Unavailable",
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+		Request:    req.WithContext(withRetryAttempt(req.Context(), 3)),
+	}
+	resp.Header.Set("x-amz-request-id", "xyz")
+	resp.Header.Set("x-amz-id-2", "abc")
+	errResp := ToErrorResponse(httpRespToErrorResponse(resp, "bucket", "object"))
+	if errResp.Attempts != 3 {
+		t.Fatalf("Expected 3 attempts, got %d", errResp.Attempts)
+	}
+	if errResp.RequestID != "xyz" || errResp.HostID != "abc" {
+		t.Fatalf("Expected request id and host id to be set, got %#v", errResp)
+	}
+}
+
+// Tests the error classification helpers.
+func TestErrorPredicates(t *testing.T) {
+	genEmptyBodyResponse := func(statusCode int) *http.Response {
+		return &http.Response{
+			StatusCode: statusCode,
+			Status:     http.StatusText(statusCode),
+			Header:     make(http.Header),
+			Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+		}
+	}
+	testCases := []struct {
+		err            error
+		notFound       bool
+		accessDenied   bool
+		bucketNotEmpty bool
+	}{
+		{httpRespToErrorResponse(genEmptyBodyResponse(http.StatusNotFound), "bucket", ""), true, false, false},
+		{httpRespToErrorResponse(genEmptyBodyResponse(http.StatusNotFound), "bucket", "object"), true, false, false},
+		{httpRespToErrorResponse(genEmptyBodyResponse(http.StatusForbidden), "bucket", "object"), false, true, false},
+		{httpRespToErrorResponse(genEmptyBodyResponse(http.StatusConflict), "bucket", ""), false, false, true},
+		{ErrorResponse{Code: "NoSuchUpload", StatusCode: http.StatusNotFound}, true, false, false},
+		{ErrorResponse{Code: "NoSuchBucketPolicy", StatusCode: http.StatusNotFound}, true, false, false},
+		{ErrorResponse{Code: "AllAccessDisabled", StatusCode: http.StatusForbidden}, false, true, false},
+		{ErrorResponse{Code: "BucketNotEmpty", StatusCode: http.StatusConflict}, false, false, true},
+		{ErrorResponse{Code: "BucketAlreadyOwnedByYou", StatusCode: http.StatusConflict}, false, false, false},
+		{ErrInvalidArgument("invalid"), false, false, false},
+		{fmt.Errorf("some error"), false, false, false},
+		{nil, false, false, false},
+	}
+	for i, testCase := range testCases {
+		if IsNotFound(testCase.err) != testCase.notFound {
+			t.Errorf("Test %d: expected IsNotFound to be %v for %#v", i+1, testCase.notFound, testCase.err)
+		}
+		if IsAccessDenied(testCase.err) != testCase.accessDenied {
+			t.Errorf("Test %d: expected IsAccessDenied to be %v for %#v", i+1, testCase.accessDenied, testCase.err)
+		}
+		if IsBucketNotEmpty(testCase.err) != testCase.bucketNotEmpty {
+			t.Errorf("Test %d: expected IsBucketNotEmpty to be %v for %#v", i+1, testCase.bucketNotEmpty, testCase.err)
+		}
+	}
+}
+
+// Tests mapping of conditional request failures.
+func TestConditionalErrors(t *testing.T) {
+	for i, statusCode := range []int{http.StatusNotModified, http.StatusPreconditionFailed} {
+		resp := &http.Response{
+			StatusCode: statusCode,
+			Status:     http.StatusText(statusCode),
+			Header:     make(http.Header),
+			Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+		}
+		err := httpRespToErrorResponse(resp, "bucket", "object")
+		if IsNotModified(err) != (statusCode == http.StatusNotModified) {
+			t.Errorf("Test %d: unexpected IsNotModified result for %#v", i+1, err)
+		}
+		if IsPreconditionFailed(err) != (statusCode == http.StatusPreconditionFailed) {
+			t.Errorf("Test %d: unexpected IsPreconditionFailed result for %#v", i+1, err)
+		}
+		if errResp := ToErrorResponse(err); errResp.Key != "object" || errResp.StatusCode != statusCode {
+			t.Errorf("Test %d: unexpected error response %#v", i+1, errResp)
+		}
+	}
+}
+
+// Tests that error documents sent with 200 OK by copy and complete
+// multipart upload operations are returned as errors.
+func TestErrorIn200(t *testing.T) {
+	const copyResult = `<CopyObjectResult><ETag>"etag"</ETag></CopyObjectResult>`
+	const completeResult = `<CompleteMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><ETag>"etag"</ETag></CompleteMultipartUploadResult>`
+	errorDocument := func(code string) string {
+		return "\n  \n<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<Error><Code>" + code + "</Code><Message>message</Message><RequestId>id</RequestId></Error>"
+	}
+
+	testCases := []struct {
+		responses []string
+		code      string
+		requests  int
+	}{
+		{[]string{copyResult}, "", 1},
+		{[]string{errorDocument("AccessDenied")}, "AccessDenied", 1},
+		{[]string{errorDocument("InternalError"), copyResult}, "", 2},
+	}
+	for i, testCase := range testCases {
+		var requests int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			response := testCase.responses[requests]
+			requests++
+			if r.Method == "POST" {
+				response = strings.Replace(response, copyResult, completeResult, 1)
+			}
+			fmt.Fprint(w, response)
+		}))
+		c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		core := Core{c}
+
+		_, err = core.CopyObject("source", "object", "bucket", "object", nil)
+		if code := ToErrorResponse(err).Code; code != testCase.code || (code == "") != (err == nil) {
+			t.Errorf("Test %d: CopyObject expected code %q, got %v", i+1, testCase.code, err)
+		}
+		if requests != testCase.requests {
+			t.Errorf("Test %d: CopyObject expected %d requests, got %d", i+1, testCase.requests, requests)
+		}
+
+		requests = 0
+		_, err = core.CompleteMultipartUpload("bucket", "object", "upload", []CompletePart{{PartNumber: 1, ETag: "etag"}})
+		if code := ToErrorResponse(err).Code; code != testCase.code || (code == "") != (err == nil) {
+			t.Errorf("Test %d: CompleteMultipartUpload expected code %q, got %v", i+1, testCase.code, err)
+		}
+		if requests != testCase.requests {
+			t.Errorf("Test %d: CompleteMultipartUpload expected %d requests, got %d", i+1, testCase.requests, requests)
+		}
+		server.Close()
+	}
+
+	if isErrorDocument([]byte(copyResult)) || isErrorDocument(nil) || !isErrorDocument([]byte(errorDocument("SlowDown"))) {
+		t.Error("unexpected error document detection")
+	}
+}
@@ -0,0 +1,198 @@
+// +build go1.16
+
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+)
+
+// bucketFS - read-only fs.FS over the objects under a prefix.
+type bucketFS struct {
+	ctx        context.Context
+	c          Client
+	bucketName string
+	prefix     string
+}
+
+// FS - returns a read-only fs.FS over the objects of the bucket under
+// prefix, a non-empty prefix is treated as a directory. Files are
+// objects while directories are the common prefixes of the object
+// names split at '/'. Files implement io.Seeker and io.ReaderAt, and
+// directories implement fs.ReadDirFile.
+func (c Client) FS(bucketName, prefix string) fs.FS {
+	return c.FSWithContext(context.Background(), bucketName, prefix)
+}
+
+// FSWithContext - Identical to FS call, but accepts context used by all requests.
+func (c Client) FSWithContext(ctx context.Context, bucketName, prefix string) fs.FS {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return &bucketFS{
+		ctx:        ctx,
+		c:          c,
+		bucketName: bucketName,
+		prefix:     prefix,
+	}
+}
+
+// Open - opens the named file or directory.
+func (b *bucketFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return b.openDir(name, b.prefix), nil
+	}
+
+	objectName := b.prefix + name
+	objInfo, err := b.c.statObject(b.ctx, b.bucketName, objectName, StatObjectOptions{})
+	if err == nil {
+		obj, err := b.c.getObjectWithContext(b.ctx, b.bucketName, objectName, GetObjectOptions{})
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+		return &fsFile{Object: obj, info: newFSFileInfo(name, objInfo)}, nil
+	}
+	if !IsNotFound(err) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	// Not an object, verify if it is a common prefix.
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	for object := range b.c.ListObjectsV2(b.bucketName, objectName+"/", false, doneCh) {
+		if object.Err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: object.Err}
+		}
+		return b.openDir(name, objectName+"/"), nil
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func (b *bucketFS) openDir(name, prefix string) *fsDir {
+	return &fsDir{
+		fs:     b,
+		prefix: prefix,
+		info:   fsFileInfo{name: path.Base(name), mode: fs.ModeDir | 0555},
+	}
+}
+
+// fsFileInfo - fs.FileInfo of an object or a common prefix.
+type fsFileInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+	sys     interface{}
+}
+
+func newFSFileInfo(name string, objInfo ObjectInfo) fsFileInfo {
+	return fsFileInfo{
+		name:    path.Base(name),
+		size:    objInfo.Size,
+		mode:    0444,
+		modTime: objInfo.LastModified,
+		sys:     objInfo,
+	}
+}
+
+func (i fsFileInfo) Name() string               { return i.name }
+func (i fsFileInfo) Size() int64                { return i.size }
+func (i fsFileInfo) Mode() fs.FileMode          { return i.mode }
+func (i fsFileInfo) ModTime() time.Time         { return i.modTime }
+func (i fsFileInfo) IsDir() bool                { return i.mode.IsDir() }
+func (i fsFileInfo) Sys() interface{}           { return i.sys }
+func (i fsFileInfo) Type() fs.FileMode          { return i.mode.Type() }
+func (i fsFileInfo) Info() (fs.FileInfo, error) { return i, nil }
+
+// fsFile - an open object.
+type fsFile struct {
+	*Object
+	info fsFileInfo
+}
+
+// Stat - returns the information of the object.
+func (f *fsFile) Stat() (fs.FileInfo, error) {
+	return f.info, nil
+}
+
+// fsDir - an open common prefix, entries are listed on the first call
+// to ReadDir.
+type fsDir struct {
+	fs      *bucketFS
+	prefix  string
+	info    fsFileInfo
+	entries []fs.DirEntry
+	listed  bool
+	offset  int
+}
+
+func (d *fsDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+
+func (d *fsDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+func (d *fsDir) Close() error { return nil }
+
+// ReadDir - reads the entries of the directory in lexical order.
+func (d *fsDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	if !d.listed {
+		doneCh := make(chan struct{})
+		defer close(doneCh)
+		for object := range d.fs.c.ListObjectsV2(d.fs.bucketName, d.prefix, false, doneCh) {
+			if object.Err != nil {
+				return nil, &fs.PathError{Op: "readdir", Path: d.info.name, Err: object.Err}
+			}
+			name := strings.TrimPrefix(object.Key, d.prefix)
+			if strings.HasSuffix(name, "/") {
+				name = strings.TrimSuffix(name, "/")
+				if name == "" || strings.Contains(name, "/") {
+					continue
+				}
+				d.entries = append(d.entries, fsFileInfo{name: name, mode: fs.ModeDir | 0555})
+				continue
+			}
+			if name == "" {
+				continue
+			}
+			d.entries = append(d.entries, newFSFileInfo(name, object))
+		}
+		d.listed = true
+	}
+
+	entries := d.entries[d.offset:]
+	if n > 0 {
+		if len(entries) == 0 {
+			return nil, io.EOF
+		}
+		if n < len(entries) {
+			entries = entries[:n]
+		}
+	}
+	d.offset += len(entries)
+	return entries, nil
+}
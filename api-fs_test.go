@@ -0,0 +1,92 @@
+// +build go1.16
+
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"testing"
+	"time"
+)
+
+// Tests that invalid names are rejected before any request is made.
+func TestFSOpenInvalidPath(t *testing.T) {
+	fsys := Client{}.FS("bucket", "prefix")
+	for i, name := range []string{"", "/object", "a/../b", "./a", "a/", "a//b"} {
+		_, err := fsys.Open(name)
+		if !errors.Is(err, fs.ErrInvalid) {
+			t.Errorf("Test %d: expected fs.ErrInvalid for %q, got %v", i+1, name, err)
+		}
+	}
+	if fsys.(*bucketFS).prefix != "prefix/" {
+		t.Errorf("expected prefix to be treated as a directory, got %q", fsys.(*bucketFS).prefix)
+	}
+}
+
+// Tests the fs.FileInfo and fs.DirEntry values of objects and prefixes.
+func TestFSFileInfo(t *testing.T) {
+	modTime := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	info := newFSFileInfo("dir/file.txt", ObjectInfo{Key: "prefix/dir/file.txt", Size: 10, LastModified: modTime})
+	if info.Name() != "file.txt" || info.Size() != 10 || !info.ModTime().Equal(modTime) {
+		t.Errorf("unexpected file info %+v", info)
+	}
+	if info.IsDir() || info.Mode() != 0444 || info.Type() != 0 {
+		t.Errorf("expected a regular read-only file, got mode %v", info.Mode())
+	}
+	if objInfo, ok := info.Sys().(ObjectInfo); !ok || objInfo.Key != "prefix/dir/file.txt" {
+		t.Errorf("expected ObjectInfo from Sys, got %v", info.Sys())
+	}
+
+	dir := Client{}.FS("bucket", "").(*bucketFS).openDir(".", "")
+	st, err := dir.Stat()
+	if err != nil || !st.IsDir() || st.Mode() != fs.ModeDir|0555 || st.Name() != "." {
+		t.Errorf("unexpected directory info %v, %v", st, err)
+	}
+	if _, err = dir.Read(make([]byte, 1)); !errors.Is(err, fs.ErrInvalid) {
+		t.Errorf("expected fs.ErrInvalid reading a directory, got %v", err)
+	}
+}
+
+// Tests paging through directory entries with ReadDir.
+func TestFSReadDir(t *testing.T) {
+	dir := &fsDir{
+		listed: true,
+		entries: []fs.DirEntry{
+			fsFileInfo{name: "a", mode: fs.ModeDir | 0555},
+			fsFileInfo{name: "b", mode: 0444},
+			fsFileInfo{name: "c", mode: 0444},
+		},
+	}
+	entries, err := dir.ReadDir(2)
+	if err != nil || len(entries) != 2 || entries[0].Name() != "a" || !entries[0].IsDir() {
+		t.Fatalf("unexpected first page %v, %v", entries, err)
+	}
+	entries, err = dir.ReadDir(2)
+	if err != nil || len(entries) != 1 || entries[0].Name() != "c" {
+		t.Fatalf("unexpected second page %v, %v", entries, err)
+	}
+	if _, err = dir.ReadDir(2); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+	if entries, err = dir.ReadDir(-1); err != nil || len(entries) != 0 {
+		t.Fatalf("expected no remaining entries, got %v, %v", entries, err)
+	}
+}
@@ -22,14 +22,12 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
-
-	"github.com/minio/minio-go/v6/pkg/s3utils"
 )
 
 // GetBucketLifecycle - get bucket lifecycle.
 func (c Client) GetBucketLifecycle(bucketName string) (string, error) {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return "", err
 	}
 	bucketLifecycle, err := c.getBucketLifecycle(bucketName)
@@ -0,0 +1,204 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v6/pkg/encrypt"
+)
+
+// GetObjectAttributesOptions represents options specified by user for
+// GetObjectAttributes call.
+type GetObjectAttributesOptions struct {
+	// Version of the object, the latest version if empty.
+	VersionID string
+
+	// Encryption of objects encrypted with SSE-C.
+	ServerSideEncryption encrypt.ServerSide
+}
+
+// ObjectChecksums - additional checksums of an object or of a part.
+type ObjectChecksums struct {
+	ChecksumCRC32  string `xml:"ChecksumCRC32,omitempty"`
+	ChecksumCRC32C string `xml:"ChecksumCRC32C,omitempty"`
+	ChecksumSHA1   string `xml:"ChecksumSHA1,omitempty"`
+	ChecksumSHA256 string `xml:"ChecksumSHA256,omitempty"`
+}
+
+// ObjectAttributesPart - size and checksums of a part of an object.
+type ObjectAttributesPart struct {
+	ObjectChecksums
+	PartNumber int
+	Size       int64
+}
+
+// ObjectAttributes - attributes of an object returned by
+// GetObjectAttributes, the parts are only set for multipart objects
+// uploaded with additional checksums.
+type ObjectAttributes struct {
+	ETag         string
+	LastModified time.Time
+	VersionID    string
+	StorageClass string
+	ObjectSize   int64
+	Checksum     ObjectChecksums
+
+	// Number of parts of a multipart object and, if it was uploaded
+	// with additional checksums, their sizes and checksums ordered by
+	// part number.
+	PartsCount int
+	Parts      []ObjectAttributesPart
+}
+
+// objectAttributesResponse - GetObjectAttributesResponse of a page of
+// the parts of an object.
+type objectAttributesResponse struct {
+	XMLName      xml.Name `xml:"GetObjectAttributesResponse"`
+	ETag         string
+	Checksum     ObjectChecksums
+	StorageClass string
+	ObjectSize   int64
+	ObjectParts  struct {
+		IsTruncated          bool
+		NextPartNumberMarker int
+		PartsCount           int
+		Parts                []ObjectAttributesPart `xml:"Part"`
+	}
+}
+
+// amzObjectAttributes - attributes requested by GetObjectAttributes.
+const amzObjectAttributes = "ETag,Checksum,ObjectParts,StorageClass,ObjectSize"
+
+// GetObjectAttributes - returns the ETag, size, storage class and
+// checksums of an object, and the number of parts of objects uploaded
+// in parts. The sizes and checksums of the parts are only returned by
+// Amazon S3 for objects uploaded with additional checksums, only the
+// number of parts is set for other multipart objects.
+func (c Client) GetObjectAttributes(bucketName, objectName string, opts GetObjectAttributesOptions) (ObjectAttributes, error) {
+	return c.GetObjectAttributesWithContext(context.Background(), bucketName, objectName, opts)
+}
+
+// GetObjectAttributesWithContext - Identical to GetObjectAttributes call, but accepts context to facilitate request cancellation.
+func (c Client) GetObjectAttributesWithContext(ctx context.Context, bucketName, objectName string, opts GetObjectAttributesOptions) (ObjectAttributes, error) {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return ObjectAttributes{}, err
+	}
+	if err := ValidateObjectKey(objectName); err != nil {
+		return ObjectAttributes{}, err
+	}
+
+	var attributes ObjectAttributes
+	partNumberMarker := 0
+	for {
+		urlValues := make(url.Values)
+		urlValues.Set("attributes", "")
+		if opts.VersionID != "" {
+			urlValues.Set("versionId", opts.VersionID)
+		}
+		headers := make(http.Header)
+		headers.Set("X-Amz-Object-Attributes", amzObjectAttributes)
+		headers.Set("X-Amz-Max-Parts", "1000")
+		if partNumberMarker > 0 {
+			headers.Set("X-Amz-Part-Number-Marker", strconv.Itoa(partNumberMarker))
+		}
+		if opts.ServerSideEncryption != nil && opts.ServerSideEncryption.Type() == encrypt.SSEC {
+			opts.ServerSideEncryption.Marshal(headers)
+		}
+
+		resp, err := c.executeMethod(ctx, "GET", requestMetadata{
+			bucketName:       bucketName,
+			objectName:       objectName,
+			queryValues:      urlValues,
+			customHeader:     headers,
+			contentSHA256Hex: emptySHA256Hex,
+		})
+		if err != nil {
+			return ObjectAttributes{}, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			err = httpRespToErrorResponse(resp, bucketName, objectName)
+			closeResponse(resp)
+			return ObjectAttributes{}, err
+		}
+		var page objectAttributesResponse
+		err = xmlDecoder(resp.Body, &page)
+		closeResponse(resp)
+		if err != nil {
+			return ObjectAttributes{}, err
+		}
+
+		if partNumberMarker == 0 {
+			attributes = ObjectAttributes{
+				ETag:         strings.Trim(page.ETag, "\""),
+				VersionID:    resp.Header.Get("X-Amz-Version-Id"),
+				StorageClass: page.StorageClass,
+				ObjectSize:   page.ObjectSize,
+				Checksum:     page.Checksum,
+				PartsCount:   page.ObjectParts.PartsCount,
+			}
+			attributes.LastModified, _ = time.Parse(http.TimeFormat, resp.Header.Get("Last-Modified"))
+		}
+		attributes.Parts = append(attributes.Parts, page.ObjectParts.Parts...)
+
+		// Pages of parts end if the response is not truncated.
+		if !page.ObjectParts.IsTruncated || page.ObjectParts.NextPartNumberMarker <= partNumberMarker {
+			return attributes, nil
+		}
+		partNumberMarker = page.ObjectParts.NextPartNumberMarker
+	}
+}
+
+// MultipartETag - computes the ETag of an object uploaded in parts of
+// the given sizes from its data, e.g. the part size of the uploader or
+// the sizes of the parts returned by GetObjectAttributes for objects
+// uploaded with additional checksums, to audit objects which are not
+// encrypted with SSE-C or SSE-KMS against their ETag. Data left after
+// the last part is an error.
+func MultipartETag(reader io.Reader, partSizes []int64) (string, error) {
+	if len(partSizes) == 0 {
+		return "", ErrInvalidArgument("Part sizes cannot be empty.")
+	}
+	sums := md5.New()
+	for i, size := range partSizes {
+		sum := md5.New()
+		n, err := io.CopyN(sum, reader, size)
+		if err != nil && err != io.EOF {
+			return "", err
+		}
+		if n != size {
+			return "", ErrInvalidArgument(fmt.Sprintf("Data ends in part %d, %d bytes short of its size.", i+1, size-n))
+		}
+		sums.Write(sum.Sum(nil))
+	}
+	if n, _ := io.ReadFull(reader, make([]byte, 1)); n > 0 {
+		return "", ErrInvalidArgument("Data continues after the last part.")
+	}
+	return fmt.Sprintf("%s-%d", hex.EncodeToString(sums.Sum(nil)), len(partSizes)), nil
+}
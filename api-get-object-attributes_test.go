@@ -0,0 +1,103 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"bytes"
+	"crypto/md5"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Tests the attributes of multipart objects are read across pages of
+// parts and their ETag recomputed from the data.
+func TestGetObjectAttributes(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 25)
+	partSizes := []int64{100, 100, 50}
+
+	var sums []byte
+	for i, offset := range []int64{0, 100, 200} {
+		sum := md5.Sum(data[offset : offset+partSizes[i]])
+		sums = append(sums, sum[:]...)
+	}
+	etag := fmt.Sprintf("%x-3", md5.Sum(sums))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["attributes"]; !ok || r.URL.Query().Get("versionId") != "v1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if attributes := r.Header.Get("X-Amz-Object-Attributes"); !strings.Contains(attributes, "ObjectParts") {
+			t.Errorf("expected ObjectParts to be requested, got %q", attributes)
+		}
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		w.Header().Set("X-Amz-Version-Id", "v1")
+		fmt.Fprintf(w, "<GetObjectAttributesResponse><ETag>%s</ETag><StorageClass>STANDARD</StorageClass><ObjectSize>250</ObjectSize>", etag)
+		fmt.Fprint(w, "<Checksum><ChecksumCRC32C>c3VtCg==-3</ChecksumCRC32C></Checksum><ObjectParts><PartsCount>3</PartsCount>")
+		if r.Header.Get("X-Amz-Part-Number-Marker") == "" {
+			fmt.Fprint(w, "<IsTruncated>true</IsTruncated><NextPartNumberMarker>2</NextPartNumberMarker>")
+			fmt.Fprint(w, "<Part><PartNumber>1</PartNumber><Size>100</Size><ChecksumCRC32C>cGFydDEK</ChecksumCRC32C></Part>")
+			fmt.Fprint(w, "<Part><PartNumber>2</PartNumber><Size>100</Size><ChecksumCRC32C>cGFydDIK</ChecksumCRC32C></Part>")
+		} else {
+			fmt.Fprint(w, "<IsTruncated>false</IsTruncated>")
+			fmt.Fprint(w, "<Part><PartNumber>3</PartNumber><Size>50</Size><ChecksumCRC32C>cGFydDMK</ChecksumCRC32C></Part>")
+		}
+		fmt.Fprint(w, "</ObjectParts></GetObjectAttributesResponse>")
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	attributes, err := c.GetObjectAttributes("bucket", "object", GetObjectAttributesOptions{VersionID: "v1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attributes.ETag != etag || attributes.ObjectSize != 250 || attributes.StorageClass != "STANDARD" ||
+		attributes.VersionID != "v1" || attributes.LastModified.IsZero() || attributes.Checksum.ChecksumCRC32C != "c3VtCg==-3" {
+		t.Errorf("unexpected attributes %+v", attributes)
+	}
+	if attributes.PartsCount != 3 || len(attributes.Parts) != 3 {
+		t.Fatalf("expected 3 parts, got %+v", attributes.Parts)
+	}
+	var sizes []int64
+	for i, part := range attributes.Parts {
+		if part.PartNumber != i+1 || part.ChecksumCRC32C == "" {
+			t.Errorf("unexpected part %+v", part)
+		}
+		sizes = append(sizes, part.Size)
+	}
+
+	computed, err := MultipartETag(bytes.NewReader(data), sizes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if computed != attributes.ETag {
+		t.Errorf("expected ETag %s, got %s", attributes.ETag, computed)
+	}
+	if _, err = MultipartETag(bytes.NewReader(data[:200]), sizes); ToErrorResponse(err).Code != "InvalidArgument" {
+		t.Errorf("expected InvalidArgument for short data, got %v", err)
+	}
+	if _, err = MultipartETag(bytes.NewReader(append(data, 'x')), sizes); ToErrorResponse(err).Code != "InvalidArgument" {
+		t.Errorf("expected InvalidArgument for trailing data, got %v", err)
+	}
+}
@@ -22,8 +22,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-
-	"github.com/minio/minio-go/v6/pkg/s3utils"
+	"sync"
 )
 
 // FGetObjectWithContext - download contents of an object to a local file.
@@ -40,10 +39,10 @@ func (c Client) FGetObject(bucketName, objectName, filePath string, opts GetObje
 // fGetObjectWithContext - fgetObject wrapper function with context
 func (c Client) fGetObjectWithContext(ctx context.Context, bucketName, objectName, filePath string, opts GetObjectOptions) error {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return err
 	}
-	if err := s3utils.CheckValidObjectName(objectName); err != nil {
+	if err := ValidateObjectKey(objectName); err != nil {
 		return err
 	}
 
@@ -81,8 +80,22 @@ func (c Client) fGetObjectWithContext(ctx context.Context, bucketName, objectNam
 	// Write to a temporary file "fileName.part.minio" before saving.
 	filePartPath := filePath + objectStat.ETag + ".part.minio"
 
+	// Download large objects with concurrent range requests unless
+	// a previous download is resumed.
+	if parallelDownload(objectStat, opts) {
+		if _, err = os.Stat(filePartPath); os.IsNotExist(err) {
+			return c.fGetObjectParallel(ctx, bucketName, objectName, filePath, objectStat, opts)
+		}
+	}
+
 	// If exists, open in append mode. If not create it as a part file.
-	filePart, err := os.OpenFile(filePartPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	// Decoded downloads cannot be resumed from an offset of the
+	// encoded object and always start over.
+	flag := os.O_CREATE | os.O_APPEND | os.O_WRONLY
+	if opts.Decompress && isGzipEncoded(objectStat.Metadata) {
+		flag |= os.O_TRUNC
+	}
+	filePart, err := os.OpenFile(filePartPath, flag, 0600)
 	if err != nil {
 		return err
 	}
@@ -94,8 +107,13 @@ func (c Client) fGetObjectWithContext(ctx context.Context, bucketName, objectNam
 	}
 
 	// Initialize get object request headers to set the
-	// appropriate range offsets to read from.
-	if st.Size() > 0 {
+	// appropriate range offsets to read from. Parts are always
+	// downloaded from their beginning.
+	if st.Size() > 0 && opts.PartNumber > 0 {
+		if err = filePart.Truncate(0); err != nil {
+			return err
+		}
+	} else if st.Size() > 0 {
 		opts.SetRange(st.Size(), 0)
 	}
 
@@ -105,9 +123,22 @@ func (c Client) fGetObjectWithContext(ctx context.Context, bucketName, objectNam
 		return err
 	}
 
-	// Write to the part file.
-	if _, err = io.CopyN(filePart, objectReader, objectStat.Size); err != nil {
-		return err
+	// Write to the part file, io.CopyN is not used since it ignores
+	// errors returned along with the last bytes of the object, such
+	// as checksum mismatches. The size of decoded objects is unknown.
+	if objectStat.Size < 0 {
+		_, err = io.Copy(filePart, objectReader)
+		if err != nil {
+			return err
+		}
+	} else {
+		n, err := io.Copy(filePart, io.LimitReader(objectReader, objectStat.Size))
+		if err != nil {
+			return err
+		}
+		if n != objectStat.Size {
+			return ErrUnexpectedEOF(n, objectStat.Size, bucketName, objectName)
+		}
 	}
 
 	// Close the file before rename, this is specifically needed for Windows users.
@@ -123,3 +154,145 @@ func (c Client) fGetObjectWithContext(ctx context.Context, bucketName, objectNam
 	// Return.
 	return nil
 }
+
+// parallelDownload - returns true if the object is downloaded with
+// concurrent range requests, ranges, parts, checksum verification and
+// decoding of the whole object require a single request.
+func parallelDownload(objectStat ObjectInfo, opts GetObjectOptions) bool {
+	if _, ok := opts.headers["Range"]; ok || opts.VerifyChecksum || opts.Extract || opts.PartNumber > 0 {
+		return false
+	}
+	if opts.Decompress && isGzipEncoded(objectStat.Metadata) {
+		return false
+	}
+	return objectStat.Size > opts.getPartSize()
+}
+
+// getPartSize - gets the part size of FGetObject range requests.
+func (o GetObjectOptions) getPartSize() int64 {
+	if o.PartSize > 0 {
+		return int64(o.PartSize)
+	}
+	return downloadPartSize
+}
+
+// offsetWriter - writes sequentially to a file from an offset.
+type offsetWriter struct {
+	file   *os.File
+	offset int64
+}
+
+func (w *offsetWriter) Write(p []byte) (int, error) {
+	n, err := w.file.WriteAt(p, w.offset)
+	w.offset += int64(n)
+	return n, err
+}
+
+// fGetObjectParallel - downloads an object to a file preallocated to
+// the size of the object, parts are downloaded concurrently and
+// written at their offsets.
+func (c Client) fGetObjectParallel(ctx context.Context, bucketName, objectName, filePath string, objectStat ObjectInfo, opts GetObjectOptions) error {
+	filePartPath := filePath + objectStat.ETag + ".parallel.part.minio"
+	filePart, err := os.OpenFile(filePartPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if err = filePart.Truncate(objectStat.Size); err != nil {
+		filePart.Close()
+		os.Remove(filePartPath)
+		return err
+	}
+
+	// Make sure all the parts are read from the same object.
+	if opts.MatchETag == "" {
+		opts.MatchETag = objectStat.ETag
+	}
+
+	numThreads := totalWorkers
+	if opts.NumThreads > 0 {
+		numThreads = int(opts.NumThreads)
+	}
+	partSize := opts.getPartSize()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		errMutex sync.Mutex
+		firstErr error
+	)
+	offsetsCh := make(chan int64)
+	for i := 0; i < numThreads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for offset := range offsetsCh {
+				length := partSize
+				if offset+length > objectStat.Size {
+					length = objectStat.Size - offset
+				}
+				if err := c.fGetObjectPart(ctx, bucketName, objectName, filePart, offset, length, opts); err != nil {
+					errMutex.Lock()
+					if firstErr == nil {
+						firstErr = err
+					}
+					errMutex.Unlock()
+					cancel()
+				}
+			}
+		}()
+	}
+	for offset := int64(0); offset < objectStat.Size && ctx.Err() == nil; offset += partSize {
+		select {
+		case offsetsCh <- offset:
+		case <-ctx.Done():
+		}
+	}
+	close(offsetsCh)
+	wg.Wait()
+
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	if err = filePart.Close(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	if firstErr != nil {
+		os.Remove(filePartPath)
+		return firstErr
+	}
+
+	// Safely completed. Now commit by renaming to actual filename.
+	return os.Rename(filePartPath, filePath)
+}
+
+// fGetObjectPart - downloads length bytes of the object from offset
+// and writes them at the same offset of the file.
+func (c Client) fGetObjectPart(ctx context.Context, bucketName, objectName string, file *os.File, offset, length int64, opts GetObjectOptions) error {
+	// Copy the headers, the range is set on the headers of the
+	// options.
+	headers := make(map[string]string, len(opts.headers)+1)
+	for k, v := range opts.headers {
+		headers[k] = v
+	}
+	opts.headers = headers
+	if err := opts.SetRange(offset, offset+length-1); err != nil {
+		return err
+	}
+
+	objectReader, _, err := c.getObject(ctx, bucketName, objectName, opts)
+	if err != nil {
+		return err
+	}
+	defer objectReader.Close()
+
+	n, err := io.Copy(&offsetWriter{file: file, offset: offset}, io.LimitReader(objectReader, length))
+	if err != nil {
+		return err
+	}
+	if n != length {
+		return ErrUnexpectedEOF(n, length, bucketName, objectName)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Tests downloading large objects to a file with concurrent range
+// requests.
+func TestFGetObjectParallel(t *testing.T) {
+	data := make([]byte, 1000*1000+7)
+	rand.New(rand.NewSource(1)).Read(data)
+	var mutex sync.Mutex
+	etag := `"etag"`
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		w.Header().Set("ETag", etag)
+		if r.Method == http.MethodGet {
+			ranges = append(ranges, r.Header.Get("Range"))
+			// The object changes after the first part is read.
+			if strings.HasSuffix(r.URL.Path, "changing") {
+				etag = `"changed"`
+			}
+		}
+		mutex.Unlock()
+		http.ServeContent(w, r, "", time.Date(2019, time.March, 10, 12, 30, 0, 0, time.UTC), bytes.NewReader(data))
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "minio-go-fget")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filePath := filepath.Join(dir, "object")
+	opts := GetObjectOptions{PartSize: 100 * 1000, NumThreads: 3}
+	if err = c.FGetObject("bucket", "object", filePath, opts); err != nil {
+		t.Fatal(err)
+	}
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(content, data) {
+		t.Errorf("Expected the downloaded file to match the object")
+	}
+	if len(ranges) != 11 {
+		t.Errorf("Expected 11 range requests, got %d", len(ranges))
+	}
+	for _, r := range ranges {
+		if r == "" {
+			t.Errorf("Expected only range requests, got a request without range")
+		}
+	}
+
+	// Small objects are downloaded with a single request.
+	ranges = nil
+	if err = c.FGetObject("bucket", "object", filePath, GetObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(ranges) != 1 || ranges[0] != "" {
+		t.Errorf("Expected a single request without range, got %q", ranges)
+	}
+
+	// Objects changing during the download are not saved.
+	filePath = filepath.Join(dir, "changing")
+	err = c.FGetObject("bucket", "changing", filePath, GetObjectOptions{PartSize: 100 * 1000, NumThreads: 1})
+	if code := ToErrorResponse(err).Code; code != "PreconditionFailed" {
+		t.Errorf("Expected PreconditionFailed, got %v", err)
+	}
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected only the first download in the directory, got %d entries", len(entries))
+	}
+}
@@ -0,0 +1,163 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"io"
+)
+
+// readAhead - returns true if sequential reads of the object are
+// prefetched in parts, ranges, parts of multipart objects, checksum
+// verification and decoding of the whole object require a single
+// request.
+func (o GetObjectOptions) readAhead() bool {
+	if _, ok := o.headers["Range"]; ok || o.VerifyChecksum || o.Extract || o.Decompress || o.PartNumber > 0 {
+		return false
+	}
+	return o.ReadAhead > 0
+}
+
+// getReadAheadPartSize - gets the part size of GetObject read ahead.
+func (o GetObjectOptions) getReadAheadPartSize() int64 {
+	if o.PartSize > 0 {
+		return int64(o.PartSize)
+	}
+	return readAheadPartSize
+}
+
+// readAheadPart - a prefetched part of an object.
+type readAheadPart struct {
+	data []byte
+	err  error
+}
+
+// readAheadReader - reads an object from parts fetched concurrently
+// in the background, parts are delivered in order.
+type readAheadReader struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	parts  <-chan chan readAheadPart
+	buf    []byte
+	err    error
+}
+
+func (r *readAheadReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		partCh, ok := <-r.parts
+		if !ok {
+			// Parts stop being queued when the context is canceled.
+			if r.err = r.ctx.Err(); r.err == nil {
+				r.err = io.EOF
+			}
+			continue
+		}
+		part := <-partCh
+		r.buf, r.err = part.data, part.err
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// Close stops prefetching, parts in flight are canceled.
+func (r *readAheadReader) Close() error {
+	r.cancel()
+	return nil
+}
+
+// getObjectReadAhead - returns a reader of the object from offset
+// which keeps opts.ReadAhead range requests in flight ahead of the
+// reads. All the parts are read from the version of the object
+// found by an initial stat.
+func (c Client) getObjectReadAhead(ctx context.Context, bucketName, objectName string, offset int64, opts GetObjectOptions) (io.ReadCloser, ObjectInfo, error) {
+	// Copy the headers, ranges are set on the headers of the options.
+	headers := make(map[string]string, len(opts.headers)+1)
+	for k, v := range opts.headers {
+		headers[k] = v
+	}
+	delete(headers, "Range")
+	opts.headers = headers
+
+	objectInfo, err := c.statObject(ctx, bucketName, objectName, StatObjectOptions{opts})
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	if opts.MatchETag == "" {
+		opts.MatchETag = objectInfo.ETag
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	// Queued parts bound the number of requests in flight.
+	parts := make(chan chan readAheadPart, opts.ReadAhead)
+	partSize := opts.getReadAheadPartSize()
+	go func() {
+		defer close(parts)
+		for ; offset < objectInfo.Size; offset += partSize {
+			length := partSize
+			if offset+length > objectInfo.Size {
+				length = objectInfo.Size - offset
+			}
+			partCh := make(chan readAheadPart, 1)
+			select {
+			case parts <- partCh:
+			case <-ctx.Done():
+				return
+			}
+			go func(offset, length int64) {
+				data, err := c.getObjectRange(ctx, bucketName, objectName, offset, length, opts)
+				partCh <- readAheadPart{data: data, err: err}
+			}(offset, length)
+		}
+	}()
+
+	return &readAheadReader{ctx: ctx, cancel: cancel, parts: parts}, objectInfo, nil
+}
+
+// getObjectRange - reads length bytes of the object from offset.
+func (c Client) getObjectRange(ctx context.Context, bucketName, objectName string, offset, length int64, opts GetObjectOptions) ([]byte, error) {
+	// Copy the headers, the range is set on the headers of the
+	// options.
+	headers := make(map[string]string, len(opts.headers)+1)
+	for k, v := range opts.headers {
+		headers[k] = v
+	}
+	opts.headers = headers
+	if err := opts.SetRange(offset, offset+length-1); err != nil {
+		return nil, err
+	}
+
+	objectReader, _, err := c.getObject(ctx, bucketName, objectName, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer objectReader.Close()
+
+	data := make([]byte, length)
+	n, err := io.ReadFull(objectReader, data)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return nil, ErrUnexpectedEOF(int64(n), length, bucketName, objectName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
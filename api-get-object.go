@@ -18,16 +18,16 @@
 package minio
 
 import (
+	"compress/gzip"
 	"context"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"sync"
 	"time"
-
-	"github.com/minio/minio-go/v6/pkg/s3utils"
 )
 
 // GetObject - returns an seekable, readable object.
@@ -38,10 +38,10 @@ func (c Client) GetObject(bucketName, objectName string, opts GetObjectOptions)
 // GetObject wrapper function that accepts a request context
 func (c Client) getObjectWithContext(ctx context.Context, bucketName, objectName string, opts GetObjectOptions) (*Object, error) {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return nil, err
 	}
-	if err := s3utils.CheckValidObjectName(objectName); err != nil {
+	if err := ValidateObjectKey(objectName); err != nil {
 		return nil, err
 	}
 
@@ -49,6 +49,11 @@ func (c Client) getObjectWithContext(ctx context.Context, bucketName, objectName
 	var objectInfo ObjectInfo
 	var err error
 
+	// Sequential reads are prefetched in parts when read ahead is
+	// enabled, the loop below sets ranges on the options so check
+	// them before.
+	readAhead := opts.readAhead()
+
 	// Create request channel.
 	reqCh := make(chan getRequest)
 	// Create response channel.
@@ -92,7 +97,11 @@ func (c Client) getObjectWithContext(ctx context.Context, bucketName, objectName
 						} else if req.Offset > 0 {
 							opts.SetRange(req.Offset, 0)
 						}
-						httpReader, objectInfo, err = c.getObject(ctx, bucketName, objectName, opts)
+						if readAhead && !req.isReadAt {
+							httpReader, objectInfo, err = c.getObjectReadAhead(ctx, bucketName, objectName, req.Offset, opts)
+						} else {
+							httpReader, objectInfo, err = c.getObject(ctx, bucketName, objectName, opts)
+						}
 						if err != nil {
 							resCh <- getResponse{Error: err}
 							return
@@ -127,6 +136,7 @@ func (c Client) getObjectWithContext(ctx context.Context, bucketName, objectName
 							// Exit the go-routine.
 							return
 						}
+						decodedSize(&objectInfo, opts)
 						etag = objectInfo.ETag
 						// Send back the first response.
 						resCh <- getResponse{
@@ -147,6 +157,7 @@ func (c Client) getObjectWithContext(ctx context.Context, bucketName, objectName
 						// Exit the goroutine.
 						return
 					}
+					decodedSize(&objectInfo, opts)
 					// Send back the objectInfo.
 					resCh <- getResponse{
 						objectInfo: objectInfo,
@@ -173,7 +184,11 @@ func (c Client) getObjectWithContext(ctx context.Context, bucketName, objectName
 						} else if req.Offset > 0 { // Range is set with respect to the offset.
 							opts.SetRange(req.Offset, 0)
 						}
-						httpReader, objectInfo, err = c.getObject(ctx, bucketName, objectName, opts)
+						if readAhead && !req.isReadAt {
+							httpReader, objectInfo, err = c.getObjectReadAhead(ctx, bucketName, objectName, req.Offset, opts)
+						} else {
+							httpReader, objectInfo, err = c.getObject(ctx, bucketName, objectName, opts)
+						}
 						if err != nil {
 							resCh <- getResponse{
 								Error: err,
@@ -598,23 +613,61 @@ func newObject(reqCh chan<- getRequest, resCh <-chan getResponse, doneCh chan<-
 // go to http://www.w3.org/Protocols/rfc2616/rfc2616-sec14.html#sec14.35.
 func (c Client) getObject(ctx context.Context, bucketName, objectName string, opts GetObjectOptions) (io.ReadCloser, ObjectInfo, error) {
 	// Validate input arguments.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	if err := ValidateObjectKey(objectName); err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	if err := validateCustomHeaders(opts.CustomHeaders); err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	if err := validateCustomQuery(opts.CustomQuery); err != nil {
 		return nil, ObjectInfo{}, err
 	}
-	if err := s3utils.CheckValidObjectName(objectName); err != nil {
+	if err := opts.validatePartNumber(); err != nil {
 		return nil, ObjectInfo{}, err
 	}
+	urlValues := make(url.Values)
+	setCustomQuery(urlValues, opts.CustomQuery)
+	opts.setPartNumber(urlValues)
+
+	// Whole objects found in the download cache are revalidated
+	// with their ETag.
+	customHeader := opts.Header()
+	var cacheKey string
+	var cachedInfo ObjectInfo
+	var cachedReader io.ReadCloser
+	if c.downloadCache != nil && opts.downloadCacheable() {
+		cacheKey = downloadCacheKey(bucketName, objectName)
+		var ok bool
+		if cachedInfo, cachedReader, ok = c.downloadCache.open(cacheKey); ok {
+			customHeader.Set("If-None-Match", quoteETag(cachedInfo.ETag))
+		}
+	}
 
 	// Execute GET on objectName.
 	resp, err := c.executeMethod(ctx, "GET", requestMetadata{
 		bucketName:       bucketName,
 		objectName:       objectName,
-		customHeader:     opts.Header(),
+		queryValues:      urlValues,
+		customHeader:     customHeader,
 		contentSHA256Hex: emptySHA256Hex,
 	})
 	if err != nil {
+		if cachedReader != nil {
+			cachedReader.Close()
+		}
 		return nil, ObjectInfo{}, err
 	}
+	if cachedReader != nil {
+		if resp.StatusCode == http.StatusNotModified {
+			closeResponse(resp)
+			return cachedReader, cachedInfo, nil
+		}
+		cachedReader.Close()
+		c.downloadCache.remove(cacheKey)
+	}
 	if resp != nil {
 		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
 			return nil, ObjectInfo{}, httpRespToErrorResponse(resp, bucketName, objectName)
@@ -644,18 +697,83 @@ func (c Client) getObject(ctx context.Context, bucketName, objectName string, op
 		contentType = "application/octet-stream"
 	}
 
+	expiryStr := resp.Header.Get("Expires")
+	var expTime time.Time
+	if t, err := time.Parse(http.TimeFormat, expiryStr); err == nil {
+		expTime = t.UTC()
+	}
+
 	objectStat := ObjectInfo{
 		ETag:         md5sum,
 		Key:          objectName,
 		Size:         resp.ContentLength,
 		LastModified: date,
 		ContentType:  contentType,
+		Expires:      expTime,
 		// Extract only the relevant header keys describing the object.
 		// following function filters out a list of standard set of keys
 		// which are not part of object metadata.
-		Metadata: extractObjMetadata(resp.Header),
+		Metadata:     extractObjMetadata(resp.Header),
+		UserMetadata: extractUserMetadata(resp.Header),
 	}
+	setObjectChecksums(&objectStat, resp.Header)
+	setObjectEncryption(&objectStat, resp.Header)
 
 	// do not close body here, caller will close
-	return resp.Body, objectStat, nil
+	body := resp.Body
+
+	// Cache whole objects once they are read to the end.
+	if cacheKey != "" && resp.StatusCode == http.StatusOK {
+		if writer := c.downloadCache.newWriter(cacheKey, objectStat); writer != nil {
+			body = &downloadCacheReader{body: body, writer: writer}
+		}
+	}
+
+	// Verify the checksum of the object, or of the part, when it is
+	// read in full.
+	if opts.VerifyChecksum && (resp.StatusCode == http.StatusOK || opts.PartNumber > 0) {
+		verifyETag := !c.isLegacyGateway(resp.Header)
+		body = newVerifyReader(body, resp.Header, resp.ContentLength, verifyETag, bucketName, objectName)
+	}
+
+	// Decode the object when it is read in full, the checksum is
+	// verified over the encoded data.
+	if opts.Decompress && resp.StatusCode == http.StatusOK && isGzipEncoded(resp.Header) {
+		gzipReader, err := gzip.NewReader(body)
+		if err != nil {
+			body.Close()
+			return nil, ObjectInfo{}, err
+		}
+		body = gzipReadCloser{Reader: gzipReader, body: body}
+		objectStat.Size = -1
+	}
+
+	return body, objectStat, nil
+}
+
+// decodedSize - reports the size of objects decoded by reads as
+// unknown.
+func decodedSize(objectInfo *ObjectInfo, opts GetObjectOptions) {
+	if opts.Decompress && isGzipEncoded(objectInfo.Metadata) {
+		objectInfo.Size = -1
+	}
+}
+
+// isGzipEncoded - returns true if the Content-Encoding of the object
+// is gzip.
+func isGzipEncoded(header http.Header) bool {
+	encoding := strings.TrimSpace(header.Get("Content-Encoding"))
+	return strings.EqualFold(encoding, "gzip") || strings.EqualFold(encoding, "x-gzip")
+}
+
+// gzipReadCloser - decodes a gzip stream, closing it closes the
+// underlying body.
+type gzipReadCloser struct {
+	*gzip.Reader
+	body io.Closer
+}
+
+func (r gzipReadCloser) Close() error {
+	r.Reader.Close()
+	return r.body.Close()
 }
@@ -0,0 +1,248 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Tests decoding gzip encoded objects on request.
+func TestGetObjectDecompress(t *testing.T) {
+	data := []byte(strings.Repeat("compressible data ", 1000))
+	var encoded bytes.Buffer
+	gzipWriter := gzip.NewWriter(&encoded)
+	gzipWriter.Write(data)
+	gzipWriter.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Content-Encoding", "gzip")
+		http.ServeContent(w, r, "", time.Date(2019, time.March, 10, 12, 30, 0, 0, time.UTC), bytes.NewReader(encoded.Bytes()))
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		opts     GetObjectOptions
+		stat     bool
+		expected []byte
+	}{
+		{GetObjectOptions{}, false, encoded.Bytes()},
+		{GetObjectOptions{}, true, encoded.Bytes()},
+		{GetObjectOptions{Decompress: true}, false, data},
+		{GetObjectOptions{Decompress: true}, true, data},
+	}
+	for i, testCase := range testCases {
+		object, err := c.GetObject("bucket", "object", testCase.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if testCase.stat {
+			if _, err = object.Stat(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		content, err := ioutil.ReadAll(object)
+		object.Close()
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if !bytes.Equal(content, testCase.expected) {
+			t.Errorf("Test %d: Expected %d bytes, got %d bytes", i+1, len(testCase.expected), len(content))
+		}
+	}
+
+	dir, err := ioutil.TempDir("", "minio-go-decompress")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filePath := filepath.Join(dir, "object")
+	if err = c.FGetObject("bucket", "object", filePath, GetObjectOptions{Decompress: true, PartSize: 100}); err != nil {
+		t.Fatal(err)
+	}
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(content, data) {
+		t.Errorf("Expected the downloaded file to be decoded, got %d bytes", len(content))
+	}
+}
+
+// Tests sequential reads prefetched with ranged requests.
+func TestGetObjectReadAhead(t *testing.T) {
+	data := []byte(strings.Repeat("0123456789", 1000))
+	var (
+		mutex  sync.Mutex
+		ranges []string
+		heads  int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		if r.Method == http.MethodHead {
+			heads++
+		} else {
+			ranges = append(ranges, r.Header.Get("Range"))
+		}
+		mutex.Unlock()
+		w.Header().Set("ETag", `"etag"`)
+		http.ServeContent(w, r, "", time.Date(2019, time.March, 10, 12, 30, 0, 0, time.UTC), bytes.NewReader(data))
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	object, err := c.GetObject("bucket", "object", GetObjectOptions{PartSize: 1024, ReadAhead: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := ioutil.ReadAll(object)
+	object.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(content, data) {
+		t.Fatal("read ahead content does not match the object")
+	}
+	mutex.Lock()
+	if heads != 1 || len(ranges) != 10 {
+		t.Fatalf("expected 1 stat and 10 ranged requests, got %d and %v", heads, ranges)
+	}
+	sort.Strings(ranges)
+	if ranges[0] != "bytes=0-1023" || ranges[1] != "bytes=1024-2047" || ranges[9] != "bytes=9216-9999" {
+		t.Fatalf("unexpected ranges %v", ranges)
+	}
+	ranges, heads = nil, 0
+	mutex.Unlock()
+
+	// Seeking restarts the prefetch from the new offset.
+	object, err = c.GetObject("bucket", "object", GetObjectOptions{PartSize: 1024, ReadAhead: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = object.Seek(9500, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	content, err = ioutil.ReadAll(object)
+	object.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(content, data[9500:]) {
+		t.Fatal("read ahead content after seek does not match the object")
+	}
+	mutex.Lock()
+	if len(ranges) != 1 || ranges[0] != "bytes=9500-9999" {
+		t.Fatalf("unexpected ranges after seek %v", ranges)
+	}
+	mutex.Unlock()
+}
+
+// Tests parts of multipart objects are read and verified on request.
+func TestGetObjectPartNumber(t *testing.T) {
+	parts := [][]byte{[]byte("first part "), []byte("second part"), []byte("last")}
+	var corrupt bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		partNumber, err := strconv.Atoi(r.URL.Query().Get("partNumber"))
+		if err != nil || partNumber < 1 || partNumber > len(parts) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		part := parts[partNumber-1]
+		w.Header().Set("ETag", `"5d41402abc4b2a76b9719d911017c592-3"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		w.Header().Set("X-Amz-Mp-Parts-Count", strconv.Itoa(len(parts)))
+		w.Header().Set("Content-Length", strconv.Itoa(len(part)))
+		if r.Header.Get("X-Amz-Checksum-Mode") == "ENABLED" {
+			sum := crc32.NewIEEE()
+			sum.Write(part)
+			w.Header().Set("X-Amz-Checksum-Crc32", base64.StdEncoding.EncodeToString(sum.Sum(nil)))
+		}
+		w.WriteHeader(http.StatusPartialContent)
+		if r.Method == http.MethodGet {
+			if corrupt {
+				part = bytes.ToUpper(part)
+			}
+			w.Write(part)
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := c.StatObject("bucket", "object", StatObjectOptions{GetObjectOptions{PartNumber: 2, VerifyChecksum: true}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.PartsCount != 3 || info.Size != int64(len(parts[1])) || info.ChecksumCRC32 == "" {
+		t.Errorf("unexpected part info %+v", info)
+	}
+
+	for _, corrupt = range []bool{false, true} {
+		reader, info, err := c.getObject(context.Background(), "bucket", "object", GetObjectOptions{PartNumber: 2, VerifyChecksum: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadAll(reader)
+		reader.Close()
+		if corrupt {
+			if ToErrorResponse(err).Code != "ObjectCorrupted" {
+				t.Errorf("expected ObjectCorrupted, got %v", err)
+			}
+			continue
+		}
+		if err != nil || !bytes.Equal(data, parts[1]) || info.PartsCount != 3 {
+			t.Errorf("expected the second part, got %q, %d parts, %v", data, info.PartsCount, err)
+		}
+	}
+
+	opts := GetObjectOptions{PartNumber: 1}
+	opts.SetRange(0, 1)
+	if _, err = c.StatObject("bucket", "object", StatObjectOptions{opts}); ToErrorResponse(err).Code != "InvalidArgument" {
+		t.Errorf("expected InvalidArgument for a range of a part, got %v", err)
+	}
+	if _, err = c.StatObject("bucket", "object", StatObjectOptions{GetObjectOptions{PartNumber: 10001}}); ToErrorResponse(err).Code != "InvalidArgument" {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+}
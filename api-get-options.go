@@ -20,6 +20,9 @@ package minio
 import (
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/minio/minio-go/v6/pkg/encrypt"
@@ -30,8 +33,84 @@ import (
 type GetObjectOptions struct {
 	headers              map[string]string
 	ServerSideEncryption encrypt.ServerSide
+
+	// Conditions under which the object is returned, sent as
+	// If-Match, If-None-Match, If-Modified-Since and
+	// If-Unmodified-Since headers. A request that does not meet
+	// them fails with a NotModified or a PreconditionFailed
+	// ErrorResponse, see IsNotModified and IsPreconditionFailed.
+	MatchETag       string
+	NotMatchETag    string
+	ModifiedSince   time.Time
+	UnmodifiedSince time.Time
+
+	// VerifyChecksum verifies the downloaded data against the
+	// x-amz-checksum-* values of the object, or its ETag for single
+	// part unencrypted objects. Only objects read in full from the
+	// beginning are verified, a mismatch is reported by the read
+	// reaching the end of the object with an ObjectCorrupted error.
+	VerifyChecksum bool
+
+	// Extract addresses a file inside a zip archive stored as an
+	// object, named by the object name followed by the path of the
+	// file in the archive, e.g. "archive.zip/dir/file.txt". This is
+	// an extension supported by MinIO server.
+	Extract bool
+
+	// Decompress decodes objects stored with a gzip Content-Encoding
+	// in the returned reader, the raw stream is returned otherwise.
+	// Only objects read in full from the beginning are decoded, the
+	// size of a decoded object is reported as -1 since it is not
+	// known before the object is read.
+	Decompress bool
+
+	// PartSize and NumThreads are used by FGetObject, objects larger
+	// than PartSize are downloaded with NumThreads concurrent range
+	// requests written at their offsets of the file. Defaults to
+	// 4 threads and parts of 64MiB.
+	PartSize   uint64
+	NumThreads uint
+
+	// CustomHeaders are sent with the GET and HEAD requests of the
+	// object, so that headers of extensions of the service can be
+	// used. The fields of the options take precedence, headers set by
+	// the client and its signers such as Authorization or Range are
+	// rejected.
+	CustomHeaders http.Header
+
+	// CustomQuery are query parameters signed and sent with the same
+	// requests as CustomHeaders, so that subresources of extensions
+	// of the service can be used. Parameters set by the client and
+	// its signers such as X-Amz-Signature are rejected.
+	CustomQuery url.Values
+
+	// ReadAhead is the number of ranged requests of PartSize bytes
+	// that GetObject keeps in flight ahead of sequential reads, which
+	// improves throughput over high latency links at the cost of
+	// buffering up to ReadAhead parts in memory. Parts default to
+	// 8MiB. ReadAt calls, ranges, checksum verification, extraction
+	// and decoding are served by a single request.
+	ReadAhead int
+
+	// PartNumber reads or stats a single part of a multipart object,
+	// sent as the partNumber query parameter. The part is read in
+	// full, its size is the size of the returned ObjectInfo and the
+	// number of parts of the object is PartsCount. With
+	// VerifyChecksum the checksums of the part are returned and the
+	// data of the part is verified.
+	PartNumber int
 }
 
+// downloadPartSize - default part size of FGetObject range requests.
+const downloadPartSize = 1024 * 1024 * 64
+
+// readAheadPartSize - default part size of GetObject read ahead.
+const readAheadPartSize = 1024 * 1024 * 8
+
+// minIOExtract - header asking MinIO server to address files inside
+// zip archives.
+const minIOExtract = "X-Minio-Extract"
+
 // StatObjectOptions are used to specify additional headers or options
 // during GET info/stat requests.
 type StatObjectOptions struct {
@@ -41,15 +120,65 @@ type StatObjectOptions struct {
 // Header returns the http.Header representation of the GET options.
 func (o GetObjectOptions) Header() http.Header {
 	headers := make(http.Header, len(o.headers))
+	setCustomHeaders(headers, o.CustomHeaders)
 	for k, v := range o.headers {
 		headers.Set(k, v)
 	}
+	if o.MatchETag != "" {
+		headers.Set("If-Match", quoteETag(o.MatchETag))
+	}
+	if o.NotMatchETag != "" {
+		headers.Set("If-None-Match", quoteETag(o.NotMatchETag))
+	}
+	if !o.ModifiedSince.IsZero() {
+		headers.Set("If-Modified-Since", o.ModifiedSince.UTC().Format(http.TimeFormat))
+	}
+	if !o.UnmodifiedSince.IsZero() {
+		headers.Set("If-Unmodified-Since", o.UnmodifiedSince.UTC().Format(http.TimeFormat))
+	}
+	if o.VerifyChecksum {
+		headers.Set(amzChecksumMode, "ENABLED")
+	}
+	if o.Extract {
+		headers.Set(minIOExtract, "true")
+	}
 	if o.ServerSideEncryption != nil && o.ServerSideEncryption.Type() == encrypt.SSEC {
 		o.ServerSideEncryption.Marshal(headers)
 	}
 	return headers
 }
 
+// validatePartNumber - validates the part number of the options,
+// ranges cannot be read from parts.
+func (o GetObjectOptions) validatePartNumber() error {
+	if o.PartNumber == 0 {
+		return nil
+	}
+	if o.PartNumber < 0 || o.PartNumber > maxPartsCount {
+		return ErrInvalidArgument(fmt.Sprintf("Part number must be between 1 and %d.", maxPartsCount))
+	}
+	if _, ok := o.headers["Range"]; ok {
+		return ErrInvalidArgument("Ranges cannot be read from a part of an object.")
+	}
+	return nil
+}
+
+// setPartNumber - adds the part number of the options to the query.
+func (o GetObjectOptions) setPartNumber(urlValues url.Values) {
+	if o.PartNumber > 0 {
+		urlValues.Set("partNumber", strconv.Itoa(o.PartNumber))
+	}
+}
+
+// quoteETag - returns the etag enclosed in double quotes, the
+// wildcard etag "*" and already quoted etags are returned as is.
+func quoteETag(etag string) string {
+	if etag == "*" || strings.HasPrefix(etag, "\"") {
+		return etag
+	}
+	return "\"" + etag + "\""
+}
+
 // Set adds a key value pair to the options. The
 // key-value pair will be part of the HTTP GET request
 // headers.
@@ -22,14 +22,12 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
-
-	"github.com/minio/minio-go/v6/pkg/s3utils"
 )
 
 // GetBucketPolicy - get bucket policy at a given path.
 func (c Client) GetBucketPolicy(bucketName string) (string, error) {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return "", err
 	}
 	bucketPolicy, err := c.getBucketPolicy(bucketName)
@@ -0,0 +1,211 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DownloadPolicy is the policy applied by DownloadPrefix when a local
+// file already exists for an object.
+type DownloadPolicy int
+
+// Different types of download policies for existing local files.
+const (
+	// DownloadOverwrite always overwrites existing files.
+	DownloadOverwrite DownloadPolicy = iota
+	// DownloadSkipExisting never overwrites existing files.
+	DownloadSkipExisting
+	// DownloadSkipNewer only overwrites existing files which were
+	// last modified before the object.
+	DownloadSkipNewer
+)
+
+// DownloadPrefixOptions represents options specified by user for
+// DownloadPrefix call.
+type DownloadPrefixOptions struct {
+	// Options used for downloading each object.
+	GetObjectOptions
+
+	// Number of objects downloaded concurrently, defaults to 4.
+	NumWorkers int
+
+	// AutoTuneWorkers adjusts the number of objects downloaded
+	// concurrently from the measured throughput, up to NumWorkers or
+	// 16 if it is not set.
+	AutoTuneWorkers bool
+
+	// Policy applied to existing local files, defaults to
+	// DownloadOverwrite.
+	Policy DownloadPolicy
+}
+
+// DownloadPrefixResult - result of downloading a single object of the
+// prefix.
+type DownloadPrefixResult struct {
+	// Name of the downloaded object.
+	ObjectName string
+
+	// Local path the object is downloaded to.
+	FilePath string
+
+	// Size of the object.
+	Size int64
+
+	// Skipped is set if the download was skipped by the policy.
+	Skipped bool
+
+	// Error is set if the object could not be listed or downloaded.
+	Err error
+}
+
+// prefixFilePath - maps an object name to a file path under dirPath,
+// the object name is taken relative to the last '/' of prefix. Object
+// names which would resolve outside of dirPath are rejected.
+func prefixFilePath(dirPath, prefix, objectName string) (string, error) {
+	base := prefix[:strings.LastIndex(prefix, "/")+1]
+	relPath := filepath.FromSlash(strings.TrimPrefix(objectName, base))
+	filePath := filepath.Join(dirPath, relPath)
+	rel, err := filepath.Rel(dirPath, filePath)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", ErrInvalidArgument("Object name ‘" + objectName + "’ cannot be mapped to a file under ‘" + dirPath + "’.")
+	}
+	return filePath, nil
+}
+
+// DownloadPrefix - downloads all objects under prefix to dirPath,
+// recreating the hierarchy of the object names relative to prefix.
+// Objects are downloaded concurrently and the result of each download
+// is sent on the returned channel, which is closed once all the
+// objects have been processed.
+func (c Client) DownloadPrefix(bucketName, prefix, dirPath string, opts DownloadPrefixOptions) <-chan DownloadPrefixResult {
+	return c.DownloadPrefixWithContext(context.Background(), bucketName, prefix, dirPath, opts)
+}
+
+// DownloadPrefixWithContext - Identical to DownloadPrefix call, but accepts context to facilitate request cancellation.
+func (c Client) DownloadPrefixWithContext(ctx context.Context, bucketName, prefix, dirPath string, opts DownloadPrefixOptions) <-chan DownloadPrefixResult {
+	resultCh := make(chan DownloadPrefixResult, 1)
+
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		defer close(resultCh)
+		resultCh <- DownloadPrefixResult{Err: err}
+		return resultCh
+	}
+
+	numWorkers := opts.NumWorkers
+	if numWorkers <= 0 {
+		numWorkers = totalWorkers
+		if opts.AutoTuneWorkers {
+			numWorkers = maxAutoTunedWorkers
+		}
+	}
+	var tuner *concurrencyTuner
+	if opts.AutoTuneWorkers {
+		tuner = newConcurrencyTuner(numWorkers)
+	}
+
+	// List the objects and send them to be downloaded.
+	objectsCh := make(chan DownloadPrefixResult)
+	go func() {
+		defer close(objectsCh)
+		doneCh := make(chan struct{})
+		defer close(doneCh)
+		for object := range c.ListObjectsV2(bucketName, prefix, true, doneCh) {
+			if object.Err != nil {
+				resultCh <- DownloadPrefixResult{Err: object.Err}
+				return
+			}
+			// Skip directory markers, directories are created
+			// along with the files.
+			if strings.HasSuffix(object.Key, "/") {
+				continue
+			}
+			result := DownloadPrefixResult{
+				ObjectName: object.Key,
+				Size:       object.Size,
+			}
+			result.FilePath, result.Err = prefixFilePath(dirPath, prefix, object.Key)
+			if result.Err == nil {
+				result.Skipped, result.Err = skipDownload(result.FilePath, object, opts.Policy)
+			}
+			if result.Err != nil || result.Skipped {
+				resultCh <- result
+				continue
+			}
+			select {
+			case objectsCh <- result:
+			case <-ctx.Done():
+				resultCh <- DownloadPrefixResult{Err: ctx.Err()}
+				return
+			}
+		}
+	}()
+
+	// Download the objects with a pool of workers.
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for object := range objectsCh {
+				if tuner != nil {
+					tuner.acquire()
+				}
+				object.Err = c.FGetObjectWithContext(ctx, bucketName, object.ObjectName, object.FilePath, opts.GetObjectOptions)
+				if tuner != nil {
+					tuner.release(object.Size)
+				}
+				resultCh <- object
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(resultCh)
+	}()
+
+	return resultCh
+}
+
+// skipDownload - returns true if the object should not be downloaded
+// to filePath according to policy.
+func skipDownload(filePath string, object ObjectInfo, policy DownloadPolicy) (bool, error) {
+	if policy == DownloadOverwrite {
+		return false, nil
+	}
+	st, err := os.Stat(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	switch policy {
+	case DownloadSkipExisting:
+		return true, nil
+	case DownloadSkipNewer:
+		return !st.ModTime().Before(object.LastModified), nil
+	}
+	return false, ErrInvalidArgument("Unsupported download policy.")
+}
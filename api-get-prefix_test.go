@@ -0,0 +1,94 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Tests mapping of object names to file paths.
+func TestPrefixFilePath(t *testing.T) {
+	dir := filepath.Join("tmp", "download")
+	testCases := []struct {
+		prefix     string
+		objectName string
+		filePath   string
+		shouldPass bool
+	}{
+		{"", "file.txt", filepath.Join(dir, "file.txt"), true},
+		{"photos/", "photos/2019/pic.jpg", filepath.Join(dir, "2019", "pic.jpg"), true},
+		{"photos/20", "photos/2019/pic.jpg", filepath.Join(dir, "2019", "pic.jpg"), true},
+		{"photos", "photos/2019/pic.jpg", filepath.Join(dir, "photos", "2019", "pic.jpg"), true},
+		{"", "../escape.txt", "", false},
+		{"a/", "a/../../escape.txt", "", false},
+		{"a/", "a/", "", false},
+	}
+	for i, testCase := range testCases {
+		filePath, err := prefixFilePath(dir, testCase.prefix, testCase.objectName)
+		if err != nil && testCase.shouldPass {
+			t.Errorf("Test %d: Expected to pass, but failed with: %v", i+1, err)
+		}
+		if err == nil && !testCase.shouldPass {
+			t.Errorf("Test %d: Expected to fail, but got %s", i+1, filePath)
+		}
+		if err == nil && filePath != testCase.filePath {
+			t.Errorf("Test %d: Expected %s, got %s", i+1, testCase.filePath, filePath)
+		}
+	}
+}
+
+// Tests the download policies for existing files.
+func TestSkipDownload(t *testing.T) {
+	f, err := ioutil.TempFile("", "minio-go-download")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+	modTime := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err = os.Chtimes(f.Name(), modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		filePath     string
+		lastModified time.Time
+		policy       DownloadPolicy
+		skip         bool
+	}{
+		{f.Name(), modTime, DownloadOverwrite, false},
+		{f.Name(), modTime, DownloadSkipExisting, true},
+		{f.Name(), modTime.Add(-time.Minute), DownloadSkipNewer, true},
+		{f.Name(), modTime, DownloadSkipNewer, true},
+		{f.Name(), modTime.Add(time.Minute), DownloadSkipNewer, false},
+		{f.Name() + ".missing", modTime, DownloadSkipExisting, false},
+	}
+	for i, testCase := range testCases {
+		skip, err := skipDownload(testCase.filePath, ObjectInfo{LastModified: testCase.lastModified}, testCase.policy)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		if skip != testCase.skip {
+			t.Errorf("Test %d: expected skip to be %v", i+1, testCase.skip)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// objectHandler - serves the objects under a prefix over HTTP.
+type objectHandler struct {
+	c          Client
+	bucketName string
+	prefix     string
+	opts       GetObjectOptions
+}
+
+// Handler - returns an http.Handler serving the objects of the bucket
+// under prefix for GET and HEAD requests, the cleaned request path is
+// used as the object name under the prefix. Responses carry the
+// Content-Type, ETag and Last-Modified of the object, and Range and
+// conditional requests are handled as documented for http.ServeContent
+// by reading only the requested ranges of the object. Each request is
+// served with a single GET or HEAD request to the server, except for
+// requests of several ranges which fetch each range.
+//
+// With Go 1.16 and later, http.FS(c.FS(bucketName, prefix)) can be
+// used wherever an http.FileSystem is required instead.
+func (c Client) Handler(bucketName, prefix string, opts GetObjectOptions) http.Handler {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return &objectHandler{
+		c:          c,
+		bucketName: bucketName,
+		prefix:     prefix,
+		opts:       opts,
+	}
+}
+
+// handlerObjectName - returns the object name under prefix for the
+// request path, empty if the path addresses a directory.
+func handlerObjectName(prefix, urlPath string) string {
+	if urlPath == "" || strings.HasSuffix(urlPath, "/") {
+		return ""
+	}
+	name := strings.TrimPrefix(path.Clean("/"+urlPath), "/")
+	if name == "" {
+		return ""
+	}
+	return prefix + name
+}
+
+// handlerErrorStatus - returns the HTTP status code reported to the
+// client for an error returned by the server.
+func handlerErrorStatus(err error) int {
+	switch {
+	case IsNotFound(err):
+		return http.StatusNotFound
+	case IsAccessDenied(err):
+		return http.StatusForbidden
+	case IsPreconditionFailed(err):
+		return http.StatusPreconditionFailed
+	case IsNotModified(err):
+		return http.StatusNotModified
+	}
+	return http.StatusBadGateway
+}
+
+// handlerContent - the content of an object served by http.ServeContent.
+// The body of the first GET request is read as long as the reads are at
+// its offset, reads at other offsets fetch the object again from the
+// offset.
+type handlerContent struct {
+	ctx        context.Context
+	h          *objectHandler
+	objectName string
+	etag       string
+	size       int64
+
+	body       io.ReadCloser
+	bodyOffset int64
+	offset     int64
+}
+
+// Read - implements io.Reader.
+func (hc *handlerContent) Read(p []byte) (int, error) {
+	if hc.body == nil || hc.bodyOffset != hc.offset {
+		if hc.body != nil {
+			hc.body.Close()
+			hc.body = nil
+		}
+		if hc.size >= 0 && hc.offset >= hc.size {
+			return 0, io.EOF
+		}
+		opts := hc.h.requestOptions("")
+		if hc.etag != "" {
+			opts.SetMatchETag(hc.etag)
+		}
+		if hc.offset > 0 {
+			opts.SetRange(hc.offset, 0)
+		}
+		body, _, err := hc.h.c.getObject(hc.ctx, hc.h.bucketName, hc.objectName, opts)
+		if err != nil {
+			return 0, err
+		}
+		hc.body, hc.bodyOffset = body, hc.offset
+	}
+	n, err := hc.body.Read(p)
+	hc.bodyOffset += int64(n)
+	hc.offset += int64(n)
+	return n, err
+}
+
+// Seek - implements io.Seeker, the object is not fetched until it is
+// read.
+func (hc *handlerContent) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += hc.offset
+	case io.SeekEnd:
+		if hc.size < 0 {
+			return 0, ErrInvalidArgument("Whence END is not supported when the object size is unknown")
+		}
+		offset += hc.size
+	default:
+		return 0, ErrInvalidArgument(fmt.Sprintf("Invalid whence %d", whence))
+	}
+	if offset < 0 {
+		return 0, ErrInvalidArgument(fmt.Sprintf("Seeking at negative offset not allowed for %d", whence))
+	}
+	hc.offset = offset
+	return offset, nil
+}
+
+// Close - closes the body of the last request, if any.
+func (hc *handlerContent) Close() error {
+	if hc.body == nil {
+		return nil
+	}
+	return hc.body.Close()
+}
+
+// requestOptions - returns a copy of the options of the handler with
+// the Range header set to rng, if not empty.
+func (h *objectHandler) requestOptions(rng string) GetObjectOptions {
+	opts := h.opts
+	opts.headers = make(map[string]string, len(h.opts.headers)+1)
+	for k, v := range h.opts.headers {
+		opts.headers[k] = v
+	}
+	if rng != "" {
+		opts.Set("Range", rng)
+	}
+	return opts
+}
+
+// handlerRange - returns the Range header of the request if it is a
+// single byte range which the server can return in one response.
+func handlerRange(r *http.Request) string {
+	rng := r.Header.Get("Range")
+	if !strings.HasPrefix(rng, "bytes=") || strings.Contains(rng, ",") || r.Header.Get("If-Range") != "" {
+		return ""
+	}
+	return rng
+}
+
+// open - fetches the object with a single request, a GET request for
+// the range requested by the client, if any, or a HEAD request for
+// HEAD requests.
+func (h *objectHandler) open(r *http.Request, objectName string) (*handlerContent, ObjectInfo, error) {
+	hc := &handlerContent{ctx: r.Context(), h: h, objectName: objectName}
+	if r.Method == http.MethodHead {
+		objInfo, err := h.c.statObject(r.Context(), h.bucketName, objectName, StatObjectOptions{h.requestOptions("")})
+		if err != nil {
+			return nil, ObjectInfo{}, err
+		}
+		decodedSize(&objInfo, h.opts)
+		hc.etag, hc.size = objInfo.ETag, objInfo.Size
+		return hc, objInfo, nil
+	}
+
+	rng := handlerRange(r)
+	if h.opts.Decompress {
+		// Ranges of encoded objects are not decoded.
+		rng = ""
+	}
+	body, objInfo, err := h.c.getObject(r.Context(), h.bucketName, objectName, h.requestOptions(rng))
+	if err != nil && rng != "" && ToErrorResponse(err).Code == "InvalidRange" {
+		// Fetch the object as a whole so that http.ServeContent
+		// reports the size of the object with the error.
+		body, objInfo, err = h.c.getObject(r.Context(), h.bucketName, objectName, h.requestOptions(""))
+	}
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	hc.etag, hc.size, hc.body = objInfo.ETag, objInfo.Size, body
+	var start, end int64
+	if contentRange := objInfo.Metadata.Get("Content-Range"); contentRange != "" {
+		// The body holds a range of the object.
+		if _, err = fmt.Sscanf(contentRange, "bytes %d-%d/%d", &start, &end, &hc.size); err != nil {
+			body.Close()
+			return nil, ObjectInfo{}, ErrInvalidArgument("Unexpected Content-Range ‘" + contentRange + "’ of the response.")
+		}
+		objInfo.Size = hc.size
+	}
+	hc.bodyOffset = start
+	return hc, objInfo, nil
+}
+
+// ServeHTTP - implements http.Handler.
+func (h *objectHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	objectName := handlerObjectName(h.prefix, r.URL.Path)
+	if objectName == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	content, objInfo, err := h.open(r, objectName)
+	if err != nil {
+		status := handlerErrorStatus(err)
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	defer content.Close()
+	if objInfo.ContentType != "" {
+		w.Header().Set("Content-Type", objInfo.ContentType)
+	}
+	if objInfo.ETag != "" {
+		w.Header().Set("ETag", quoteETag(objInfo.ETag))
+	}
+	http.ServeContent(w, r, objectName, objInfo.LastModified, content)
+}
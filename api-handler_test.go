@@ -0,0 +1,183 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Tests mapping of request paths to object names.
+func TestHandlerObjectName(t *testing.T) {
+	testCases := []struct {
+		prefix     string
+		urlPath    string
+		objectName string
+	}{
+		{"", "/object", "object"},
+		{"site/", "/index.html", "site/index.html"},
+		{"site/", "/a/../b/c.css", "site/b/c.css"},
+		{"site/", "/../../secret", "site/secret"},
+		{"site/", "/", ""},
+		{"site/", "/dir/", ""},
+		{"site/", "", ""},
+	}
+	for i, testCase := range testCases {
+		if objectName := handlerObjectName(testCase.prefix, testCase.urlPath); objectName != testCase.objectName {
+			t.Errorf("Test %d: expected %q, got %q", i+1, testCase.objectName, objectName)
+		}
+	}
+}
+
+// Tests the status codes reported for server errors.
+func TestHandlerErrorStatus(t *testing.T) {
+	testCases := []struct {
+		err    error
+		status int
+	}{
+		{ErrorResponse{Code: "NoSuchKey"}, http.StatusNotFound},
+		{ErrorResponse{Code: "AccessDenied"}, http.StatusForbidden},
+		{ErrPreconditionFailed("bucket", "object"), http.StatusPreconditionFailed},
+		{ErrorResponse{Code: "InternalError"}, http.StatusBadGateway},
+	}
+	for i, testCase := range testCases {
+		if status := handlerErrorStatus(testCase.err); status != testCase.status {
+			t.Errorf("Test %d: expected %d, got %d", i+1, testCase.status, status)
+		}
+	}
+}
+
+// Tests serving objects, with ranges, from a fake server.
+func TestHandlerServeHTTP(t *testing.T) {
+	const content = "hello, world"
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.Header.Get("Range"))
+		if r.URL.Path != "/bucket/site/hello.txt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body := content
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("ETag", `"abc"`)
+		w.Header().Set("Last-Modified", "Wed, 02 Jan 2019 03:04:05 GMT")
+		if rng := r.Header.Get("Range"); rng != "" {
+			var start int
+			fmt.Sscanf(rng, "bytes=%d-", &start)
+			if start >= len(content) {
+				w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+				fmt.Fprint(w, "<Error><Code>InvalidRange</Code><Message>The requested range is not satisfiable</Message></Error>")
+				return
+			}
+			body = content[start:]
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(content)-1, len(content)))
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+			w.WriteHeader(http.StatusPartialContent)
+		} else {
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		}
+		if r.Method != http.MethodHead {
+			w.Write([]byte(body))
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := c.Handler("bucket", "site", GetObjectOptions{})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/hello.txt", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != content {
+		t.Fatalf("expected %q, got %d %q", content, rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("Content-Type") != "text/plain" || rec.Header().Get("ETag") != `"abc"` ||
+		rec.Header().Get("Last-Modified") != "Wed, 02 Jan 2019 03:04:05 GMT" {
+		t.Errorf("unexpected headers %v", rec.Header())
+	}
+	if !reflect.DeepEqual(requests, []string{"GET "}) {
+		t.Errorf("expected a single GET request, got %q", requests)
+	}
+	requests = nil
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/hello.txt", nil)
+	req.Header.Set("Range", "bytes=7-")
+	handler.ServeHTTP(rec, req)
+	if body, _ := ioutil.ReadAll(rec.Body); rec.Code != http.StatusPartialContent || string(body) != "world" {
+		t.Errorf("expected partial content %q, got %d %q", "world", rec.Code, body)
+	}
+	if !reflect.DeepEqual(requests, []string{"GET bytes=7-"}) {
+		t.Errorf("expected a single GET request of the range, got %q", requests)
+	}
+	requests = nil
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/hello.txt", nil)
+	req.Header.Set("Range", "bytes=20-")
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusRequestedRangeNotSatisfiable || rec.Header().Get("Content-Range") != "bytes */12" {
+		t.Errorf("expected %d with the size of the object, got %d %v", http.StatusRequestedRangeNotSatisfiable, rec.Code, rec.Header())
+	}
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/hello.txt", nil)
+	req.Header.Set("Range", "bytes=0-4,7-")
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusPartialContent || !strings.HasPrefix(rec.Header().Get("Content-Type"), "multipart/byteranges") {
+		t.Errorf("expected multipart partial content, got %d %v", rec.Code, rec.Header())
+	}
+
+	rec = httptest.NewRecorder()
+	requests = nil
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/hello.txt", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Length") != strconv.Itoa(len(content)) {
+		t.Errorf("expected %d with the length of the object, got %d %v", http.StatusOK, rec.Code, rec.Header())
+	}
+	if !reflect.DeepEqual(requests, []string{"HEAD "}) {
+		t.Errorf("expected a single HEAD request, got %q", requests)
+	}
+
+	rec = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodGet, "/hello.txt", nil)
+	req.Header.Set("If-None-Match", `"abc"`)
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("expected %d, got %d", http.StatusNotModified, rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/missing.txt", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected %d, got %d", http.StatusNotFound, rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/hello.txt", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected %d, got %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+}
@@ -0,0 +1,253 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// listEntryFunc - called with each object of a list response as soon
+// as it is decoded, common prefixes are passed as objects named by the
+// prefix with isPrefix set. Returning false stops the decoding.
+type listEntryFunc func(object ObjectInfo, isPrefix bool) bool
+
+// listEntry - an entry of a list response held back by
+// decodeListResponse.
+type listEntry struct {
+	object   ObjectInfo
+	isPrefix bool
+}
+
+// listObjectEntry - a Contents entry of a list response, with the user
+// metadata MinIO sends along when asked to.
+type listObjectEntry struct {
+	ObjectInfo
+	UserMetadata listUserMetadata `xml:"UserMetadata"`
+}
+
+// listUserMetadata - the metadata of a listed object, each child
+// element of UserMetadata is named by a metadata header.
+type listUserMetadata map[string]string
+
+// UnmarshalXML - decodes the metadata elements.
+func (m *listUserMetadata) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*m = make(listUserMetadata)
+	for {
+		token, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			var value string
+			if err = d.DecodeElement(&value, &t); err != nil {
+				return err
+			}
+			(*m)[t.Name.Local] = value
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// objectInfo - returns the listed object, with its metadata if any.
+func (e listObjectEntry) objectInfo() ObjectInfo {
+	object := e.ObjectInfo
+	if len(e.UserMetadata) == 0 {
+		return object
+	}
+	object.Metadata = make(http.Header)
+	object.UserMetadata = make(UserMetadata)
+	for k, v := range e.UserMetadata {
+		object.Metadata.Set(k, v)
+		if strings.HasPrefix(strings.ToLower(k), "x-amz-meta-") {
+			object.UserMetadata[http.CanonicalHeaderKey(k[len("x-amz-meta-"):])] = v
+		}
+	}
+	return object
+}
+
+// decodeListResponse - decodes a ListObjects or ListObjectsV2 response
+// incrementally, the Contents and CommonPrefixes entries are passed to
+// fn one at a time instead of unmarshaling a whole page in memory, all
+// other elements are decoded into the field of the same name of result,
+// a *ListBucketResult or a *ListBucketV2Result. Returns stopped true if
+// fn returned false.
+//
+// Keys of responses with the url encoding type are decoded before they
+// are passed to fn. Since the encoding type may only be sent after the
+// entries, entries with keys which would be changed by decoding are
+// held back, along with all entries after them, until it is known.
+func decodeListResponse(body io.Reader, result interface{}, fn listEntryFunc) (stopped bool, err error) {
+	resultValue := reflect.ValueOf(result).Elem()
+	encodingType := resultValue.FieldByName("EncodingType")
+	encodingKnown := false
+	var pending []listEntry
+
+	// Passes an entry to fn, decoding its key if necessary.
+	emit := func(entry listEntry) (bool, error) {
+		if encodingType.String() == "url" {
+			key, err := url.QueryUnescape(entry.object.Key)
+			if err != nil {
+				return false, err
+			}
+			entry.object.Key = key
+		}
+		return fn(entry.object, entry.isPrefix), nil
+	}
+	// Passes the entries held back to fn.
+	flush := func() (bool, error) {
+		for len(pending) > 0 {
+			entry := pending[0]
+			pending = pending[1:]
+			if ok, err := emit(entry); !ok || err != nil {
+				return ok, err
+			}
+		}
+		return true, nil
+	}
+	// Passes an entry to fn or holds it back.
+	add := func(entry listEntry) (bool, error) {
+		if !encodingKnown && (len(pending) > 0 || strings.ContainsAny(entry.object.Key, "%+")) {
+			pending = append(pending, entry)
+			return true, nil
+		}
+		return emit(entry)
+	}
+
+	d := xml.NewDecoder(body)
+	depth := 0
+	for {
+		token, err := d.Token()
+		if err != nil {
+			// An empty body is reported as io.EOF and a truncated
+			// body as a syntax error, as with xml.Decoder.Decode.
+			return false, err
+		}
+		var ok = true
+		switch t := token.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				// Root element.
+				depth++
+				continue
+			}
+			switch t.Name.Local {
+			case "Contents":
+				var entry listObjectEntry
+				if err = d.DecodeElement(&entry, &t); err != nil {
+					return false, err
+				}
+				ok, err = add(listEntry{object: entry.objectInfo()})
+			case "CommonPrefixes":
+				var prefix CommonPrefix
+				if err = d.DecodeElement(&prefix, &t); err != nil {
+					return false, err
+				}
+				ok, err = add(listEntry{object: ObjectInfo{Key: prefix.Prefix, IsPrefix: true}, isPrefix: true})
+			default:
+				field := resultValue.FieldByName(t.Name.Local)
+				if !field.IsValid() || field.Kind() == reflect.Slice {
+					err = d.Skip()
+				} else {
+					err = d.DecodeElement(field.Addr().Interface(), &t)
+				}
+				if err == nil && t.Name.Local == "EncodingType" {
+					encodingKnown = true
+					ok, err = flush()
+				}
+			}
+		case xml.EndElement:
+			// End of the root element.
+			encodingKnown = true
+			if ok, err = flush(); err == nil && ok {
+				return false, decodeListFields(resultValue)
+			}
+		}
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return true, nil
+		}
+	}
+}
+
+// listEncodedFields - fields of list results which are encoded along
+// with the keys for the url encoding type.
+var listEncodedFields = []string{"Prefix", "Delimiter", "Marker", "NextMarker", "StartAfter", "KeyMarker", "NextKeyMarker"}
+
+// decodeListFields - decodes the listEncodedFields of a list result
+// with the url encoding type.
+func decodeListFields(resultValue reflect.Value) error {
+	if resultValue.FieldByName("EncodingType").String() != "url" {
+		return nil
+	}
+	for _, name := range listEncodedFields {
+		field := resultValue.FieldByName(name)
+		if !field.IsValid() || field.Kind() != reflect.String {
+			continue
+		}
+		value, err := url.QueryUnescape(field.String())
+		if err != nil {
+			return err
+		}
+		field.SetString(value)
+	}
+	return nil
+}
+
+// decodeListVersionsResult - decodes the keys of a ListObjectVersions
+// response with the url encoding type.
+func decodeListVersionsResult(result *listVersionsResult) (err error) {
+	if result.EncodingType != "url" {
+		return nil
+	}
+	for _, versions := range [][]objectVersion{result.Versions, result.DeleteMarkers} {
+		for i := range versions {
+			if versions[i].Key, err = url.QueryUnescape(versions[i].Key); err != nil {
+				return err
+			}
+		}
+	}
+	return decodeListFields(reflect.ValueOf(result).Elem())
+}
+
+// decodeListMultipartUploadsResult - decodes the keys and prefixes of a
+// ListMultipartUploads response with the url encoding type.
+func decodeListMultipartUploadsResult(result *ListMultipartUploadsResult) (err error) {
+	if result.EncodingType != "url" {
+		return nil
+	}
+	for i := range result.Uploads {
+		if result.Uploads[i].Key, err = url.QueryUnescape(result.Uploads[i].Key); err != nil {
+			return err
+		}
+	}
+	for i := range result.CommonPrefixes {
+		if result.CommonPrefixes[i].Prefix, err = url.QueryUnescape(result.CommonPrefixes[i].Prefix); err != nil {
+			return err
+		}
+	}
+	return decodeListFields(reflect.ValueOf(result).Elem())
+}
@@ -0,0 +1,204 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const listObjectsV2Response = `<?xml version="1.0" encoding="UTF-8"?>
+<ListBucketResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <Name>bucket</Name>
+  <Prefix>photos/</Prefix>
+  <KeyCount>3</KeyCount>
+  <MaxKeys>1000</MaxKeys>
+  <Delimiter>/</Delimiter>
+  <IsTruncated>true</IsTruncated>
+  <NextContinuationToken>token</NextContinuationToken>
+  <Contents>
+    <Key>photos/a.jpg</Key>
+    <LastModified>2019-01-02T03:04:05.000Z</LastModified>
+    <ETag>"etag-a"</ETag>
+    <Size>10</Size>
+    <Owner><ID>owner</ID><DisplayName>name</DisplayName></Owner>
+    <StorageClass>STANDARD</StorageClass>
+  </Contents>
+  <Contents>
+    <Key>photos/b.jpg</Key>
+    <Size>20</Size>
+  </Contents>
+  <CommonPrefixes><Prefix>photos/2019/</Prefix></CommonPrefixes>
+</ListBucketResult>`
+
+// Tests that incremental decoding matches decoding the whole response.
+func TestDecodeListResponse(t *testing.T) {
+	var expected ListBucketV2Result
+	if err := xmlDecoder(strings.NewReader(listObjectsV2Response), &expected); err != nil {
+		t.Fatal(err)
+	}
+
+	var result ListBucketV2Result
+	stopped, err := decodeListResponse(strings.NewReader(listObjectsV2Response), &result, func(object ObjectInfo, isPrefix bool) bool {
+		if isPrefix {
+			result.CommonPrefixes = append(result.CommonPrefixes, CommonPrefix{Prefix: object.Key})
+		} else {
+			result.Contents = append(result.Contents, object)
+		}
+		return true
+	})
+	if err != nil || stopped {
+		t.Fatalf("unexpected result %v, %v", stopped, err)
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("expected %+v, got %+v", expected, result)
+	}
+
+	// Version 1 responses decode into ListBucketResult.
+	var v1Result ListBucketResult
+	v1Response := strings.Replace(listObjectsV2Response, "NextContinuationToken", "NextMarker", -1)
+	if _, err = decodeListResponse(strings.NewReader(v1Response), &v1Result, func(ObjectInfo, bool) bool { return true }); err != nil {
+		t.Fatal(err)
+	}
+	if v1Result.NextMarker != "token" || !v1Result.IsTruncated || v1Result.Name != "bucket" {
+		t.Errorf("unexpected result %+v", v1Result)
+	}
+
+	// Decoding stops once fn returns false.
+	var keys []string
+	stopped, err = decodeListResponse(strings.NewReader(listObjectsV2Response), &ListBucketV2Result{}, func(object ObjectInfo, isPrefix bool) bool {
+		keys = append(keys, object.Key)
+		return false
+	})
+	if err != nil || !stopped || !reflect.DeepEqual(keys, []string{"photos/a.jpg"}) {
+		t.Errorf("expected to stop after the first object, got %v, %v, %v", keys, stopped, err)
+	}
+
+	// Empty and truncated bodies fail.
+	_, err = decodeListResponse(strings.NewReader(""), &ListBucketV2Result{}, func(ObjectInfo, bool) bool { return true })
+	if err != io.EOF {
+		t.Errorf("expected io.EOF for an empty body, got %v", err)
+	}
+	_, err = decodeListResponse(strings.NewReader(listObjectsV2Response[:len(listObjectsV2Response)/2]), &ListBucketV2Result{}, func(ObjectInfo, bool) bool { return true })
+	if err == nil {
+		t.Error("expected an error for a truncated body")
+	}
+}
+
+// Tests decoding of keys of responses with the url encoding type.
+func TestDecodeListResponseEncoded(t *testing.T) {
+	const entries = `<Contents><Key>plain</Key></Contents>
+  <Contents><Key>a%01b+c%25</Key></Contents>
+  <Contents><Key>after</Key></Contents>
+  <CommonPrefixes><Prefix>dir%2F%0A/</Prefix></CommonPrefixes>`
+	const fields = `<Prefix>p%0D</Prefix><Delimiter>%2F</Delimiter><StartAfter>s+t</StartAfter>`
+	expectedKeys := []string{"plain", "a\x01b c%", "after", "dir/\n/"}
+
+	testCases := []struct {
+		response string
+		keys     []string
+		prefix   string
+	}{
+		// Encoding type before the entries.
+		{"<ListBucketResult><EncodingType>url</EncodingType>" + fields + entries + "</ListBucketResult>", expectedKeys, "p\r"},
+		// Encoding type after the entries, as sent by MinIO.
+		{"<ListBucketResult>" + entries + fields + "<EncodingType>url</EncodingType></ListBucketResult>", expectedKeys, "p\r"},
+		// Without the encoding type keys are left as they are.
+		{"<ListBucketResult>" + fields + entries + "</ListBucketResult>", []string{"plain", "a%01b+c%25", "after", "dir%2F%0A/"}, "p%0D"},
+	}
+	for i, testCase := range testCases {
+		var result ListBucketV2Result
+		var keys []string
+		_, err := decodeListResponse(strings.NewReader(testCase.response), &result, func(object ObjectInfo, isPrefix bool) bool {
+			keys = append(keys, object.Key)
+			return true
+		})
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if !reflect.DeepEqual(keys, testCase.keys) {
+			t.Errorf("Test %d: expected keys %q, got %q", i+1, testCase.keys, keys)
+		}
+		if result.Prefix != testCase.prefix {
+			t.Errorf("Test %d: expected prefix %q, got %q", i+1, testCase.prefix, result.Prefix)
+		}
+	}
+
+	// Stopping while entries are held back.
+	var keys []string
+	response := "<ListBucketResult>" + entries + "<EncodingType>url</EncodingType></ListBucketResult>"
+	stopped, err := decodeListResponse(strings.NewReader(response), &ListBucketV2Result{}, func(object ObjectInfo, isPrefix bool) bool {
+		keys = append(keys, object.Key)
+		return len(keys) < 2
+	})
+	if err != nil || !stopped || !reflect.DeepEqual(keys, expectedKeys[:2]) {
+		t.Errorf("expected to stop after two entries, got %q, %v, %v", keys, stopped, err)
+	}
+
+	// Invalid escapes fail.
+	response = "<ListBucketResult><EncodingType>url</EncodingType><Contents><Key>%zz</Key></Contents></ListBucketResult>"
+	if _, err = decodeListResponse(strings.NewReader(response), &ListBucketV2Result{}, func(ObjectInfo, bool) bool { return true }); err == nil {
+		t.Error("expected an error for an invalid escape")
+	}
+
+	// ListMultipartUploads responses.
+	uploads := ListMultipartUploadsResult{
+		EncodingType:   "url",
+		NextKeyMarker:  "n%01",
+		Uploads:        []ObjectMultipartInfo{{Key: "k%02"}},
+		CommonPrefixes: []CommonPrefix{{Prefix: "c%03/"}},
+	}
+	if err = decodeListMultipartUploadsResult(&uploads); err != nil {
+		t.Fatal(err)
+	}
+	if uploads.NextKeyMarker != "n\x01" || uploads.Uploads[0].Key != "k\x02" || uploads.CommonPrefixes[0].Prefix != "c\x03/" {
+		t.Errorf("unexpected result %+v", uploads)
+	}
+}
+
+// Tests decoding the metadata MinIO lists along with the objects.
+func TestDecodeListResponseMetadata(t *testing.T) {
+	response := `<ListBucketResult><Name>bucket</Name>` +
+		`<Contents><Key>a.jpg</Key><Size>10</Size><UserMetadata>` +
+		`<X-Amz-Meta-Camera>x100</X-Amz-Meta-Camera><content-type>image/jpeg</content-type>` +
+		`</UserMetadata></Contents>` +
+		`<Contents><Key>b.jpg</Key><Size>20</Size></Contents></ListBucketResult>`
+
+	var objects []ObjectInfo
+	var result ListBucketV2Result
+	if _, err := decodeListResponse(strings.NewReader(response), &result, func(object ObjectInfo, isPrefix bool) bool {
+		objects = append(objects, object)
+		return true
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 2 {
+		t.Fatalf("expected 2 objects, got %d", len(objects))
+	}
+	if expected := (UserMetadata{"Camera": "x100"}); !reflect.DeepEqual(objects[0].UserMetadata, expected) {
+		t.Errorf("expected user metadata %v, got %v", expected, objects[0].UserMetadata)
+	}
+	if objects[0].Size != 10 || objects[0].Metadata.Get("Content-Type") != "image/jpeg" {
+		t.Errorf("unexpected object %+v", objects[0])
+	}
+	if objects[1].UserMetadata != nil || objects[1].Metadata != nil {
+		t.Errorf("expected no metadata, got %+v", objects[1])
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 
 	"github.com/minio/minio-go/v6/pkg/s3utils"
 )
@@ -39,8 +40,27 @@ import (
 //   }
 //
 func (c Client) ListBuckets() ([]BucketInfo, error) {
+	return c.ListBucketsWithOptions(context.Background(), ListBucketsOptions{})
+}
+
+// ListBucketsOptions represents options specified by user for
+// ListBucketsWithOptions call.
+type ListBucketsOptions struct {
+	// Region resolves the region of every bucket, which populates
+	// the bucket location cache as well.
+	Region bool
+
+	// Number of regions resolved concurrently, defaults to 4.
+	NumWorkers int
+}
+
+// ListBucketsWithOptions - lists all buckets like ListBuckets, the
+// regions of the buckets are resolved concurrently if opts.Region is
+// set. Clients with a region configured report that region, unless
+// the server sends the regions along with the listing.
+func (c Client) ListBucketsWithOptions(ctx context.Context, opts ListBucketsOptions) ([]BucketInfo, error) {
 	// Execute GET on service.
-	resp, err := c.executeMethod(context.Background(), "GET", requestMetadata{contentSHA256Hex: emptySHA256Hex})
+	resp, err := c.executeMethod(ctx, "GET", requestMetadata{contentSHA256Hex: emptySHA256Hex})
 	defer closeResponse(resp)
 	if err != nil {
 		return nil, err
@@ -55,7 +75,63 @@ func (c Client) ListBuckets() ([]BucketInfo, error) {
 	if err != nil {
 		return nil, err
 	}
-	return listAllMyBucketsResult.Buckets.Bucket, nil
+	buckets := listAllMyBucketsResult.Buckets.Bucket
+	if !opts.Region {
+		return buckets, nil
+	}
+	if err = c.resolveBucketRegions(ctx, buckets, opts.NumWorkers); err != nil {
+		return nil, err
+	}
+	return buckets, nil
+}
+
+// resolveBucketRegions - sets the region of all buckets, looking them
+// up with numWorkers concurrent requests. Regions sent along with the
+// bucket listing are only cached.
+func (c Client) resolveBucketRegions(ctx context.Context, buckets []BucketInfo, numWorkers int) error {
+	if numWorkers <= 0 {
+		numWorkers = totalWorkers
+	}
+	errs := make([]error, len(buckets))
+	indexCh := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexCh {
+				bucket := &buckets[i]
+				if bucket.Region != "" {
+					if c.region == "" {
+						c.bucketLocCache.Set(bucket.Name, bucket.Region)
+					}
+					continue
+				}
+				bucket.Region, errs[i] = c.getBucketLocation(bucket.Name)
+			}
+		}()
+	}
+	for i := range buckets {
+		select {
+		case indexCh <- i:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(indexCh)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 /// Bucket Read Operations.
@@ -81,20 +157,108 @@ func (c Client) ListBuckets() ([]BucketInfo, error) {
 //   }
 //
 func (c Client) ListObjectsV2(bucketName, objectPrefix string, recursive bool, doneCh <-chan struct{}) <-chan ObjectInfo {
+	return c.ListObjectsV2WithOptions(bucketName, ListObjectsOptions{Prefix: objectPrefix, Recursive: recursive}, doneCh)
+}
+
+// ListObjectsOptions holds all options of a list objects request.
+type ListObjectsOptions struct {
+	// Only list objects whose names begin with Prefix.
+	Prefix string
+
+	// List all objects under the prefix instead of delimiting the
+	// listing at '/'.
+	Recursive bool
+
+	// Delimiter of a listing which is not recursive, defaults to
+	// '/'. The names of the objects up to the first delimiter after
+	// the prefix are listed once, as a common prefix with IsPrefix set,
+	// e.g. to list the "directories" of a file browser.
+	Delimiter string
+
+	// Extract lists the files inside a zip archive stored as an
+	// object, with a prefix naming the archive followed by '/', e.g.
+	// "archive.zip/". This is an extension supported by MinIO server.
+	Extract bool
+
+	// Start listing after this object name.
+	StartAfter string
+
+	// Resume a listing at the NextContinuationToken of a page
+	// returned by ListObjectsV2Page, takes precedence over StartAfter.
+	ContinuationToken string
+
+	// Maximum number of entries of a page returned by
+	// ListObjectsV2Page, defaults to and is at most 1000.
+	MaxKeys int
+
+	// WithMetadata lists the objects along with their metadata,
+	// saving a StatObject per object. This is an extension supported
+	// by MinIO server, other services list the objects without.
+	WithMetadata bool
+}
+
+// delimiter - returns the delimiter of the listing, empty if the
+// listing is recursive.
+func (o ListObjectsOptions) delimiter() string {
+	if o.Recursive {
+		return ""
+	}
+	if o.Delimiter != "" {
+		return o.Delimiter
+	}
+	return "/"
+}
+
+// validateDirectoryBucketListing - checks that a listing of an S3
+// Express directory bucket meets their restrictions: prefixes end in
+// '/', the only delimiter, and listings cannot start after an object
+// name since objects are not listed in lexical order.
+func (c Client) validateDirectoryBucketListing(bucketName string, opts ListObjectsOptions) error {
+	if !c.isS3ExpressBucket(bucketName) {
+		return nil
+	}
+	if delimiter := opts.delimiter(); delimiter != "" && delimiter != "/" {
+		return ErrInvalidArgument("Listings of directory bucket " + bucketName + " can only be delimited at '/'.")
+	}
+	if opts.Prefix != "" && !strings.HasSuffix(opts.Prefix, "/") {
+		return ErrInvalidArgument("Prefixes of listings of directory bucket " + bucketName + " must end with '/'.")
+	}
+	if opts.StartAfter != "" {
+		return ErrInvalidArgument("Listings of directory bucket " + bucketName + " cannot start after an object name, resume them with a continuation token instead.")
+	}
+	return nil
+}
+
+// ListObjectsV2WithOptions - identical to ListObjectsV2 call, but
+// accepts the options of the listing.
+func (c Client) ListObjectsV2WithOptions(bucketName string, opts ListObjectsOptions, doneCh <-chan struct{}) <-chan ObjectInfo {
+	objectPrefix, delimiter := opts.Prefix, opts.delimiter()
+
+	// Services which do not fully support version 2 of the
+	// listing are listed with version 1.
+	if c.profile.listObjectsV1 && !opts.Extract && !c.isS3ExpressBucket(bucketName) {
+		marker := opts.StartAfter
+		if opts.ContinuationToken != "" {
+			marker = opts.ContinuationToken
+		}
+		return c.listObjects(bucketName, objectPrefix, delimiter, marker, doneCh)
+	}
+
 	// Allocate new list objects channel.
 	objectStatCh := make(chan ObjectInfo, 1)
-	// Default listing is delimited at "/"
-	delimiter := "/"
-	if recursive {
-		// If recursive we do not delimit.
-		delimiter = ""
-	}
 
 	// Return object owner information by default
 	fetchOwner := true
 
+	// Headers sent with all requests.
+	var headers http.Header
+	if opts.Extract {
+		headers = make(http.Header)
+		headers.Set(minIOExtract, "true")
+	}
+
 	// Validate bucket name.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		defer close(objectStatCh)
 		objectStatCh <- ObjectInfo{
 			Err: err,
@@ -111,45 +275,43 @@ func (c Client) ListObjectsV2(bucketName, objectPrefix string, recursive bool, d
 		return objectStatCh
 	}
 
+	// Directory buckets only support some listings.
+	if err := c.validateDirectoryBucketListing(bucketName, opts); err != nil {
+		defer close(objectStatCh)
+		objectStatCh <- ObjectInfo{
+			Err: err,
+		}
+		return objectStatCh
+	}
+
 	// Initiate list objects goroutine here.
 	go func(objectStatCh chan<- ObjectInfo) {
 		defer close(objectStatCh)
 		// Save continuationToken for next request.
-		var continuationToken string
+		continuationToken := opts.ContinuationToken
 		for {
-			// Get list of objects a maximum of 1000 per request.
-			result, err := c.listObjectsV2Query(bucketName, objectPrefix, continuationToken, fetchOwner, delimiter, 1000, "")
+			// Get list of objects a maximum of 1000 per request, objects
+			// and common prefixes are sent as soon as they are decoded.
+			// NOTE: prefixes are only present if the request is delimited.
+			result, stopped, err := c.listObjectsV2Stream(bucketName, objectPrefix, continuationToken, fetchOwner, delimiter, 1000, opts.StartAfter, opts.WithMetadata, headers,
+				func(object ObjectInfo, isPrefix bool) bool {
+					select {
+					// Send object content or prefix.
+					case objectStatCh <- object:
+						return true
+					// If receives done from the caller, return here.
+					case <-doneCh:
+						return false
+					}
+				})
 			if err != nil {
 				objectStatCh <- ObjectInfo{
 					Err: err,
 				}
 				return
 			}
-
-			// If contents are available loop through and send over channel.
-			for _, object := range result.Contents {
-				select {
-				// Send object content.
-				case objectStatCh <- object:
-				// If receives done from the caller, return here.
-				case <-doneCh:
-					return
-				}
-			}
-
-			// Send all common prefixes if any.
-			// NOTE: prefixes are only present if the request is delimited.
-			for _, obj := range result.CommonPrefixes {
-				select {
-				// Send object prefixes.
-				case objectStatCh <- ObjectInfo{
-					Key:  obj.Prefix,
-					Size: 0,
-				}:
-				// If receives done from the caller, return here.
-				case <-doneCh:
-					return
-				}
+			if stopped {
+				return
 			}
 
 			// If continuation token present, save it for next request.
@@ -166,6 +328,61 @@ func (c Client) ListObjectsV2(bucketName, objectPrefix string, recursive bool, d
 	return objectStatCh
 }
 
+// ListObjectsV2Page - lists a single page of up to opts.MaxKeys objects
+// and common prefixes, starting at opts.ContinuationToken or after
+// opts.StartAfter. Unless the listing is complete, the
+// NextContinuationToken of the page resumes the listing with the next
+// page, so that a listing can be checkpointed and resumed later, e.g.
+// after a restart of the process.
+func (c Client) ListObjectsV2Page(bucketName string, opts ListObjectsOptions) (ListBucketV2Result, error) {
+	delimiter := opts.delimiter()
+
+	// Directory buckets only support some listings.
+	if err := c.validateDirectoryBucketListing(bucketName, opts); err != nil {
+		return ListBucketV2Result{}, err
+	}
+
+	// Services which do not fully support version 2 of the
+	// listing are listed with version 1, the marker of the next
+	// page is used as continuation token.
+	if c.profile.listObjectsV1 && !opts.Extract && !c.isS3ExpressBucket(bucketName) {
+		marker := opts.StartAfter
+		if opts.ContinuationToken != "" {
+			marker = opts.ContinuationToken
+		}
+		result, err := c.listObjectsQuery(bucketName, opts.Prefix, marker, delimiter, opts.MaxKeys)
+		if err != nil {
+			return ListBucketV2Result{}, err
+		}
+		page := ListBucketV2Result{
+			CommonPrefixes:    result.CommonPrefixes,
+			Contents:          result.Contents,
+			Delimiter:         result.Delimiter,
+			EncodingType:      result.EncodingType,
+			IsTruncated:       result.IsTruncated,
+			MaxKeys:           result.MaxKeys,
+			Name:              result.Name,
+			ContinuationToken: opts.ContinuationToken,
+			Prefix:            result.Prefix,
+			StartAfter:        opts.StartAfter,
+		}
+		if result.IsTruncated {
+			page.NextContinuationToken = result.NextMarker
+			if page.NextContinuationToken == "" && len(result.Contents) > 0 {
+				page.NextContinuationToken = result.Contents[len(result.Contents)-1].Key
+			}
+		}
+		return page, nil
+	}
+
+	var headers http.Header
+	if opts.Extract {
+		headers = make(http.Header)
+		headers.Set(minIOExtract, "true")
+	}
+	return c.listObjectsV2Query(bucketName, opts.Prefix, opts.ContinuationToken, true, delimiter, opts.MaxKeys, opts.StartAfter, opts.WithMetadata, headers)
+}
+
 // listObjectsV2Query - (List Objects V2) - List some or all (up to 1000) of the objects in a bucket.
 //
 // You can use the request parameters as selection criteria to return a subset of the objects in a bucket.
@@ -176,14 +393,39 @@ func (c Client) ListObjectsV2(bucketName, objectPrefix string, recursive bool, d
 // ?prefix - Limits the response to keys that begin with the specified prefix.
 // ?max-keys - Sets the maximum number of keys returned in the response body.
 // ?start-after - Specifies the key to start after when listing objects in a bucket.
-func (c Client) listObjectsV2Query(bucketName, objectPrefix, continuationToken string, fetchOwner bool, delimiter string, maxkeys int, startAfter string) (ListBucketV2Result, error) {
+// ?metadata - Lists the metadata of the objects, a MinIO extension.
+//
+// headers are sent with the request, if any.
+func (c Client) listObjectsV2Query(bucketName, objectPrefix, continuationToken string, fetchOwner bool, delimiter string, maxkeys int, startAfter string, metadata bool, headers http.Header) (ListBucketV2Result, error) {
+	var contents []ObjectInfo
+	var commonPrefixes []CommonPrefix
+	listBucketResult, _, err := c.listObjectsV2Stream(bucketName, objectPrefix, continuationToken, fetchOwner, delimiter, maxkeys, startAfter, metadata, headers,
+		func(object ObjectInfo, isPrefix bool) bool {
+			if isPrefix {
+				commonPrefixes = append(commonPrefixes, CommonPrefix{Prefix: object.Key})
+			} else {
+				contents = append(contents, object)
+			}
+			return true
+		})
+	listBucketResult.Contents = contents
+	listBucketResult.CommonPrefixes = commonPrefixes
+	return listBucketResult, err
+}
+
+// listObjectsV2Stream - identical to listObjectsV2Query, but passes the
+// objects and common prefixes of the response to fn as soon as they are
+// decoded instead of returning them with the result. Returns stopped
+// true if fn returned false.
+func (c Client) listObjectsV2Stream(bucketName, objectPrefix, continuationToken string, fetchOwner bool, delimiter string, maxkeys int, startAfter string, metadata bool, headers http.Header,
+	fn listEntryFunc) (result ListBucketV2Result, stopped bool, err error) {
 	// Validate bucket name.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
-		return ListBucketV2Result{}, err
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return ListBucketV2Result{}, false, err
 	}
 	// Validate object prefix.
 	if err := s3utils.CheckValidObjectNamePrefix(objectPrefix); err != nil {
-		return ListBucketV2Result{}, err
+		return ListBucketV2Result{}, false, err
 	}
 	// Get resources properly escaped and lined up before
 	// using them in http request.
@@ -215,41 +457,50 @@ func (c Client) listObjectsV2Query(bucketName, objectPrefix, continuationToken s
 	// Set max keys.
 	urlValues.Set("max-keys", fmt.Sprintf("%d", maxkeys))
 
+	// Have keys url encoded, keys may contain characters
+	// which are not valid in XML.
+	urlValues.Set("encoding-type", "url")
+
 	// Set start-after
 	if startAfter != "" {
 		urlValues.Set("start-after", startAfter)
 	}
 
+	// Ask MinIO to list the metadata of the objects.
+	if metadata {
+		urlValues.Set("metadata", "true")
+	}
+
 	// Execute GET on bucket to list objects.
 	resp, err := c.executeMethod(context.Background(), "GET", requestMetadata{
 		bucketName:       bucketName,
 		queryValues:      urlValues,
+		customHeader:     headers,
 		contentSHA256Hex: emptySHA256Hex,
 	})
 	defer closeResponse(resp)
 	if err != nil {
-		return ListBucketV2Result{}, err
+		return ListBucketV2Result{}, false, err
 	}
 	if resp != nil {
 		if resp.StatusCode != http.StatusOK {
-			return ListBucketV2Result{}, httpRespToErrorResponse(resp, bucketName, "")
+			return ListBucketV2Result{}, false, httpRespToErrorResponse(resp, bucketName, "")
 		}
 	}
 
 	// Decode listBuckets XML.
-	listBucketResult := ListBucketV2Result{}
-	if err = xmlDecoder(resp.Body, &listBucketResult); err != nil {
-		return listBucketResult, err
+	if stopped, err = decodeListResponse(resp.Body, &result, fn); err != nil || stopped {
+		return result, stopped, err
 	}
 
 	// This is an additional verification check to make
 	// sure proper responses are received.
-	if listBucketResult.IsTruncated && listBucketResult.NextContinuationToken == "" {
-		return listBucketResult, errors.New("Truncated response should have continuation token set")
+	if result.IsTruncated && result.NextContinuationToken == "" {
+		return result, false, errors.New("Truncated response should have continuation token set")
 	}
 
 	// Success.
-	return listBucketResult, nil
+	return result, false, nil
 }
 
 // ListObjects - (List Objects) - List some objects or all recursively.
@@ -275,16 +526,28 @@ func (c Client) listObjectsV2Query(bucketName, objectPrefix, continuationToken s
 //   }
 //
 func (c Client) ListObjects(bucketName, objectPrefix string, recursive bool, doneCh <-chan struct{}) <-chan ObjectInfo {
-	// Allocate new list objects channel.
-	objectStatCh := make(chan ObjectInfo, 1)
+	// Directory buckets are only listed with version 2 of the
+	// listing.
+	if c.isS3ExpressBucket(bucketName) {
+		return c.ListObjectsV2WithOptions(bucketName, ListObjectsOptions{Prefix: objectPrefix, Recursive: recursive}, doneCh)
+	}
+
 	// Default listing is delimited at "/"
 	delimiter := "/"
 	if recursive {
 		// If recursive we do not delimit.
 		delimiter = ""
 	}
+	return c.listObjects(bucketName, objectPrefix, delimiter, "", doneCh)
+}
+
+// listObjects - identical to ListObjects, but delimits the listing at
+// delimiter and lists the objects after the given marker only.
+func (c Client) listObjects(bucketName, objectPrefix, delimiter, marker string, doneCh <-chan struct{}) <-chan ObjectInfo {
+	// Allocate new list objects channel.
+	objectStatCh := make(chan ObjectInfo, 1)
 	// Validate bucket name.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		defer close(objectStatCh)
 		objectStatCh <- ObjectInfo{
 			Err: err,
@@ -304,43 +567,33 @@ func (c Client) ListObjects(bucketName, objectPrefix string, recursive bool, don
 	go func(objectStatCh chan<- ObjectInfo) {
 		defer close(objectStatCh)
 		// Save marker for next request.
-		var marker string
 		for {
-			// Get list of objects a maximum of 1000 per request.
-			result, err := c.listObjectsQuery(bucketName, objectPrefix, marker, delimiter, 1000)
+			// Get list of objects a maximum of 1000 per request, objects
+			// and common prefixes are sent as soon as they are decoded.
+			// NOTE: prefixes are only present if the request is delimited.
+			result, stopped, err := c.listObjectsStream(bucketName, objectPrefix, marker, delimiter, 1000,
+				func(object ObjectInfo, isPrefix bool) bool {
+					// Save the marker.
+					if !isPrefix {
+						marker = object.Key
+					}
+					select {
+					// Send object content or prefix.
+					case objectStatCh <- object:
+						return true
+					// If receives done from the caller, return here.
+					case <-doneCh:
+						return false
+					}
+				})
 			if err != nil {
 				objectStatCh <- ObjectInfo{
 					Err: err,
 				}
 				return
 			}
-
-			// If contents are available loop through and send over channel.
-			for _, object := range result.Contents {
-				// Save the marker.
-				marker = object.Key
-				select {
-				// Send object content.
-				case objectStatCh <- object:
-				// If receives done from the caller, return here.
-				case <-doneCh:
-					return
-				}
-			}
-
-			// Send all common prefixes if any.
-			// NOTE: prefixes are only present if the request is delimited.
-			for _, obj := range result.CommonPrefixes {
-				object := ObjectInfo{}
-				object.Key = obj.Prefix
-				object.Size = 0
-				select {
-				// Send object prefixes.
-				case objectStatCh <- object:
-				// If receives done from the caller, return here.
-				case <-doneCh:
-					return
-				}
+			if stopped {
+				return
 			}
 
 			// If next marker present, save it for next request.
@@ -367,13 +620,35 @@ func (c Client) ListObjects(bucketName, objectPrefix string, recursive bool, don
 // ?prefix - Limits the response to keys that begin with the specified prefix.
 // ?max-keys - Sets the maximum number of keys returned in the response body.
 func (c Client) listObjectsQuery(bucketName, objectPrefix, objectMarker, delimiter string, maxkeys int) (ListBucketResult, error) {
+	var contents []ObjectInfo
+	var commonPrefixes []CommonPrefix
+	listBucketResult, _, err := c.listObjectsStream(bucketName, objectPrefix, objectMarker, delimiter, maxkeys,
+		func(object ObjectInfo, isPrefix bool) bool {
+			if isPrefix {
+				commonPrefixes = append(commonPrefixes, CommonPrefix{Prefix: object.Key})
+			} else {
+				contents = append(contents, object)
+			}
+			return true
+		})
+	listBucketResult.Contents = contents
+	listBucketResult.CommonPrefixes = commonPrefixes
+	return listBucketResult, err
+}
+
+// listObjectsStream - identical to listObjectsQuery, but passes the
+// objects and common prefixes of the response to fn as soon as they are
+// decoded instead of returning them with the result. Returns stopped
+// true if fn returned false.
+func (c Client) listObjectsStream(bucketName, objectPrefix, objectMarker, delimiter string, maxkeys int,
+	fn listEntryFunc) (result ListBucketResult, stopped bool, err error) {
 	// Validate bucket name.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
-		return ListBucketResult{}, err
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return ListBucketResult{}, false, err
 	}
 	// Validate object prefix.
 	if err := s3utils.CheckValidObjectNamePrefix(objectPrefix); err != nil {
-		return ListBucketResult{}, err
+		return ListBucketResult{}, false, err
 	}
 	// Get resources properly escaped and lined up before
 	// using them in http request.
@@ -397,6 +672,10 @@ func (c Client) listObjectsQuery(bucketName, objectPrefix, objectMarker, delimit
 	// Set max keys.
 	urlValues.Set("max-keys", fmt.Sprintf("%d", maxkeys))
 
+	// Have keys url encoded, keys may contain characters
+	// which are not valid in XML.
+	urlValues.Set("encoding-type", "url")
+
 	// Execute GET on bucket to list objects.
 	resp, err := c.executeMethod(context.Background(), "GET", requestMetadata{
 		bucketName:       bucketName,
@@ -405,20 +684,16 @@ func (c Client) listObjectsQuery(bucketName, objectPrefix, objectMarker, delimit
 	})
 	defer closeResponse(resp)
 	if err != nil {
-		return ListBucketResult{}, err
+		return ListBucketResult{}, false, err
 	}
 	if resp != nil {
 		if resp.StatusCode != http.StatusOK {
-			return ListBucketResult{}, httpRespToErrorResponse(resp, bucketName, "")
+			return ListBucketResult{}, false, httpRespToErrorResponse(resp, bucketName, "")
 		}
 	}
 	// Decode listBuckets XML.
-	listBucketResult := ListBucketResult{}
-	err = xmlDecoder(resp.Body, &listBucketResult)
-	if err != nil {
-		return listBucketResult, err
-	}
-	return listBucketResult, nil
+	stopped, err = decodeListResponse(resp.Body, &result, fn)
+	return result, stopped, err
 }
 
 // ListIncompleteUploads - List incompletely uploaded multipart objects.
@@ -445,11 +720,11 @@ func (c Client) listObjectsQuery(bucketName, objectPrefix, objectMarker, delimit
 func (c Client) ListIncompleteUploads(bucketName, objectPrefix string, recursive bool, doneCh <-chan struct{}) <-chan ObjectMultipartInfo {
 	// Turn on size aggregation of individual parts.
 	isAggregateSize := true
-	return c.listIncompleteUploads(bucketName, objectPrefix, recursive, isAggregateSize, doneCh)
+	return c.listIncompleteUploads(context.Background(), bucketName, objectPrefix, recursive, isAggregateSize, doneCh)
 }
 
 // listIncompleteUploads lists all incomplete uploads.
-func (c Client) listIncompleteUploads(bucketName, objectPrefix string, recursive, aggregateSize bool, doneCh <-chan struct{}) <-chan ObjectMultipartInfo {
+func (c Client) listIncompleteUploads(ctx context.Context, bucketName, objectPrefix string, recursive, aggregateSize bool, doneCh <-chan struct{}) <-chan ObjectMultipartInfo {
 	// Allocate channel for multipart uploads.
 	objectMultipartStatCh := make(chan ObjectMultipartInfo, 1)
 	// Delimiter is set to "/" by default.
@@ -459,7 +734,7 @@ func (c Client) listIncompleteUploads(bucketName, objectPrefix string, recursive
 		delimiter = ""
 	}
 	// Validate bucket name.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		defer close(objectMultipartStatCh)
 		objectMultipartStatCh <- ObjectMultipartInfo{
 			Err: err,
@@ -481,7 +756,7 @@ func (c Client) listIncompleteUploads(bucketName, objectPrefix string, recursive
 		var uploadIDMarker string
 		for {
 			// list all multipart uploads.
-			result, err := c.listMultipartUploadsQuery(bucketName, objectMarker, uploadIDMarker, objectPrefix, delimiter, 1000)
+			result, err := c.listMultipartUploadsQuery(ctx, bucketName, objectMarker, uploadIDMarker, objectPrefix, delimiter, 1000)
 			if err != nil {
 				objectMultipartStatCh <- ObjectMultipartInfo{
 					Err: err,
@@ -547,7 +822,7 @@ func (c Client) listIncompleteUploads(bucketName, objectPrefix string, recursive
 // ?delimiter - A delimiter is a character you use to group keys.
 // ?prefix - Limits the response to keys that begin with the specified prefix.
 // ?max-uploads - Sets the maximum number of multipart uploads returned in the response body.
-func (c Client) listMultipartUploadsQuery(bucketName, keyMarker, uploadIDMarker, prefix, delimiter string, maxUploads int) (ListMultipartUploadsResult, error) {
+func (c Client) listMultipartUploadsQuery(ctx context.Context, bucketName, keyMarker, uploadIDMarker, prefix, delimiter string, maxUploads int) (ListMultipartUploadsResult, error) {
 	// Get resources properly escaped and lined up before using them in http request.
 	urlValues := make(url.Values)
 	// Set uploads.
@@ -574,8 +849,12 @@ func (c Client) listMultipartUploadsQuery(bucketName, keyMarker, uploadIDMarker,
 	// Set max-uploads.
 	urlValues.Set("max-uploads", fmt.Sprintf("%d", maxUploads))
 
+	// Have keys url encoded, keys may contain characters
+	// which are not valid in XML.
+	urlValues.Set("encoding-type", "url")
+
 	// Execute GET on bucketName to list multipart uploads.
-	resp, err := c.executeMethod(context.Background(), "GET", requestMetadata{
+	resp, err := c.executeMethod(ctx, "GET", requestMetadata{
 		bucketName:       bucketName,
 		queryValues:      urlValues,
 		contentSHA256Hex: emptySHA256Hex,
@@ -595,9 +874,68 @@ func (c Client) listMultipartUploadsQuery(bucketName, keyMarker, uploadIDMarker,
 	if err != nil {
 		return listMultipartUploadsResult, err
 	}
+	if err = decodeListMultipartUploadsResult(&listMultipartUploadsResult); err != nil {
+		return listMultipartUploadsResult, err
+	}
 	return listMultipartUploadsResult, nil
 }
 
+// listObjectVersionsQuery - (List Object Versions) - List all the
+// versions and delete markers of the objects of a bucket.
+//
+// You can use the request parameters as selection criteria to return
+// a subset of the versions in a bucket.
+// request parameters :-
+// ---------
+// ?key-marker - Specifies the key to start with when listing versions.
+// ?version-id-marker - Specifies the version to start with, within
+// the versions of the key marker.
+func (c Client) listObjectVersionsQuery(ctx context.Context, bucketName, keyMarker, versionIDMarker string) (listVersionsResult, error) {
+	// Get resources properly escaped and lined up before using them in http request.
+	urlValues := make(url.Values)
+	// Set versions.
+	urlValues.Set("versions", "")
+	// Set object key marker.
+	if keyMarker != "" {
+		urlValues.Set("key-marker", keyMarker)
+	}
+	// Set version id marker.
+	if versionIDMarker != "" {
+		urlValues.Set("version-id-marker", versionIDMarker)
+	}
+	// Set max-keys.
+	urlValues.Set("max-keys", "1000")
+
+	// Have keys url encoded, keys may contain characters
+	// which are not valid in XML.
+	urlValues.Set("encoding-type", "url")
+
+	// Execute GET on bucketName to list object versions.
+	resp, err := c.executeMethod(ctx, "GET", requestMetadata{
+		bucketName:       bucketName,
+		queryValues:      urlValues,
+		contentSHA256Hex: emptySHA256Hex,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return listVersionsResult{}, err
+	}
+	if resp != nil {
+		if resp.StatusCode != http.StatusOK {
+			return listVersionsResult{}, httpRespToErrorResponse(resp, bucketName, "")
+		}
+	}
+	// Decode response body.
+	result := listVersionsResult{}
+	if err = xmlDecoder(resp.Body, &result); err != nil {
+		return result, err
+	}
+	if err = decodeListVersionsResult(&result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
 // listObjectParts list all object parts recursively.
 func (c Client) listObjectParts(bucketName, objectName, uploadID string) (partsInfo map[int]ObjectPart, err error) {
 	// Part number marker for the next batch of request.
@@ -616,12 +954,27 @@ func (c Client) listObjectParts(bucketName, objectName, uploadID string) (partsI
 			part.ETag = strings.TrimSuffix(part.ETag, "\"")
 			partsInfo[part.PartNumber] = part
 		}
-		// Keep part number marker, for the next iteration.
-		nextPartNumberMarker = listObjPartsResult.NextPartNumberMarker
+		// Keep part number marker, for the next iteration. Older
+		// gateways may not send the marker, continue after the
+		// last part listed then.
+		marker := listObjPartsResult.NextPartNumberMarker
+		if marker <= nextPartNumberMarker {
+			for _, part := range listObjPartsResult.ObjectParts {
+				if part.PartNumber > marker {
+					marker = part.PartNumber
+				}
+			}
+		}
 		// Listing ends result is not truncated, return right here.
 		if !listObjPartsResult.IsTruncated {
 			break
 		}
+		// This is an additional verification check to make
+		// sure proper responses are received.
+		if marker <= nextPartNumberMarker {
+			return nil, errors.New("Truncated response should have next part number marker set")
+		}
+		nextPartNumberMarker = marker
 	}
 
 	// Return all the parts.
@@ -639,7 +992,7 @@ func (c Client) findUploadIDs(bucketName, objectName string) ([]string, error) {
 	doneCh := make(chan struct{})
 	defer close(doneCh)
 	// List all incomplete uploads.
-	for mpUpload := range c.listIncompleteUploads(bucketName, objectName, isRecursive, isAggregateSize, doneCh) {
+	for mpUpload := range c.listIncompleteUploads(context.Background(), bucketName, objectName, isRecursive, isAggregateSize, doneCh) {
 		if mpUpload.Err != nil {
 			return nil, mpUpload.Err
 		}
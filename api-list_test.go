@@ -0,0 +1,354 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/minio/minio-go/v6/pkg/credentials"
+)
+
+// Tests listing and reading the files of a zip archive with Extract.
+func TestExtractZipMembers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Minio-Extract") != "true" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch {
+		case r.URL.Path == "/bucket/" && r.URL.Query().Get("prefix") == "archive.zip/":
+			fmt.Fprint(w, `<ListBucketResult><Name>bucket</Name><Prefix>archive.zip/</Prefix>`+
+				`<Contents><Key>archive.zip/a.txt</Key><Size>1</Size></Contents>`+
+				`<Contents><Key>archive.zip/b.txt</Key><Size>2</Size></Contents>`+
+				`<CommonPrefixes><Prefix>archive.zip/dir/</Prefix></CommonPrefixes></ListBucketResult>`)
+		case r.URL.Path == "/bucket/archive.zip/b.txt":
+			w.Header().Set("Content-Length", "2")
+			w.Header().Set("ETag", `"etag"`)
+			w.Header().Set("Last-Modified", "Wed, 02 Jan 2019 03:04:05 GMT")
+			w.Write([]byte("bb"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	var keys []string
+	for object := range c.ListObjectsV2WithOptions("bucket", ListObjectsOptions{Prefix: "archive.zip/", Extract: true}, doneCh) {
+		if object.Err != nil {
+			t.Fatal(object.Err)
+		}
+		keys = append(keys, object.Key)
+	}
+	expected := []string{"archive.zip/a.txt", "archive.zip/b.txt", "archive.zip/dir/"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected %v, got %v", expected, keys)
+	}
+
+	obj, err := c.GetObject("bucket", "archive.zip/b.txt", GetObjectOptions{Extract: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer obj.Close()
+	if data, err := ioutil.ReadAll(obj); err != nil || string(data) != "bb" {
+		t.Errorf("expected %q, got %q, %v", "bb", data, err)
+	}
+
+	// Without Extract the server does not look inside archives.
+	for object := range c.ListObjectsV2("bucket", "archive.zip/", false, doneCh) {
+		if !IsNotFound(object.Err) {
+			t.Errorf("expected a not found error, got %v", object.Err)
+		}
+	}
+}
+
+// Tests resolving the regions of the listed buckets.
+func TestListBucketsRegion(t *testing.T) {
+	var lookups int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["location"]; ok {
+			atomic.AddInt32(&lookups, 1)
+			location := ""
+			if r.URL.Path == "/eu-bucket/" {
+				location = "eu-west-1"
+			}
+			fmt.Fprintf(w, `<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">%s</LocationConstraint>`, location)
+			return
+		}
+		fmt.Fprint(w, `<ListAllMyBucketsResult><Buckets>`+
+			`<Bucket><Name>us-bucket</Name><CreationDate>2020-01-02T03:04:05.000Z</CreationDate></Bucket>`+
+			`<Bucket><Name>eu-bucket</Name><CreationDate>2020-01-02T03:04:05.000Z</CreationDate></Bucket>`+
+			`<Bucket><Name>ap-bucket</Name><CreationDate>2020-01-02T03:04:05.000Z</CreationDate><BucketRegion>ap-south-1</BucketRegion></Bucket>`+
+			`</Buckets></ListAllMyBucketsResult>`)
+	}))
+	defer server.Close()
+
+	c, err := New(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buckets, err := c.ListBuckets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buckets) != 3 || buckets[0].Name != "us-bucket" || buckets[0].CreationDate.Year() != 2020 || buckets[0].Region != "" {
+		t.Fatalf("unexpected buckets %+v", buckets)
+	}
+	if lookups != 0 {
+		t.Errorf("expected no region lookups, got %d", lookups)
+	}
+
+	buckets, err = c.ListBucketsWithOptions(context.Background(), ListBucketsOptions{Region: true, NumWorkers: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var regions []string
+	for _, bucket := range buckets {
+		regions = append(regions, bucket.Region)
+	}
+	if expected := []string{"us-east-1", "eu-west-1", "ap-south-1"}; !reflect.DeepEqual(regions, expected) {
+		t.Errorf("expected regions %v, got %v", expected, regions)
+	}
+	if lookups != 2 {
+		t.Errorf("expected 2 region lookups, got %d", lookups)
+	}
+	for _, bucket := range buckets {
+		if location, ok := c.bucketLocCache.Get(bucket.Name); !ok || location != bucket.Region {
+			t.Errorf("expected %s to be cached as %s, got %q", bucket.Name, bucket.Region, location)
+		}
+	}
+}
+
+// newListTestServer - returns a server listing the given object names
+// with version 1 and 2 of the listing, pages end at max-keys entries.
+func newListTestServer(names []string) *httptest.Server {
+	return httptest.NewServer(newListTestHandler(names))
+}
+
+// newListTestHandler - returns the handler of newListTestServer.
+func newListTestHandler(names []string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		prefix, delimiter := query.Get("prefix"), query.Get("delimiter")
+		maxKeys := 1000
+		if n, err := strconv.Atoi(query.Get("max-keys")); err == nil && n > 0 {
+			maxKeys = n
+		}
+		after := query.Get("marker")
+		if query.Get("list-type") == "2" {
+			after = query.Get("start-after")
+			if token := query.Get("continuation-token"); token != "" {
+				after = token
+			}
+		}
+
+		var entries, last string
+		count, truncated := 0, false
+		seenPrefixes := make(map[string]bool)
+		for _, name := range names {
+			if !strings.HasPrefix(name, prefix) || name <= after {
+				continue
+			}
+			entry := fmt.Sprintf("<Contents><Key>%s</Key><Size>1</Size></Contents>", name)
+			if i := strings.Index(name[len(prefix):], delimiter); delimiter != "" && i >= 0 {
+				commonPrefix := name[:len(prefix)+i+len(delimiter)]
+				if seenPrefixes[commonPrefix] {
+					last = name
+					continue
+				}
+				seenPrefixes[commonPrefix] = true
+				entry = fmt.Sprintf("<CommonPrefixes><Prefix>%s</Prefix></CommonPrefixes>", commonPrefix)
+			}
+			if count == maxKeys {
+				truncated = true
+				break
+			}
+			entries += entry
+			last = name
+			count++
+		}
+
+		next := ""
+		if truncated {
+			next = fmt.Sprintf("<NextMarker>%s</NextMarker><NextContinuationToken>%s</NextContinuationToken>", last, last)
+		}
+		fmt.Fprintf(w, "<ListBucketResult><Name>bucket</Name><Prefix>%s</Prefix><IsTruncated>%t</IsTruncated>%s%s</ListBucketResult>",
+			prefix, truncated, next, entries)
+	}
+}
+
+// Tests resuming listings after a given object name or at the
+// continuation token of a page.
+func TestListObjectsResume(t *testing.T) {
+	server := newListTestServer([]string{"a", "b", "c", "d", "e"})
+	defer server.Close()
+
+	for _, profile := range []EndpointProfile{EndpointProfileAuto, EndpointProfileGCS} {
+		c, err := NewWithOptions(strings.TrimPrefix(server.URL, "http://"), &Options{
+			Creds:           credentials.NewStaticV4("access", "secret", ""),
+			Region:          "us-east-1",
+			EndpointProfile: profile,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var keys []string
+		opts := ListObjectsOptions{Recursive: true, MaxKeys: 2}
+		for {
+			page, err := c.ListObjectsV2Page("bucket", opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, object := range page.Contents {
+				keys = append(keys, object.Key)
+			}
+			if !page.IsTruncated {
+				break
+			}
+			if len(page.Contents) != 2 {
+				t.Fatalf("expected pages of 2 objects, got %d", len(page.Contents))
+			}
+			opts.ContinuationToken = page.NextContinuationToken
+		}
+		if expected := []string{"a", "b", "c", "d", "e"}; !reflect.DeepEqual(keys, expected) {
+			t.Errorf("%d: expected %v, got %v", profile, expected, keys)
+		}
+
+		testCases := []struct {
+			opts     ListObjectsOptions
+			expected []string
+		}{
+			{ListObjectsOptions{Recursive: true, StartAfter: "b"}, []string{"c", "d", "e"}},
+			{ListObjectsOptions{Recursive: true, StartAfter: "b", ContinuationToken: "c"}, []string{"d", "e"}},
+			{ListObjectsOptions{Recursive: true, StartAfter: "e"}, nil},
+		}
+		for i, testCase := range testCases {
+			keys = nil
+			for object := range c.ListObjectsV2WithOptions("bucket", testCase.opts, nil) {
+				if object.Err != nil {
+					t.Fatal(object.Err)
+				}
+				keys = append(keys, object.Key)
+			}
+			if !reflect.DeepEqual(keys, testCase.expected) {
+				t.Errorf("%d: Test %d: expected %v, got %v", profile, i+1, testCase.expected, keys)
+			}
+		}
+	}
+}
+
+// Tests asking MinIO to list the metadata of the objects.
+func TestListObjectsWithMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		metadata := ""
+		if r.URL.Query().Get("metadata") == "true" {
+			metadata = "<UserMetadata><X-Amz-Meta-Camera>x100</X-Amz-Meta-Camera></UserMetadata>"
+		}
+		fmt.Fprintf(w, "<ListBucketResult><Name>bucket</Name><Contents><Key>a.jpg</Key>%s</Contents></ListBucketResult>", metadata)
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, withMetadata := range []bool{false, true} {
+		for object := range c.ListObjectsV2WithOptions("bucket", ListObjectsOptions{WithMetadata: withMetadata}, nil) {
+			if object.Err != nil {
+				t.Fatal(object.Err)
+			}
+			if camera := object.UserMetadata.Get("camera"); (camera == "x100") != withMetadata {
+				t.Errorf("%t: unexpected user metadata %v", withMetadata, object.UserMetadata)
+			}
+		}
+		page, err := c.ListObjectsV2Page("bucket", ListObjectsOptions{WithMetadata: withMetadata})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if camera := page.Contents[0].UserMetadata.Get("camera"); (camera == "x100") != withMetadata {
+			t.Errorf("%t: unexpected user metadata of the page %v", withMetadata, page.Contents[0].UserMetadata)
+		}
+	}
+}
+
+// Tests listing the common prefixes of a delimited listing as
+// pseudo-directories.
+func TestListObjectsFolders(t *testing.T) {
+	server := newListTestServer([]string{"a.txt", "docs/x", "docs/y", "img/sub/w", "img/z", "log-1", "log-2"})
+	defer server.Close()
+
+	testCases := []struct {
+		opts     ListObjectsOptions
+		expected []string
+	}{
+		{ListObjectsOptions{}, []string{"a.txt", "docs/ (dir)", "img/ (dir)", "log-1", "log-2"}},
+		{ListObjectsOptions{Prefix: "img/"}, []string{"img/sub/ (dir)", "img/z"}},
+		{ListObjectsOptions{Delimiter: "-"}, []string{"a.txt", "docs/x", "docs/y", "img/sub/w", "img/z", "log- (dir)"}},
+		{ListObjectsOptions{Delimiter: "-", Recursive: true}, []string{"a.txt", "docs/x", "docs/y", "img/sub/w", "img/z", "log-1", "log-2"}},
+	}
+	for _, profile := range []EndpointProfile{EndpointProfileAuto, EndpointProfileGCS} {
+		c, err := NewWithOptions(strings.TrimPrefix(server.URL, "http://"), &Options{
+			Creds:           credentials.NewStaticV4("access", "secret", ""),
+			Region:          "us-east-1",
+			EndpointProfile: profile,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, testCase := range testCases {
+			var entries []string
+			for object := range c.ListObjectsV2WithOptions("bucket", testCase.opts, nil) {
+				if object.Err != nil {
+					t.Fatal(object.Err)
+				}
+				if object.IsPrefix {
+					entries = append(entries, object.Key+" (dir)")
+				} else {
+					entries = append(entries, object.Key)
+				}
+			}
+			if !reflect.DeepEqual(entries, testCase.expected) {
+				t.Errorf("%d: Test %d: expected %v, got %v", profile, i+1, testCase.expected, entries)
+			}
+		}
+
+		page, err := c.ListObjectsV2Page("bucket", ListObjectsOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(page.Contents) != 3 || len(page.CommonPrefixes) != 2 {
+			t.Errorf("%d: expected 3 objects and 2 common prefixes, got %v and %v", profile, page.Contents, page.CommonPrefixes)
+		}
+	}
+}
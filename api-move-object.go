@@ -0,0 +1,84 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/minio/minio-go/v6/pkg/encrypt"
+)
+
+// MoveObject - moves a source object to a new object with a
+// server-side copy. The copy is verified against the source object
+// before the source object is removed, the source object is kept if
+// the copy fails or does not match.
+func (c Client) MoveObject(dst DestinationInfo, src SourceInfo) error {
+	if src.bucket == dst.bucket && src.object == dst.object {
+		return ErrInvalidArgument("Source and destination of a move cannot be the same object.")
+	}
+	if err := c.validateBucketName(src.bucket, false); err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	size, etag, _, err := src.getProps(c)
+	if err != nil {
+		return err
+	}
+
+	// Copy the state of the source object which was stat'ed, the
+	// headers are cloned to leave the source info of the caller
+	// untouched.
+	headers := make(http.Header, len(src.Headers))
+	for k, v := range src.Headers {
+		headers[k] = v
+	}
+	src.Headers = headers
+	if src.Headers.Get("x-amz-copy-source-if-match") == "" {
+		src.SetMatchETagCond(etag)
+	}
+	if size <= maxPartSize {
+		err = c.copyObject(ctx, dst, src, nil)
+	} else {
+		err = c.composeObject(ctx, dst, []SourceInfo{src}, nil)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Verify the copy before removing the source object.
+	objInfo, err := c.statObject(ctx, dst.bucket, dst.object, StatObjectOptions{GetObjectOptions{ServerSideEncryption: encrypt.SSE(dst.encryption)}})
+	if err != nil {
+		return err
+	}
+	if objInfo.Size != size {
+		return ErrCopyMismatch(dst.bucket, dst.object, src.bucket+"/"+src.object)
+	}
+	// ETags of unencrypted single part objects are the MD5 sums of
+	// their content.
+	plainETag := func(etag string) bool {
+		return !strings.Contains(etag, "-")
+	}
+	if src.encryption == nil && dst.encryption == nil && plainETag(etag) && plainETag(objInfo.ETag) && etag != objInfo.ETag {
+		return ErrCopyMismatch(dst.bucket, dst.object, src.bucket+"/"+src.object)
+	}
+
+	return c.RemoveObject(src.bucket, src.object)
+}
@@ -0,0 +1,110 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Tests moving objects, the source object is only removed once the
+// copy is verified.
+func TestMoveObject(t *testing.T) {
+	var mutex sync.Mutex
+	objects := make(map[string]string)
+	corrupt := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		etag, ok := objects[r.URL.Path]
+		switch r.Method {
+		case http.MethodHead:
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("ETag", `"`+etag+`"`)
+			w.Header().Set("Content-Length", "4")
+			w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		case http.MethodPut:
+			source := "/" + r.Header.Get("X-Amz-Copy-Source")
+			if r.Header.Get("X-Amz-Copy-Source-If-Match") != objects[source] {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				fmt.Fprint(w, "<Error><Code>PreconditionFailed</Code><Message>At least one of the pre-conditions you specified did not hold</Message></Error>")
+				return
+			}
+			objects[r.URL.Path] = objects[source]
+			if corrupt {
+				objects[r.URL.Path] = "corrupt"
+			}
+			fmt.Fprint(w, "<CopyObjectResult><ETag>\"etag\"</ETag></CopyObjectResult>")
+		case http.MethodDelete:
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		src, dst string
+		corrupt  bool
+		code     string
+		objects  string
+	}{
+		{"a", "b", false, "", "/bucket/b"},
+		{"a", "b", true, "CopyMismatch", "/bucket/a /bucket/b"},
+		{"a", "a", false, "InvalidArgument", "/bucket/a"},
+		{"missing", "b", false, "InvalidArgument", "/bucket/a"},
+	}
+	for i, testCase := range testCases {
+		mutex.Lock()
+		objects = map[string]string{"/bucket/a": "d41d8cd98f00b204e9800998ecf8427e"}
+		corrupt = testCase.corrupt
+		mutex.Unlock()
+
+		dst, err := NewDestinationInfo("bucket", testCase.dst, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = c.MoveObject(dst, NewSourceInfo("bucket", testCase.src, nil))
+		if code := ToErrorResponse(err).Code; code != testCase.code {
+			t.Errorf("Test %d: Expected error code %q, got %q (%v)", i+1, testCase.code, code, err)
+		}
+
+		mutex.Lock()
+		var names []string
+		for name := range objects {
+			names = append(names, name)
+		}
+		mutex.Unlock()
+		sort.Strings(names)
+		if got := strings.Join(names, " "); got != testCase.objects {
+			t.Errorf("Test %d: Expected objects %q, got %q", i+1, testCase.objects, got)
+		}
+	}
+}
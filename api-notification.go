@@ -21,6 +21,7 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/url"
 	"time"
@@ -31,7 +32,7 @@ import (
 // GetBucketNotification - get bucket notification at a given path.
 func (c Client) GetBucketNotification(bucketName string) (bucketNotification BucketNotification, err error) {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return BucketNotification{}, err
 	}
 	notification, err := c.getBucketNotification(bucketName)
@@ -133,6 +134,41 @@ type NotificationInfo struct {
 	Err     error
 }
 
+// EventType - returns the event name as a notification event type.
+func (e NotificationEvent) EventType() NotificationEventType {
+	return NotificationEventType(e.EventName)
+}
+
+// Time - returns the time at which the event occurred.
+func (e NotificationEvent) Time() (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, e.EventTime)
+}
+
+// ObjectKey - returns the decoded name of the object the event is about.
+// Object keys are URL encoded in event records, with spaces encoded as '+'.
+func (e NotificationEvent) ObjectKey() (string, error) {
+	return url.QueryUnescape(e.S3.Object.Key)
+}
+
+// ParseNotificationEvents - decodes the event records of a bucket
+// notification message, such as the body delivered to a webhook or a
+// Lambda function.
+func ParseNotificationEvents(reader io.Reader) ([]NotificationEvent, error) {
+	var notificationInfo NotificationInfo
+	if err := json.NewDecoder(reader).Decode(&notificationInfo); err != nil {
+		return nil, err
+	}
+	for _, event := range notificationInfo.Records {
+		if event.EventName == "" {
+			return nil, ErrInvalidArgument("Notification event record is missing the event name.")
+		}
+		if _, err := event.ObjectKey(); err != nil {
+			return nil, ErrInvalidArgument("Notification event record has an invalid object key: " + err.Error())
+		}
+	}
+	return notificationInfo.Records, nil
+}
+
 // ListenBucketNotification - listen on bucket notifications.
 func (c Client) ListenBucketNotification(bucketName, prefix, suffix string, events []string, doneCh <-chan struct{}) <-chan NotificationInfo {
 	notificationInfoCh := make(chan NotificationInfo, 1)
@@ -141,7 +177,7 @@ func (c Client) ListenBucketNotification(bucketName, prefix, suffix string, even
 		defer close(notificationInfoCh)
 
 		// Validate the bucket name.
-		if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+		if err := c.validateBucketName(bucketName, false); err != nil {
 			notificationInfoCh <- NotificationInfo{
 				Err: err,
 			}
@@ -220,3 +256,20 @@ func (c Client) ListenBucketNotification(bucketName, prefix, suffix string, even
 	// Returns the notification info channel, for caller to start reading from.
 	return notificationInfoCh
 }
+
+// ListenBucketNotificationWithRules - listen on bucket notifications
+// matching the given notification rules. The rules are validated before
+// listening, a validation error is sent on the returned channel.
+func (c Client) ListenBucketNotificationWithRules(bucketName string, rules *NotificationRules, doneCh <-chan struct{}) <-chan NotificationInfo {
+	if err := rules.Validate(); err != nil {
+		notificationInfoCh := make(chan NotificationInfo, 1)
+		notificationInfoCh <- NotificationInfo{Err: err}
+		close(notificationInfoCh)
+		return notificationInfoCh
+	}
+	events := make([]string, 0, len(rules.events))
+	for _, event := range rules.events {
+		events = append(events, string(event))
+	}
+	return c.ListenBucketNotification(bucketName, rules.prefix, rules.suffix, events, doneCh)
+}
@@ -0,0 +1,186 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/url"
+)
+
+// RetentionMode - object retention mode.
+type RetentionMode string
+
+const (
+	// Governance - governance mode, privileged users may remove
+	// retention or delete objects.
+	Governance RetentionMode = "GOVERNANCE"
+
+	// Compliance - compliance mode, nobody may remove retention or
+	// delete objects until retention expires.
+	Compliance RetentionMode = "COMPLIANCE"
+)
+
+func (r RetentionMode) String() string {
+	return string(r)
+}
+
+// IsValid - check whether this retention mode is valid or not.
+func (r RetentionMode) IsValid() bool {
+	return r == Governance || r == Compliance
+}
+
+// ValidityUnit - retention validity unit.
+type ValidityUnit string
+
+const (
+	// Days - denotes no. of days.
+	Days ValidityUnit = "DAYS"
+
+	// Years - denotes no. of years.
+	Years ValidityUnit = "YEARS"
+)
+
+func (unit ValidityUnit) String() string {
+	return string(unit)
+}
+
+// IsValid - check whether this validity unit is valid or not.
+func (unit ValidityUnit) IsValid() bool {
+	return unit == Days || unit == Years
+}
+
+// LegalHoldStatus - object legal hold status.
+type LegalHoldStatus string
+
+const (
+	// LegalHoldEnabled - legal hold is placed on the object.
+	LegalHoldEnabled LegalHoldStatus = "ON"
+
+	// LegalHoldDisabled - legal hold is released from the object.
+	LegalHoldDisabled LegalHoldStatus = "OFF"
+)
+
+func (l LegalHoldStatus) String() string {
+	return string(l)
+}
+
+// IsValid - check whether this legal hold status is valid or not.
+func (l LegalHoldStatus) IsValid() bool {
+	return l == LegalHoldEnabled || l == LegalHoldDisabled
+}
+
+// objectLockConfig container for the object lock configuration of a
+// bucket.
+type objectLockConfig struct {
+	XMLName           xml.Name        `xml:"ObjectLockConfiguration"`
+	ObjectLockEnabled string          `xml:"ObjectLockEnabled"`
+	Rule              *objectLockRule `xml:"Rule,omitempty"`
+}
+
+// objectLockRule - default retention of the new objects of a bucket,
+// either Days or Years is set.
+type objectLockRule struct {
+	DefaultRetention struct {
+		Mode  RetentionMode `xml:"Mode"`
+		Days  *uint         `xml:"Days,omitempty"`
+		Years *uint         `xml:"Years,omitempty"`
+	} `xml:"DefaultRetention"`
+}
+
+func newObjectLockConfig(mode *RetentionMode, validity *uint, unit *ValidityUnit) (*objectLockConfig, error) {
+	config := &objectLockConfig{
+		ObjectLockEnabled: "Enabled",
+	}
+
+	if mode == nil && validity == nil && unit == nil {
+		return config, nil
+	}
+	if mode == nil || validity == nil || unit == nil {
+		return nil, ErrInvalidArgument("Mode, validity and unit must be all present or all absent.")
+	}
+	if !mode.IsValid() {
+		return nil, ErrInvalidArgument(fmt.Sprintf("Invalid retention mode `%v`.", *mode))
+	}
+	if !unit.IsValid() {
+		return nil, ErrInvalidArgument(fmt.Sprintf("Invalid validity unit `%v`.", *unit))
+	}
+	if *validity == 0 {
+		return nil, ErrInvalidArgument("Validity must be at least 1.")
+	}
+
+	config.Rule = &objectLockRule{}
+	config.Rule.DefaultRetention.Mode = *mode
+	if *unit == Days {
+		config.Rule.DefaultRetention.Days = validity
+	} else {
+		config.Rule.DefaultRetention.Years = validity
+	}
+	return config, nil
+}
+
+// SetBucketObjectLockConfig - enables object locking on a bucket and
+// sets the default retention of new objects. Mode, validity and unit
+// are either all set or all nil, which removes the default retention.
+func (c Client) SetBucketObjectLockConfig(bucketName string, mode *RetentionMode, validity *uint, unit *ValidityUnit) error {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return err
+	}
+	config, err := newObjectLockConfig(mode, validity, unit)
+	if err != nil {
+		return err
+	}
+
+	urlValues := make(url.Values)
+	urlValues.Set("object-lock", "")
+	return c.putBucketConfig(bucketName, urlValues, config)
+}
+
+// GetBucketObjectLockConfig - gets the object lock configuration of a
+// bucket. objectLock is "Enabled" if object locking is enabled, mode,
+// validity and unit are nil if there is no default retention.
+func (c Client) GetBucketObjectLockConfig(bucketName string) (objectLock string, mode *RetentionMode, validity *uint, unit *ValidityUnit, err error) {
+	// Input validation.
+	if err = c.validateBucketName(bucketName, false); err != nil {
+		return "", nil, nil, nil, err
+	}
+
+	urlValues := make(url.Values)
+	urlValues.Set("object-lock", "")
+	var config objectLockConfig
+	if err = c.getBucketConfig(bucketName, urlValues, &config); err != nil {
+		if ToErrorResponse(err).Code == "ObjectLockConfigurationNotFoundError" {
+			return "", nil, nil, nil, nil
+		}
+		return "", nil, nil, nil, err
+	}
+
+	if config.Rule != nil {
+		retention := config.Rule.DefaultRetention
+		mode = &retention.Mode
+		if retention.Days != nil {
+			validity, unit = retention.Days, new(ValidityUnit)
+			*unit = Days
+		} else if retention.Years != nil {
+			validity, unit = retention.Years, new(ValidityUnit)
+			*unit = Years
+		}
+	}
+	return config.ObjectLockEnabled, mode, validity, unit, nil
+}
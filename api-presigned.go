@@ -28,17 +28,20 @@ import (
 )
 
 // presignURL - Returns a presigned URL for an input 'method'.
-// Expires maximum is 7days - ie. 604800 and minimum is 1.
+// Expires minimum is 1sec, the maximum is 7days - ie. 604800 for
+// signature V4 and unlimited for signature V2.
 func (c Client) presignURL(method string, bucketName string, objectName string, expires time.Duration, reqParams url.Values) (u *url.URL, err error) {
 	// Input validation.
 	if method == "" {
 		return nil, ErrInvalidArgument("method cannot be empty.")
 	}
-	if err = s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err = c.validateBucketName(bucketName, false); err != nil {
 		return nil, err
 	}
-	if err = isValidExpiry(expires); err != nil {
-		return nil, err
+	// The upper limit of the expiry depends on the signature
+	// version, which is checked when the URL is signed.
+	if expires < time.Second {
+		return nil, ErrInvalidArgument("Expires cannot be lesser than 1 second.")
 	}
 
 	// Convert expires into seconds.
@@ -65,7 +68,7 @@ func (c Client) presignURL(method string, bucketName string, objectName string,
 // upto 7days or a minimum of 1sec. Additionally you can override
 // a set of response headers using the query parameters.
 func (c Client) PresignedGetObject(bucketName string, objectName string, expires time.Duration, reqParams url.Values) (u *url.URL, err error) {
-	if err = s3utils.CheckValidObjectName(objectName); err != nil {
+	if err = ValidateObjectKey(objectName); err != nil {
 		return nil, err
 	}
 	return c.presignURL("GET", bucketName, objectName, expires, reqParams)
@@ -76,7 +79,7 @@ func (c Client) PresignedGetObject(bucketName string, objectName string, expires
 // upto 7days or a minimum of 1sec. Additionally you can override
 // a set of response headers using the query parameters.
 func (c Client) PresignedHeadObject(bucketName string, objectName string, expires time.Duration, reqParams url.Values) (u *url.URL, err error) {
-	if err = s3utils.CheckValidObjectName(objectName); err != nil {
+	if err = ValidateObjectKey(objectName); err != nil {
 		return nil, err
 	}
 	return c.presignURL("HEAD", bucketName, objectName, expires, reqParams)
@@ -86,7 +89,7 @@ func (c Client) PresignedHeadObject(bucketName string, objectName string, expire
 // without credentials. URL can have a maximum expiry of upto 7days
 // or a minimum of 1sec.
 func (c Client) PresignedPutObject(bucketName string, objectName string, expires time.Duration) (u *url.URL, err error) {
-	if err = s3utils.CheckValidObjectName(objectName); err != nil {
+	if err = ValidateObjectKey(objectName); err != nil {
 		return nil, err
 	}
 	return c.presignURL("PUT", bucketName, objectName, expires, nil)
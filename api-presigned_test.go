@@ -0,0 +1,146 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v6/pkg/credentials"
+)
+
+// Tests the expiry limits of presigned URLs for each signature version.
+func TestPresignExpiry(t *testing.T) {
+	testCases := []struct {
+		creds   *credentials.Credentials
+		expires time.Duration
+		success bool
+	}{
+		{credentials.NewStaticV4("access", "secret", ""), time.Second, true},
+		{credentials.NewStaticV4("access", "secret", ""), 7 * 24 * time.Hour, true},
+		{credentials.NewStaticV4("access", "secret", ""), 7*24*time.Hour + time.Second, false},
+		{credentials.NewStaticV4("access", "secret", ""), 500 * time.Millisecond, false},
+		{credentials.NewStaticV2("access", "secret", ""), 30 * 24 * time.Hour, true},
+		{credentials.NewStaticV2("access", "secret", ""), 0, false},
+	}
+	for i, testCase := range testCases {
+		c, err := NewWithOptions("localhost:9000", &Options{Creds: testCase.creds, Region: "us-east-1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = c.PresignedGetObject("bucket", "object", testCase.expires, nil)
+		if testCase.success && err != nil {
+			t.Errorf("Test %d: unexpected error %v", i+1, err)
+		}
+		if !testCase.success && err == nil {
+			t.Errorf("Test %d: expected an error", i+1)
+		}
+	}
+}
+
+// Tests presigned URLs follow the bucket lookup style of the client.
+func TestPresignBucketLookup(t *testing.T) {
+	customLookup := func(u url.URL, bucketName string) BucketLookupType {
+		if bucketName == "vhost" {
+			return BucketLookupDNS
+		}
+		return BucketLookupAuto
+	}
+	testCases := []struct {
+		opts   Options
+		bucket string
+		url    string
+	}{
+		{Options{}, "bucket", "https://s3.example.com/bucket/object"},
+		{Options{BucketLookup: BucketLookupDNS}, "bucket", "https://bucket.s3.example.com/object"},
+		{Options{BucketLookup: BucketLookupPath}, "bucket", "https://s3.example.com/bucket/object"},
+		{Options{BucketLookupViaURL: customLookup}, "vhost", "https://vhost.s3.example.com/object"},
+		{Options{BucketLookupViaURL: customLookup}, "bucket", "https://s3.example.com/bucket/object"},
+		{Options{BucketLookup: BucketLookupDNS, BucketLookupViaURL: customLookup}, "bucket", "https://bucket.s3.example.com/object"},
+	}
+	for i, testCase := range testCases {
+		for _, creds := range []*credentials.Credentials{credentials.NewStaticV4("access", "secret", ""), credentials.NewStaticV2("access", "secret", "")} {
+			opts := testCase.opts
+			opts.Creds, opts.Secure, opts.Region = creds, true, "us-east-1"
+			c, err := NewWithOptions("s3.example.com", &opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			u, err := c.PresignedGetObject(testCase.bucket, "object", time.Hour, nil)
+			if err != nil {
+				t.Fatalf("Test %d: %v", i+1, err)
+			}
+			u.RawQuery = ""
+			if u.String() != testCase.url {
+				t.Errorf("Test %d: expected %s, got %s", i+1, testCase.url, u)
+			}
+		}
+	}
+}
+
+// failingTransport - fails the test on any request.
+type failingTransport struct {
+	t *testing.T
+}
+
+func (f failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.t.Errorf("unexpected request %s %s", req.Method, req.URL)
+	return nil, errors.New("offline")
+}
+
+// Tests presigning without network requests once the location of the
+// bucket is known.
+func TestPresignOffline(t *testing.T) {
+	pinned, err := NewWithRegion("s3.example.com", "access", "secret", true, "eu-west-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached, err := New("s3.example.com", "access", "secret", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = cached.SetBucketLocation("bucket", "eu-west-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err = cached.SetBucketLocation("bucket", ""); err == nil {
+		t.Fatal("expected an error for an empty location")
+	}
+
+	for i, c := range []*Client{pinned, cached} {
+		c.SetCustomTransport(failingTransport{t})
+		u, err := c.PresignedGetObject("bucket", "object", time.Hour, nil)
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if credential := u.Query().Get("X-Amz-Credential"); !strings.Contains(credential, "/eu-west-1/s3/") {
+			t.Errorf("Test %d: expected the URL to be signed for eu-west-1, got %s", i+1, credential)
+		}
+
+		policy := NewPostPolicy()
+		policy.SetBucket("bucket")
+		policy.SetKey("object")
+		policy.SetExpires(time.Now().UTC().Add(time.Hour))
+		if _, _, err = c.PresignedPostPolicy(policy); err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+	}
+}
@@ -25,8 +25,6 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
-
-	"github.com/minio/minio-go/v6/pkg/s3utils"
 )
 
 /// Bucket operations
@@ -47,17 +45,21 @@ func (c Client) MakeBucket(bucketName string, location string) (err error) {
 	}()
 
 	// Validate the input arguments.
-	if err := s3utils.CheckValidBucketNameStrict(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, true); err != nil {
 		return err
 	}
 
+	if location != "" && !Region(location).IsValid() {
+		return ErrInvalidArgument("Invalid bucket location `" + location + "`.")
+	}
+
 	// If location is empty, treat is a default region 'us-east-1'.
 	if location == "" {
 		location = "us-east-1"
 		// For custom region clients, default
 		// to custom region instead not 'us-east-1'.
-		if c.region != "" {
-			location = c.region
+		if region := c.regionOverride(); region != "" {
+			location = region
 		}
 	}
 	// PUT bucket request metadata.
@@ -67,7 +69,8 @@ func (c Client) MakeBucket(bucketName string, location string) (err error) {
 	}
 
 	// If location is not 'us-east-1' create bucket location config.
-	if location != "us-east-1" && location != "" {
+	// The region of the endpoint profile is not a location.
+	if location != "us-east-1" && location != "" && location != c.profile.region {
 		createBucketConfig := createBucketConfiguration{}
 		createBucketConfig.Location = location
 		var createBucketConfigBytes []byte
@@ -101,7 +104,7 @@ func (c Client) MakeBucket(bucketName string, location string) (err error) {
 // SetBucketPolicy set the access permissions on an existing bucket.
 func (c Client) SetBucketPolicy(bucketName, policy string) error {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return err
 	}
 
@@ -117,7 +120,7 @@ func (c Client) SetBucketPolicy(bucketName, policy string) error {
 // Saves a new bucket policy.
 func (c Client) putBucketPolicy(bucketName, policy string) error {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return err
 	}
 
@@ -157,7 +160,7 @@ func (c Client) putBucketPolicy(bucketName, policy string) error {
 // Removes all policies on a bucket.
 func (c Client) removeBucketPolicy(bucketName string) error {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return err
 	}
 	// Get resources properly escaped and lined up before
@@ -181,7 +184,7 @@ func (c Client) removeBucketPolicy(bucketName string) error {
 // SetBucketLifecycle set the lifecycle on an existing bucket.
 func (c Client) SetBucketLifecycle(bucketName, lifecycle string) error {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return err
 	}
 
@@ -197,7 +200,7 @@ func (c Client) SetBucketLifecycle(bucketName, lifecycle string) error {
 // Saves a new bucket lifecycle.
 func (c Client) putBucketLifecycle(bucketName, lifecycle string) error {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return err
 	}
 
@@ -238,7 +241,7 @@ func (c Client) putBucketLifecycle(bucketName, lifecycle string) error {
 // Remove lifecycle from a bucket.
 func (c Client) removeBucketLifecycle(bucketName string) error {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return err
 	}
 	// Get resources properly escaped and lined up before
@@ -262,7 +265,7 @@ func (c Client) removeBucketLifecycle(bucketName string) error {
 // SetBucketNotification saves a new bucket notification.
 func (c Client) SetBucketNotification(bucketName string, bucketNotification BucketNotification) error {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return err
 	}
 
@@ -0,0 +1,161 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// UploadDirectoryOptions represents options specified by user for
+// UploadDirectory call.
+type UploadDirectoryOptions struct {
+	// Options used for uploading each file.
+	PutObjectOptions
+
+	// Number of files uploaded concurrently, defaults to 4.
+	NumWorkers int
+}
+
+// UploadDirectoryResult - result of uploading a single file of the
+// directory.
+type UploadDirectoryResult struct {
+	// Local path of the file.
+	FilePath string
+
+	// Name of the object the file is uploaded to.
+	ObjectName string
+
+	// Number of bytes uploaded.
+	Size int64
+
+	// Error is set if the file could not be read or uploaded.
+	Err error
+}
+
+// directoryObjectName - maps the path of a file relative to the
+// uploaded directory to an object name under prefix.
+func directoryObjectName(prefix, relPath string) string {
+	objectName := filepath.ToSlash(relPath)
+	if prefix == "" {
+		return objectName
+	}
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return prefix + objectName
+}
+
+// UploadDirectory - uploads all regular files found recursively under
+// dirPath to objects under prefix, the path of a file relative to
+// dirPath is used as the object name under prefix. Files are uploaded
+// concurrently and the result of each upload is sent on the returned
+// channel, which is closed once all the files have been processed.
+func (c Client) UploadDirectory(bucketName, prefix, dirPath string, opts UploadDirectoryOptions) <-chan UploadDirectoryResult {
+	return c.UploadDirectoryWithContext(context.Background(), bucketName, prefix, dirPath, opts)
+}
+
+// UploadDirectoryWithContext - Identical to UploadDirectory call, but accepts context to facilitate request cancellation.
+func (c Client) UploadDirectoryWithContext(ctx context.Context, bucketName, prefix, dirPath string, opts UploadDirectoryOptions) <-chan UploadDirectoryResult {
+	resultCh := make(chan UploadDirectoryResult, 1)
+
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		defer close(resultCh)
+		resultCh <- UploadDirectoryResult{FilePath: dirPath, Err: err}
+		return resultCh
+	}
+	if err := opts.validate(); err != nil {
+		defer close(resultCh)
+		resultCh <- UploadDirectoryResult{FilePath: dirPath, Err: err}
+		return resultCh
+	}
+
+	numWorkers := opts.NumWorkers
+	if numWorkers <= 0 {
+		numWorkers = totalWorkers
+	}
+
+	// Walk the directory and send the files to be uploaded.
+	filesCh := make(chan UploadDirectoryResult)
+	go func() {
+		defer close(filesCh)
+		walkErr := filepath.Walk(dirPath, func(filePath string, info os.FileInfo, err error) error {
+			if err != nil {
+				select {
+				case resultCh <- UploadDirectoryResult{FilePath: filePath, Err: err}:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+				// Skip unreadable directories but keep walking.
+				if info != nil && info.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			// Directories, symbolic links and special files are not uploaded.
+			if !info.Mode().IsRegular() {
+				return nil
+			}
+			relPath, err := filepath.Rel(dirPath, filePath)
+			if err != nil {
+				return err
+			}
+			select {
+			case filesCh <- UploadDirectoryResult{
+				FilePath:   filePath,
+				ObjectName: directoryObjectName(prefix, relPath),
+				Size:       info.Size(),
+			}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			return nil
+		})
+		if walkErr != nil {
+			resultCh <- UploadDirectoryResult{FilePath: dirPath, Err: walkErr}
+		}
+	}()
+
+	// Upload the files with a pool of workers.
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range filesCh {
+				if err := ValidateObjectKey(file.ObjectName); err != nil {
+					file.Err = err
+				} else {
+					file.Size, file.Err = c.FPutObjectWithContext(ctx, bucketName, file.ObjectName, file.FilePath, opts.PutObjectOptions)
+				}
+				resultCh <- file
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(resultCh)
+	}()
+
+	return resultCh
+}
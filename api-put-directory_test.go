@@ -0,0 +1,57 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// Tests mapping of file paths to object names.
+func TestDirectoryObjectName(t *testing.T) {
+	testCases := []struct {
+		prefix     string
+		relPath    string
+		objectName string
+	}{
+		{"", "file.txt", "file.txt"},
+		{"backup", "file.txt", "backup/file.txt"},
+		{"backup/", "file.txt", "backup/file.txt"},
+		{"backup/2019", filepath.Join("photos", "pic.jpg"), "backup/2019/photos/pic.jpg"},
+	}
+	for i, testCase := range testCases {
+		if objectName := directoryObjectName(testCase.prefix, testCase.relPath); objectName != testCase.objectName {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.objectName, objectName)
+		}
+	}
+}
+
+// Tests input validation of UploadDirectory.
+func TestUploadDirectoryInvalidBucket(t *testing.T) {
+	c, err := New("localhost:9000", "access", "secret", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []UploadDirectoryResult
+	for result := range c.UploadDirectory("a", "prefix", ".", UploadDirectoryOptions{}) {
+		results = append(results, result)
+	}
+	if len(results) != 1 || ToErrorResponse(results[0].Err).Code != "InvalidBucketName" {
+		t.Errorf("Expected a single InvalidBucketName error, got %v", results)
+	}
+}
@@ -18,14 +18,63 @@
 package minio
 
 import (
+	"bytes"
 	"context"
 	"io"
 	"math"
+	"mime"
+	"net/http"
 	"os"
-
-	"github.com/minio/minio-go/v6/pkg/s3utils"
+	"path"
+	"time"
 )
 
+// sniffLen is the number of bytes used for content type detection,
+// http.DetectContentType considers at most these many bytes.
+const sniffLen = 512
+
+// detectContentType - detects the content type of an object from the
+// extension of its name, falling back to sniffing the first 512 bytes
+// of the input reader. The returned reader must be used in place of
+// the input reader, seekable readers are rewound to their original
+// offset while other readers are wrapped to replay the sniffed bytes.
+func detectContentType(objectName string, reader io.Reader, size int64) (contentType string, r io.Reader, err error) {
+	if contentType = mime.TypeByExtension(path.Ext(objectName)); contentType != "" {
+		return contentType, reader, nil
+	}
+	if reader == nil || size == 0 {
+		return "", reader, nil
+	}
+
+	n := int64(sniffLen)
+	if size > 0 && size < n {
+		n = size
+	}
+	buf := make([]byte, n)
+
+	if seeker, ok := reader.(io.ReadSeeker); ok {
+		// Stdin and other pipes are *os.File as well, seeking fails
+		// on them in which case they are treated as plain readers.
+		if offset, serr := seeker.Seek(0, io.SeekCurrent); serr == nil {
+			length, rerr := io.ReadFull(seeker, buf)
+			if rerr != nil && rerr != io.EOF && rerr != io.ErrUnexpectedEOF {
+				return "", nil, rerr
+			}
+			if _, err = seeker.Seek(offset, io.SeekStart); err != nil {
+				return "", nil, err
+			}
+			return http.DetectContentType(buf[:length]), reader, nil
+		}
+	}
+
+	length, rerr := io.ReadFull(reader, buf)
+	if rerr != nil && rerr != io.EOF && rerr != io.ErrUnexpectedEOF {
+		return "", nil, rerr
+	}
+	buf = buf[:length]
+	return http.DetectContentType(buf), io.MultiReader(bytes.NewReader(buf), reader), nil
+}
+
 // Verify if reader is *minio.Object
 func isObject(reader io.Reader) (ok bool) {
 	_, ok = reader.(*Object)
@@ -57,6 +106,24 @@ func isReadAt(reader io.Reader) (ok bool) {
 	return
 }
 
+// newReaderAtWindow - returns a ReaderAt for the size bytes of reader
+// starting at its current offset, such that parts read from it
+// start where reading the reader sequentially would start. The
+// reader must implement io.ReaderAt.
+func newReaderAtWindow(reader io.Reader, size int64) (io.ReaderAt, error) {
+	var offset int64
+	if seeker, ok := reader.(io.Seeker); ok {
+		var err error
+		if offset, err = seeker.Seek(0, io.SeekCurrent); err != nil {
+			return nil, ErrInvalidArgument(err.Error())
+		}
+	}
+	if offset == 0 {
+		return reader.(io.ReaderAt), nil
+	}
+	return io.NewSectionReader(reader.(io.ReaderAt), offset, size), nil
+}
+
 // optimalPartInfo - calculate the optimal part info for a given
 // object size.
 //
@@ -118,14 +185,28 @@ func optimalPartInfo(objectSize int64, configuredPartSize uint64) (totalPartsCou
 	return totalPartsCount, partSize, lastPartSize, nil
 }
 
+// abortUploadTimeout is the time the abort of a failed multipart
+// upload may take, retries included.
+var abortUploadTimeout = 30 * time.Second
+
+// cleanupMultipartUpload - aborts a failed multipart upload on a best
+// effort basis, so that its parts do not leak storage. The abort does
+// not use the context of the upload, which may have been cancelled or
+// timed out, but its own timeout.
+func (c Client) cleanupMultipartUpload(bucketName, objectName, uploadID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), abortUploadTimeout)
+	defer cancel()
+	c.abortMultipartUpload(ctx, bucketName, objectName, uploadID)
+}
+
 // getUploadID - fetch upload id if already present for an object name
 // or initiate a new request to fetch a new upload id.
 func (c Client) newUploadID(ctx context.Context, bucketName, objectName string, opts PutObjectOptions) (uploadID string, err error) {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return "", err
 	}
-	if err := s3utils.CheckValidObjectName(objectName); err != nil {
+	if err := ValidateObjectKey(objectName); err != nil {
 		return "", err
 	}
 
@@ -29,5 +29,62 @@ func (c Client) PutObjectWithContext(ctx context.Context, bucketName, objectName
 	if err != nil {
 		return 0, err
 	}
+	// Clients set to create only never overwrite objects.
+	if c.createOnly && !opts.CreateOnly {
+		if !c.profile.noConditionalWrites {
+			opts.CreateOnly = true
+		} else if err = c.checkObjectAbsent(ctx, bucketName, objectName); err != nil {
+			return 0, err
+		}
+	}
+	// Detect the content type when it is not set by the caller.
+	if opts.ContentType == "" {
+		opts.ContentType, reader, err = detectContentType(objectName, reader, objectSize)
+		if err != nil {
+			return 0, err
+		}
+	}
+	// Compress the data, the compressed size is not known in advance.
+	if opts.Compression != nil {
+		compressed, err := compressReader(reader, opts.Compression)
+		if err != nil {
+			return 0, err
+		}
+		defer compressed.Close()
+		// Size the parts after the size of the data when it is known.
+		if objectSize >= 0 && opts.PartSize == 0 {
+			bound := compressBound(objectSize)
+			if bound > maxMultipartPutObjectSize {
+				bound = maxMultipartPutObjectSize
+			}
+			switch {
+			case bound < absMinPartSize:
+				opts.PartSize = absMinPartSize
+			case bound < minPartSize:
+				opts.PartSize = uint64(bound)
+			default:
+				_, partSize, _, err := optimalPartInfo(bound, 0)
+				if err != nil {
+					return 0, err
+				}
+				opts.PartSize = uint64(partSize)
+			}
+		}
+		opts.ContentEncoding = opts.Compression.ContentEncoding()
+		reader, objectSize = compressed, -1
+	}
 	return c.putObjectCommon(ctx, bucketName, objectName, reader, objectSize, opts)
 }
+
+// checkObjectAbsent - returns a PreconditionFailed error if the object
+// exists, for services which do not support conditional writes.
+func (c Client) checkObjectAbsent(ctx context.Context, bucketName, objectName string) error {
+	_, err := c.statObject(ctx, bucketName, objectName, StatObjectOptions{})
+	if err == nil {
+		return ErrPreconditionFailed(bucketName, objectName)
+	}
+	if ToErrorResponse(err).Code == "NoSuchKey" {
+		return nil
+	}
+	return err
+}
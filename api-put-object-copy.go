@@ -34,6 +34,10 @@ func (c Client) CopyObject(dst DestinationInfo, src SourceInfo) error {
 // CopyObjectWithProgress - copy a source object into a new object, optionally takes
 // progress bar input to notify current progress.
 func (c Client) CopyObjectWithProgress(dst DestinationInfo, src SourceInfo, progress io.Reader) error {
+	return c.copyObject(context.Background(), dst, src, progress)
+}
+
+func (c Client) copyObject(ctx context.Context, dst DestinationInfo, src SourceInfo, progress io.Reader) error {
 	header := make(http.Header)
 	for k, v := range src.Headers {
 		header[k] = v
@@ -60,7 +64,7 @@ func (c Client) CopyObjectWithProgress(dst DestinationInfo, src SourceInfo, prog
 		header.Set(k, v)
 	}
 
-	resp, err := c.executeMethod(context.Background(), "PUT", requestMetadata{
+	resp, err := c.executeMethod(ctx, "PUT", requestMetadata{
 		bucketName:   dst.bucket,
 		objectName:   dst.object,
 		customHeader: header,
@@ -81,3 +85,38 @@ func (c Client) CopyObjectWithProgress(dst DestinationInfo, src SourceInfo, prog
 
 	return nil
 }
+
+// RotateObjectKey - re-encrypts an object encrypted with the SSE-C key
+// oldKey with newKey, with a server-side copy of the object onto
+// itself. The data never leaves the server and the user metadata of
+// the object is kept. Only the version of the object which was
+// stat'ed with oldKey is re-encrypted, the rotation fails if the
+// object is overwritten concurrently.
+func (c Client) RotateObjectKey(bucketName, objectName string, oldKey, newKey encrypt.ServerSide) error {
+	if oldKey == nil || oldKey.Type() != encrypt.SSEC {
+		return ErrInvalidArgument("The current key of the object must be a SSE-C key.")
+	}
+	if newKey == nil {
+		return ErrInvalidArgument("The new key of the object cannot be empty.")
+	}
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return err
+	}
+	if err := ValidateObjectKey(objectName); err != nil {
+		return err
+	}
+
+	src := NewSourceInfo(bucketName, objectName, oldKey)
+	size, etag, _, err := src.getProps(c)
+	if err != nil {
+		return err
+	}
+	src.SetMatchETagCond(etag)
+	dst := DestinationInfo{bucket: bucketName, object: objectName, encryption: newKey}
+
+	ctx := context.Background()
+	if size <= maxPartSize {
+		return c.copyObject(ctx, dst, src, nil)
+	}
+	return c.composeObject(ctx, dst, []SourceInfo{src}, nil)
+}
@@ -0,0 +1,97 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/v6/pkg/encrypt"
+)
+
+// Tests re-encrypting an object in place with a new SSE-C key.
+func TestRotateObjectKey(t *testing.T) {
+	oldKey, _ := encrypt.NewSSEC([]byte(strings.Repeat("a", 32)))
+	newKey, _ := encrypt.NewSSEC([]byte(strings.Repeat("b", 32)))
+	encodedKey := func(c byte) string {
+		return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(c), 32)))
+	}
+
+	objectKey := encodedKey('a')
+	var copies int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodHead:
+			if r.Header.Get("X-Amz-Server-Side-Encryption-Customer-Key") != objectKey {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("ETag", `"etag"`)
+			w.Header().Set("Content-Length", "4")
+			w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		case http.MethodPut:
+			copies++
+			switch {
+			case r.Header.Get("X-Amz-Copy-Source") != "bucket/object":
+				t.Errorf("unexpected copy source %q", r.Header.Get("X-Amz-Copy-Source"))
+			case r.Header.Get("X-Amz-Copy-Source-If-Match") != "etag":
+				t.Errorf("expected the copy to be pinned to the stat'ed object")
+			case r.Header.Get("X-Amz-Copy-Source-Server-Side-Encryption-Customer-Key") != objectKey:
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, "<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>")
+				return
+			}
+			objectKey = r.Header.Get("X-Amz-Server-Side-Encryption-Customer-Key")
+			fmt.Fprint(w, "<CopyObjectResult><ETag>\"etag\"</ETag></CopyObjectResult>")
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = c.RotateObjectKey("bucket", "object", oldKey, newKey); err != nil {
+		t.Fatal(err)
+	}
+	if objectKey != encodedKey('b') || copies != 1 {
+		t.Fatalf("expected the object to be re-encrypted with the new key in a single copy")
+	}
+	// The object is no longer encrypted with the old key.
+	if err = c.RotateObjectKey("bucket", "object", oldKey, newKey); err == nil {
+		t.Fatal("expected the rotation with a stale key to fail")
+	}
+
+	testCases := []struct {
+		oldKey, newKey encrypt.ServerSide
+	}{
+		{nil, newKey},
+		{encrypt.NewSSE(), newKey},
+		{newKey, nil},
+	}
+	for i, testCase := range testCases {
+		if err = c.RotateObjectKey("bucket", "object", testCase.oldKey, testCase.newKey); err == nil {
+			t.Errorf("Test %d: expected an error", i+1)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v6/pkg/encrypt"
+)
+
+// PutObjectFanOutEntry - name and metadata of one of the objects
+// created by PutObjectFanOut.
+type PutObjectFanOutEntry struct {
+	Key                string            `json:"key"`
+	UserMetadata       map[string]string `json:"metadata,omitempty"`
+	UserTags           map[string]string `json:"tags,omitempty"`
+	ContentType        string            `json:"contentType,omitempty"`
+	ContentEncoding    string            `json:"contentEncoding,omitempty"`
+	ContentDisposition string            `json:"contentDisposition,omitempty"`
+	ContentLanguage    string            `json:"contentLanguage,omitempty"`
+	CacheControl       string            `json:"cacheControl,omitempty"`
+}
+
+// PutObjectFanOutRequest - objects created by PutObjectFanOut.
+type PutObjectFanOutRequest struct {
+	Entries []PutObjectFanOutEntry
+
+	// Server side encryption applied to all objects.
+	ServerSideEncryption encrypt.ServerSide
+}
+
+// PutObjectFanOutResponse - result of the creation of one of the
+// objects of a PutObjectFanOut request, Error is set if the object
+// could not be created.
+type PutObjectFanOutResponse struct {
+	Key          string     `json:"key"`
+	ETag         string     `json:"etag,omitempty"`
+	VersionID    string     `json:"versionId,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	Error        string     `json:"error,omitempty"`
+}
+
+// fanOutListField - form field of the entries of a fan-out request.
+const fanOutListField = "x-minio-fanout-list"
+
+// PutObjectFanOut - uploads the content of the reader once and creates
+// an object with this content for each entry of the request, on the
+// server side. This is an extension supported by MinIO server, the
+// upload is a POST policy request. The response of each object is
+// returned in the order of the entries.
+func (c Client) PutObjectFanOut(bucketName string, reader io.Reader, fanOutReq PutObjectFanOutRequest) ([]PutObjectFanOutResponse, error) {
+	return c.PutObjectFanOutWithContext(context.Background(), bucketName, reader, fanOutReq)
+}
+
+// PutObjectFanOutWithContext - Identical to PutObjectFanOut call, but
+// accepts context to facilitate request cancellation.
+func (c Client) PutObjectFanOutWithContext(ctx context.Context, bucketName string, reader io.Reader, fanOutReq PutObjectFanOutRequest) ([]PutObjectFanOutResponse, error) {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return nil, err
+	}
+	if len(fanOutReq.Entries) == 0 {
+		return nil, ErrInvalidArgument("Fan out entries cannot be empty.")
+	}
+
+	// Entries are sent as newline delimited JSON.
+	var entries strings.Builder
+	enc := json.NewEncoder(&entries)
+	for _, entry := range fanOutReq.Entries {
+		if err := ValidateObjectKey(entry.Key); err != nil {
+			return nil, err
+		}
+		if err := enc.Encode(entry); err != nil {
+			return nil, err
+		}
+	}
+
+	// The key of the policy is not used by the server, the objects
+	// are named by the entries.
+	policy := NewPostPolicy()
+	if err := policy.SetBucket(bucketName); err != nil {
+		return nil, err
+	}
+	if err := policy.SetKey(strconv.FormatInt(time.Now().UnixNano(), 16)); err != nil {
+		return nil, err
+	}
+	if err := policy.SetExpires(time.Now().UTC().Add(15 * time.Minute)); err != nil {
+		return nil, err
+	}
+	if fanOutReq.ServerSideEncryption != nil {
+		header := make(http.Header)
+		fanOutReq.ServerSideEncryption.Marshal(header)
+		for k := range header {
+			k, v := strings.ToLower(k), header.Get(k)
+			if err := policy.addNewPolicy(policyCondition{
+				matchType: "eq",
+				condition: "$" + k,
+				value:     v,
+			}); err != nil {
+				return nil, err
+			}
+			policy.formData[k] = v
+		}
+	}
+	u, formData, err := c.PresignedPostPolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	// Stream the form, the content is the last field.
+	pipeReader, pipeWriter := io.Pipe()
+	defer pipeReader.Close()
+	mwriter := multipart.NewWriter(pipeWriter)
+	go func() {
+		var err error
+		defer func() {
+			if err == nil {
+				err = mwriter.Close()
+			}
+			pipeWriter.CloseWithError(err)
+		}()
+		for k, v := range formData {
+			if err = mwriter.WriteField(k, v); err != nil {
+				return
+			}
+		}
+		if err = mwriter.WriteField(fanOutListField, entries.String()); err != nil {
+			return
+		}
+		var fw io.Writer
+		if fw, err = mwriter.CreateFormFile("file", "fanout-content"); err != nil {
+			return
+		}
+		_, err = io.Copy(fw, reader)
+	}()
+
+	req, err := http.NewRequest(http.MethodPost, u.String(), pipeReader)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", mwriter.FormDataContentType())
+	c.setUserAgent(req)
+
+	resp, err := c.do(req)
+	defer closeResponse(resp)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpRespToErrorResponse(resp, bucketName, "")
+	}
+
+	// Responses are newline delimited JSON, one per entry.
+	fanOutResp := make([]PutObjectFanOutResponse, 0, len(fanOutReq.Entries))
+	dec := json.NewDecoder(resp.Body)
+	for dec.More() {
+		var entryResp PutObjectFanOutResponse
+		if err = dec.Decode(&entryResp); err != nil {
+			return nil, err
+		}
+		fanOutResp = append(fanOutResp, entryResp)
+	}
+	return fanOutResp, nil
+}
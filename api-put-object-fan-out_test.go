@@ -0,0 +1,102 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Tests the form sent by PutObjectFanOut and decoding of its response.
+func TestPutObjectFanOut(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/bucket/" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.FormValue("policy") == "" || r.FormValue("x-amz-signature") == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		content, _ := ioutil.ReadAll(file)
+
+		enc := json.NewEncoder(w)
+		scanner := bufio.NewScanner(strings.NewReader(r.FormValue(fanOutListField)))
+		for scanner.Scan() {
+			var entry PutObjectFanOutEntry
+			if err = json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			resp := PutObjectFanOutResponse{Key: entry.Key, ETag: fmt.Sprintf("%s-%s-%s", content, entry.ContentType, entry.UserMetadata["size"])}
+			if entry.Key == "fail" {
+				resp = PutObjectFanOutResponse{Key: entry.Key, Error: "failed"}
+			}
+			enc.Encode(resp)
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fanOutReq := PutObjectFanOutRequest{
+		Entries: []PutObjectFanOutEntry{
+			{Key: "small.jpg", ContentType: "image/jpeg", UserMetadata: map[string]string{"size": "small"}},
+			{Key: "large.png", ContentType: "image/png", UserMetadata: map[string]string{"size": "large"}},
+			{Key: "fail"},
+		},
+	}
+	resp, err := c.PutObjectFanOut("bucket", strings.NewReader("data"), fanOutReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []PutObjectFanOutResponse{
+		{Key: "small.jpg", ETag: "data-image/jpeg-small"},
+		{Key: "large.png", ETag: "data-image/png-large"},
+		{Key: "fail", Error: "failed"},
+	}
+	if !reflect.DeepEqual(resp, expected) {
+		t.Errorf("expected %v, got %v", expected, resp)
+	}
+
+	if _, err = c.PutObjectFanOut("bucket", strings.NewReader("data"), PutObjectFanOutRequest{}); err == nil {
+		t.Error("expected an error for a request without entries")
+	}
+	if _, err = c.PutObjectFanOut("bucket", strings.NewReader("data"), PutObjectFanOutRequest{Entries: []PutObjectFanOutEntry{{}}}); err == nil {
+		t.Error("expected an error for an entry without a key")
+	}
+}
@@ -22,17 +22,15 @@ import (
 	"mime"
 	"os"
 	"path/filepath"
-
-	"github.com/minio/minio-go/v6/pkg/s3utils"
 )
 
 // FPutObjectWithContext - Create an object in a bucket, with contents from file at filePath. Allows request cancellation.
 func (c Client) FPutObjectWithContext(ctx context.Context, bucketName, objectName, filePath string, opts PutObjectOptions) (n int64, err error) {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return 0, err
 	}
-	if err := s3utils.CheckValidObjectName(objectName); err != nil {
+	if err := ValidateObjectKey(objectName); err != nil {
 		return 0, err
 	}
 
@@ -53,12 +51,10 @@ func (c Client) FPutObjectWithContext(ctx context.Context, bucketName, objectNam
 	// Save the file size.
 	fileSize := fileStat.Size()
 
-	// Set contentType based on filepath extension if not given or default
-	// value of "application/octet-stream" if the extension has no associated type.
+	// Set contentType based on filepath extension if not given, otherwise
+	// it is detected from the object name or the file content.
 	if opts.ContentType == "" {
-		if opts.ContentType = mime.TypeByExtension(filepath.Ext(filePath)); opts.ContentType == "" {
-			opts.ContentType = "application/octet-stream"
-		}
+		opts.ContentType = mime.TypeByExtension(filepath.Ext(filePath))
 	}
 	return c.PutObjectWithContext(ctx, bucketName, objectName, fileReader, fileSize, opts)
 }
@@ -28,18 +28,26 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
-	"runtime/debug"
 	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/minio/minio-go/v6/pkg/encrypt"
-	"github.com/minio/minio-go/v6/pkg/s3utils"
 )
 
 func (c Client) putObjectMultipart(ctx context.Context, bucketName, objectName string, reader io.Reader, size int64,
 	opts PutObjectOptions) (n int64, err error) {
-	n, err = c.putObjectMultipartNoStream(ctx, bucketName, objectName, reader, opts)
+	if size >= 0 && !isObject(reader) && isReadAt(reader) {
+		// Upload each part from its own window of the reader, such
+		// that no part needs to be buffered in memory.
+		var readerAt io.ReaderAt
+		if readerAt, err = newReaderAtWindow(reader, size); err != nil {
+			return 0, err
+		}
+		n, err = c.putObjectMultipartStreamFromReadAt(ctx, bucketName, objectName, readerAt, size, opts)
+	} else {
+		n, err = c.putObjectMultipartNoStream(ctx, bucketName, objectName, reader, opts)
+	}
 	if err != nil {
 		errResp := ToErrorResponse(err)
 		// Verify if multipart functionality is not available, if not
@@ -58,10 +66,10 @@ func (c Client) putObjectMultipart(ctx context.Context, bucketName, objectName s
 
 func (c Client) putObjectMultipartNoStream(ctx context.Context, bucketName, objectName string, reader io.Reader, opts PutObjectOptions) (n int64, err error) {
 	// Input validation.
-	if err = s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err = c.validateBucketName(bucketName, false); err != nil {
 		return 0, err
 	}
-	if err = s3utils.CheckValidObjectName(objectName); err != nil {
+	if err = ValidateObjectKey(objectName); err != nil {
 		return 0, err
 	}
 
@@ -86,7 +94,7 @@ func (c Client) putObjectMultipartNoStream(ctx context.Context, bucketName, obje
 
 	defer func() {
 		if err != nil {
-			c.abortMultipartUpload(ctx, bucketName, objectName, uploadID)
+			c.cleanupMultipartUpload(bucketName, objectName, uploadID)
 		}
 	}()
 
@@ -96,17 +104,21 @@ func (c Client) putObjectMultipartNoStream(ctx context.Context, bucketName, obje
 	// Initialize parts uploaded map.
 	partsInfo := make(map[int]ObjectPart)
 
-	// Create a buffer.
-	buf := make([]byte, partSize)
-	defer debug.FreeOSMemory()
+	// Get a buffer for the parts, from the pool and spilled to disk
+	// beyond the configured memory limit.
+	partBuf := newPartBuffer(c.getBufferPool(), partSize, opts)
+	defer partBuf.Close()
 
 	for partNumber <= totalPartsCount {
 		// Choose hash algorithms to be calculated by hashCopyN,
 		// avoid sha256 with non-v4 signature request or
 		// HTTPS connection.
-		hashAlgos, hashSums := c.hashMaterials()
+		hashAlgos, hashSums := c.hashMaterials(opts.SendContentMd5, opts.DisableContentMd5, opts.DisableContentSha256)
+		if opts.SendCRC32C {
+			hashAlgos["crc32c"] = newCRC32C()
+		}
 
-		length, rErr := io.ReadFull(reader, buf)
+		length, rErr := partBuf.readFull(reader)
 		if rErr == io.EOF {
 			break
 		}
@@ -114,20 +126,23 @@ func (c Client) putObjectMultipartNoStream(ctx context.Context, bucketName, obje
 			return 0, rErr
 		}
 
-		// Calculates hash sums while copying partSize bytes into cw.
+		// Calculates hash sums of the part.
 		for k, v := range hashAlgos {
-			v.Write(buf[:length])
+			if err = partBuf.writeTo(v); err != nil {
+				return totalUploadedSize, err
+			}
 			hashSums[k] = v.Sum(nil)
 		}
 
 		// Update progress reader appropriately to the latest offset
 		// as we read from the source.
-		rd := newHook(bytes.NewReader(buf[:length]), opts.Progress)
+		rd := newHook(partBuf.reader(), opts.Progress)
 
 		// Checksums..
 		var (
-			md5Base64 string
-			sha256Hex string
+			md5Base64    string
+			sha256Hex    string
+			crc32cBase64 string
 		)
 		if hashSums["md5"] != nil {
 			md5Base64 = base64.StdEncoding.EncodeToString(hashSums["md5"])
@@ -135,11 +150,14 @@ func (c Client) putObjectMultipartNoStream(ctx context.Context, bucketName, obje
 		if hashSums["sha256"] != nil {
 			sha256Hex = hex.EncodeToString(hashSums["sha256"])
 		}
+		if hashSums["crc32c"] != nil {
+			crc32cBase64 = base64.StdEncoding.EncodeToString(hashSums["crc32c"])
+		}
 
 		// Proceed to upload the part.
 		var objPart ObjectPart
 		objPart, err = c.uploadPart(ctx, bucketName, objectName, uploadID, rd, partNumber,
-			md5Base64, sha256Hex, int64(length), opts.ServerSideEncryption)
+			md5Base64, sha256Hex, crc32cBase64, length, opts)
 		if err != nil {
 			return totalUploadedSize, err
 		}
@@ -148,7 +166,7 @@ func (c Client) putObjectMultipartNoStream(ctx context.Context, bucketName, obje
 		partsInfo[partNumber] = objPart
 
 		// Save successfully uploaded size.
-		totalUploadedSize += int64(length)
+		totalUploadedSize += length
 
 		// Increment part number.
 		partNumber++
@@ -168,14 +186,15 @@ func (c Client) putObjectMultipartNoStream(ctx context.Context, bucketName, obje
 			return 0, ErrInvalidArgument(fmt.Sprintf("Missing part number %d", i))
 		}
 		complMultipartUpload.Parts = append(complMultipartUpload.Parts, CompletePart{
-			ETag:       part.ETag,
-			PartNumber: part.PartNumber,
+			ETag:           part.ETag,
+			PartNumber:     part.PartNumber,
+			ChecksumCRC32C: part.ChecksumCRC32C,
 		})
 	}
 
 	// Sort all completed parts.
 	sort.Sort(completedParts(complMultipartUpload.Parts))
-	if _, err = c.completeMultipartUpload(ctx, bucketName, objectName, uploadID, complMultipartUpload); err != nil {
+	if _, err = c.completeMultipartUpload(ctx, bucketName, objectName, uploadID, complMultipartUpload, opts); err != nil {
 		return totalUploadedSize, err
 	}
 
@@ -186,20 +205,30 @@ func (c Client) putObjectMultipartNoStream(ctx context.Context, bucketName, obje
 // initiateMultipartUpload - Initiates a multipart upload and returns an upload ID.
 func (c Client) initiateMultipartUpload(ctx context.Context, bucketName, objectName string, opts PutObjectOptions) (initiateMultipartUploadResult, error) {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return initiateMultipartUploadResult{}, err
 	}
-	if err := s3utils.CheckValidObjectName(objectName); err != nil {
+	if err := ValidateObjectKey(objectName); err != nil {
 		return initiateMultipartUploadResult{}, err
 	}
 
 	// Initialize url queries.
 	urlValues := make(url.Values)
+	setCustomQuery(urlValues, opts.CustomQuery)
 	urlValues.Set("uploads", "")
 
 	// Set ContentType header.
 	customHeader := opts.Header()
 
+	// Conditional writes are verified when the upload is completed.
+	customHeader.Del("If-None-Match")
+
+	// The checksum algorithm of the parts is chosen when the upload
+	// is initiated.
+	if opts.SendCRC32C {
+		customHeader.Set(amzChecksumAlgorithm, "CRC32C")
+	}
+
 	reqMetadata := requestMetadata{
 		bucketName:   bucketName,
 		objectName:   objectName,
@@ -229,12 +258,12 @@ func (c Client) initiateMultipartUpload(ctx context.Context, bucketName, objectN
 
 // uploadPart - Uploads a part in a multipart upload.
 func (c Client) uploadPart(ctx context.Context, bucketName, objectName, uploadID string, reader io.Reader,
-	partNumber int, md5Base64, sha256Hex string, size int64, sse encrypt.ServerSide) (ObjectPart, error) {
+	partNumber int, md5Base64, sha256Hex, crc32cBase64 string, size int64, opts PutObjectOptions) (ObjectPart, error) {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return ObjectPart{}, err
 	}
-	if err := s3utils.CheckValidObjectName(objectName); err != nil {
+	if err := ValidateObjectKey(objectName); err != nil {
 		return ObjectPart{}, err
 	}
 	if size > maxPartSize {
@@ -263,8 +292,11 @@ func (c Client) uploadPart(ctx context.Context, bucketName, objectName, uploadID
 	// Server-side encryption is supported by the S3 Multipart Upload actions.
 	// Unless you are using a customer-provided encryption key, you don't need
 	// to specify the encryption parameters in each UploadPart request.
-	if sse != nil && sse.Type() == encrypt.SSEC {
-		sse.Marshal(customHeader)
+	if opts.ServerSideEncryption != nil && opts.ServerSideEncryption.Type() == encrypt.SSEC {
+		opts.ServerSideEncryption.Marshal(customHeader)
+	}
+	if crc32cBase64 != "" {
+		customHeader.Set(amzChecksumCRC32C, crc32cBase64)
 	}
 
 	reqMetadata := requestMetadata{
@@ -276,6 +308,7 @@ func (c Client) uploadPart(ctx context.Context, bucketName, objectName, uploadID
 		contentLength:    size,
 		contentMD5Base64: md5Base64,
 		contentSHA256Hex: sha256Hex,
+		unsignedPayload:  opts.DisableContentSha256,
 	}
 
 	// Execute PUT on each part.
@@ -296,17 +329,18 @@ func (c Client) uploadPart(ctx context.Context, bucketName, objectName, uploadID
 	// Trim off the odd double quotes from ETag in the beginning and end.
 	objPart.ETag = strings.TrimPrefix(resp.Header.Get("ETag"), "\"")
 	objPart.ETag = strings.TrimSuffix(objPart.ETag, "\"")
+	objPart.ChecksumCRC32C = crc32cBase64
 	return objPart, nil
 }
 
 // completeMultipartUpload - Completes a multipart upload by assembling previously uploaded parts.
 func (c Client) completeMultipartUpload(ctx context.Context, bucketName, objectName, uploadID string,
-	complete completeMultipartUpload) (completeMultipartUploadResult, error) {
+	complete completeMultipartUpload, opts PutObjectOptions) (completeMultipartUploadResult, error) {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return completeMultipartUploadResult{}, err
 	}
-	if err := s3utils.CheckValidObjectName(objectName); err != nil {
+	if err := ValidateObjectKey(objectName); err != nil {
 		return completeMultipartUploadResult{}, err
 	}
 
@@ -328,6 +362,10 @@ func (c Client) completeMultipartUpload(ctx context.Context, bucketName, objectN
 		contentBody:      completeMultipartUploadBuffer,
 		contentLength:    int64(len(completeMultipartUploadBytes)),
 		contentSHA256Hex: sum256Hex(completeMultipartUploadBytes),
+		errorIn200:       true,
+	}
+	if opts.CreateOnly {
+		reqMetadata.customHeader = http.Header{"If-None-Match": []string{"*"}}
 	}
 
 	// Execute POST to complete multipart upload for an objectName.
@@ -354,7 +392,7 @@ func (c Client) completeMultipartUpload(ctx context.Context, bucketName, objectN
 	if err != nil {
 		// xml parsing failure due to presence an ill-formed xml fragment
 		return completeMultipartUploadResult, err
-	} else if completeMultipartUploadResult.Bucket == "" {
+	} else if completeMultipartUploadResult.Bucket == "" && !c.isLegacyGateway(resp.Header) {
 		// xml's Decode method ignores well-formed xml that don't apply to the type of value supplied.
 		// In this case, it would leave completeMultipartUploadResult with the corresponding zero-values
 		// of the members.
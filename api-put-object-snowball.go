@@ -0,0 +1,292 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+)
+
+// SnowballOptions - options for PutObjectsSnowball.
+type SnowballOptions struct {
+	// Options used for the upload of the archives, user metadata
+	// and other headers are applied to all extracted objects.
+	PutObjectOptions
+
+	// Prefix prepended by the server to the names of all objects.
+	Prefix string
+
+	// InMemory buffers the archives in memory instead of a
+	// temporary file.
+	InMemory bool
+
+	// MaxArchiveSize is the size at which the buffered archive is
+	// uploaded and a new one started, defaults to and may not exceed
+	// 5GiB, the maximum size of a single PUT operation.
+	MaxArchiveSize int64
+}
+
+// SnowballObject - object uploaded by PutObjectsSnowball.
+type SnowballObject struct {
+	// Name of the object.
+	Key string
+
+	// Exact number of bytes read from Content.
+	Size int64
+
+	// Modification time stored in the archive, defaults to the
+	// current time.
+	ModTime time.Time
+
+	// Content of the object.
+	Content io.Reader
+
+	// Close, if set, is called once Content has been read.
+	Close func()
+}
+
+// snowball archive buffer constants.
+const (
+	// tarBlockSize - size of tar records, entries are padded to it.
+	tarBlockSize = 512
+	// tarEntryOverhead - upper bound of the headers of an entry,
+	// including PAX records for long names.
+	tarEntryOverhead = 3 * tarBlockSize
+	// tarTrailerSize - two zero blocks ending an archive.
+	tarTrailerSize = 2 * tarBlockSize
+)
+
+// snowballArchive - tar archive buffered in memory or in a temporary
+// file until it is uploaded.
+type snowballArchive struct {
+	buf   *bytes.Buffer
+	file  *os.File
+	tw    *tar.Writer
+	size  int64
+	count int
+}
+
+func newSnowballArchive(inMemory bool) (*snowballArchive, error) {
+	a := &snowballArchive{}
+	if inMemory {
+		a.buf = new(bytes.Buffer)
+	} else {
+		file, err := ioutil.TempFile("", "snowball-")
+		if err != nil {
+			return nil, err
+		}
+		a.file = file
+	}
+	a.reset()
+	return a, nil
+}
+
+// Write - implements io.Writer for the tar writer.
+func (a *snowballArchive) Write(p []byte) (n int, err error) {
+	if a.buf != nil {
+		n, err = a.buf.Write(p)
+	} else {
+		n, err = a.file.Write(p)
+	}
+	a.size += int64(n)
+	return n, err
+}
+
+// reset - empties the archive.
+func (a *snowballArchive) reset() error {
+	a.size = 0
+	a.count = 0
+	a.tw = tar.NewWriter(a)
+	if a.buf != nil {
+		a.buf.Reset()
+		return nil
+	}
+	if _, err := a.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return a.file.Truncate(0)
+}
+
+// reader - ends the archive and returns a reader of it.
+func (a *snowballArchive) reader() (io.Reader, error) {
+	if err := a.tw.Close(); err != nil {
+		return nil, err
+	}
+	if a.buf != nil {
+		return bytes.NewReader(a.buf.Bytes()), nil
+	}
+	return io.NewSectionReader(a.file, 0, a.size), nil
+}
+
+// close - removes the temporary file if any.
+func (a *snowballArchive) close() {
+	if a.file != nil {
+		a.file.Close()
+		os.Remove(a.file.Name())
+	}
+}
+
+// add - writes the object to the archive.
+func (a *snowballArchive) add(bucketName string, object SnowballObject) error {
+	if object.Close != nil {
+		defer object.Close()
+	}
+	modTime := object.ModTime
+	if modTime.IsZero() {
+		modTime = time.Now()
+	}
+	header := &tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     object.Key,
+		Size:     object.Size,
+		Mode:     0644,
+		ModTime:  modTime,
+	}
+	if err := a.tw.WriteHeader(header); err != nil {
+		return err
+	}
+	n, err := io.CopyN(a.tw, object.Content, object.Size)
+	if err == io.EOF {
+		return ErrUnexpectedEOF(n, object.Size, bucketName, object.Key)
+	}
+	if err != nil {
+		return err
+	}
+	a.count++
+	return nil
+}
+
+// tarEntrySize - returns the upper bound of the size of an object in a
+// tar archive.
+func tarEntrySize(size int64) int64 {
+	return tarEntryOverhead + (size+tarBlockSize-1)/tarBlockSize*tarBlockSize
+}
+
+// PutObjectsSnowball - uploads all objects received from objectsCh as
+// tar archives which the server extracts to individual objects, such
+// that many small objects are uploaded with a few PUT operations. This
+// is an extension supported by MinIO server. The objects are batched
+// in archives of up to MaxArchiveSize bytes, all archives are uploaded
+// once objectsCh is closed. Objects received after an error are not
+// consumed.
+func (c Client) PutObjectsSnowball(bucketName string, objectsCh <-chan SnowballObject, opts SnowballOptions) error {
+	return c.PutObjectsSnowballWithContext(context.Background(), bucketName, objectsCh, opts)
+}
+
+// PutObjectsSnowballWithContext - Identical to PutObjectsSnowball call,
+// but accepts context to facilitate request cancellation.
+func (c Client) PutObjectsSnowballWithContext(ctx context.Context, bucketName string, objectsCh <-chan SnowballObject, opts SnowballOptions) error {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return err
+	}
+	maxArchiveSize := opts.MaxArchiveSize
+	if maxArchiveSize <= 0 {
+		maxArchiveSize = maxSinglePutObjectSize - 1
+	}
+	if maxArchiveSize >= maxSinglePutObjectSize {
+		return ErrEntityTooLarge(maxArchiveSize, maxSinglePutObjectSize-1, bucketName, "")
+	}
+
+	// Options of the archive uploads, the server only extracts
+	// archives uploaded with a single PUT operation.
+	putOpts := opts.PutObjectOptions
+	putOpts.UserMetadata = make(map[string]string, len(opts.UserMetadata)+2)
+	for k, v := range opts.UserMetadata {
+		putOpts.UserMetadata[k] = v
+	}
+	putOpts.UserMetadata["X-Amz-Meta-Snowball-Auto-Extract"] = "true"
+	if opts.Prefix != "" {
+		putOpts.UserMetadata["X-Amz-Meta-Minio-Snowball-Prefix"] = opts.Prefix
+	}
+	putOpts.ContentType = "application/x-tar"
+	putOpts.PartSize = maxPartSize
+
+	archive, err := newSnowballArchive(opts.InMemory)
+	if err != nil {
+		return err
+	}
+	defer archive.close()
+
+	upload := func() error {
+		if archive.count == 0 {
+			return nil
+		}
+		reader, err := archive.reader()
+		if err != nil {
+			return err
+		}
+		archiveName, err := snowballArchiveName()
+		if err != nil {
+			return err
+		}
+		if _, err = c.PutObjectWithContext(ctx, bucketName, archiveName, reader, archive.size, putOpts); err != nil {
+			return err
+		}
+		return archive.reset()
+	}
+
+	for {
+		var object SnowballObject
+		var ok bool
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case object, ok = <-objectsCh:
+		}
+		if !ok {
+			break
+		}
+		if err = ValidateObjectKey(object.Key); err != nil {
+			return err
+		}
+		if object.Size < 0 {
+			return ErrEntityTooSmall(object.Size, bucketName, object.Key)
+		}
+		entrySize := tarEntrySize(object.Size)
+		if entrySize+tarTrailerSize > maxArchiveSize {
+			return ErrEntityTooLarge(object.Size, maxArchiveSize, bucketName, object.Key)
+		}
+		if archive.size+entrySize+tarTrailerSize > maxArchiveSize {
+			if err = upload(); err != nil {
+				return err
+			}
+		}
+		if err = archive.add(bucketName, object); err != nil {
+			return err
+		}
+	}
+	return upload()
+}
+
+// snowballArchiveName - returns a random name for an archive, the
+// archive itself is not stored by the server.
+func snowballArchiveName() (string, error) {
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return "", err
+	}
+	return "snowball-upload-" + hex.EncodeToString(id[:]) + ".tar", nil
+}
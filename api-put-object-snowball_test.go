@@ -0,0 +1,116 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Tests batching objects in archives extracted by the server.
+func TestPutObjectsSnowball(t *testing.T) {
+	var mutex sync.Mutex
+	var archives [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.Header.Get("X-Amz-Meta-Snowball-Auto-Extract") != "true" ||
+			r.Header.Get("X-Amz-Meta-Minio-Snowball-Prefix") != "batch" || r.Header.Get("Content-Type") != "application/x-tar" ||
+			!strings.HasPrefix(r.URL.Path, "/bucket/snowball-upload-") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var names []string
+		tr := tar.NewReader(r.Body)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			data, _ := ioutil.ReadAll(tr)
+			names = append(names, header.Name+":"+string(data))
+		}
+		mutex.Lock()
+		archives = append(archives, names)
+		mutex.Unlock()
+		w.Header().Set("ETag", `"etag"`)
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, inMemory := range []bool{true, false} {
+		archives = nil
+		objectsCh := make(chan SnowballObject)
+		go func() {
+			defer close(objectsCh)
+			for _, name := range []string{"a", "b", "c"} {
+				content := strings.Repeat(name, 1000)
+				objectsCh <- SnowballObject{Key: name, Size: int64(len(content)), Content: strings.NewReader(content)}
+			}
+		}()
+		opts := SnowballOptions{
+			PutObjectOptions: PutObjectOptions{DisableContentSha256: true},
+			Prefix:           "batch",
+			InMemory:         inMemory,
+			MaxArchiveSize:   3 * tarBlockSize * 4,
+		}
+		if err = c.PutObjectsSnowball("bucket", objectsCh, opts); err != nil {
+			t.Fatalf("InMemory %v: %v", inMemory, err)
+		}
+		expected := [][]string{
+			{"a:" + strings.Repeat("a", 1000), "b:" + strings.Repeat("b", 1000)},
+			{"c:" + strings.Repeat("c", 1000)},
+		}
+		if !reflect.DeepEqual(archives, expected) {
+			t.Errorf("InMemory %v: unexpected archives %v", inMemory, archives)
+		}
+	}
+
+	// Invalid objects fail the upload.
+	testCases := []struct {
+		object SnowballObject
+		code   string
+	}{
+		{SnowballObject{Key: "short", Size: 10, Content: bytes.NewReader(make([]byte, 5))}, "UnexpectedEOF"},
+		{SnowballObject{Key: "large", Size: 1 << 20, Content: bytes.NewReader(make([]byte, 1<<20))}, "EntityTooLarge"},
+		{SnowballObject{Key: "", Size: 0, Content: bytes.NewReader(nil)}, "NoSuchKey"},
+	}
+	for i, testCase := range testCases {
+		objectsCh := make(chan SnowballObject, 1)
+		objectsCh <- testCase.object
+		close(objectsCh)
+		err = c.PutObjectsSnowball("bucket", objectsCh, SnowballOptions{InMemory: true, MaxArchiveSize: 1 << 16})
+		if code := ToErrorResponse(err).Code; code != testCase.code {
+			t.Errorf("Test %d: expected %s, got %v", i+1, testCase.code, err)
+		}
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"sort"
 	"strings"
 
@@ -42,7 +43,11 @@ func (c Client) putObjectMultipartStream(ctx context.Context, bucketName, object
 
 	if !isObject(reader) && isReadAt(reader) {
 		// Verify if the reader implements ReadAt and it is not a *minio.Object then we will use parallel uploader.
-		n, err = c.putObjectMultipartStreamFromReadAt(ctx, bucketName, objectName, reader.(io.ReaderAt), size, opts)
+		var readerAt io.ReaderAt
+		if readerAt, err = newReaderAtWindow(reader, size); err != nil {
+			return 0, err
+		}
+		n, err = c.putObjectMultipartStreamFromReadAt(ctx, bucketName, objectName, readerAt, size, opts)
 	} else {
 		n, err = c.putObjectMultipartStreamNoChecksum(ctx, bucketName, objectName, reader, size, opts)
 	}
@@ -82,17 +87,16 @@ type uploadPartReq struct {
 // NOTE: This function is meant to be used for all readers which
 // implement io.ReaderAt which allows us for resuming multipart
 // uploads but reading at an offset, which would avoid re-read the
-// data which was already uploaded. Internally this function uses
-// temporary files for staging all the data, these temporary files are
-// cleaned automatically when the caller i.e http client closes the
-// stream after uploading all the contents successfully.
+// data which was already uploaded. Each part is read through its own
+// io.SectionReader while it is sent, parts are uploaded in parallel
+// without buffering them in memory.
 func (c Client) putObjectMultipartStreamFromReadAt(ctx context.Context, bucketName, objectName string,
 	reader io.ReaderAt, size int64, opts PutObjectOptions) (n int64, err error) {
 	// Input validation.
-	if err = s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err = c.validateBucketName(bucketName, false); err != nil {
 		return 0, err
 	}
-	if err = s3utils.CheckValidObjectName(objectName); err != nil {
+	if err = ValidateObjectKey(objectName); err != nil {
 		return 0, err
 	}
 
@@ -114,7 +118,7 @@ func (c Client) putObjectMultipartStreamFromReadAt(ctx context.Context, bucketNa
 	// to relinquish storage space.
 	defer func() {
 		if err != nil {
-			c.abortMultipartUpload(ctx, bucketName, objectName, uploadID)
+			c.cleanupMultipartUpload(bucketName, objectName, uploadID)
 		}
 	}()
 
@@ -142,53 +146,93 @@ func (c Client) putObjectMultipartStreamFromReadAt(ctx context.Context, bucketNa
 		uploadPartsCh <- uploadPartReq{PartNum: p, Part: nil}
 	}
 	close(uploadPartsCh)
-	// Receive each part number from the channel allowing three parallel uploads.
-	for w := 1; w <= opts.getNumThreads(); w++ {
-		go func(partSize int64) {
-			// Each worker will draw from the part channel and upload in parallel.
-			for uploadReq := range uploadPartsCh {
+	// Uploads a part read through its own window of the reader.
+	uploadPartAt := func(uploadReq uploadPartReq) uploadedPartRes {
+		// If partNumber was not uploaded we calculate the missing
+		// part offset and size. For all other part numbers we
+		// calculate offset based on multiples of partSize.
+		readOffset := int64(uploadReq.PartNum-1) * partSize
+		readSize := partSize
+
+		// As a special case if partNumber is lastPartNumber, we
+		// calculate the offset based on the last part size.
+		if uploadReq.PartNum == lastPartNumber {
+			readOffset = (size - lastPartSize)
+			readSize = lastPartSize
+		}
+
+		// Get a section reader on a particular offset, the part
+		// is read through it while uploading and never buffered.
+		var sectionReader io.Reader = io.NewSectionReader(reader, readOffset, readSize)
+
+		// Compute the MD5 sum of the part if requested.
+		var md5Base64 string
+		if opts.SendContentMd5 {
+			var err error
+			md5Base64, sectionReader, err = contentMD5(sectionReader, readSize)
+			if err != nil {
+				return uploadedPartRes{Size: 0, Error: err}
+			}
+		}
+
+		// Compute the CRC32C checksum of the part if requested.
+		var crc32cBase64 string
+		if opts.SendCRC32C {
+			var err error
+			crc32cBase64, sectionReader, err = contentCRC32C(sectionReader, readSize)
+			if err != nil {
+				return uploadedPartRes{Size: 0, Error: err}
+			}
+		}
 
-				// If partNumber was not uploaded we calculate the missing
-				// part offset and size. For all other part numbers we
-				// calculate offset based on multiples of partSize.
-				readOffset := int64(uploadReq.PartNum-1) * partSize
+		// Proceed to upload the part.
+		objPart, err := c.uploadPart(ctx, bucketName, objectName, uploadID,
+			newHook(sectionReader, opts.Progress), uploadReq.PartNum,
+			md5Base64, "", crc32cBase64, readSize, opts)
+		if err != nil {
+			return uploadedPartRes{Size: 0, Error: err}
+		}
+
+		// Save successfully uploaded part metadata.
+		uploadReq.Part = &objPart
+		return uploadedPartRes{
+			Size:    objPart.Size,
+			PartNum: uploadReq.PartNum,
+			Part:    uploadReq.Part,
+			Error:   nil,
+		}
+	}
 
-				// As a special case if partNumber is lastPartNumber, we
-				// calculate the offset based on the last part size.
-				if uploadReq.PartNum == lastPartNumber {
-					readOffset = (size - lastPartSize)
-					partSize = lastPartSize
+	// Receive each part number from the channel allowing parallel
+	// uploads, there is no use for more workers than parts.
+	numThreads := opts.getNumThreads()
+	if numThreads > totalPartsCount {
+		numThreads = totalPartsCount
+	}
+	var tuner *concurrencyTuner
+	if opts.AutoTuneThreads {
+		tuner = newConcurrencyTuner(numThreads)
+	}
+	for w := 1; w <= numThreads; w++ {
+		go func() {
+			// Each worker will draw from the part channel and upload in parallel.
+			for uploadReq := range uploadPartsCh {
+				if tuner != nil {
+					tuner.acquire()
+				}
+				uploadRes := uploadPartAt(uploadReq)
+				if tuner != nil {
+					tuner.release(uploadRes.Size)
 				}
 
-				// Get a section reader on a particular offset.
-				sectionReader := newHook(io.NewSectionReader(reader, readOffset, partSize), opts.Progress)
-
-				// Proceed to upload the part.
-				var objPart ObjectPart
-				objPart, err = c.uploadPart(ctx, bucketName, objectName, uploadID,
-					sectionReader, uploadReq.PartNum,
-					"", "", partSize, opts.ServerSideEncryption)
-				if err != nil {
-					uploadedPartsCh <- uploadedPartRes{
-						Size:  0,
-						Error: err,
-					}
+				// Send the part info through the channel.
+				uploadedPartsCh <- uploadRes
+				if uploadRes.Error != nil {
 					// Exit the goroutine.
 					return
 				}
-
-				// Save successfully uploaded part metadata.
-				uploadReq.Part = &objPart
-
-				// Send successful part info through the channel.
-				uploadedPartsCh <- uploadedPartRes{
-					Size:    objPart.Size,
-					PartNum: uploadReq.PartNum,
-					Part:    uploadReq.Part,
-					Error:   nil,
-				}
 			}
-		}(partSize)
+		}()
 	}
 
 	// Gather the responses as they occur and update any
@@ -208,8 +252,9 @@ func (c Client) putObjectMultipartStreamFromReadAt(ctx context.Context, bucketNa
 		totalUploadedSize += uploadRes.Size
 		// Store the parts to be completed in order.
 		complMultipartUpload.Parts = append(complMultipartUpload.Parts, CompletePart{
-			ETag:       part.ETag,
-			PartNumber: part.PartNumber,
+			ETag:           part.ETag,
+			PartNumber:     part.PartNumber,
+			ChecksumCRC32C: part.ChecksumCRC32C,
 		})
 	}
 
@@ -220,7 +265,7 @@ func (c Client) putObjectMultipartStreamFromReadAt(ctx context.Context, bucketNa
 
 	// Sort all completed parts.
 	sort.Sort(completedParts(complMultipartUpload.Parts))
-	_, err = c.completeMultipartUpload(ctx, bucketName, objectName, uploadID, complMultipartUpload)
+	_, err = c.completeMultipartUpload(ctx, bucketName, objectName, uploadID, complMultipartUpload, opts)
 	if err != nil {
 		return totalUploadedSize, err
 	}
@@ -232,10 +277,10 @@ func (c Client) putObjectMultipartStreamFromReadAt(ctx context.Context, bucketNa
 func (c Client) putObjectMultipartStreamNoChecksum(ctx context.Context, bucketName, objectName string,
 	reader io.Reader, size int64, opts PutObjectOptions) (n int64, err error) {
 	// Input validation.
-	if err = s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err = c.validateBucketName(bucketName, false); err != nil {
 		return 0, err
 	}
-	if err = s3utils.CheckValidObjectName(objectName); err != nil {
+	if err = ValidateObjectKey(objectName); err != nil {
 		return 0, err
 	}
 
@@ -256,7 +301,7 @@ func (c Client) putObjectMultipartStreamNoChecksum(ctx context.Context, bucketNa
 	// storage space.
 	defer func() {
 		if err != nil {
-			c.abortMultipartUpload(ctx, bucketName, objectName, uploadID)
+			c.cleanupMultipartUpload(bucketName, objectName, uploadID)
 		}
 	}()
 
@@ -269,18 +314,37 @@ func (c Client) putObjectMultipartStreamNoChecksum(ctx context.Context, bucketNa
 	// Part number always starts with '1'.
 	var partNumber int
 	for partNumber = 1; partNumber <= totalPartsCount; partNumber++ {
+		if partNumber == totalPartsCount {
+			partSize = lastPartSize
+		}
+		partReader := io.LimitReader(reader, partSize)
+
+		// Compute the MD5 sum of the part if requested.
+		var md5Base64 string
+		if opts.SendContentMd5 {
+			md5Base64, partReader, err = contentMD5(partReader, partSize)
+			if err != nil {
+				return totalUploadedSize, err
+			}
+		}
+
+		// Compute the CRC32C checksum of the part if requested.
+		var crc32cBase64 string
+		if opts.SendCRC32C {
+			crc32cBase64, partReader, err = contentCRC32C(partReader, partSize)
+			if err != nil {
+				return totalUploadedSize, err
+			}
+		}
+
 		// Update progress reader appropriately to the latest offset
 		// as we read from the source.
-		hookReader := newHook(reader, opts.Progress)
+		hookReader := newHook(partReader, opts.Progress)
 
 		// Proceed to upload the part.
-		if partNumber == totalPartsCount {
-			partSize = lastPartSize
-		}
 		var objPart ObjectPart
 		objPart, err = c.uploadPart(ctx, bucketName, objectName, uploadID,
-			io.LimitReader(hookReader, partSize),
-			partNumber, "", "", partSize, opts.ServerSideEncryption)
+			hookReader, partNumber, md5Base64, "", crc32cBase64, partSize, opts)
 		if err != nil {
 			return totalUploadedSize, err
 		}
@@ -310,14 +374,15 @@ func (c Client) putObjectMultipartStreamNoChecksum(ctx context.Context, bucketNa
 			return 0, ErrInvalidArgument(fmt.Sprintf("Missing part number %d", i))
 		}
 		complMultipartUpload.Parts = append(complMultipartUpload.Parts, CompletePart{
-			ETag:       part.ETag,
-			PartNumber: part.PartNumber,
+			ETag:           part.ETag,
+			PartNumber:     part.PartNumber,
+			ChecksumCRC32C: part.ChecksumCRC32C,
 		})
 	}
 
 	// Sort all completed parts.
 	sort.Sort(completedParts(complMultipartUpload.Parts))
-	_, err = c.completeMultipartUpload(ctx, bucketName, objectName, uploadID, complMultipartUpload)
+	_, err = c.completeMultipartUpload(ctx, bucketName, objectName, uploadID, complMultipartUpload, opts)
 	if err != nil {
 		return totalUploadedSize, err
 	}
@@ -330,10 +395,10 @@ func (c Client) putObjectMultipartStreamNoChecksum(ctx context.Context, bucketNa
 // is used for Google Cloud Storage since Google's multipart API is not S3 compatible.
 func (c Client) putObjectNoChecksum(ctx context.Context, bucketName, objectName string, reader io.Reader, size int64, opts PutObjectOptions) (n int64, err error) {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return 0, err
 	}
-	if err := s3utils.CheckValidObjectName(objectName); err != nil {
+	if err := ValidateObjectKey(objectName); err != nil {
 		return 0, err
 	}
 
@@ -353,13 +418,30 @@ func (c Client) putObjectNoChecksum(ctx context.Context, bucketName, objectName
 		}
 	}
 
+	// Compute the MD5 sum of the object if requested, objects of
+	// unknown size are uploaded without it.
+	var md5Base64 string
+	if opts.SendContentMd5 && size >= 0 {
+		md5Base64, reader, err = contentMD5(reader, size)
+		if err != nil {
+			return 0, err
+		}
+	}
+	var crc32cBase64 string
+	if opts.SendCRC32C && size >= 0 {
+		crc32cBase64, reader, err = contentCRC32C(reader, size)
+		if err != nil {
+			return 0, err
+		}
+	}
+
 	// Update progress reader appropriately to the latest offset as we
 	// read from the source.
 	readSeeker := newHook(reader, opts.Progress)
 
-	// This function does not calculate sha256 and md5sum for payload.
+	// This function does not calculate sha256 for payload.
 	// Execute put object.
-	st, err := c.putObjectDo(ctx, bucketName, objectName, readSeeker, "", "", size, opts)
+	st, err := c.putObjectDo(ctx, bucketName, objectName, readSeeker, md5Base64, "", crc32cBase64, size, opts)
 	if err != nil {
 		return 0, err
 	}
@@ -371,26 +453,35 @@ func (c Client) putObjectNoChecksum(ctx context.Context, bucketName, objectName
 
 // putObjectDo - executes the put object http operation.
 // NOTE: You must have WRITE permissions on a bucket to add an object to it.
-func (c Client) putObjectDo(ctx context.Context, bucketName, objectName string, reader io.Reader, md5Base64, sha256Hex string, size int64, opts PutObjectOptions) (ObjectInfo, error) {
+func (c Client) putObjectDo(ctx context.Context, bucketName, objectName string, reader io.Reader, md5Base64, sha256Hex, crc32cBase64 string, size int64, opts PutObjectOptions) (ObjectInfo, error) {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return ObjectInfo{}, err
 	}
-	if err := s3utils.CheckValidObjectName(objectName); err != nil {
+	if err := ValidateObjectKey(objectName); err != nil {
 		return ObjectInfo{}, err
 	}
 	// Set headers.
 	customHeader := opts.Header()
+	if crc32cBase64 != "" {
+		customHeader.Set(amzChecksumCRC32C, crc32cBase64)
+	}
+
+	// Set custom query parameters.
+	urlValues := make(url.Values)
+	setCustomQuery(urlValues, opts.CustomQuery)
 
 	// Populate request metadata.
 	reqMetadata := requestMetadata{
 		bucketName:       bucketName,
 		objectName:       objectName,
+		queryValues:      urlValues,
 		customHeader:     customHeader,
 		contentBody:      reader,
 		contentLength:    size,
 		contentMD5Base64: md5Base64,
 		contentSHA256Hex: sha256Hex,
+		unsignedPayload:  opts.DisableContentSha256,
 	}
 
 	// Execute PUT an objectName.
@@ -0,0 +1,102 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// ObjectWriter - an io.WriteCloser uploading all data written to it to
+// an object, returned by OpenWriter. Data is buffered in memory one
+// part at a time as for PutObject with an unknown size, and the upload
+// is completed when the writer is closed.
+type ObjectWriter struct {
+	pipeWriter *io.PipeWriter
+	doneCh     chan struct{}
+
+	// Error of the upload, set before doneCh is closed.
+	err error
+
+	closeOnce sync.Once
+}
+
+// OpenWriter - returns an ObjectWriter uploading everything written to
+// it to objectName, the object is created when Close returns without
+// an error. Writers can be chained with gzip.Writer, tar.Writer and
+// encoders, close them before closing the ObjectWriter.
+func (c Client) OpenWriter(bucketName, objectName string, opts PutObjectOptions) (*ObjectWriter, error) {
+	return c.OpenWriterWithContext(context.Background(), bucketName, objectName, opts)
+}
+
+// OpenWriterWithContext - Identical to OpenWriter call, but accepts
+// context to facilitate request cancellation.
+func (c Client) OpenWriterWithContext(ctx context.Context, bucketName, objectName string, opts PutObjectOptions) (*ObjectWriter, error) {
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return nil, err
+	}
+	if err := ValidateObjectKey(objectName); err != nil {
+		return nil, err
+	}
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	w := &ObjectWriter{
+		pipeWriter: pipeWriter,
+		doneCh:     make(chan struct{}),
+	}
+	go func() {
+		defer close(w.doneCh)
+		_, w.err = c.PutObjectWithContext(ctx, bucketName, objectName, pipeReader, -1, opts)
+		if w.err == nil {
+			// Reading stops at EOF which is only seen once the
+			// writer is closed.
+			return
+		}
+		// Fail all further writes with the upload error.
+		pipeReader.CloseWithError(w.err)
+	}()
+	return w, nil
+}
+
+// Write - writes len(p) bytes to the object, blocks while the buffered
+// part is uploaded. Fails with the upload error once the upload failed.
+func (w *ObjectWriter) Write(p []byte) (n int, err error) {
+	return w.pipeWriter.Write(p)
+}
+
+// Close - completes the upload and waits for it to finish, returns
+// the error of the upload if any.
+func (w *ObjectWriter) Close() error {
+	return w.CloseWithError(nil)
+}
+
+// CloseWithError - aborts the upload with err if err is not nil, the
+// object is not created and all uploaded parts are removed. Completes
+// the upload as Close otherwise.
+func (w *ObjectWriter) CloseWithError(err error) error {
+	w.closeOnce.Do(func() {
+		w.pipeWriter.CloseWithError(err)
+	})
+	<-w.doneCh
+	return w.err
+}
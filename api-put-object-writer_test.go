@@ -0,0 +1,104 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// Tests uploading through an ObjectWriter chained with a gzip.Writer.
+func TestObjectWriter(t *testing.T) {
+	server := newUploadTestServer()
+	defer server.Close()
+	c := server.client(t)
+
+	w, err := c.OpenWriter("bucket", "object.gz", PutObjectOptions{PartSize: absMinPartSize})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gw := gzip.NewWriter(w)
+	if _, err = io.Copy(gw, strings.NewReader(strings.Repeat("minio", 1024))); err != nil {
+		t.Fatal(err)
+	}
+	if err = gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	requests := server.takeRequests()
+	if len(requests) != 1 || !strings.HasPrefix(requests[0], "put ") {
+		t.Errorf("expected a single PUT request, got %v", requests)
+	}
+
+	// Closing again returns the same result.
+	if err = w.Close(); err != nil {
+		t.Errorf("expected no error closing again, got %v", err)
+	}
+
+	// Uploads spanning several parts are completed on Close.
+	w, err = c.OpenWriter("bucket", "object", PutObjectOptions{PartSize: absMinPartSize})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = w.Write(make([]byte, absMinPartSize+1)); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"initiate", "part 1 5242880", "part 2 1", "complete"}
+	if requests = server.takeRequests(); !reflect.DeepEqual(requests, expected) {
+		t.Errorf("expected requests %v, got %v", expected, requests)
+	}
+}
+
+// Tests aborting an upload with CloseWithError.
+func TestObjectWriterAbort(t *testing.T) {
+	server := newUploadTestServer()
+	defer server.Close()
+	c := server.client(t)
+
+	if _, err := c.OpenWriter("bucket", "", PutObjectOptions{}); err == nil {
+		t.Error("expected an error for an empty object name")
+	}
+
+	w, err := c.OpenWriter("bucket", "object", PutObjectOptions{PartSize: absMinPartSize})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = w.Write(make([]byte, absMinPartSize+1)); err != nil {
+		t.Fatal(err)
+	}
+	abortErr := errors.New("aborted")
+	if err = w.CloseWithError(abortErr); err != abortErr {
+		t.Fatalf("expected %v, got %v", abortErr, err)
+	}
+	expected := []string{"initiate", "part 1 5242880", "DELETE"}
+	if requests := server.takeRequests(); !reflect.DeepEqual(requests, expected) {
+		t.Errorf("expected requests %v, got %v", expected, requests)
+	}
+	if _, err = w.Write([]byte("data")); err == nil {
+		t.Error("expected an error writing after abort")
+	}
+}
@@ -18,13 +18,14 @@
 package minio
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
-	"runtime/debug"
+	"net/url"
 	"sort"
+	"time"
 
 	"github.com/minio/minio-go/v6/pkg/encrypt"
 	"github.com/minio/minio-go/v6/pkg/s3utils"
@@ -40,11 +41,85 @@ type PutObjectOptions struct {
 	ContentDisposition      string
 	ContentLanguage         string
 	CacheControl            string
+	Expires                 time.Time
 	ServerSideEncryption    encrypt.ServerSide
 	NumThreads              uint
 	StorageClass            string
 	WebsiteRedirectLocation string
 	PartSize                uint64
+
+	// SendContentMd5 computes the MD5 sum of the object, or of each
+	// part for multipart uploads, and sends it as the Content-MD5
+	// header. Readers which cannot seek are buffered in memory.
+	SendContentMd5 bool
+
+	// SendCRC32C computes the CRC32C checksum of the object, or of
+	// each part for multipart uploads, and sends it as the
+	// X-Amz-Checksum-Crc32c header which S3 verifies and stores with
+	// the object. Readers which cannot seek are buffered in memory.
+	SendCRC32C bool
+
+	// DisableContentSha256 skips computing the SHA256 sum of the
+	// payload, including the streaming signature, and sends the
+	// request with an unsigned payload instead.
+	DisableContentSha256 bool
+
+	// DisableContentMd5 skips computing the MD5 sum which is sent by
+	// default with the parts of uploads over secure connections and
+	// of anonymous uploads. It is ignored if SendContentMd5 is set or
+	// the object is uploaded with Object Lock settings.
+	DisableContentMd5 bool
+
+	// CreateOnly makes the upload fail with a PreconditionFailed
+	// error if an object already exists with the same name, the
+	// request is sent with the If-None-Match: * header. Multipart
+	// uploads are checked when the upload is completed.
+	CreateOnly bool
+
+	// AutoTuneThreads adjusts the number of parts of a multipart
+	// upload which are uploaded in parallel from the measured
+	// throughput, up to NumThreads or 16 if it is not set. It is
+	// only used for readers which can be read at an offset.
+	AutoTuneThreads bool
+
+	// MaxMemoryBuffer limits the memory used to buffer each part of
+	// uploads of unknown size, or of readers which cannot be read at
+	// an offset with signature V2. The rest of larger parts is
+	// spilled to a temporary file in SpillDir. Zero buffers whole
+	// parts in memory.
+	MaxMemoryBuffer uint64
+
+	// SpillDir is the directory of the temporary files used by
+	// MaxMemoryBuffer, the default directory for temporary files is
+	// used if empty.
+	SpillDir string
+
+	// Mode and RetainUntilDate lock the object until the date, both
+	// are required. LegalHold places a legal hold on the object.
+	// Object locking must be enabled on the bucket, the MD5 sum of
+	// these uploads is always sent.
+	Mode            *RetentionMode
+	RetainUntilDate *time.Time
+	LegalHold       LegalHoldStatus
+
+	// Compression compresses the data while it is uploaded and sets
+	// the Content-Encoding of the object accordingly, which cannot be
+	// set by ContentEncoding then. The size of the upload is the size
+	// of the compressed data.
+	Compression Compressor
+
+	// CustomHeaders are sent with the upload request, or the request
+	// initiating a multipart upload, so that headers of extensions
+	// of the service can be used. The fields of the options take
+	// precedence, headers set by the client and its signers such as
+	// Authorization or X-Amz-Date are rejected.
+	CustomHeaders http.Header
+
+	// CustomQuery are query parameters signed and sent with the same
+	// requests as CustomHeaders, so that subresources of extensions
+	// of the service can be used. Parameters set by the client and
+	// its signers such as uploadId or X-Amz-Signature are rejected.
+	CustomQuery url.Values
 }
 
 // getNumThreads - gets the number of threads to be used in the multipart
@@ -52,6 +127,8 @@ type PutObjectOptions struct {
 func (opts PutObjectOptions) getNumThreads() (numThreads int) {
 	if opts.NumThreads > 0 {
 		numThreads = int(opts.NumThreads)
+	} else if opts.AutoTuneThreads {
+		numThreads = maxAutoTunedWorkers
 	} else {
 		numThreads = totalWorkers
 	}
@@ -62,6 +139,7 @@ func (opts PutObjectOptions) getNumThreads() (numThreads int) {
 // PutObjectOptions struct
 func (opts PutObjectOptions) Header() (header http.Header) {
 	header = make(http.Header)
+	setCustomHeaders(header, opts.CustomHeaders)
 
 	if opts.ContentType != "" {
 		header["Content-Type"] = []string{opts.ContentType}
@@ -80,6 +158,9 @@ func (opts PutObjectOptions) Header() (header http.Header) {
 	if opts.CacheControl != "" {
 		header["Cache-Control"] = []string{opts.CacheControl}
 	}
+	if !opts.Expires.IsZero() {
+		header["Expires"] = []string{opts.Expires.UTC().Format(http.TimeFormat)}
+	}
 	if opts.ServerSideEncryption != nil {
 		opts.ServerSideEncryption.Marshal(header)
 	}
@@ -89,6 +170,18 @@ func (opts PutObjectOptions) Header() (header http.Header) {
 	if opts.WebsiteRedirectLocation != "" {
 		header[amzWebsiteRedirectLocation] = []string{opts.WebsiteRedirectLocation}
 	}
+	if opts.CreateOnly {
+		header["If-None-Match"] = []string{"*"}
+	}
+	if opts.Mode != nil {
+		header[amzLockMode] = []string{opts.Mode.String()}
+	}
+	if opts.RetainUntilDate != nil {
+		header[amzLockRetainUntilDate] = []string{opts.RetainUntilDate.UTC().Format(time.RFC3339)}
+	}
+	if opts.LegalHold != "" {
+		header[amzLockLegalHold] = []string{opts.LegalHold.String()}
+	}
 	for k, v := range opts.UserMetadata {
 		if !isAmzHeader(k) && !isStandardHeader(k) && !isStorageClassHeader(k) {
 			header["X-Amz-Meta-"+k] = []string{v}
@@ -109,6 +202,24 @@ func (opts PutObjectOptions) validate() (err error) {
 			return ErrInvalidArgument(v + " unsupported user defined metadata value")
 		}
 	}
+	if err = validateCustomHeaders(opts.CustomHeaders); err != nil {
+		return err
+	}
+	if err = validateCustomQuery(opts.CustomQuery); err != nil {
+		return err
+	}
+	if opts.Compression != nil && opts.ContentEncoding != "" {
+		return ErrInvalidArgument("Content encoding cannot be set for compressed uploads.")
+	}
+	if (opts.Mode != nil) != (opts.RetainUntilDate != nil) {
+		return ErrInvalidArgument("Retention mode and retain until date must be both present or both absent.")
+	}
+	if opts.Mode != nil && !opts.Mode.IsValid() {
+		return ErrInvalidArgument("Invalid retention mode `" + opts.Mode.String() + "`.")
+	}
+	if opts.LegalHold != "" && !opts.LegalHold.IsValid() {
+		return ErrInvalidArgument("Invalid legal hold status `" + opts.LegalHold.String() + "`.")
+	}
 	return nil
 }
 
@@ -128,9 +239,13 @@ func (a completedParts) Less(i, j int) bool { return a[i].PartNumber < a[j].Part
 //    single atomic Put operation.
 //  - For size larger than 128MiB PutObject automatically does a
 //    multipart Put operation.
-//  - For size input as -1 PutObject does a multipart Put operation
-//    until input stream reaches EOF. Maximum object size that can
-//    be uploaded through this operation will be 5TiB.
+//  - For size input as -1 PutObject buffers the input stream in
+//    memory one part at a time, streams ending within the first part
+//    are uploaded with a single Put operation and longer streams with
+//    a multipart Put operation until the input stream reaches EOF.
+//    Maximum object size that can be uploaded through this operation
+//    will be 5TiB, a smaller PartSize lowers the memory used but
+//    limits the object to 10000 parts of this size.
 func (c Client) PutObject(bucketName, objectName string, reader io.Reader, objectSize int64,
 	opts PutObjectOptions) (n int64, err error) {
 	return c.PutObjectWithContext(context.Background(), bucketName, objectName, reader, objectSize, opts)
@@ -142,6 +257,11 @@ func (c Client) putObjectCommon(ctx context.Context, bucketName, objectName stri
 		return 0, ErrEntityTooLarge(size, maxMultipartPutObjectSize, bucketName, objectName)
 	}
 
+	// Uploads of locked objects require the MD5 sum.
+	if opts.Mode != nil || opts.LegalHold != "" {
+		opts.SendContentMd5 = true
+	}
+
 	// NOTE: Streaming signature is not supported by GCS.
 	if s3utils.IsGoogleEndpoint(*c.endpointURL) {
 		// Do not compute MD5 for Google Cloud Storage.
@@ -173,10 +293,10 @@ func (c Client) putObjectCommon(ctx context.Context, bucketName, objectName stri
 
 func (c Client) putObjectMultipartStreamNoLength(ctx context.Context, bucketName, objectName string, reader io.Reader, opts PutObjectOptions) (n int64, err error) {
 	// Input validation.
-	if err = s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err = c.validateBucketName(bucketName, false); err != nil {
 		return 0, err
 	}
-	if err = s3utils.CheckValidObjectName(objectName); err != nil {
+	if err = ValidateObjectKey(objectName); err != nil {
 		return 0, err
 	}
 
@@ -187,11 +307,53 @@ func (c Client) putObjectMultipartStreamNoLength(ctx context.Context, bucketName
 	// Complete multipart upload.
 	var complMultipartUpload completeMultipartUpload
 
-	// Calculate the optimal parts info for a given size.
-	totalPartsCount, partSize, _, err := optimalPartInfo(-1, opts.PartSize)
+	// Calculate the optimal parts info for a given size, a configured
+	// part size limits the object to as many parts of this size.
+	objectSize := int64(-1)
+	if opts.PartSize > 0 && opts.PartSize*maxPartsCount < maxMultipartPutObjectSize {
+		objectSize = int64(opts.PartSize * maxPartsCount)
+	}
+	totalPartsCount, partSize, _, err := optimalPartInfo(objectSize, opts.PartSize)
 	if err != nil {
 		return 0, err
 	}
+
+	// Get a buffer for the parts, from the pool and spilled to disk
+	// beyond the configured memory limit.
+	partBuf := newPartBuffer(c.getBufferPool(), partSize, opts)
+	defer partBuf.Close()
+
+	// Read the first part before initiating the multipart upload,
+	// streams which fit in a single part are uploaded with a single
+	// Put operation instead.
+	length, rErr := partBuf.readFull(reader)
+	if rErr != nil && rErr != io.EOF && rErr != io.ErrUnexpectedEOF {
+		return 0, rErr
+	}
+	if rErr != nil {
+		var md5Base64 string
+		if opts.SendContentMd5 {
+			if md5Base64, err = partBuf.sumMD5Base64(); err != nil {
+				return 0, err
+			}
+		}
+		var crc32cBase64 string
+		if opts.SendCRC32C {
+			if crc32cBase64, err = partBuf.sumCRC32CBase64(); err != nil {
+				return 0, err
+			}
+		}
+		rd := newHook(partBuf.reader(), opts.Progress)
+		st, err := c.putObjectDo(ctx, bucketName, objectName, rd, md5Base64, "", crc32cBase64, length, opts)
+		if err != nil {
+			return 0, err
+		}
+		if st.Size != length {
+			return 0, ErrUnexpectedEOF(st.Size, length, bucketName, objectName)
+		}
+		return length, nil
+	}
+
 	// Initiate a new multipart upload.
 	uploadID, err := c.newUploadID(ctx, bucketName, objectName, opts)
 	if err != nil {
@@ -200,7 +362,7 @@ func (c Client) putObjectMultipartStreamNoLength(ctx context.Context, bucketName
 
 	defer func() {
 		if err != nil {
-			c.abortMultipartUpload(ctx, bucketName, objectName, uploadID)
+			c.cleanupMultipartUpload(bucketName, objectName, uploadID)
 		}
 	}()
 
@@ -210,26 +372,40 @@ func (c Client) putObjectMultipartStreamNoLength(ctx context.Context, bucketName
 	// Initialize parts uploaded map.
 	partsInfo := make(map[int]ObjectPart)
 
-	// Create a buffer.
-	buf := make([]byte, partSize)
-	defer debug.FreeOSMemory()
-
 	for partNumber <= totalPartsCount {
-		length, rErr := io.ReadFull(reader, buf)
-		if rErr == io.EOF && partNumber > 1 {
-			break
+		// The first part is already read.
+		if partNumber > 1 {
+			length, rErr = partBuf.readFull(reader)
+			if rErr == io.EOF {
+				break
+			}
+			if rErr != nil && rErr != io.ErrUnexpectedEOF {
+				return totalUploadedSize, rErr
+			}
+		}
+		// Compute the MD5 sum of the part if requested.
+		var md5Base64 string
+		if opts.SendContentMd5 {
+			if md5Base64, err = partBuf.sumMD5Base64(); err != nil {
+				return totalUploadedSize, err
+			}
 		}
-		if rErr != nil && rErr != io.ErrUnexpectedEOF && rErr != io.EOF {
-			return 0, rErr
+		// Compute the CRC32C checksum of the part if requested.
+		var crc32cBase64 string
+		if opts.SendCRC32C {
+			if crc32cBase64, err = partBuf.sumCRC32CBase64(); err != nil {
+				return totalUploadedSize, err
+			}
 		}
+
 		// Update progress reader appropriately to the latest offset
 		// as we read from the source.
-		rd := newHook(bytes.NewReader(buf[:length]), opts.Progress)
+		rd := newHook(partBuf.reader(), opts.Progress)
 
 		// Proceed to upload the part.
 		var objPart ObjectPart
 		objPart, err = c.uploadPart(ctx, bucketName, objectName, uploadID, rd, partNumber,
-			"", "", int64(length), opts.ServerSideEncryption)
+			md5Base64, "", crc32cBase64, length, opts)
 		if err != nil {
 			return totalUploadedSize, err
 		}
@@ -238,18 +414,31 @@ func (c Client) putObjectMultipartStreamNoLength(ctx context.Context, bucketName
 		partsInfo[partNumber] = objPart
 
 		// Save successfully uploaded size.
-		totalUploadedSize += int64(length)
+		totalUploadedSize += length
 
 		// Increment part number.
 		partNumber++
 
-		// For unknown size, Read EOF we break away.
+		// For unknown size, a short part is the last one.
 		// We do not have to upload till totalPartsCount.
-		if rErr == io.EOF {
+		if rErr == io.ErrUnexpectedEOF {
 			break
 		}
 	}
 
+	// All parts are used up, the stream must be exhausted or the
+	// object would be silently truncated.
+	if rErr == nil {
+		var extra int64
+		if extra, err = io.CopyN(ioutil.Discard, reader, 1); extra > 0 {
+			err = ErrEntityTooLarge(totalUploadedSize+extra, totalUploadedSize, bucketName, objectName)
+		}
+		if err != nil && err != io.EOF {
+			return totalUploadedSize, err
+		}
+		err = nil
+	}
+
 	// Loop over total uploaded parts to save them in
 	// Parts array before completing the multipart request.
 	for i := 1; i < partNumber; i++ {
@@ -258,14 +447,15 @@ func (c Client) putObjectMultipartStreamNoLength(ctx context.Context, bucketName
 			return 0, ErrInvalidArgument(fmt.Sprintf("Missing part number %d", i))
 		}
 		complMultipartUpload.Parts = append(complMultipartUpload.Parts, CompletePart{
-			ETag:       part.ETag,
-			PartNumber: part.PartNumber,
+			ETag:           part.ETag,
+			PartNumber:     part.PartNumber,
+			ChecksumCRC32C: part.ChecksumCRC32C,
 		})
 	}
 
 	// Sort all completed parts.
 	sort.Sort(completedParts(complMultipartUpload.Parts))
-	if _, err = c.completeMultipartUpload(ctx, bucketName, objectName, uploadID, complMultipartUpload); err != nil {
+	if _, err = c.completeMultipartUpload(ctx, bucketName, objectName, uploadID, complMultipartUpload, opts); err != nil {
 		return totalUploadedSize, err
 	}
 
@@ -17,7 +17,25 @@
 package minio
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v6/pkg/credentials"
+	"github.com/minio/minio-go/v6/pkg/encrypt"
 )
 
 func TestPutObjectOptionsValidate(t *testing.T) {
@@ -61,3 +79,511 @@ func TestPutObjectOptionsValidate(t *testing.T) {
 		}
 	}
 }
+
+func TestDetectContentType(t *testing.T) {
+	pngData := "\x89PNG\x0D\x0A\x1A\x0A" + strings.Repeat("x", 600)
+	testCases := []struct {
+		objectName  string
+		reader      io.Reader
+		data        string
+		size        int64
+		contentType string
+	}{
+		// Detected from the extension, reader is not consumed.
+		{"photo.jpg", strings.NewReader("hello"), "hello", 5, "image/jpeg"},
+		// Sniffed from a seekable reader.
+		{"photo", strings.NewReader(pngData), pngData, int64(len(pngData)), "image/png"},
+		// Sniffed from a stream of unknown length.
+		{"photo", ioutil.NopCloser(strings.NewReader(pngData)), pngData, -1, "image/png"},
+		// Sniffed from a stream shorter than 512 bytes.
+		{"notes", ioutil.NopCloser(strings.NewReader("plain text")), "plain text", 10, "text/plain; charset=utf-8"},
+		// Nothing to sniff for empty objects.
+		{"empty", ioutil.NopCloser(strings.NewReader("")), "", 0, ""},
+	}
+	for i, testCase := range testCases {
+		contentType, reader, err := detectContentType(testCase.objectName, testCase.reader, testCase.size)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		if contentType != testCase.contentType {
+			t.Errorf("Test %d: expected content type %q, got %q", i+1, testCase.contentType, contentType)
+		}
+		data, err := ioutil.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		if string(data) != testCase.data {
+			t.Errorf("Test %d: reader did not return the original data after detection", i+1)
+		}
+	}
+}
+
+func TestPutObjectOptionsHeader(t *testing.T) {
+	expires := time.Date(2019, time.March, 10, 12, 30, 0, 0, time.FixedZone("IST", 19800))
+	header := PutObjectOptions{
+		ContentType:        "text/html",
+		ContentEncoding:    "gzip",
+		ContentDisposition: "attachment; filename=\"index.html\"",
+		CacheControl:       "max-age=600",
+		Expires:            expires,
+	}.Header()
+
+	testCases := []struct {
+		key, value string
+	}{
+		{"Content-Type", "text/html"},
+		{"Content-Encoding", "gzip"},
+		{"Content-Disposition", "attachment; filename=\"index.html\""},
+		{"Cache-Control", "max-age=600"},
+		{"Expires", "Sun, 10 Mar 2019 07:00:00 GMT"},
+	}
+	for i, testCase := range testCases {
+		if value := header.Get(testCase.key); value != testCase.value {
+			t.Errorf("Test %d: expected %s to be %q, got %q", i+1, testCase.key, testCase.value, value)
+		}
+	}
+
+	if _, ok := (PutObjectOptions{}).Header()["Expires"]; ok {
+		t.Errorf("Expected no Expires header when unset")
+	}
+	if _, ok := (PutObjectOptions{}).Header()["If-None-Match"]; ok {
+		t.Errorf("Expected no If-None-Match header when CreateOnly is unset")
+	}
+	if value := (PutObjectOptions{CreateOnly: true}).Header().Get("If-None-Match"); value != "*" {
+		t.Errorf("Expected If-None-Match header to be \"*\", got %q", value)
+	}
+}
+
+func TestPutObjectOptionsObjectLock(t *testing.T) {
+	governance := Governance
+	invalidMode := RetentionMode("invalid")
+	retainUntil := time.Date(2030, time.January, 2, 8, 30, 0, 0, time.FixedZone("IST", 19800))
+
+	header := PutObjectOptions{
+		Mode:            &governance,
+		RetainUntilDate: &retainUntil,
+		LegalHold:       LegalHoldEnabled,
+	}.Header()
+	testCases := []struct {
+		key, value string
+	}{
+		{"X-Amz-Object-Lock-Mode", "GOVERNANCE"},
+		{"X-Amz-Object-Lock-Retain-Until-Date", "2030-01-02T03:00:00Z"},
+		{"X-Amz-Object-Lock-Legal-Hold", "ON"},
+	}
+	for i, testCase := range testCases {
+		if value := header.Get(testCase.key); value != testCase.value {
+			t.Errorf("Test %d: expected %s to be %q, got %q", i+1, testCase.key, testCase.value, value)
+		}
+	}
+
+	validateCases := []struct {
+		opts       PutObjectOptions
+		shouldPass bool
+	}{
+		{PutObjectOptions{}, true},
+		{PutObjectOptions{Mode: &governance, RetainUntilDate: &retainUntil}, true},
+		{PutObjectOptions{LegalHold: LegalHoldDisabled}, true},
+		{PutObjectOptions{Mode: &governance}, false},
+		{PutObjectOptions{RetainUntilDate: &retainUntil}, false},
+		{PutObjectOptions{Mode: &invalidMode, RetainUntilDate: &retainUntil}, false},
+		{PutObjectOptions{LegalHold: "invalid"}, false},
+	}
+	for i, testCase := range validateCases {
+		err := testCase.opts.validate()
+		if testCase.shouldPass && err != nil {
+			t.Errorf("Test %d: expected to pass, got %s", i+1, err)
+		}
+		if !testCase.shouldPass && err == nil {
+			t.Errorf("Test %d: expected to fail", i+1)
+		}
+	}
+}
+
+// uploadTestServer - fake server recording the PUT and multipart
+// upload requests it receives.
+type uploadTestServer struct {
+	*httptest.Server
+
+	mutex    sync.Mutex
+	requests []string
+	payload  bytes.Buffer // Data of PUT requests without chunk signatures.
+}
+
+func newUploadTestServer() *uploadTestServer {
+	s := &uploadTestServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		size := r.ContentLength
+		if decoded := r.Header.Get("X-Amz-Decoded-Content-Length"); decoded != "" {
+			size, _ = strconv.ParseInt(decoded, 10, 64)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+
+		query := r.URL.Query()
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		if r.Method == http.MethodPut && r.Header.Get("X-Amz-Decoded-Content-Length") == "" {
+			s.payload.Write(body)
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.RawQuery == "uploads=":
+			s.requests = append(s.requests, "initiate")
+			fmt.Fprint(w, "<InitiateMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><UploadId>id</UploadId></InitiateMultipartUploadResult>")
+		case r.Method == http.MethodPut && query.Get("partNumber") != "":
+			s.requests = append(s.requests, fmt.Sprintf("part %s %d", query.Get("partNumber"), size))
+			w.Header().Set("ETag", `"part"`)
+		case r.Method == http.MethodPost:
+			s.requests = append(s.requests, "complete")
+			fmt.Fprint(w, "<CompleteMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><ETag>\"etag\"</ETag></CompleteMultipartUploadResult>")
+		case r.Method == http.MethodPut:
+			s.requests = append(s.requests, fmt.Sprintf("put %d", size))
+			w.Header().Set("ETag", `"etag"`)
+		default:
+			s.requests = append(s.requests, r.Method)
+		}
+	}))
+	return s
+}
+
+// client - returns a client for the server.
+func (s *uploadTestServer) client(t *testing.T) *Client {
+	c, err := NewWithRegion(strings.TrimPrefix(s.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// takeRequests - returns and resets the requests received.
+func (s *uploadTestServer) takeRequests() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	requests := s.requests
+	s.requests = nil
+	return requests
+}
+
+// takePayload - returns and resets the data received.
+func (s *uploadTestServer) takePayload() []byte {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	payload := append([]byte(nil), s.payload.Bytes()...)
+	s.payload.Reset()
+	return payload
+}
+
+// Tests that multipart uploads from an io.ReaderAt read each part from
+// the current offset of the reader without buffering the parts.
+func TestPutObjectReaderAt(t *testing.T) {
+	server := newUploadTestServer()
+	defer server.Close()
+
+	const partSize = absMinPartSize
+	data := make([]byte, 2*partSize+2)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	expected := []string{"initiate", fmt.Sprintf("part 1 %d", partSize), fmt.Sprintf("part 2 %d", partSize), "part 3 1", "complete"}
+	for _, signerType := range []credentials.SignatureType{credentials.SignatureV4, credentials.SignatureV2} {
+		c := server.client(t)
+		c.overrideSignerType = signerType
+		pool := &countingBufferPool{}
+		c.SetBufferPool(pool)
+
+		// Start at an offset beyond the first byte.
+		reader := bytes.NewReader(data)
+		reader.Seek(1, io.SeekStart)
+		size := int64(len(data) - 1)
+		n, err := c.PutObject("bucket", "object", reader, size, PutObjectOptions{PartSize: partSize, NumThreads: 1})
+		if err != nil {
+			t.Fatalf("%s: %v", signerType, err)
+		}
+		if n != size {
+			t.Errorf("%s: expected %d bytes uploaded, got %d", signerType, size, n)
+		}
+		if requests := server.takeRequests(); !reflect.DeepEqual(requests, expected) {
+			t.Errorf("%s: expected requests %v, got %v", signerType, expected, requests)
+		}
+		if pool.gets != 0 {
+			t.Errorf("%s: expected no part buffers, got %d", signerType, pool.gets)
+		}
+		// Chunk signed data is not recorded.
+		if payload := server.takePayload(); signerType.IsV2() && !bytes.Equal(payload, data[1:]) {
+			t.Errorf("%s: uploaded data does not match", signerType)
+		}
+	}
+}
+
+// Tests uploads of streams of unknown size against a fake server.
+func TestPutObjectUnknownSize(t *testing.T) {
+	server := newUploadTestServer()
+	defer server.Close()
+	c := server.client(t)
+
+	const partSize = absMinPartSize
+	testCases := []struct {
+		size     int
+		requests []string
+	}{
+		{0, []string{"put 0"}},
+		{1024, []string{"put 1024"}},
+		{partSize - 1, []string{fmt.Sprintf("put %d", partSize-1)}},
+		{partSize, []string{"initiate", fmt.Sprintf("part 1 %d", partSize), "complete"}},
+		{2*partSize + 1, []string{"initiate", fmt.Sprintf("part 1 %d", partSize), fmt.Sprintf("part 2 %d", partSize), "part 3 1", "complete"}},
+	}
+	for i, testCase := range testCases {
+		// Hide io.Seeker and io.ReaderAt of the data.
+		reader := struct{ io.Reader }{bytes.NewReader(make([]byte, testCase.size))}
+		n, err := c.PutObject("bucket", "object", reader, -1, PutObjectOptions{PartSize: partSize})
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if n != int64(testCase.size) {
+			t.Errorf("Test %d: expected %d bytes uploaded, got %d", i+1, testCase.size, n)
+		}
+		if requests := server.takeRequests(); !reflect.DeepEqual(requests, testCase.requests) {
+			t.Errorf("Test %d: expected requests %v, got %v", i+1, testCase.requests, requests)
+		}
+	}
+}
+
+// cancelingReader - calls cancel once more than limit bytes are read.
+type cancelingReader struct {
+	io.Reader
+	limit  int64
+	read   int64
+	cancel context.CancelFunc
+}
+
+func (r *cancelingReader) Read(p []byte) (int, error) {
+	if r.read >= r.limit {
+		r.cancel()
+	}
+	n, err := r.Reader.Read(p)
+	r.read += int64(n)
+	return n, err
+}
+
+// Tests that multipart uploads are aborted when the context of the
+// upload is cancelled mid-way.
+func TestPutObjectCancelAbortsUpload(t *testing.T) {
+	server := newUploadTestServer()
+	defer server.Close()
+	c := server.client(t)
+
+	const partSize = absMinPartSize
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reader := &cancelingReader{Reader: bytes.NewReader(make([]byte, 2*partSize+1)), limit: partSize, cancel: cancel}
+	if _, err := c.PutObjectWithContext(ctx, "bucket", "object", reader, -1, PutObjectOptions{PartSize: partSize}); err == nil {
+		t.Fatal("expected the cancelled upload to fail")
+	}
+	expected := []string{"initiate", fmt.Sprintf("part 1 %d", partSize), "DELETE"}
+	if requests := server.takeRequests(); !reflect.DeepEqual(requests, expected) {
+		t.Errorf("expected requests %v, got %v", expected, requests)
+	}
+}
+
+// Tests compressing the data of uploads.
+func TestPutObjectCompression(t *testing.T) {
+	var encoding string
+	var payload []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding = r.Header.Get("Content-Encoding")
+		payload, _ = ioutil.ReadAll(r.Body)
+		w.Header().Set("ETag", `"etag"`)
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte(strings.Repeat("log line\n", 10000))
+	opts := PutObjectOptions{Compression: NewGzipCompressor(gzip.BestCompression), DisableContentSha256: true}
+	n, err := c.PutObject("bucket", "object", bytes.NewReader(data), int64(len(data)), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(payload)) || n >= int64(len(data)) {
+		t.Errorf("Expected the compressed size to be uploaded, got %d bytes of %d", n, len(payload))
+	}
+	if encoding != "gzip" {
+		t.Errorf("Expected Content-Encoding gzip, got %q", encoding)
+	}
+	gzipReader, err := gzip.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded, err := ioutil.ReadAll(gzipReader); err != nil || !bytes.Equal(decoded, data) {
+		t.Errorf("Expected the payload to decode to the data, got %d bytes, %v", len(decoded), err)
+	}
+
+	opts.ContentEncoding = "br"
+	if _, err = c.PutObject("bucket", "object", bytes.NewReader(data), int64(len(data)), opts); err == nil {
+		t.Errorf("Expected compressed uploads with a content encoding to fail")
+	}
+}
+
+// Tests uploads of clients set to create only never overwrite objects.
+func TestSetCreateOnly(t *testing.T) {
+	for _, profile := range []EndpointProfile{EndpointProfileDefault, EndpointProfileR2} {
+		var (
+			mu       sync.Mutex
+			requests []string
+			objects  = map[string]bool{}
+		)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			requests = append(requests, r.Method+" "+r.Header.Get("If-None-Match"))
+			switch {
+			case r.Method == http.MethodHead && !objects[r.URL.Path]:
+				w.WriteHeader(http.StatusNotFound)
+			case r.Method == http.MethodPut && objects[r.URL.Path] && r.Header.Get("If-None-Match") == "*":
+				w.WriteHeader(http.StatusPreconditionFailed)
+				fmt.Fprint(w, "<Error><Code>PreconditionFailed</Code></Error>")
+			default:
+				objects[r.URL.Path] = true
+				w.Header().Set("ETag", `"etag"`)
+				w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+			}
+		}))
+		c, err := NewWithOptions(strings.TrimPrefix(server.URL, "http://"), &Options{
+			Creds:           credentials.NewStaticV4("access", "secret", ""),
+			Region:          "us-east-1",
+			EndpointProfile: profile,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.SetCreateOnly(true)
+		if _, err = c.PutObject("bucket", "object", bytes.NewReader([]byte("data")), 4, PutObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		_, err = c.PutObject("bucket", "object", bytes.NewReader([]byte("data")), 4, PutObjectOptions{})
+		if ToErrorResponse(err).Code != "PreconditionFailed" {
+			t.Errorf("%d: expected PreconditionFailed, got %v", profile, err)
+		}
+		expected := []string{"PUT *", "PUT *"}
+		if profile == EndpointProfileR2 {
+			expected = []string{"HEAD ", "PUT ", "HEAD "}
+		}
+		if !reflect.DeepEqual(requests, expected) {
+			t.Errorf("%d: expected requests %q, got %q", profile, expected, requests)
+		}
+		server.Close()
+	}
+}
+
+func TestSSEKMSEncryptionContext(t *testing.T) {
+	encryptionContext := map[string]string{"department": "audit"}
+	data, err := json.Marshal(encryptionContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := base64.StdEncoding.EncodeToString(data)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPut:
+			if r.Header.Get("X-Amz-Server-Side-Encryption") != "aws:kms" || r.Header.Get("X-Amz-Server-Side-Encryption-Context") != header {
+				t.Errorf("unexpected encryption headers %v", r.Header)
+			}
+			if _, ok := r.Header["X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id"]; ok {
+				t.Error("expected no key id with the default key")
+			}
+		case http.MethodHead:
+			w.Header().Set("X-Amz-Server-Side-Encryption", "aws:kms")
+			w.Header().Set("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id", "arn:aws:kms:us-east-1:123456789012:key/key")
+			w.Header().Set("X-Amz-Server-Side-Encryption-Context", header)
+			w.Header().Set("Content-Length", "4")
+		}
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sse, err := encrypt.NewSSEKMS("", encryptionContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.PutObject("bucket", "object", bytes.NewReader([]byte("data")), 4, PutObjectOptions{ServerSideEncryption: sse}); err != nil {
+		t.Fatal(err)
+	}
+	objInfo, err := c.StatObject("bucket", "object", StatObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.ServerSideEncryption != "aws:kms" || objInfo.SSEKMSKeyID != "arn:aws:kms:us-east-1:123456789012:key/key" ||
+		!reflect.DeepEqual(objInfo.SSEKMSEncryptionContext, encryptionContext) {
+		t.Errorf("unexpected encryption %q %q %v", objInfo.ServerSideEncryption, objInfo.SSEKMSKeyID, objInfo.SSEKMSEncryptionContext)
+	}
+}
+
+func TestSSEKMSBucketKey(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.Header.Get("X-Amz-Server-Side-Encryption-Bucket-Key-Enabled"))
+		switch {
+		case r.Method == http.MethodHead:
+			w.Header().Set("X-Amz-Server-Side-Encryption", "aws:kms")
+			w.Header().Set("X-Amz-Server-Side-Encryption-Bucket-Key-Enabled", "true")
+			w.Header().Set("Content-Length", "4")
+		case r.Header.Get("X-Amz-Copy-Source") != "":
+			fmt.Fprint(w, `<CopyObjectResult><ETag>"etag"</ETag><LastModified>2006-01-02T15:04:05.000Z</LastModified></CopyObjectResult>`)
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sse, err := encrypt.NewSSEKMS("key", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sse = encrypt.BucketKey(sse)
+	if _, err = c.PutObject("bucket", "object", bytes.NewReader([]byte("data")), 4, PutObjectOptions{ServerSideEncryption: sse}); err != nil {
+		t.Fatal(err)
+	}
+	dst, err := NewDestinationInfo("bucket", "copy", sse, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = c.CopyObject(dst, NewSourceInfo("bucket", "object", nil)); err != nil {
+		t.Fatal(err)
+	}
+	objInfo, err := c.StatObject("bucket", "copy", StatObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !objInfo.SSEKMSBucketKeyEnabled {
+		t.Error("expected the object to be encrypted with a bucket key")
+	}
+	var puts int
+	for _, request := range requests {
+		if strings.HasPrefix(request, "PUT") && request != "PUT true" {
+			t.Errorf("expected uploads and copies with bucket keys, got %q", requests)
+		}
+		if request == "PUT true" {
+			puts++
+		}
+	}
+	if puts != 2 {
+		t.Errorf("expected an upload and a copy, got %q", requests)
+	}
+
+	if encrypt.BucketKey(encrypt.NewSSE()).Type() != encrypt.S3 {
+		t.Error("expected BucketKey to return SSE-S3 encryptions unmodified")
+	}
+}
@@ -0,0 +1,130 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RemoveIncompleteUploadsOptions represents options specified by user
+// for RemoveIncompleteUploads call.
+type RemoveIncompleteUploadsOptions struct {
+	// Only uploads initiated longer ago are aborted, it must be
+	// positive unless AbortAll is set.
+	OlderThan time.Duration
+
+	// AbortAll aborts all uploads regardless of their age.
+	AbortAll bool
+
+	// Number of uploads aborted concurrently, defaults to 4.
+	NumWorkers int
+}
+
+// RemoveIncompleteUploadsResult - summary of the uploads processed by
+// RemoveIncompleteUploads.
+type RemoveIncompleteUploadsResult struct {
+	// Number of aborted uploads.
+	Aborted int
+
+	// Number of uploads kept since they are more recent than
+	// OlderThan.
+	Kept int
+
+	// Uploads which could not be aborted.
+	Errors []RemoveObjectError
+}
+
+// RemoveIncompleteUploads - aborts all incomplete multipart uploads of
+// objects under prefix initiated longer ago than opts.OlderThan, e.g.
+// in scheduled cleanup jobs, or all of them if opts.AbortAll is set.
+// Uploads are aborted concurrently, those which could not be aborted
+// are reported in the returned summary. An error is only returned if
+// the uploads could not be listed.
+func (c Client) RemoveIncompleteUploads(bucketName, prefix string, opts RemoveIncompleteUploadsOptions) (RemoveIncompleteUploadsResult, error) {
+	return c.RemoveIncompleteUploadsWithContext(context.Background(), bucketName, prefix, opts)
+}
+
+// RemoveIncompleteUploadsWithContext - Identical to RemoveIncompleteUploads call, but accepts context to facilitate request cancellation.
+func (c Client) RemoveIncompleteUploadsWithContext(ctx context.Context, bucketName, prefix string, opts RemoveIncompleteUploadsOptions) (RemoveIncompleteUploadsResult, error) {
+	var result RemoveIncompleteUploadsResult
+	if opts.OlderThan <= 0 && !opts.AbortAll {
+		return result, ErrInvalidArgument("OlderThan must be positive unless AbortAll is set.")
+	}
+
+	numWorkers := opts.NumWorkers
+	if numWorkers <= 0 {
+		numWorkers = totalWorkers
+	}
+	cutoff := time.Now().Add(-opts.OlderThan)
+	if opts.AbortAll {
+		cutoff = time.Now()
+	}
+
+	// Abort the uploads with a pool of workers.
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		uploadsCh = make(chan ObjectMultipartInfo)
+	)
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for upload := range uploadsCh {
+				err := c.abortMultipartUpload(ctx, bucketName, upload.Key, upload.UploadID)
+				if ToErrorResponse(err).Code == "NoSuchUpload" {
+					// Completed or aborted in the meantime.
+					continue
+				}
+				mu.Lock()
+				if err != nil {
+					result.Errors = append(result.Errors, RemoveObjectError{ObjectName: upload.Key, Err: err})
+				} else {
+					result.Aborted++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	doneCh := make(chan struct{})
+	var err error
+	for upload := range c.listIncompleteUploads(ctx, bucketName, prefix, true, false, doneCh) {
+		if upload.Err != nil {
+			err = upload.Err
+			break
+		}
+		if upload.Initiated.After(cutoff) {
+			result.Kept++
+			continue
+		}
+		select {
+		case uploadsCh <- upload:
+			continue
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+		break
+	}
+	close(doneCh)
+	close(uploadsCh)
+	wg.Wait()
+	return result, err
+}
@@ -24,23 +24,44 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-
-	"github.com/minio/minio-go/v6/pkg/s3utils"
 )
 
+// RemoveBucketOptions represents options specified by user for
+// RemoveBucket call.
+type RemoveBucketOptions struct {
+	// ForceDelete removes the bucket along with all of its objects in
+	// a single request. This is an extension supported by MinIO server.
+	ForceDelete bool
+}
+
 // RemoveBucket deletes the bucket name.
 //
 //  All objects (including all object versions and delete markers).
 //  in the bucket must be deleted before successfully attempting this request.
 func (c Client) RemoveBucket(bucketName string) error {
+	return c.RemoveBucketWithOptions(bucketName, RemoveBucketOptions{})
+}
+
+// RemoveBucketWithOptions deletes the bucket name with the options
+// specified.
+func (c Client) RemoveBucketWithOptions(bucketName string, opts RemoveBucketOptions) error {
+	return c.removeBucket(context.Background(), bucketName, opts)
+}
+
+func (c Client) removeBucket(ctx context.Context, bucketName string, opts RemoveBucketOptions) error {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return err
 	}
+	headers := make(http.Header)
+	if opts.ForceDelete {
+		headers.Set(minIOForceDelete, "true")
+	}
 	// Execute DELETE on bucket.
-	resp, err := c.executeMethod(context.Background(), "DELETE", requestMetadata{
+	resp, err := c.executeMethod(ctx, "DELETE", requestMetadata{
 		bucketName:       bucketName,
 		contentSHA256Hex: emptySHA256Hex,
+		customHeader:     headers,
 	})
 	defer closeResponse(resp)
 	if err != nil {
@@ -58,20 +79,167 @@ func (c Client) RemoveBucket(bucketName string) error {
 	return nil
 }
 
+// RemoveBucketResult - result of removing a single object of the
+// bucket, or of removing the bucket itself when ObjectName is empty.
+type RemoveBucketResult struct {
+	// Name of the removed object, empty for the bucket.
+	ObjectName string
+
+	// Version of the removed object or delete marker.
+	VersionID string
+
+	// Error is set if the object or the bucket could not be removed.
+	Err error
+}
+
+// RemoveBucketWithObjects - removes all versions and delete markers of
+// the objects of the bucket and then the bucket. The result of each
+// removal is sent on the returned channel, the last result is for the
+// bucket. The channel is closed once the bucket has been processed.
+func (c Client) RemoveBucketWithObjects(bucketName string) <-chan RemoveBucketResult {
+	return c.RemoveBucketWithObjectsWithContext(context.Background(), bucketName)
+}
+
+// RemoveBucketWithObjectsWithContext - Identical to RemoveBucketWithObjects call, but accepts context to facilitate request cancellation.
+func (c Client) RemoveBucketWithObjectsWithContext(ctx context.Context, bucketName string) <-chan RemoveBucketResult {
+	resultCh := make(chan RemoveBucketResult, 1)
+
+	// Validate if bucket name is valid.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		defer close(resultCh)
+		resultCh <- RemoveBucketResult{Err: err}
+		return resultCh
+	}
+
+	go func() {
+		defer close(resultCh)
+
+		send := func(result RemoveBucketResult) bool {
+			select {
+			case resultCh <- result:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		// Remove the versions listed in batches of 1000.
+		removeBatch := func(batch []deleteObject) bool {
+			failed := make(map[deleteObject]bool)
+			for _, e := range c.removeObjectsBatch(ctx, bucketName, batch) {
+				failed[deleteObject{Key: e.ObjectName, VersionID: e.VersionID}] = true
+				if !send(RemoveBucketResult{ObjectName: e.ObjectName, VersionID: e.VersionID, Err: e.Err}) {
+					return false
+				}
+			}
+			if failed[deleteObject{}] {
+				// The request failed, none of the versions are known
+				// to be removed.
+				return true
+			}
+			for _, object := range batch {
+				if failed[object] {
+					continue
+				}
+				if !send(RemoveBucketResult{ObjectName: object.Key, VersionID: object.VersionID}) {
+					return false
+				}
+			}
+			return true
+		}
+
+		var batch []deleteObject
+		for keyMarker, versionIDMarker, more := "", "", true; more; {
+			result, err := c.listObjectVersionsQuery(ctx, bucketName, keyMarker, versionIDMarker)
+			if err != nil {
+				send(RemoveBucketResult{Err: err})
+				return
+			}
+			for _, versions := range [][]objectVersion{result.Versions, result.DeleteMarkers} {
+				for _, version := range versions {
+					batch = append(batch, deleteObject{Key: version.Key, VersionID: version.VersionID})
+					if len(batch) == maxRemoveEntries {
+						if !removeBatch(batch) {
+							return
+						}
+						batch = nil
+					}
+				}
+			}
+			keyMarker, versionIDMarker, more = result.NextKeyMarker, result.NextVersionIDMarker, result.IsTruncated
+		}
+		if len(batch) > 0 && !removeBatch(batch) {
+			return
+		}
+		if ctx.Err() != nil {
+			send(RemoveBucketResult{Err: ctx.Err()})
+			return
+		}
+		send(RemoveBucketResult{Err: c.removeBucket(ctx, bucketName, RemoveBucketOptions{})})
+	}()
+	return resultCh
+}
+
+// RemoveObjectOptions represents options specified by user for RemoveObject call
+type RemoveObjectOptions struct {
+	// GovernanceBypass removes an object locked in governance mode,
+	// the caller needs the s3:BypassGovernanceRetention permission.
+	GovernanceBypass bool
+
+	// CustomHeaders are sent with the DELETE request, so that headers
+	// of extensions of the service can be used. Headers set by the
+	// client and its signers such as Authorization are rejected.
+	CustomHeaders http.Header
+
+	// CustomQuery are query parameters signed and sent with the
+	// DELETE request, so that subresources of extensions of the
+	// service can be used. Parameters set by the client and its
+	// signers such as X-Amz-Signature are rejected.
+	CustomQuery url.Values
+}
+
 // RemoveObject remove an object from a bucket.
 func (c Client) RemoveObject(bucketName, objectName string) error {
+	return c.RemoveObjectWithOptions(bucketName, objectName, RemoveObjectOptions{})
+}
+
+// RemoveObjectWithOptions removes an object from a bucket with the
+// options specified.
+func (c Client) RemoveObjectWithOptions(bucketName, objectName string, opts RemoveObjectOptions) error {
+	return c.RemoveObjectWithContext(context.Background(), bucketName, objectName, opts)
+}
+
+// RemoveObjectWithContext - Identical to RemoveObjectWithOptions call,
+// but accepts context to facilitate request cancellation.
+func (c Client) RemoveObjectWithContext(ctx context.Context, bucketName, objectName string, opts RemoveObjectOptions) error {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return err
+	}
+	if err := ValidateObjectKey(objectName); err != nil {
+		return err
+	}
+	if err := validateCustomHeaders(opts.CustomHeaders); err != nil {
 		return err
 	}
-	if err := s3utils.CheckValidObjectName(objectName); err != nil {
+	if err := validateCustomQuery(opts.CustomQuery); err != nil {
 		return err
 	}
+	urlValues := make(url.Values)
+	setCustomQuery(urlValues, opts.CustomQuery)
+	headers := make(http.Header)
+	setCustomHeaders(headers, opts.CustomHeaders)
+	if opts.GovernanceBypass {
+		// Set the bypass governance retention header
+		headers.Set(amzBypassGovernance, "true")
+	}
 	// Execute DELETE on objectName.
-	resp, err := c.executeMethod(context.Background(), "DELETE", requestMetadata{
+	resp, err := c.executeMethod(ctx, "DELETE", requestMetadata{
 		bucketName:       bucketName,
 		objectName:       objectName,
+		queryValues:      urlValues,
 		contentSHA256Hex: emptySHA256Hex,
+		customHeader:     headers,
 	})
 	defer closeResponse(resp)
 	if err != nil {
@@ -93,40 +261,40 @@ func (c Client) RemoveObject(bucketName, objectName string) error {
 // RemoveObjectError - container of Multi Delete S3 API error
 type RemoveObjectError struct {
 	ObjectName string
-	Err        error
+	// Version of the object, if a version was removed.
+	VersionID string
+	Err       error
 }
 
 // generateRemoveMultiObjects - generate the XML request for remove multi objects request
-func generateRemoveMultiObjectsRequest(objects []string) []byte {
-	rmObjects := []deleteObject{}
-	for _, obj := range objects {
-		rmObjects = append(rmObjects, deleteObject{Key: obj})
-	}
-	xmlBytes, _ := xml.Marshal(deleteMultiObjects{Objects: rmObjects, Quiet: true})
+func generateRemoveMultiObjectsRequest(objects []deleteObject) []byte {
+	xmlBytes, _ := xml.Marshal(deleteMultiObjects{Objects: objects, Quiet: true})
 	return xmlBytes
 }
 
 // processRemoveMultiObjectsResponse - parse the remove multi objects web service
-// and return the success/failure result status for each object
-func processRemoveMultiObjectsResponse(body io.Reader, objects []string, errorCh chan<- RemoveObjectError) {
+// and return the failure result status for each object
+func processRemoveMultiObjectsResponse(body io.Reader, objects []deleteObject) []RemoveObjectError {
 	// Parse multi delete XML response
 	rmResult := &deleteMultiObjectsResult{}
 	err := xmlDecoder(body, rmResult)
 	if err != nil {
-		errorCh <- RemoveObjectError{ObjectName: "", Err: err}
-		return
+		return []RemoveObjectError{{ObjectName: "", Err: err}}
 	}
 
 	// Fill deletion that returned an error.
+	var errs []RemoveObjectError
 	for _, obj := range rmResult.UnDeletedObjects {
-		errorCh <- RemoveObjectError{
+		errs = append(errs, RemoveObjectError{
 			ObjectName: obj.Key,
+			VersionID:  obj.VersionID,
 			Err: ErrorResponse{
 				Code:    obj.Code,
 				Message: obj.Message,
 			},
-		}
+		})
 	}
+	return errs
 }
 
 // RemoveObjectsWithContext - Identical to RemoveObjects call, but accepts context to facilitate request cancellation.
@@ -134,7 +302,7 @@ func (c Client) RemoveObjectsWithContext(ctx context.Context, bucketName string,
 	errorCh := make(chan RemoveObjectError, 1)
 
 	// Validate if bucket name is valid.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		defer close(errorCh)
 		errorCh <- RemoveObjectError{
 			Err: err,
@@ -152,10 +320,7 @@ func (c Client) RemoveObjectsWithContext(ctx context.Context, bucketName string,
 
 	// Generate and call MultiDelete S3 requests based on entries received from objectsCh
 	go func(errorCh chan<- RemoveObjectError) {
-		maxEntries := 1000
 		finish := false
-		urlValues := make(url.Values)
-		urlValues.Set("delete", "")
 
 		// Close error channel when Multi delete finishes.
 		defer close(errorCh)
@@ -166,12 +331,12 @@ func (c Client) RemoveObjectsWithContext(ctx context.Context, bucketName string,
 				break
 			}
 			count := 0
-			var batch []string
+			var batch []deleteObject
 
 			// Try to gather 1000 entries
 			for object := range objectsCh {
-				batch = append(batch, object)
-				if count++; count >= maxEntries {
+				batch = append(batch, deleteObject{Key: object})
+				if count++; count >= maxRemoveEntries {
 					break
 				}
 			}
@@ -179,44 +344,60 @@ func (c Client) RemoveObjectsWithContext(ctx context.Context, bucketName string,
 				// Multi Objects Delete API doesn't accept empty object list, quit immediately
 				break
 			}
-			if count < maxEntries {
+			if count < maxRemoveEntries {
 				// We didn't have 1000 entries, so this is the last batch
 				finish = true
 			}
 
-			// Generate remove multi objects XML request
-			removeBytes := generateRemoveMultiObjectsRequest(batch)
-			// Execute GET on bucket to list objects.
-			resp, err := c.executeMethod(ctx, "POST", requestMetadata{
-				bucketName:       bucketName,
-				queryValues:      urlValues,
-				contentBody:      bytes.NewReader(removeBytes),
-				contentLength:    int64(len(removeBytes)),
-				contentMD5Base64: sumMD5Base64(removeBytes),
-				contentSHA256Hex: sum256Hex(removeBytes),
-			})
-			if resp != nil {
-				if resp.StatusCode != http.StatusOK {
-					e := httpRespToErrorResponse(resp, bucketName, "")
-					errorCh <- RemoveObjectError{ObjectName: "", Err: e}
-				}
+			for _, e := range c.removeObjectsBatch(ctx, bucketName, batch) {
+				errorCh <- e
 			}
-			if err != nil {
-				for _, b := range batch {
-					errorCh <- RemoveObjectError{ObjectName: b, Err: err}
-				}
-				continue
-			}
-
-			// Process multiobjects remove xml response
-			processRemoveMultiObjectsResponse(resp.Body, batch, errorCh)
-
-			closeResponse(resp)
 		}
 	}(errorCh)
 	return errorCh
 }
 
+// maxRemoveEntries - maximum number of objects removed by a single
+// MultiDelete request.
+const maxRemoveEntries = 1000
+
+// removeObjectsBatch - removes a batch of objects, or of their versions
+// if set, with a MultiDelete request and returns the failures observed.
+func (c Client) removeObjectsBatch(ctx context.Context, bucketName string, batch []deleteObject) []RemoveObjectError {
+	var errs []RemoveObjectError
+
+	urlValues := make(url.Values)
+	urlValues.Set("delete", "")
+
+	// Generate remove multi objects XML request
+	removeBytes := generateRemoveMultiObjectsRequest(batch)
+	// Execute POST on bucket to remove objects.
+	resp, err := c.executeMethod(ctx, "POST", requestMetadata{
+		bucketName:       bucketName,
+		queryValues:      urlValues,
+		contentBody:      bytes.NewReader(removeBytes),
+		contentLength:    int64(len(removeBytes)),
+		contentMD5Base64: sumMD5Base64(removeBytes),
+		contentSHA256Hex: sum256Hex(removeBytes),
+	})
+	defer closeResponse(resp)
+	if resp != nil {
+		if resp.StatusCode != http.StatusOK {
+			e := httpRespToErrorResponse(resp, bucketName, "")
+			errs = append(errs, RemoveObjectError{ObjectName: "", Err: e})
+		}
+	}
+	if err != nil {
+		for _, b := range batch {
+			errs = append(errs, RemoveObjectError{ObjectName: b.Key, VersionID: b.VersionID, Err: err})
+		}
+		return errs
+	}
+
+	// Process multiobjects remove xml response
+	return append(errs, processRemoveMultiObjectsResponse(resp.Body, batch)...)
+}
+
 // RemoveObjects removes multiple objects from a bucket.
 // The list of objects to remove are received from objectsCh.
 // Remove failures are sent back via error channel.
@@ -227,10 +408,10 @@ func (c Client) RemoveObjects(bucketName string, objectsCh <-chan string) <-chan
 // RemoveIncompleteUpload aborts an partially uploaded object.
 func (c Client) RemoveIncompleteUpload(bucketName, objectName string) error {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return err
 	}
-	if err := s3utils.CheckValidObjectName(objectName); err != nil {
+	if err := ValidateObjectKey(objectName); err != nil {
 		return err
 	}
 	// Find multipart upload ids of the object to be aborted.
@@ -254,10 +435,10 @@ func (c Client) RemoveIncompleteUpload(bucketName, objectName string) error {
 // uploadID, all previously uploaded parts are deleted.
 func (c Client) abortMultipartUpload(ctx context.Context, bucketName, objectName, uploadID string) error {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return err
 	}
-	if err := s3utils.CheckValidObjectName(objectName); err != nil {
+	if err := ValidateObjectKey(objectName); err != nil {
 		return err
 	}
 
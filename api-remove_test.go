@@ -0,0 +1,256 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Tests that the governance retention is only bypassed on request.
+func TestRemoveObjectGovernanceBypass(t *testing.T) {
+	var bypass []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bypass = append(bypass, r.Header.Get("X-Amz-Bypass-Governance-Retention"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = c.RemoveObject("bucket", "object"); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.RemoveObjectWithOptions("bucket", "object", RemoveObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.RemoveObjectWithOptions("bucket", "object", RemoveObjectOptions{GovernanceBypass: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"", "", "true"}
+	if strings.Join(bypass, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected bypass headers %q, got %q", expected, bypass)
+	}
+}
+
+// Tests removing a bucket along with all versions of its objects.
+func TestRemoveBucketWithObjects(t *testing.T) {
+	type version struct {
+		key, versionID string
+		deleteMarker   bool
+	}
+	var mutex sync.Mutex
+	versions := []version{{"a", "v1", false}, {"a", "v2", false}, {"b/c", "dm1", true}, {"b/c", "v3", false}, {"locked", "v4", false}}
+	var forceDelete []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		switch r.Method {
+		case http.MethodGet:
+			query := r.URL.Query()
+			if _, ok := query["versions"]; !ok {
+				t.Errorf("expected versions to be listed, got %s", r.URL.RawQuery)
+			}
+			// Pages of two versions, starting after the markers.
+			start := 0
+			for i, v := range versions {
+				if v.key == query.Get("key-marker") && v.versionID == query.Get("version-id-marker") {
+					start = i + 1
+				}
+			}
+			end := start + 2
+			if end > len(versions) {
+				end = len(versions)
+			}
+			page := versions[start:end]
+			fmt.Fprintf(w, "<ListVersionsResult><IsTruncated>%t</IsTruncated>", end < len(versions))
+			if len(page) > 0 {
+				last := page[len(page)-1]
+				fmt.Fprintf(w, "<NextKeyMarker>%s</NextKeyMarker><NextVersionIdMarker>%s</NextVersionIdMarker>", last.key, last.versionID)
+			}
+			for _, deleteMarkers := range []bool{false, true} {
+				for _, v := range page {
+					if v.deleteMarker != deleteMarkers {
+						continue
+					}
+					element := "Version"
+					if v.deleteMarker {
+						element = "DeleteMarker"
+					}
+					fmt.Fprintf(w, "<%s><Key>%s</Key><VersionId>%s</VersionId></%s>", element, v.key, v.versionID, element)
+				}
+			}
+			fmt.Fprint(w, "</ListVersionsResult>")
+		case http.MethodPost:
+			var request deleteMultiObjects
+			if err := xml.NewDecoder(r.Body).Decode(&request); err != nil {
+				t.Error(err)
+			}
+			removed := make(map[version]bool)
+			fmt.Fprint(w, "<DeleteResult>")
+			for _, object := range request.Objects {
+				if object.Key == "locked" {
+					fmt.Fprintf(w, "<Error><Key>%s</Key><VersionId>%s</VersionId><Code>AccessDenied</Code><Message>Access Denied</Message></Error>", object.Key, object.VersionID)
+					continue
+				}
+				removed[version{key: object.Key, versionID: object.VersionID}] = true
+			}
+			fmt.Fprint(w, "</DeleteResult>")
+			var remaining []version
+			for _, v := range versions {
+				if !removed[version{key: v.key, versionID: v.versionID}] {
+					remaining = append(remaining, v)
+				}
+			}
+			versions = remaining
+		case http.MethodDelete:
+			forceDelete = append(forceDelete, r.Header.Get("X-Minio-Force-Delete"))
+			if len(versions) > 0 && r.Header.Get("X-Minio-Force-Delete") != "true" {
+				w.WriteHeader(http.StatusConflict)
+				fmt.Fprint(w, "<Error><Code>BucketNotEmpty</Code><Message>The bucket you tried to delete is not empty</Message></Error>")
+				return
+			}
+			versions = nil
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var results []string
+	for result := range c.RemoveBucketWithObjects("bucket") {
+		results = append(results, fmt.Sprintf("%s@%s:%s", result.ObjectName, result.VersionID, ToErrorResponse(result.Err).Code))
+	}
+	expected := []string{"locked@v4:AccessDenied", "a@v1:", "a@v2:", "b/c@v3:", "b/c@dm1:", "@:BucketNotEmpty"}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Expected results %q, got %q", expected, results)
+	}
+
+	if err = c.RemoveBucketWithOptions("bucket", RemoveBucketOptions{ForceDelete: true}); err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 0 {
+		t.Errorf("Expected all versions to be removed, got %v", versions)
+	}
+	if expected := []string{"", "true"}; !reflect.DeepEqual(forceDelete, expected) {
+		t.Errorf("Expected force delete headers %q, got %q", expected, forceDelete)
+	}
+}
+
+// Tests incomplete uploads older than the given age are aborted.
+func TestRemoveIncompleteUploads(t *testing.T) {
+	now := time.Now().UTC()
+	uploads := []struct {
+		key, uploadID string
+		initiated     time.Time
+		status        int
+	}{
+		{"logs/a", "1", now.Add(-48 * time.Hour), http.StatusNoContent},
+		{"logs/b", "2", now.Add(-25 * time.Hour), http.StatusNoContent},
+		{"logs/c", "3", now.Add(-time.Hour), http.StatusNoContent},
+		{"logs/d", "4", now.Add(-30 * time.Hour), http.StatusNotFound},
+		{"logs/e", "5", now.Add(-30 * time.Hour), http.StatusForbidden},
+	}
+	var (
+		mu      sync.Mutex
+		aborted []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			if prefix := r.URL.Query().Get("prefix"); prefix != "logs/" {
+				t.Errorf("expected prefix logs/, got %q", prefix)
+			}
+			fmt.Fprint(w, "<ListMultipartUploadsResult>")
+			for _, upload := range uploads {
+				fmt.Fprintf(w, "<Upload><Key>%s</Key><UploadId>%s</UploadId><Initiated>%s</Initiated></Upload>",
+					upload.key, upload.uploadID, upload.initiated.Format(time.RFC3339))
+			}
+			fmt.Fprint(w, "</ListMultipartUploadsResult>")
+			return
+		}
+		for _, upload := range uploads {
+			if upload.uploadID == r.URL.Query().Get("uploadId") {
+				mu.Lock()
+				aborted = append(aborted, upload.key)
+				mu.Unlock()
+				w.WriteHeader(upload.status)
+				return
+			}
+		}
+		t.Errorf("unexpected request %s %s", r.Method, r.URL)
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := c.RemoveIncompleteUploads("bucket", "logs/", RemoveIncompleteUploadsOptions{OlderThan: 24 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(aborted)
+	if expected := []string{"logs/a", "logs/b", "logs/d", "logs/e"}; !reflect.DeepEqual(aborted, expected) {
+		t.Errorf("expected aborts of %v, got %v", expected, aborted)
+	}
+	if result.Aborted != 2 || result.Kept != 1 {
+		t.Errorf("expected 2 aborted and 1 kept uploads, got %+v", result)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].ObjectName != "logs/e" {
+		t.Errorf("expected an error for logs/e, got %v", result.Errors)
+	}
+
+	// All uploads are only aborted on request.
+	aborted = nil
+	if _, err = c.RemoveIncompleteUploads("bucket", "logs/", RemoveIncompleteUploadsOptions{}); ToErrorResponse(err).Code != "InvalidArgument" {
+		t.Errorf("expected InvalidArgument without OlderThan, got %v", err)
+	}
+	if result, err = c.RemoveIncompleteUploads("bucket", "logs/", RemoveIncompleteUploadsOptions{AbortAll: true}); err != nil {
+		t.Fatal(err)
+	}
+	if len(aborted) != len(uploads) || result.Kept != 0 {
+		t.Errorf("expected all uploads aborted, got %v and %+v", aborted, result)
+	}
+
+	// Listing is cancelled with the context.
+	aborted = nil
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = c.RemoveIncompleteUploadsWithContext(ctx, "bucket", "logs/", RemoveIncompleteUploadsOptions{AbortAll: true}); err == nil {
+		t.Error("expected an error with a cancelled context")
+	}
+	if len(aborted) != 0 {
+		t.Errorf("expected no aborts with a cancelled context, got %v", aborted)
+	}
+}
@@ -103,6 +103,24 @@ type ListBucketResult struct {
 	Prefix     string
 }
 
+// objectVersion container for a Version or DeleteMarker element of a
+// ListObjectVersions response.
+type objectVersion struct {
+	Key       string
+	VersionID string `xml:"VersionId"`
+}
+
+// listVersionsResult container for ListObjectVersions response, only
+// the fields needed to remove all versions of a bucket are decoded.
+type listVersionsResult struct {
+	EncodingType        string
+	IsTruncated         bool
+	NextKeyMarker       string
+	NextVersionIDMarker string          `xml:"NextVersionIdMarker"`
+	Versions            []objectVersion `xml:"Version"`
+	DeleteMarkers       []objectVersion `xml:"DeleteMarker"`
+}
+
 // ListMultipartUploadsResult container for ListMultipartUploads response
 type ListMultipartUploadsResult struct {
 	Bucket             string
@@ -146,6 +164,10 @@ type ObjectPart struct {
 
 	// Size of the uploaded part data.
 	Size int64
+
+	// Base64 encoded CRC32C checksum of the part, set when it was
+	// uploaded with PutObjectOptions.SendCRC32C.
+	ChecksumCRC32C string `xml:",omitempty"`
 }
 
 // ListObjectPartsResult container for ListObjectParts response.
@@ -194,6 +216,10 @@ type CompletePart struct {
 	// Part number identifies the part.
 	PartNumber int
 	ETag       string
+
+	// Base64 encoded CRC32C checksum of the part, required for uploads
+	// initiated with the CRC32C checksum algorithm.
+	ChecksumCRC32C string `xml:",omitempty"`
 }
 
 // completeMultipartUpload container for completing multipart upload.
@@ -225,9 +251,10 @@ type deletedObject struct {
 
 // nonDeletedObject container for Error element (failed deletion) in MultiObjects Delete XML response
 type nonDeletedObject struct {
-	Key     string
-	Code    string
-	Message string
+	Key       string
+	VersionID string `xml:"VersionId"`
+	Code      string
+	Message   string
 }
 
 // deletedMultiObjects container for MultiObjects Delete XML request
@@ -20,17 +20,16 @@ package minio
 import (
 	"context"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
-
-	"github.com/minio/minio-go/v6/pkg/s3utils"
 )
 
 // BucketExists verify if bucket exists and you have permission to access it.
 func (c Client) BucketExists(bucketName string) (bool, error) {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return false, err
 	}
 
@@ -89,13 +88,29 @@ func extractObjMetadata(header http.Header) http.Header {
 	return filterHeader(header, filterKeys)
 }
 
+// Extract user defined metadata from the x-amz-meta-* headers, the
+// prefix is removed from the keys.
+func extractUserMetadata(header http.Header) UserMetadata {
+	var userMetadata UserMetadata
+	for k, v := range header {
+		if len(v) == 0 || !strings.HasPrefix(strings.ToLower(k), "x-amz-meta-") {
+			continue
+		}
+		if userMetadata == nil {
+			userMetadata = make(UserMetadata)
+		}
+		userMetadata[http.CanonicalHeaderKey(k[len("x-amz-meta-"):])] = v[0]
+	}
+	return userMetadata
+}
+
 // StatObject verifies if object exists and you have permission to access.
 func (c Client) StatObject(bucketName, objectName string, opts StatObjectOptions) (ObjectInfo, error) {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return ObjectInfo{}, err
 	}
-	if err := s3utils.CheckValidObjectName(objectName); err != nil {
+	if err := ValidateObjectKey(objectName); err != nil {
 		return ObjectInfo{}, err
 	}
 	return c.statObject(context.Background(), bucketName, objectName, opts)
@@ -104,17 +119,30 @@ func (c Client) StatObject(bucketName, objectName string, opts StatObjectOptions
 // Lower level API for statObject supporting pre-conditions and range headers.
 func (c Client) statObject(ctx context.Context, bucketName, objectName string, opts StatObjectOptions) (ObjectInfo, error) {
 	// Input validation.
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return ObjectInfo{}, err
 	}
-	if err := s3utils.CheckValidObjectName(objectName); err != nil {
+	if err := ValidateObjectKey(objectName); err != nil {
 		return ObjectInfo{}, err
 	}
+	if err := validateCustomHeaders(opts.CustomHeaders); err != nil {
+		return ObjectInfo{}, err
+	}
+	if err := validateCustomQuery(opts.CustomQuery); err != nil {
+		return ObjectInfo{}, err
+	}
+	if err := opts.validatePartNumber(); err != nil {
+		return ObjectInfo{}, err
+	}
+	urlValues := make(url.Values)
+	setCustomQuery(urlValues, opts.CustomQuery)
+	opts.setPartNumber(urlValues)
 
 	// Execute HEAD on objectName.
 	resp, err := c.executeMethod(ctx, "HEAD", requestMetadata{
 		bucketName:       bucketName,
 		objectName:       objectName,
+		queryValues:      urlValues,
 		contentSHA256Hex: emptySHA256Hex,
 		customHeader:     opts.Header(),
 	})
@@ -177,7 +205,7 @@ func (c Client) statObject(ctx context.Context, bucketName, objectName string, o
 		expTime = t.UTC()
 	}
 	// Save object metadata info.
-	return ObjectInfo{
+	objectInfo := ObjectInfo{
 		ETag:         md5sum,
 		Key:          objectName,
 		Size:         size,
@@ -187,6 +215,10 @@ func (c Client) statObject(ctx context.Context, bucketName, objectName string, o
 		// Extract only the relevant header keys describing the object.
 		// following function filters out a list of standard set of keys
 		// which are not part of object metadata.
-		Metadata: extractObjMetadata(resp.Header),
-	}, nil
+		Metadata:     extractObjMetadata(resp.Header),
+		UserMetadata: extractUserMetadata(resp.Header),
+	}
+	setObjectChecksums(&objectInfo, resp.Header)
+	setObjectEncryption(&objectInfo, resp.Header)
+	return objectInfo, nil
 }
@@ -0,0 +1,369 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SyncDirection is the direction in which Sync transfers data.
+type SyncDirection int
+
+// Different directions supported by Sync.
+const (
+	// SyncToBucket uploads local files to the bucket.
+	SyncToBucket SyncDirection = iota
+	// SyncFromBucket downloads objects from the bucket.
+	SyncFromBucket
+)
+
+// SyncAction is the action taken by Sync for an entry.
+type SyncAction int
+
+// Different actions taken by Sync.
+const (
+	// SyncUpload - a local file was uploaded.
+	SyncUpload SyncAction = iota
+	// SyncDownload - an object was downloaded.
+	SyncDownload
+	// SyncDelete - an object or a local file was removed since it no
+	// longer exists at the source.
+	SyncDelete
+)
+
+// SyncOptions represents options specified by user for Sync call.
+type SyncOptions struct {
+	// Direction of the transfers, defaults to SyncToBucket.
+	Direction SyncDirection
+
+	// Glob patterns, as understood by path.Match, matched against
+	// the slash separated path relative to the synced directory or
+	// prefix, patterns without a '/' are also matched against the
+	// base name. Only entries matching one of the Include patterns,
+	// if any, and none of the Exclude patterns are synced.
+	Include []string
+	Exclude []string
+
+	// Delete removes entries at the destination which do not exist
+	// at the source. Excluded entries are never removed.
+	Delete bool
+
+	// CompareETag compares the MD5 sum of local files with the ETag
+	// of single part unencrypted objects instead of relying on the
+	// modification time when their sizes match.
+	CompareETag bool
+
+	// Number of concurrent transfers, defaults to 4.
+	NumWorkers int
+
+	// Options used for uploads and downloads.
+	PutObjectOptions PutObjectOptions
+	GetObjectOptions GetObjectOptions
+}
+
+// SyncResult - result of syncing a single entry.
+type SyncResult struct {
+	Action     SyncAction
+	ObjectName string
+	FilePath   string
+	Size       int64
+
+	// Error is set if the entry could not be synced.
+	Err error
+}
+
+// syncEntry - a local file or a remote object considered by Sync.
+type syncEntry struct {
+	filePath string
+	size     int64
+	modTime  time.Time
+	etag     string
+}
+
+// syncMatch - returns true if the relative path passes the include
+// and exclude filters.
+func (opts SyncOptions) syncMatch(relPath string) bool {
+	match := func(patterns []string) bool {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, relPath); ok {
+				return true
+			}
+			if !strings.Contains(pattern, "/") {
+				if ok, _ := path.Match(pattern, path.Base(relPath)); ok {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if len(opts.Include) > 0 && !match(opts.Include) {
+		return false
+	}
+	return !match(opts.Exclude)
+}
+
+// syncNeedsTransfer - returns true if the source entry differs from
+// the destination entry. Entries of different sizes always differ,
+// otherwise the ETag is compared when requested and possible, falling
+// back to transferring sources newer than their destination.
+func syncNeedsTransfer(local, remote syncEntry, opts SyncOptions) (bool, error) {
+	if local.size != remote.size {
+		return true, nil
+	}
+	if opts.CompareETag && len(remote.etag) == hex.EncodedLen(md5.Size) && !strings.Contains(remote.etag, "-") {
+		md5Hex, err := fileMD5Hex(local.filePath)
+		if err != nil {
+			return false, err
+		}
+		return md5Hex != strings.ToLower(remote.etag), nil
+	}
+	if opts.Direction == SyncFromBucket {
+		return remote.modTime.After(local.modTime), nil
+	}
+	return local.modTime.After(remote.modTime), nil
+}
+
+// fileMD5Hex - returns the hex encoded MD5 sum of a file.
+func fileMD5Hex(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := md5.New()
+	if _, err = io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// syncListLocal - lists the regular files under dirPath passing the
+// filters, indexed by their slash separated relative path.
+func syncListLocal(dirPath string, opts SyncOptions) (map[string]syncEntry, error) {
+	entries := make(map[string]syncEntry)
+	err := filepath.Walk(dirPath, func(filePath string, info os.FileInfo, err error) error {
+		if err != nil {
+			// The directory is created on download.
+			if filePath == dirPath && os.IsNotExist(err) && opts.Direction == SyncFromBucket {
+				return nil
+			}
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		relPath, err := filepath.Rel(dirPath, filePath)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+		// Partially downloaded files are not synced.
+		if strings.HasSuffix(relPath, ".part.minio") || !opts.syncMatch(relPath) {
+			return nil
+		}
+		entries[relPath] = syncEntry{
+			filePath: filePath,
+			size:     info.Size(),
+			modTime:  info.ModTime(),
+		}
+		return nil
+	})
+	return entries, err
+}
+
+// syncListRemote - lists the objects under prefix passing the filters,
+// indexed by their path relative to prefix.
+func (c Client) syncListRemote(ctx context.Context, bucketName, prefix string, opts SyncOptions) (map[string]syncEntry, error) {
+	entries := make(map[string]syncEntry)
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	for object := range c.ListObjectsV2(bucketName, prefix, true, doneCh) {
+		if object.Err != nil {
+			return nil, object.Err
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		relPath := strings.TrimPrefix(object.Key, prefix)
+		// Directory markers are not synced.
+		if relPath == "" || strings.HasSuffix(relPath, "/") || !opts.syncMatch(relPath) {
+			continue
+		}
+		entry := syncEntry{
+			size:    object.Size,
+			modTime: object.LastModified,
+			etag:    object.ETag,
+		}
+		// ETags of older gateways are not always MD5 sums.
+		if c.profile.legacyGateway {
+			entry.etag = ""
+		}
+		entries[relPath] = entry
+	}
+	return entries, nil
+}
+
+// Sync - mirrors dirPath and the objects under prefix in the direction
+// specified by opts, only entries which are missing or differ at the
+// destination are transferred. Objects are named after the slash
+// separated path of the files relative to dirPath under prefix, a
+// non-empty prefix is treated as a directory. The result of each
+// transfer or removal is sent on the returned channel, which is closed
+// once the sync is complete.
+func (c Client) Sync(bucketName, prefix, dirPath string, opts SyncOptions) <-chan SyncResult {
+	return c.SyncWithContext(context.Background(), bucketName, prefix, dirPath, opts)
+}
+
+// SyncWithContext - Identical to Sync call, but accepts context to facilitate request cancellation.
+func (c Client) SyncWithContext(ctx context.Context, bucketName, prefix, dirPath string, opts SyncOptions) <-chan SyncResult {
+	resultCh := make(chan SyncResult, 1)
+
+	// Input validation.
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		defer close(resultCh)
+		resultCh <- SyncResult{Err: err}
+		return resultCh
+	}
+	if opts.Direction != SyncToBucket && opts.Direction != SyncFromBucket {
+		defer close(resultCh)
+		resultCh <- SyncResult{Err: ErrInvalidArgument("Unsupported sync direction.")}
+		return resultCh
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	numWorkers := opts.NumWorkers
+	if numWorkers <= 0 {
+		numWorkers = totalWorkers
+	}
+
+	go func() {
+		defer close(resultCh)
+
+		local, err := syncListLocal(dirPath, opts)
+		if err != nil {
+			resultCh <- SyncResult{FilePath: dirPath, Err: err}
+			return
+		}
+		remote, err := c.syncListRemote(ctx, bucketName, prefix, opts)
+		if err != nil {
+			resultCh <- SyncResult{Err: err}
+			return
+		}
+		source, destination := local, remote
+		if opts.Direction == SyncFromBucket {
+			source, destination = remote, local
+		}
+
+		// Compute the transfers and the removals.
+		var tasks []SyncResult
+		for relPath, src := range source {
+			task := SyncResult{
+				Action:     SyncUpload,
+				ObjectName: prefix + relPath,
+				FilePath:   filepath.Join(dirPath, filepath.FromSlash(relPath)),
+				Size:       src.size,
+			}
+			if opts.Direction == SyncFromBucket {
+				task.Action = SyncDownload
+				// Object names which would resolve outside of dirPath
+				// are not downloaded.
+				if task.FilePath, err = prefixFilePath(dirPath, prefix, task.ObjectName); err != nil {
+					task.Err = err
+					resultCh <- task
+					continue
+				}
+			}
+			if dst, ok := destination[relPath]; ok {
+				localEntry, remoteEntry := src, dst
+				if opts.Direction == SyncFromBucket {
+					localEntry, remoteEntry = dst, src
+				}
+				transfer, err := syncNeedsTransfer(localEntry, remoteEntry, opts)
+				if err != nil {
+					task.Err = err
+					resultCh <- task
+					continue
+				}
+				if !transfer {
+					continue
+				}
+			}
+			tasks = append(tasks, task)
+		}
+		if opts.Delete {
+			for relPath, dst := range destination {
+				if _, ok := source[relPath]; ok {
+					continue
+				}
+				tasks = append(tasks, SyncResult{
+					Action:     SyncDelete,
+					ObjectName: prefix + relPath,
+					FilePath:   filepath.Join(dirPath, filepath.FromSlash(relPath)),
+					Size:       dst.size,
+				})
+			}
+		}
+
+		tasksCh := make(chan SyncResult)
+		var wg sync.WaitGroup
+		for i := 0; i < numWorkers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for task := range tasksCh {
+					switch {
+					case task.Action == SyncUpload:
+						task.Size, task.Err = c.FPutObjectWithContext(ctx, bucketName, task.ObjectName, task.FilePath, opts.PutObjectOptions)
+					case task.Action == SyncDownload:
+						task.Err = c.FGetObjectWithContext(ctx, bucketName, task.ObjectName, task.FilePath, opts.GetObjectOptions)
+					case opts.Direction == SyncToBucket:
+						task.Err = c.RemoveObjectWithContext(ctx, bucketName, task.ObjectName, RemoveObjectOptions{})
+					default:
+						task.Err = os.Remove(task.FilePath)
+					}
+					resultCh <- task
+				}
+			}()
+		}
+		for _, task := range tasks {
+			select {
+			case tasksCh <- task:
+			case <-ctx.Done():
+				resultCh <- SyncResult{Err: ctx.Err()}
+				close(tasksCh)
+				wg.Wait()
+				return
+			}
+		}
+		close(tasksCh)
+		wg.Wait()
+	}()
+
+	return resultCh
+}
@@ -0,0 +1,189 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Tests the include and exclude filters.
+func TestSyncMatch(t *testing.T) {
+	testCases := []struct {
+		include, exclude []string
+		relPath          string
+		match            bool
+	}{
+		{nil, nil, "a/b.txt", true},
+		{[]string{"*.txt"}, nil, "a/b.txt", true},
+		{[]string{"*.jpg"}, nil, "a/b.txt", false},
+		{[]string{"a/*"}, nil, "a/b.txt", true},
+		{[]string{"a/*"}, nil, "c/a/b.txt", false},
+		{nil, []string{"*.tmp"}, "a/b.tmp", false},
+		{[]string{"*.txt"}, []string{"secret*"}, "a/secret.txt", false},
+		{nil, []string{"a/*"}, "a/b.txt", false},
+	}
+	for i, testCase := range testCases {
+		opts := SyncOptions{Include: testCase.include, Exclude: testCase.exclude}
+		if match := opts.syncMatch(testCase.relPath); match != testCase.match {
+			t.Errorf("Test %d: expected match to be %v for %s", i+1, testCase.match, testCase.relPath)
+		}
+	}
+}
+
+// Tests delta detection between local files and objects.
+func TestSyncNeedsTransfer(t *testing.T) {
+	f, err := ioutil.TempFile("", "minio-go-sync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err = f.WriteString("hello"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	now := time.Now()
+	local := syncEntry{filePath: f.Name(), size: 5, modTime: now}
+	testCases := []struct {
+		remote   syncEntry
+		opts     SyncOptions
+		transfer bool
+	}{
+		{syncEntry{size: 4, modTime: now}, SyncOptions{}, true},
+		{syncEntry{size: 5, modTime: now}, SyncOptions{}, false},
+		{syncEntry{size: 5, modTime: now.Add(-time.Hour)}, SyncOptions{}, true},
+		{syncEntry{size: 5, modTime: now.Add(-time.Hour)}, SyncOptions{Direction: SyncFromBucket}, false},
+		{syncEntry{size: 5, modTime: now.Add(time.Hour)}, SyncOptions{Direction: SyncFromBucket}, true},
+		// md5("hello")
+		{syncEntry{size: 5, modTime: now.Add(-time.Hour), etag: "5d41402abc4b2a76b9719d911017c592"}, SyncOptions{CompareETag: true}, false},
+		{syncEntry{size: 5, modTime: now, etag: "00000000000000000000000000000000"}, SyncOptions{CompareETag: true}, true},
+		// Multipart ETags fall back to the modification time.
+		{syncEntry{size: 5, modTime: now, etag: "00000000000000000000000000000000-2"}, SyncOptions{CompareETag: true}, false},
+	}
+	for i, testCase := range testCases {
+		transfer, err := syncNeedsTransfer(local, testCase.remote, testCase.opts)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		if transfer != testCase.transfer {
+			t.Errorf("Test %d: expected transfer to be %v", i+1, testCase.transfer)
+		}
+	}
+}
+
+// Tests listing local files for sync.
+func TestSyncListLocal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "minio-go-sync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"a.txt", "b.tmp", filepath.Join("sub", "c.txt"), "d.txt.part.minio"} {
+		filePath := filepath.Join(dir, name)
+		if err = os.MkdirAll(filepath.Dir(filePath), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err = ioutil.WriteFile(filePath, []byte(name), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := syncListLocal(dir, SyncOptions{Exclude: []string{"*.tmp"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var relPaths []string
+	for relPath := range entries {
+		relPaths = append(relPaths, relPath)
+	}
+	sort.Strings(relPaths)
+	if expected := []string{"a.txt", "sub/c.txt"}; !reflect.DeepEqual(relPaths, expected) {
+		t.Errorf("Expected %v, got %v", expected, relPaths)
+	}
+
+	// A missing destination directory is empty when downloading.
+	if _, err = syncListLocal(filepath.Join(dir, "missing"), SyncOptions{Direction: SyncFromBucket}); err != nil {
+		t.Errorf("Expected no error for a missing directory, got %v", err)
+	}
+	if _, err = syncListLocal(filepath.Join(dir, "missing"), SyncOptions{}); err == nil {
+		t.Errorf("Expected an error for a missing directory")
+	}
+}
+
+// Tests that objects which would be downloaded outside of the
+// directory are rejected.
+func TestSyncFromBucketTraversal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"8d777f385d3dfec8815d20f7496026dc"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		switch r.Method {
+		case http.MethodGet:
+			if r.URL.Query().Get("list-type") != "2" {
+				w.Header().Set("Content-Length", "4")
+				w.Write([]byte("data"))
+				return
+			}
+			fmt.Fprint(w, "<ListBucketResult><IsTruncated>false</IsTruncated>")
+			for _, key := range []string{"dir/a", "dir/../../escape"} {
+				fmt.Fprintf(w, "<Contents><Key>%s</Key><Size>4</Size></Contents>", key)
+			}
+			fmt.Fprint(w, "</ListBucketResult>")
+		case http.MethodHead:
+			w.Header().Set("Content-Length", "4")
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, err := ioutil.TempDir("", "minio-go-sync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	dir := filepath.Join(root, "a", "b")
+	if err = os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	results := make(map[string]error)
+	for result := range c.Sync("bucket", "dir", dir, SyncOptions{Direction: SyncFromBucket}) {
+		results[result.ObjectName] = result.Err
+	}
+	if err := results["dir/a"]; err != nil {
+		t.Errorf("expected dir/a to be downloaded, got %v", err)
+	}
+	if results["dir/../../escape"] == nil {
+		t.Error("expected dir/../../escape to be rejected")
+	}
+	if _, err = os.Stat(filepath.Join(root, "escape")); !os.IsNotExist(err) {
+		t.Errorf("expected no file outside of the directory, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"time"
+)
+
+// WaitForObjectOptions represents options specified by user for
+// WaitForObject call.
+type WaitForObjectOptions struct {
+	// Options used for the stat requests.
+	StatObjectOptions
+
+	// Timeout after which waiting fails with the NoSuchKey error of
+	// the last stat request, zero waits until the context is done.
+	Timeout time.Duration
+
+	// The delay between stat requests starts at RetryUnit and
+	// doubles up to RetryCap, defaults to 100ms and DefaultRetryCap.
+	RetryUnit time.Duration
+	RetryCap  time.Duration
+}
+
+// defaultWaitRetryUnit - initial delay between the stat requests of
+// WaitForObject.
+const defaultWaitRetryUnit = 100 * time.Millisecond
+
+// WaitForObject - waits until an object exists, the object is stat'ed
+// with exponentially increasing delays for as long as it is not
+// found. The info of the object is returned once it exists, other
+// errors are returned immediately.
+func (c Client) WaitForObject(bucketName, objectName string, opts WaitForObjectOptions) (ObjectInfo, error) {
+	return c.WaitForObjectWithContext(context.Background(), bucketName, objectName, opts)
+}
+
+// WaitForObjectWithContext - Identical to WaitForObject call, but accepts context to facilitate request cancellation.
+func (c Client) WaitForObjectWithContext(ctx context.Context, bucketName, objectName string, opts WaitForObjectOptions) (ObjectInfo, error) {
+	unit, maxDelay := opts.RetryUnit, opts.RetryCap
+	if unit <= 0 {
+		unit = defaultWaitRetryUnit
+	}
+	if maxDelay <= 0 {
+		maxDelay = DefaultRetryCap
+	}
+	if maxDelay < unit {
+		maxDelay = unit
+	}
+	var deadline <-chan time.Time
+	if opts.Timeout > 0 {
+		timer := time.NewTimer(opts.Timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	delay := unit
+	for {
+		objInfo, err := c.statObject(ctx, bucketName, objectName, opts.StatObjectOptions)
+		if err != nil && ctx.Err() != nil {
+			return ObjectInfo{}, ctx.Err()
+		}
+		if err == nil || ToErrorResponse(err).Code != "NoSuchKey" {
+			return objInfo, err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-deadline:
+			timer.Stop()
+			return ObjectInfo{}, err
+		case <-ctx.Done():
+			timer.Stop()
+			return ObjectInfo{}, ctx.Err()
+		}
+		if delay *= 2; delay > maxDelay {
+			delay = maxDelay
+		}
+	}
+}
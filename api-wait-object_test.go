@@ -0,0 +1,77 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Tests waiting for objects which appear after some stat requests.
+func TestWaitForObject(t *testing.T) {
+	var stats int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&stats, 1)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/denied"):
+			w.WriteHeader(http.StatusForbidden)
+		case strings.HasSuffix(r.URL.Path, "/appearing") && n > 3:
+			w.Header().Set("ETag", `"etag"`)
+			w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := WaitForObjectOptions{RetryUnit: time.Millisecond, RetryCap: 5 * time.Millisecond}
+
+	objInfo, err := c.WaitForObject("bucket", "appearing", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objInfo.ETag != "etag" || atomic.LoadInt32(&stats) != 4 {
+		t.Errorf("Expected the object after 4 stat requests, got %q after %d", objInfo.ETag, stats)
+	}
+
+	atomic.StoreInt32(&stats, 0)
+	if _, err = c.WaitForObject("bucket", "denied", opts); ToErrorResponse(err).Code != "AccessDenied" || atomic.LoadInt32(&stats) != 1 {
+		t.Errorf("Expected AccessDenied after a single stat request, got %v after %d", err, stats)
+	}
+
+	opts.Timeout = 50 * time.Millisecond
+	if _, err = c.WaitForObject("bucket", "missing", opts); ToErrorResponse(err).Code != "NoSuchKey" {
+		t.Errorf("Expected NoSuchKey after the timeout, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	opts.Timeout = 0
+	if _, err = c.WaitForObjectWithContext(ctx, "bucket", "missing", opts); err != context.DeadlineExceeded {
+		t.Errorf("Expected the context deadline to be exceeded, got %v", err)
+	}
+}
@@ -28,12 +28,12 @@ import (
 	"io"
 	"io/ioutil"
 	"math/rand"
-	"net"
 	"net/http"
 	"net/http/cookiejar"
 	"net/http/httputil"
 	"net/url"
 	"os"
+	"path"
 	"runtime"
 	"strings"
 	"sync"
@@ -47,6 +47,12 @@ import (
 )
 
 // Client implements Amazon S3 compatible methods.
+//
+// A *Client is safe for concurrent use by multiple goroutines, its
+// settings must however be changed before it is used. Copies of a
+// Client value share its transport, location cache and other state
+// with the original; use Clone for a client with its own settings
+// which shares only the connections and caches of the endpoint.
 type Client struct {
 	///  Standard options.
 
@@ -89,6 +95,53 @@ type Client struct {
 	// lookup indicates type of url lookup supported by server. If not specified,
 	// default to Auto.
 	lookup BucketLookupType
+
+	// Custom lookup type of a bucket, overrides lookup if set.
+	lookupViaURL func(u url.URL, bucketName string) BucketLookupType
+
+	// Allocator of multipart upload buffers, defaultBufferPool if nil.
+	bufferPool BufferPool
+
+	// Bucket name validation rules, defaults to Auto.
+	bucketNameValidation BucketNameValidationType
+
+	// Disables Expect: 100-continue on uploads.
+	disableExpectContinue bool
+
+	// Deviations of the S3 compatible service from Amazon S3.
+	profile endpointProfile
+
+	// Result of the health check of the endpoint.
+	health *healthStatus
+
+	// Other endpoints of the same deployment reads can be sent to.
+	readEndpoints []*url.URL
+
+	// Total time the retries of a request may take, unlimited if zero.
+	maxRetryElapsed time.Duration
+
+	// Retries allowed to requests of the client, unlimited if nil.
+	retryBudget *RetryBudget
+
+	// Cache of downloaded objects, downloads are not cached if nil.
+	downloadCache *DownloadCache
+
+	// Called with the signature V4 of each signed request if set.
+	signatureHook func(req *http.Request, signature s3signer.SignatureV4)
+
+	// Uploads never overwrite existing objects if set.
+	createOnly bool
+
+	// Directory buckets are accessed with S3 Express sessions, their
+	// credentials are shared by copies of the client.
+	s3Express         bool
+	s3ExpressSessions *s3ExpressSessions
+
+	// Multi-region clients look up the location of every bucket and
+	// send its requests to the endpoint of its region, requests
+	// without a bucket are sent in the default region.
+	multiRegion   bool
+	defaultRegion string
 }
 
 // Options for New method
@@ -97,6 +150,30 @@ type Options struct {
 	Secure       bool
 	Region       string
 	BucketLookup BucketLookupType
+	// Bucket name validation rules, BucketNameAuto if not set.
+	BucketNameValidation BucketNameValidationType
+	// Profile of the S3 compatible service, EndpointProfileAuto if not set.
+	EndpointProfile EndpointProfile
+	// Other endpoints of the same deployment, e.g. the sites of a
+	// geo-distributed MinIO cluster. While HealthCheck runs, reads
+	// are sent to the online endpoint with the lowest latency.
+	ReadEndpoints []string
+	// Decides the bucket lookup type per endpoint and bucket, e.g. to
+	// address the buckets of a custom domain in virtual host style.
+	// Overrides BucketLookup unless BucketLookupAuto is returned.
+	BucketLookupViaURL func(u url.URL, bucketName string) BucketLookupType
+	// Access directory buckets, named 'bucket--zone--x-s3', with
+	// S3 Express One Zone sessions on endpoints other than Amazon S3,
+	// where they are always used.
+	S3Express bool
+	// Send the requests of every bucket to the regional endpoint of
+	// its location, even if the endpoint or Region is of one region,
+	// which is then used for requests without a bucket.
+	MultiRegion bool
+	// Time uploads wait for the server to accept a request before
+	// the body is sent anyway, one second if zero. A negative
+	// timeout disables Expect: 100-continue.
+	ExpectContinueTimeout time.Duration
 	// Add future fields here
 }
 
@@ -125,6 +202,21 @@ const (
 	BucketLookupPath
 )
 
+// BucketNameValidationType is the type of bucket name validation
+// rules applied before requests are made.
+type BucketNameValidationType int
+
+// Different bucket name validation rules. BucketNameAuto applies the
+// AWS S3 DNS compatible naming rules to Amazon S3 and Google cloud
+// storage endpoints, for all other endpoints existing buckets are
+// validated with the relaxed rules accepted by MinIO and new buckets
+// with the strict rules.
+const (
+	BucketNameAuto BucketNameValidationType = iota
+	BucketNameStrict
+	BucketNameRelaxed
+)
+
 // NewV2 - instantiate minio client with Amazon S3 signature version
 // '2' compatibility.
 func NewV2(endpoint string, accessKeyID, secretAccessKey string, secure bool) (*Client, error) {
@@ -184,7 +276,88 @@ func NewWithRegion(endpoint, accessKeyID, secretAccessKey string, secure bool, r
 
 // NewWithOptions - instantiate minio client with options
 func NewWithOptions(endpoint string, opts *Options) (*Client, error) {
-	return privateNew(endpoint, opts.Creds, opts.Secure, opts.Region, opts.BucketLookup)
+	clnt, err := privateNew(endpoint, opts.Creds, opts.Secure, opts.Region, opts.BucketLookup)
+	if err != nil {
+		return nil, err
+	}
+	clnt.bucketNameValidation = opts.BucketNameValidation
+	clnt.lookupViaURL = opts.BucketLookupViaURL
+	if opts.S3Express {
+		clnt.s3Express = true
+	}
+	if opts.EndpointProfile != EndpointProfileAuto {
+		clnt.applyEndpointProfile(opts.EndpointProfile, opts.Region)
+	}
+	if opts.ExpectContinueTimeout < 0 {
+		clnt.disableExpectContinue = true
+	} else if tr, ok := clnt.httpClient.Transport.(*http.Transport); ok && opts.ExpectContinueTimeout > 0 {
+		// The default transport of the new client is not used yet.
+		tr.ExpectContinueTimeout = opts.ExpectContinueTimeout
+	}
+	if opts.MultiRegion {
+		clnt.multiRegion = true
+		clnt.defaultRegion, clnt.region = clnt.region, ""
+	}
+	for _, endpoint := range opts.ReadEndpoints {
+		endpointURL, err := getEndpointURL(endpoint, opts.Secure)
+		if err != nil {
+			return nil, err
+		}
+		clnt.readEndpoints = append(clnt.readEndpoints, endpointURL)
+	}
+	return clnt, nil
+}
+
+// CloneOptions - overrides of the settings of a clone of a client.
+type CloneOptions struct {
+	// Credentials of the clone, those of the client if nil.
+	Creds *credentials.Credentials
+
+	// Region of the clone, the region of the client if empty.
+	Region string
+}
+
+// Clone - returns a new client for the endpoint of the client, with
+// the same settings except those overridden by opts, e.g. to access
+// the endpoint on behalf of many tenants. The clone shares the
+// connections, bucket location cache, health check, retry budget
+// and download cache of the client, and S3 Express sessions unless
+// its credentials are overridden. Settings changed on the clone do
+// not affect the client.
+func (c *Client) Clone(opts CloneOptions) (*Client, error) {
+	if opts.Region != "" && !Region(opts.Region).IsValid() {
+		return nil, ErrInvalidArgument("Invalid region `" + opts.Region + "`.")
+	}
+
+	// Cookies are never shared with other credentials.
+	jar, err := cookiejar.New(&cookiejar.Options{PublicSuffixList: publicsuffix.List})
+	if err != nil {
+		return nil, err
+	}
+
+	clnt := new(Client)
+	*clnt = *c
+	endpointURL := *c.endpointURL
+	clnt.endpointURL = &endpointURL
+	clnt.readEndpoints = append([]*url.URL(nil), c.readEndpoints...)
+	clnt.httpClient = &http.Client{
+		Jar:           jar,
+		Transport:     c.httpClient.Transport,
+		CheckRedirect: clnt.redirectHeaders,
+		Timeout:       c.httpClient.Timeout,
+	}
+	if opts.Creds != nil {
+		clnt.credsProvider = opts.Creds
+		clnt.s3ExpressSessions = newS3ExpressSessions()
+	}
+	if opts.Region != "" {
+		if clnt.multiRegion {
+			clnt.defaultRegion = opts.Region
+		} else {
+			clnt.region = opts.Region
+		}
+	}
+	return clnt, nil
 }
 
 // EndpointURL returns the URL of the S3 endpoint.
@@ -223,6 +396,15 @@ func (c *Client) redirectHeaders(req *http.Request, via []*http.Request) error {
 	if len(via) == 0 {
 		return nil
 	}
+	// Redirects to the endpoint of the region of the bucket are not
+	// followed, executeMethod retries them in the region of the bucket
+	// and updates the bucket location cache.
+	if c.region == "" && req.Response != nil {
+		errResp := ErrorResponse{Region: req.Response.Header.Get("x-amz-bucket-region")}
+		if redirectRegion(req.Response, errResp) != "" {
+			return http.ErrUseLastResponse
+		}
+	}
 	lastRequest := via[len(via)-1]
 	var reAuth bool
 	for attr, val := range lastRequest.Header {
@@ -236,7 +418,10 @@ func (c *Client) redirectHeaders(req *http.Request, via []*http.Request) error {
 		}
 	}
 
-	*c.endpointURL = *req.URL
+	// Only the scheme and host follow the redirect, the base path of
+	// the endpoint is kept.
+	c.endpointURL.Scheme = req.URL.Scheme
+	c.endpointURL.Host = req.URL.Host
 
 	value, err := c.credsProvider.Get()
 	if err != nil {
@@ -282,6 +467,9 @@ func privateNew(endpoint string, creds *credentials.Credentials, secure bool, re
 	if err != nil {
 		return nil, err
 	}
+	if region != "" && !Region(region).IsValid() {
+		return nil, ErrInvalidArgument("Invalid region `" + region + "`.")
+	}
 
 	// Initialize cookies to preserve server sent cookies if any and replay
 	// them upon each request.
@@ -296,13 +484,14 @@ func privateNew(endpoint string, creds *credentials.Credentials, secure bool, re
 	// Save the credentials.
 	clnt.credsProvider = creds
 
-	// Remember whether we are using https or not
-	clnt.secure = secure
+	// Remember whether we are using https or not, an explicit scheme
+	// of the endpoint takes precedence over secure.
+	clnt.secure = endpointURL.Scheme == "https"
 
 	// Save endpoint URL, user agent for future uses.
 	clnt.endpointURL = endpointURL
 
-	transport, err := DefaultTransport(secure)
+	transport, err := DefaultTransport(clnt.secure)
 	if err != nil {
 		return nil, err
 	}
@@ -314,15 +503,21 @@ func privateNew(endpoint string, creds *credentials.Credentials, secure bool, re
 		CheckRedirect: clnt.redirectHeaders,
 	}
 
-	// Sets custom region, if region is empty bucket location cache is used automatically.
-	if region == "" {
-		region = s3utils.GetRegionFromURL(*clnt.endpointURL)
-	}
-	clnt.region = region
+	// Sets the endpoint profile determined from the endpoint and
+	// the custom region.
+	clnt.applyEndpointProfile(EndpointProfileAuto, region)
 
 	// Instantiate bucket location cache.
 	clnt.bucketLocCache = newBucketLocationCache()
 
+	// Instantiate the health check state.
+	clnt.health = &healthStatus{}
+
+	// Directory buckets of Amazon S3 are accessed with S3 Express
+	// sessions.
+	clnt.s3Express = s3utils.IsAmazonEndpoint(*endpointURL)
+	clnt.s3ExpressSessions = newS3ExpressSessions()
+
 	// Introduce a new locked random seed.
 	clnt.random = rand.New(&lockedRandSource{src: rand.NewSource(time.Now().UTC().UnixNano())})
 
@@ -342,6 +537,111 @@ func (c *Client) SetAppInfo(appName string, appVersion string) {
 	}
 }
 
+// SetBufferPool - set the allocator of the part sized buffers used by
+// multipart uploads, nil restores the default pool shared by all
+// clients.
+func (c *Client) SetBufferPool(pool BufferPool) {
+	c.bufferPool = pool
+}
+
+// SetBucketNameValidation - set the bucket name validation rules
+// applied before requests are made.
+func (c *Client) SetBucketNameValidation(validation BucketNameValidationType) {
+	c.bucketNameValidation = validation
+}
+
+// validateBucketName - checks if the bucket name is valid with the
+// bucket name validation rules of the client, newBucket is used for
+// names of buckets to be created.
+func (c Client) validateBucketName(bucketName string, newBucket bool) error {
+	if newBucket && isAccessPointARN(bucketName) {
+		return ErrInvalidBucketName("Buckets cannot be created with the ARN of an access point.")
+	}
+	var strict bool
+	switch c.bucketNameValidation {
+	case BucketNameStrict:
+		strict = true
+	case BucketNameRelaxed:
+		strict = false
+	default:
+		strict = newBucket || s3utils.IsAmazonEndpoint(*c.endpointURL) || s3utils.IsGoogleEndpoint(*c.endpointURL)
+	}
+	return ValidateBucketName(bucketName, strict)
+}
+
+// SetExpectContinueTimeout - enable or disable Expect: 100-continue.
+// Requests with bodies of unknown size or of at least 1MiB are sent
+// with Expect: 100-continue, so that authentication failures and
+// redirects are detected before the body is streamed. A timeout of
+// zero disables Expect: 100-continue, any other timeout enables it
+// again. The transport, which may be shared with other clients, is not
+// changed: its timeout is set with opts.ExpectContinueTimeout of
+// NewWithOptions, or on a custom transport.
+func (c *Client) SetExpectContinueTimeout(timeout time.Duration) {
+	c.disableExpectContinue = timeout <= 0
+}
+
+// SetRetryDeadline - limit the total time a request and its retries
+// may take. No retry is attempted if it could only start after the
+// deadline, zero removes the limit. Retries also never outlast the
+// deadline of the context of the request.
+func (c *Client) SetRetryDeadline(maxElapsed time.Duration) {
+	c.maxRetryElapsed = maxElapsed
+}
+
+// SetRetryBudget - limit the retries of the requests of the client to
+// the given budget, which may be shared with other clients. Nil
+// removes the limit.
+func (c *Client) SetRetryBudget(budget *RetryBudget) {
+	c.retryBudget = budget
+}
+
+// SetDownloadCache - serve repeat downloads of whole objects from the
+// given cache after revalidating their ETag with the server, which
+// may be shared with other clients of the endpoint. Nil disables the
+// cache.
+func (c *Client) SetDownloadCache(cache *DownloadCache) {
+	c.downloadCache = cache
+}
+
+// SetSignatureHook - call hook with the canonical request, string to
+// sign and signature of each request signed with signature V4, so
+// that SignatureDoesNotMatch errors can be compared with the values
+// reported by the server. The request must not be modified. Nil
+// removes the hook.
+func (c *Client) SetSignatureHook(hook func(req *http.Request, signature s3signer.SignatureV4)) {
+	c.signatureHook = hook
+}
+
+// SetCreateOnly - make all uploads of PutObject and the calls based
+// on it fail with a PreconditionFailed error if an object already
+// exists with the same name, as if CreateOnly was set in their
+// options. Services without conditional writes are checked with a
+// HEAD request before each upload instead, which does not detect
+// objects created in the meantime.
+func (c *Client) SetCreateOnly(createOnly bool) {
+	c.createOnly = createOnly
+}
+
+// traceSignature - reports the signature V4 of a signed request to
+// the signature hook.
+func (c Client) traceSignature(req *http.Request, secretAccessKey, location string) {
+	if c.signatureHook == nil {
+		return
+	}
+	if signature, err := s3signer.GetSignatureV4(*req, secretAccessKey, location); err == nil {
+		c.signatureHook(req, signature)
+	}
+}
+
+// getBufferPool - returns the buffer pool of the client.
+func (c Client) getBufferPool() BufferPool {
+	if c.bufferPool != nil {
+		return c.bufferPool
+	}
+	return defaultBufferPool
+}
+
 // SetCustomTransport - set new custom transport.
 func (c *Client) SetCustomTransport(customHTTPTransport http.RoundTripper) {
 	// Set this to override default transport
@@ -412,20 +712,28 @@ func (c *Client) SetS3TransferAccelerate(accelerateEndpoint string) {
 //  - For signature v4 request if the connection is insecure compute only sha256.
 //  - For signature v4 request if the connection is secure compute only md5.
 //  - For anonymous request compute md5.
-func (c *Client) hashMaterials() (hashAlgos map[string]hash.Hash, hashSums map[string][]byte) {
+//  - Compute md5 whenever it is requested and never compute sha256
+//    when it is disabled.
+//  - Never compute md5 when it is disabled, unless it is requested.
+func (c *Client) hashMaterials(isMd5Requested, isMd5Disabled, isSha256Disabled bool) (hashAlgos map[string]hash.Hash, hashSums map[string][]byte) {
 	hashSums = make(map[string][]byte)
 	hashAlgos = make(map[string]hash.Hash)
 	if c.overrideSignerType.IsV4() {
 		if c.secure {
-			hashAlgos["md5"] = md5.New()
-		} else {
+			if !isMd5Disabled {
+				hashAlgos["md5"] = md5.New()
+			}
+		} else if !isSha256Disabled {
 			hashAlgos["sha256"] = sha256.New()
 		}
 	} else {
-		if c.overrideSignerType.IsAnonymous() {
+		if c.overrideSignerType.IsAnonymous() && !isMd5Disabled {
 			hashAlgos["md5"] = md5.New()
 		}
 	}
+	if isMd5Requested {
+		hashAlgos["md5"] = md5.New()
+	}
 	return hashAlgos, hashSums
 }
 
@@ -447,6 +755,10 @@ type requestMetadata struct {
 	contentLength    int64
 	contentMD5Base64 string // carries base64 encoded md5sum
 	contentSHA256Hex string // carries hex encoded sha256sum
+	unsignedPayload  bool   // disables streaming signature of the payload
+	errorIn200       bool   // response may be an error document with 200 OK
+	createSession    bool   // creates an S3 Express session of the bucket
+	adminAPI         string // MinIO admin API addressed instead of a bucket
 }
 
 // dumpHTTP - dump HTTP request and response.
@@ -586,10 +898,20 @@ func (c Client) executeMethod(ctx context.Context, method string, metadata reque
 	// Indicate to our routine to exit cleanly upon return.
 	defer close(doneCh)
 
-	// Blank indentifier is kept here on purpose since 'range' without
-	// blank identifiers is only supported since go1.4
-	// https://golang.org/doc/go1.4#forrange.
-	for range c.newRetryTimer(reqRetry, DefaultRetryUnit, DefaultRetryCap, MaxJitter, doneCh) {
+	if c.retryBudget != nil {
+		c.retryBudget.deposit()
+	}
+	deadline := c.retryDeadline(ctx, time.Now())
+
+	// Each value received from the retry timer is the current attempt
+	// number, it is recorded on the request to be reported back on
+	// error responses.
+	for attempt := range c.newRetryTimer(reqRetry, DefaultRetryUnit, DefaultRetryCap, MaxJitter, deadline, doneCh) {
+		// Retries are paid from the retry budget, once exhausted
+		// the last error is returned.
+		if attempt > 1 && c.retryBudget != nil && !c.retryBudget.withdraw() {
+			break
+		}
 		// Retry executes the following function body if request has an
 		// error until maxRetries have been exhausted, retry attempts are
 		// performed after waiting for a given period of time in a
@@ -613,8 +935,8 @@ func (c Client) executeMethod(ctx context.Context, method string, metadata reque
 			return nil, err
 		}
 
-		// Add context to request
-		req = req.WithContext(ctx)
+		// Add context to request, along with the current attempt.
+		req = req.WithContext(withRetryAttempt(ctx, attempt))
 
 		// Initiate the request.
 		res, err = c.do(req)
@@ -627,6 +949,22 @@ func (c Client) executeMethod(ctx context.Context, method string, metadata reque
 			return nil, err
 		}
 
+		// Operations failing after the response status was sent
+		// return an error document with 200 OK.
+		if metadata.errorIn200 && res.StatusCode == http.StatusOK {
+			errResponse, found, rerr := errorIn200Response(res, metadata.bucketName, metadata.objectName)
+			if rerr != nil {
+				return nil, rerr
+			}
+			if found {
+				res, err = nil, errResponse
+				if isS3CodeRetryable(errResponse.Code) {
+					continue // Retry.
+				}
+				return nil, err
+			}
+		}
+
 		// For any known successful http status, return quickly.
 		for _, httpStatus := range successStatus {
 			if httpStatus == res.StatusCode {
@@ -653,6 +991,13 @@ func (c Client) executeMethod(ctx context.Context, method string, metadata reque
 		errBodySeeker.Seek(0, 0) // Seek back to starting point.
 		res.Body = ioutil.NopCloser(errBodySeeker)
 
+		// Sessions of directory buckets may end before they expire,
+		// a new session is created for the retry.
+		if (errResponse.Code == "ExpiredToken" || errResponse.Code == "InvalidToken") && !metadata.createSession &&
+			c.isS3ExpressBucket(metadata.bucketName) && c.s3ExpressSessions.remove(metadata.bucketName) {
+			continue // Retry.
+		}
+
 		// Bucket region if set in error response and the error
 		// code dictates invalid region, we can retry the request
 		// with the new region.
@@ -660,6 +1005,20 @@ func (c Client) executeMethod(ctx context.Context, method string, metadata reque
 		// Additionally we should only retry if bucketLocation and custom
 		// region is empty.
 		if c.region == "" {
+			// Redirected to the endpoint of the region of the bucket,
+			// retry the request in this region.
+			if region := redirectRegion(res, errResponse); region != "" && metadata.bucketName != "" {
+				if metadata.bucketLocation != "" {
+					if metadata.bucketLocation != region {
+						metadata.bucketLocation = region
+						continue // Retry.
+					}
+				} else if cachedLocation, _ := c.bucketLocCache.Get(metadata.bucketName); cachedLocation != region {
+					c.bucketLocCache.Set(metadata.bucketName, region)
+					continue // Retry.
+				}
+			}
+
 			switch errResponse.Code {
 			case "AuthorizationHeaderMalformed":
 				fallthrough
@@ -703,6 +1062,43 @@ func (c Client) executeMethod(ctx context.Context, method string, metadata reque
 	return res, err
 }
 
+// redirectRegion - returns the region of the bucket if res redirects
+// to the endpoint of another region, from the bucket region header or
+// the endpoint the bucket must be addressed at. Returns empty if the
+// region is not known.
+func redirectRegion(res *http.Response, errResp ErrorResponse) string {
+	switch {
+	case res.StatusCode == http.StatusMovedPermanently, res.StatusCode == http.StatusTemporaryRedirect:
+	case errResp.Code == "PermanentRedirect", errResp.Code == "TemporaryRedirect":
+	default:
+		return ""
+	}
+	if errResp.Region != "" {
+		return errResp.Region
+	}
+	host := errResp.Endpoint
+	if host == "" {
+		location, err := url.Parse(res.Header.Get("Location"))
+		if err != nil {
+			return ""
+		}
+		host = location.Hostname()
+	}
+	// Strip the labels of virtual host style endpoints, bucket names
+	// may contain dots.
+	for host != "" {
+		if region := s3utils.GetRegionFromURL(url.URL{Host: host}); region != "" {
+			return region
+		}
+		i := strings.Index(host, ".")
+		if i < 0 {
+			break
+		}
+		host = host[i+1:]
+	}
+	return ""
+}
+
 // newRequest - instantiate a new HTTP request for a given method.
 func (c Client) newRequest(method string, metadata requestMetadata) (req *http.Request, err error) {
 	// If no method is supplied default to 'POST'.
@@ -710,6 +1106,12 @@ func (c Client) newRequest(method string, metadata requestMetadata) (req *http.R
 		method = "POST"
 	}
 
+	// Send reads to the endpoint selected by the health check, the
+	// endpoint of this copy of the client is replaced.
+	if (method == "GET" || method == "HEAD") && metadata.bucketName != "" && !metadata.presignURL && metadata.adminAPI == "" {
+		c.endpointURL = c.readEndpointURL()
+	}
+
 	location := metadata.bucketLocation
 	if location == "" {
 		if metadata.bucketName != "" {
@@ -720,7 +1122,7 @@ func (c Client) newRequest(method string, metadata requestMetadata) (req *http.R
 			}
 		}
 		if location == "" {
-			location = getDefaultLocation(*c.endpointURL, c.region)
+			location = getDefaultLocation(*c.endpointURL, c.regionOverride())
 		}
 	}
 
@@ -737,6 +1139,16 @@ func (c Client) newRequest(method string, metadata requestMetadata) (req *http.R
 		return nil, err
 	}
 
+	// MinIO admin APIs are addressed below the admin prefix.
+	if metadata.adminAPI != "" {
+		targetURL.Path = path.Join(targetURL.Path, minIOAdminPrefix, metadata.adminAPI)
+	}
+
+	// Fail requests the service cannot handle as expected.
+	if err = c.checkEndpointProfile(method, metadata); err != nil {
+		return nil, err
+	}
+
 	// Initialize a new HTTP request for the method.
 	req, err = http.NewRequest(method, targetURL.String(), nil)
 	if err != nil {
@@ -767,17 +1179,45 @@ func (c Client) newRequest(method string, metadata requestMetadata) (req *http.R
 		signerType = credentials.SignatureAnonymous
 	}
 
+	// Access points only accept requests signed with signature V4,
+	// those of Object Lambda and Outposts for their own services and
+	// Multi-Region Access Points with signature V4A for all regions.
+	// Object Lambda access points only serve reads of objects and
+	// listings.
+	if signerType.IsV2() && isAccessPointARN(metadata.bucketName) {
+		return nil, ErrAPINotSupported("Access points require signature V4.")
+	}
+	accessPointService := accessPointSigningName(metadata.bucketName)
+	isMultiRegion := isMultiRegionARN(metadata.bucketName)
+	if accessPointService == s3signer.ServiceS3ObjectLambda && method != "GET" && method != "HEAD" {
+		return nil, ErrAPINotSupported("Object Lambda access points only support reading objects.")
+	}
+
 	// Generate presign url if needed, return right here.
 	if metadata.expires != 0 && metadata.presignURL {
 		if signerType.IsAnonymous() {
 			return nil, ErrInvalidArgument("Presigned URLs cannot be generated with anonymous credentials.")
 		}
+		if err = isValidPresignExpiry(signerType, time.Duration(metadata.expires)*time.Second); err != nil {
+			return nil, err
+		}
+		if c.isS3ExpressBucket(metadata.bucketName) {
+			return nil, ErrAPINotSupported("Presigned URLs of S3 Express directory buckets are not supported.")
+		}
 		if signerType.IsV2() {
 			// Presign URL with signature v2.
 			req = s3signer.PreSignV2(*req, accessKeyID, secretAccessKey, metadata.expires, isVirtualHost)
 		} else if signerType.IsV4() {
 			// Presign URL with signature v4.
-			req = s3signer.PreSignV4(*req, accessKeyID, secretAccessKey, sessionToken, location, metadata.expires)
+			if isMultiRegion {
+				req = s3signer.PreSignV4A(*req, accessKeyID, secretAccessKey, sessionToken, []string{"*"}, metadata.expires)
+			} else if accessPointService != "" {
+				req = s3signer.PreSignV4Service(*req, accessKeyID, secretAccessKey, sessionToken, location,
+					accessPointService, metadata.expires)
+			} else {
+				req = s3signer.PreSignV4(*req, accessKeyID, secretAccessKey, sessionToken, location, metadata.expires)
+			}
+			c.traceSignature(req, secretAccessKey, location)
 		}
 		return req, nil
 	}
@@ -807,6 +1247,13 @@ func (c Client) newRequest(method string, metadata requestMetadata) (req *http.R
 		req.TransferEncoding = []string{"chunked"}
 	}
 
+	// Wait for the server to accept large uploads before sending
+	// the body.
+	if !c.disableExpectContinue && (method == "PUT" || method == "POST") &&
+		(req.ContentLength < 0 || req.ContentLength >= expectContinueMinSize) {
+		req.Header.Set("Expect", "100-continue")
+	}
+
 	// set md5Sum for content protection.
 	if len(metadata.contentMD5Base64) > 0 {
 		req.Header.Set("Content-Md5", metadata.contentMD5Base64)
@@ -817,16 +1264,24 @@ func (c Client) newRequest(method string, metadata requestMetadata) (req *http.R
 		return req, nil
 	}
 
+	// Requests to directory buckets are signed for S3 Express, with
+	// the credentials of a session of the bucket once created.
+	isS3Express := c.isS3ExpressBucket(metadata.bucketName)
+
 	switch {
+	case isS3Express && signerType.IsV2():
+		return nil, ErrAPINotSupported("S3 Express directory buckets require signature V4.")
 	case signerType.IsV2():
 		// Add signature version '2' authorization header.
 		req = s3signer.SignV2(*req, accessKeyID, secretAccessKey, isVirtualHost)
-	case metadata.objectName != "" && method == "PUT" && metadata.customHeader.Get("X-Amz-Copy-Source") == "" && !c.secure:
+	case metadata.objectName != "" && method == "PUT" && metadata.customHeader.Get("X-Amz-Copy-Source") == "" && !c.secure && !metadata.unsignedPayload &&
+		!c.profile.noStreamingSignature && !isS3Express && accessPointService == "" && !isMultiRegion:
 		// Streaming signature is used by default for a PUT object request. Additionally we also
 		// look if the initialized client is secure, if yes then we don't need to perform
 		// streaming signature.
 		req = s3signer.StreamingSignV4(req, accessKeyID,
 			secretAccessKey, sessionToken, location, metadata.contentLength, time.Now().UTC())
+		c.traceSignature(req, secretAccessKey, location)
 	default:
 		// Set sha256 sum for signature calculation only with signature version '4'.
 		shaHeader := unsignedPayload
@@ -836,7 +1291,24 @@ func (c Client) newRequest(method string, metadata requestMetadata) (req *http.R
 		req.Header.Set("X-Amz-Content-Sha256", shaHeader)
 
 		// Add signature version '4' authorization header.
-		req = s3signer.SignV4(*req, accessKeyID, secretAccessKey, sessionToken, location)
+		switch {
+		case metadata.createSession:
+			req = s3signer.SignV4Service(*req, accessKeyID, secretAccessKey, sessionToken, location, s3signer.ServiceS3Express)
+		case accessPointService != "":
+			req = s3signer.SignV4Service(*req, accessKeyID, secretAccessKey, sessionToken, location, accessPointService)
+		case isMultiRegion:
+			req = s3signer.SignV4A(*req, accessKeyID, secretAccessKey, sessionToken, []string{"*"})
+		case isS3Express:
+			session, err := c.getS3ExpressSession(metadata.bucketName)
+			if err != nil {
+				return nil, err
+			}
+			secretAccessKey = session.secretAccessKey
+			req = s3signer.SignV4Express(*req, session.accessKeyID, secretAccessKey, session.sessionToken, location)
+		default:
+			req = s3signer.SignV4(*req, accessKeyID, secretAccessKey, sessionToken, location)
+		}
+		c.traceSignature(req, secretAccessKey, location)
 	}
 
 	// Return request.
@@ -854,9 +1326,35 @@ func (c Client) setUserAgent(req *http.Request) {
 // makeTargetURL make a new target url.
 func (c Client) makeTargetURL(bucketName, objectName, bucketLocation string, isVirtualHostStyle bool, queryValues url.Values) (*url.URL, error) {
 	host := c.endpointURL.Host
+	isAccessPoint := isAccessPointARN(bucketName)
+	if isAccessPoint && !s3utils.IsAmazonEndpoint(*c.endpointURL) {
+		return nil, ErrAPINotSupported("Access point ARNs are only supported by Amazon S3 endpoints.")
+	}
 	// For Amazon S3 endpoint, try to fetch location based endpoint.
 	if s3utils.IsAmazonEndpoint(*c.endpointURL) {
-		if c.s3AccelerateEndpoint != "" && bucketName != "" {
+		// Regions of other partitions cannot be reached through
+		// this endpoint.
+		partition := s3utils.GetPartitionFromURL(*c.endpointURL)
+		if locationPartition := s3utils.GetPartition(bucketLocation); locationPartition != "" && locationPartition != partition {
+			return nil, ErrCrossPartitionBucket(bucketName, bucketLocation, partition)
+		}
+		// The acceleration of a bucket is configured through the
+		// regular endpoint.
+		_, isAccelerateConfig := queryValues["accelerate"]
+		if isAccessPoint {
+			// Access points have their own endpoints, the host
+			// names the access point in place of the bucket.
+			ap, err := parseAccessPointARN(bucketName)
+			if err != nil {
+				return nil, ErrInvalidBucketName(err.Error())
+			}
+			host = getAccessPointEndpoint(ap, *c.endpointURL)
+		} else if c.isS3ExpressBucket(bucketName) {
+			// Directory buckets are addressed in virtual host style
+			// through the endpoint of their availability zone.
+			host = getS3ExpressEndpoint(s3ExpressZone(bucketName), bucketLocation)
+			isVirtualHostStyle = true
+		} else if c.s3AccelerateEndpoint != "" && bucketName != "" && !isAccelerateConfig {
 			// http://docs.aws.amazon.com/AmazonS3/latest/dev/transfer-acceleration.html
 			// Disable transfer acceleration for non-compliant bucket names.
 			if strings.Contains(bucketName, ".") {
@@ -867,8 +1365,14 @@ func (c Client) makeTargetURL(bucketName, objectName, bucketLocation string, isV
 			// http://docs.aws.amazon.com/AmazonS3/latest/dev/transfer-acceleration.html
 			host = c.s3AccelerateEndpoint
 		} else {
-			// Do not change the host if the endpoint URL is a FIPS S3 endpoint.
-			if !s3utils.IsAmazonFIPSEndpoint(*c.endpointURL) {
+			if c.multiRegion {
+				// Send requests to the endpoint of the region.
+				var err error
+				if host, err = getRegionEndpoint(*c.endpointURL, bucketLocation); err != nil {
+					return nil, err
+				}
+			} else if !s3utils.IsAmazonFIPSEndpoint(*c.endpointURL) {
+				// Do not change the host if the endpoint URL is a FIPS S3 endpoint.
 				// Fetch new host based on the bucket location.
 				host = getS3Endpoint(bucketLocation)
 			}
@@ -881,21 +1385,25 @@ func (c Client) makeTargetURL(bucketName, objectName, bucketLocation string, isV
 	// Strip port 80 and 443 so we won't send these ports in Host header.
 	// The reason is that browsers and curl automatically remove :80 and :443
 	// with the generated presigned urls, then a signature mismatch error.
-	if h, p, err := net.SplitHostPort(host); err == nil {
-		if scheme == "http" && p == "80" || scheme == "https" && p == "443" {
-			host = h
-		}
-	}
+	host = stripDefaultPort(scheme, host)
 
-	urlStr := scheme + "://" + host + "/"
+	// Requests to servers behind a reverse proxy are prefixed with
+	// the base path of the endpoint.
+	basePath := s3utils.EncodePath(c.endpointURL.Path)
+
+	urlStr := scheme + "://" + host + basePath + "/"
 	// Make URL only if bucketName is available, otherwise use the
 	// endpoint URL.
-	if bucketName != "" {
+	if bucketName != "" && isAccessPoint {
+		if objectName != "" {
+			urlStr = urlStr + s3utils.EncodePath(objectName)
+		}
+	} else if bucketName != "" {
 		// If endpoint supports virtual host style use that always.
 		// Currently only S3 and Google Cloud Storage would support
 		// virtual host style.
 		if isVirtualHostStyle {
-			urlStr = scheme + "://" + bucketName + "." + host + "/"
+			urlStr = scheme + "://" + bucketName + "." + host + basePath + "/"
 			if objectName != "" {
 				urlStr = urlStr + s3utils.EncodePath(objectName)
 			}
@@ -922,13 +1430,30 @@ func (c *Client) isVirtualHostStyleRequest(url url.URL, bucketName string) bool
 		return false
 	}
 
-	if c.lookup == BucketLookupDNS {
+	// Bucket names cannot prefix an IP address.
+	if s3utils.IsValidIP(url.Hostname()) {
+		return false
+	}
+
+	lookup := c.lookup
+	if c.lookupViaURL != nil {
+		if custom := c.lookupViaURL(url, bucketName); custom != BucketLookupAuto {
+			lookup = custom
+		}
+	}
+	if lookup == BucketLookupDNS {
 		return true
 	}
-	if c.lookup == BucketLookupPath {
+	if lookup == BucketLookupPath {
 		return false
 	}
 
+	// Services requiring virtual host style are addressed so, unless
+	// names with '.' would fail certificate validation.
+	if c.profile.virtualHostStyle {
+		return url.Scheme != "https" || !strings.Contains(bucketName, ".")
+	}
+
 	// default to virtual only for Amazon/Google  storage. In all other cases use
 	// path style requests
 	return s3utils.IsVirtualHostSupported(url, bucketName)
@@ -18,11 +18,21 @@
 package minio
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/minio/minio-go/v6/pkg/credentials"
 	"github.com/minio/minio-go/v6/pkg/policy"
+	"github.com/minio/minio-go/v6/pkg/s3signer"
 )
 
 // Tests valid hosts for location.
@@ -34,6 +44,8 @@ func TestValidBucketLocation(t *testing.T) {
 		{"us-east-1", "s3.dualstack.us-east-1.amazonaws.com"},
 		{"unknown", "s3.dualstack.us-east-1.amazonaws.com"},
 		{"ap-southeast-1", "s3.dualstack.ap-southeast-1.amazonaws.com"},
+		{"cn-north-1", "s3.cn-north-1.amazonaws.com.cn"},
+		{"cn-south-9", "s3.cn-south-9.amazonaws.com.cn"},
 	}
 	for _, s3Host := range s3Hosts {
 		endpoint := getS3Endpoint(s3Host.bucketLocation)
@@ -187,6 +199,14 @@ func TestMakeTargetURL(t *testing.T) {
 		{"localhost:80", false, "mybucket", "myobject", "", nil, url.URL{Host: "localhost", Scheme: "http", Path: "/mybucket/myobject"}, nil},
 		// Test 9, testing with port 443
 		{"localhost:443", true, "mybucket", "myobject", "", nil, url.URL{Host: "localhost", Scheme: "https", Path: "/mybucket/myobject"}, nil},
+		// Test 10, testing with a base path
+		{"proxy.example.com:8443/minio", true, "mybucket", "myobject", "", nil, url.URL{Host: "proxy.example.com:8443", Scheme: "https", Path: "/minio/mybucket/myobject"}, nil},
+		// Test 11, testing with a base path and no bucket
+		{"http://proxy.example.com/s3/minio/", true, "", "", "", nil, url.URL{Host: "proxy.example.com", Scheme: "http", Path: "/s3/minio/"}, nil},
+		// Test 12, testing with an IPv6 literal
+		{"[::1]:9000", false, "mybucket", "myobject", "", nil, url.URL{Host: "[::1]:9000", Scheme: "http", Path: "/mybucket/myobject"}, nil},
+		// Test 13, testing with an IPv6 literal and port 443
+		{"[2001:db8::1]:443", true, "mybucket", "", "", nil, url.URL{Host: "[2001:db8::1]", Scheme: "https", Path: "/mybucket/"}, nil},
 	}
 
 	for i, testCase := range testCases {
@@ -210,3 +230,510 @@ func TestMakeTargetURL(t *testing.T) {
 		}
 	}
 }
+
+// Tests extracting user metadata from response headers.
+func TestExtractUserMetadata(t *testing.T) {
+	header := http.Header{}
+	header.Set("Content-Type", "text/plain")
+	header.Set("X-Amz-Meta-Project", "minio")
+	header["x-amz-meta-owner-name"] = []string{"alice"}
+
+	userMetadata := extractUserMetadata(header)
+	if len(userMetadata) != 2 {
+		t.Fatalf("Expected 2 user metadata keys, got %v", userMetadata)
+	}
+	testCases := []struct {
+		key, value string
+	}{
+		{"Project", "minio"},
+		{"project", "minio"},
+		{"X-AMZ-META-PROJECT", "minio"},
+		{"owner-name", "alice"},
+		{"x-amz-meta-Owner-Name", "alice"},
+		{"Content-Type", ""},
+	}
+	for i, testCase := range testCases {
+		if value := userMetadata.Get(testCase.key); value != testCase.value {
+			t.Errorf("Test %d: Expected %q for key %q, got %q", i+1, testCase.value, testCase.key, value)
+		}
+	}
+	if extractUserMetadata(http.Header{"Etag": []string{"abc"}}) != nil {
+		t.Errorf("Expected no user metadata")
+	}
+}
+
+// Tests the regions found in redirect responses.
+func TestRedirectRegion(t *testing.T) {
+	testCases := []struct {
+		statusCode int
+		location   string
+		errResp    ErrorResponse
+		region     string
+	}{
+		{http.StatusMovedPermanently, "", ErrorResponse{Region: "eu-west-1"}, "eu-west-1"},
+		{http.StatusBadRequest, "", ErrorResponse{Code: "PermanentRedirect", Endpoint: "my.bucket.s3.eu-west-2.amazonaws.com"}, "eu-west-2"},
+		{http.StatusTemporaryRedirect, "https://bucket.s3-ap-south-1.amazonaws.com/object?x=y", ErrorResponse{}, "ap-south-1"},
+		{http.StatusTemporaryRedirect, "https://bucket.s3.amazonaws.com/object", ErrorResponse{}, ""},
+		{http.StatusTemporaryRedirect, "http://localhost:9000/bucket", ErrorResponse{}, ""},
+		{http.StatusBadRequest, "", ErrorResponse{Region: "eu-west-1"}, ""},
+	}
+	for i, testCase := range testCases {
+		res := &http.Response{StatusCode: testCase.statusCode, Header: http.Header{}}
+		if testCase.location != "" {
+			res.Header.Set("Location", testCase.location)
+		}
+		if region := redirectRegion(res, testCase.errResp); region != testCase.region {
+			t.Errorf("Test %d: expected region %q, got %q", i+1, testCase.region, region)
+		}
+	}
+}
+
+// Tests that requests redirected to the region of the bucket are
+// retried in this region.
+func TestRedirectBucketRegion(t *testing.T) {
+	regions := map[string]string{
+		"moved-bucket":      "eu-west-1",
+		"redirected-bucket": "eu-central-1",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bucket := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")[0]
+		if _, ok := r.URL.Query()["location"]; ok {
+			// Outdated location of the bucket.
+			fmt.Fprint(w, "<LocationConstraint>us-west-2</LocationConstraint>")
+			return
+		}
+		if strings.Contains(r.Header.Get("Authorization"), "/"+regions[bucket]+"/s3/") {
+			w.Header().Set("ETag", `"etag"`)
+			w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+			return
+		}
+		switch bucket {
+		case "moved-bucket":
+			w.Header().Set("x-amz-bucket-region", regions[bucket])
+			w.WriteHeader(http.StatusMovedPermanently)
+		default:
+			w.Header().Set("Location", "https://"+bucket+".s3."+regions[bucket]+".amazonaws.com/object")
+			w.WriteHeader(http.StatusTemporaryRedirect)
+		}
+	}))
+	defer server.Close()
+
+	c, err := New(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for bucket, region := range regions {
+		if _, err = c.StatObject(bucket, "object", StatObjectOptions{}); err != nil {
+			t.Fatalf("%s: %v", bucket, err)
+		}
+		if location, _ := c.bucketLocCache.Get(bucket); location != region {
+			t.Errorf("%s: expected cached location %q, got %q", bucket, region, location)
+		}
+	}
+}
+
+// Tests that object names with special characters reach the server
+// unchanged and are encoded in copy source headers.
+func TestObjectNameEncoding(t *testing.T) {
+	objectNames := []string{
+		"space in name",
+		"plus+sign",
+		"hash#and?question",
+		"question?mark=[x]",
+		"semi;colon=equals&amp",
+		"本語/unicode",
+		"consecutive//slashes/",
+		"/leading-slash",
+		"percent%20literal",
+	}
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.RawQuery != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+	}))
+	defer server.Close()
+
+	for _, signerType := range []credentials.SignatureType{credentials.SignatureV4, credentials.SignatureV2} {
+		c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.overrideSignerType = signerType
+		for _, objectName := range objectNames {
+			paths = nil
+			if _, err = c.StatObject("bucket", objectName, StatObjectOptions{}); err != nil {
+				t.Fatalf("%s: %q: %v", signerType, objectName, err)
+			}
+			if expected := []string{"/bucket/" + objectName}; !reflect.DeepEqual(paths, expected) {
+				t.Errorf("%s: expected paths %q, got %q", signerType, expected, paths)
+			}
+		}
+	}
+
+	for _, objectName := range objectNames {
+		src := NewSourceInfo("bucket", objectName, nil)
+		copySource, err := url.PathUnescape(src.Headers.Get("x-amz-copy-source"))
+		if err != nil || copySource != "bucket/"+objectName {
+			t.Errorf("%q: unexpected copy source %q, %v", objectName, src.Headers.Get("x-amz-copy-source"), err)
+		}
+	}
+}
+
+// Tests selection of the bucket name validation rules.
+func TestBucketNameValidation(t *testing.T) {
+	testCases := []struct {
+		endpoint   string
+		validation BucketNameValidationType
+		bucketName string
+		newBucket  bool
+		valid      bool
+	}{
+		{"localhost:9000", BucketNameAuto, "My_Bucket", false, true},
+		{"localhost:9000", BucketNameAuto, "My_Bucket", true, false},
+		{"localhost:9000", BucketNameAuto, "my-bucket", true, true},
+		{"s3.amazonaws.com", BucketNameAuto, "My_Bucket", false, false},
+		{"storage.googleapis.com", BucketNameAuto, "My_Bucket", false, false},
+		{"localhost:9000", BucketNameStrict, "My_Bucket", false, false},
+		{"s3.amazonaws.com", BucketNameRelaxed, "My_Bucket", false, true},
+		{"localhost:9000", BucketNameRelaxed, "My_Bucket", true, true},
+		{"localhost:9000", BucketNameRelaxed, "a..b", false, false},
+	}
+	for i, testCase := range testCases {
+		c, err := NewWithOptions(testCase.endpoint, &Options{BucketNameValidation: testCase.validation})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = c.validateBucketName(testCase.bucketName, testCase.newBucket); (err == nil) != testCase.valid {
+			t.Errorf("Test %d: expected valid %v, got %v", i+1, testCase.valid, err)
+		}
+	}
+
+	// Invalid names fail before any request is made.
+	c, err := NewWithOptions("localhost:1", &Options{BucketNameValidation: BucketNameStrict})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.StatObject("My_Bucket", "object", StatObjectOptions{}); ToErrorResponse(err).Code != "InvalidBucketName" {
+		t.Errorf("expected InvalidBucketName, got %v", err)
+	}
+	c.SetBucketNameValidation(BucketNameRelaxed)
+	if err = c.validateBucketName("My_Bucket", true); err != nil {
+		t.Errorf("expected relaxed validation, got %v", err)
+	}
+}
+
+// countingReader - counts the bytes read from a reader.
+type countingReader struct {
+	*bytes.Reader
+	n int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.n += n
+	return n, err
+}
+
+// Tests that large uploads rejected by the server are not sent.
+func TestExpectContinue(t *testing.T) {
+	expects := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expects <- r.Header.Get("Expect")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, "<Error><Code>AccessDenied</Code><Message>Access Denied.</Message></Error>")
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	upload := func(size int) (*countingReader, string) {
+		body := &countingReader{Reader: bytes.NewReader(make([]byte, size))}
+		res, err := c.executeMethod(context.Background(), "PUT", requestMetadata{
+			bucketName:       "bucket",
+			objectName:       "object",
+			contentBody:      body,
+			contentLength:    int64(size),
+			contentSHA256Hex: unsignedPayload,
+			unsignedPayload:  true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		closeResponse(res)
+		if res.StatusCode != http.StatusForbidden {
+			t.Fatalf("expected status %d, got %d", http.StatusForbidden, res.StatusCode)
+		}
+		return body, <-expects
+	}
+
+	if body, expect := upload(expectContinueMinSize); expect != "100-continue" || body.n != 0 {
+		t.Errorf("expected body not to be sent, got expect %q and %d bytes read", expect, body.n)
+	}
+	if _, expect := upload(expectContinueMinSize - 1); expect != "" {
+		t.Errorf("expected no expectation for small bodies, got %q", expect)
+	}
+	c.SetExpectContinueTimeout(0)
+	if _, expect := upload(expectContinueMinSize); expect != "" {
+		t.Errorf("expected no expectation when disabled, got %q", expect)
+	}
+	c.SetExpectContinueTimeout(5 * time.Second)
+	if _, expect := upload(expectContinueMinSize); expect != "100-continue" {
+		t.Errorf("expected expectation when enabled, got %q", expect)
+	}
+
+	c, err = NewWithOptions(strings.TrimPrefix(server.URL, "http://"), &Options{
+		Creds:                 credentials.NewStaticV4("access", "secret", ""),
+		Region:                "us-east-1",
+		ExpectContinueTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr := c.httpClient.Transport.(*http.Transport); tr.ExpectContinueTimeout != 5*time.Second {
+		t.Errorf("expected transport timeout to be set, got %v", tr.ExpectContinueTimeout)
+	}
+	c, err = NewWithOptions(strings.TrimPrefix(server.URL, "http://"), &Options{
+		Creds:                 credentials.NewStaticV4("access", "secret", ""),
+		Region:                "us-east-1",
+		ExpectContinueTimeout: -1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, expect := upload(expectContinueMinSize); expect != "" {
+		t.Errorf("expected no expectation when disabled by the options, got %q", expect)
+	}
+}
+
+// Tests that buckets of other partitions than the endpoint are rejected.
+func TestCrossPartitionBucket(t *testing.T) {
+	testCases := []struct {
+		endpoint string
+		location string
+		host     string
+	}{
+		{"s3.amazonaws.com", "eu-west-1", "s3.dualstack.eu-west-1.amazonaws.com"},
+		{"s3.amazonaws.com", "cn-north-1", ""},
+		{"s3.amazonaws.com", "us-gov-west-1", ""},
+		{"s3.cn-north-1.amazonaws.com.cn", "cn-northwest-1", "s3.cn-northwest-1.amazonaws.com.cn"},
+		{"s3.cn-north-1.amazonaws.com.cn", "us-east-1", ""},
+		{"s3-us-gov-west-1.amazonaws.com", "us-gov-east-1", "s3.dualstack.us-gov-east-1.amazonaws.com"},
+		{"localhost:9000", "cn-north-1", "localhost:9000"},
+	}
+	for i, testCase := range testCases {
+		c, err := New(testCase.endpoint, "access", "secret", true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, err := c.makeTargetURL("bucket", "object", testCase.location, false, nil)
+		if testCase.host == "" {
+			if code := ToErrorResponse(err).Code; code != "InvalidRegion" {
+				t.Errorf("Test %d: expected InvalidRegion, got %v", i+1, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if u.Host != testCase.host {
+			t.Errorf("Test %d: expected host %q, got %q", i+1, testCase.host, u.Host)
+		}
+	}
+}
+
+// Tests that requests to an endpoint behind a reverse proxy carry its
+// base path and port.
+func TestEndpointBasePath(t *testing.T) {
+	var host string
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256") {
+			t.Errorf("expected a signed request, got %q", r.Header.Get("Authorization"))
+		}
+		if r.Host != host {
+			t.Errorf("expected host with port %q, got %q", host, r.Host)
+		}
+		paths = append(paths, r.URL.Path)
+		if _, ok := r.URL.Query()["location"]; ok {
+			fmt.Fprint(w, `<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">us-east-1</LocationConstraint>`)
+			return
+		}
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Length", "0")
+	}))
+	defer server.Close()
+	host = strings.TrimPrefix(server.URL, "http://")
+
+	c, err := New(server.URL+"/s3/minio/", "access", "secret", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.EndpointURL().String() != server.URL+"/s3/minio" {
+		t.Errorf("expected endpoint %q, got %q", server.URL+"/s3/minio", c.EndpointURL())
+	}
+	if _, err = c.StatObject("bucket", "dir/object", StatObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"/s3/minio/bucket/", "/s3/minio/bucket/dir/object"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected paths %v, got %v", expected, paths)
+	}
+}
+
+// Tests that an explicit http scheme of the endpoint takes precedence
+// over secure for the transport and the signing of the payload.
+func TestEndpointSchemeOverridesSecure(t *testing.T) {
+	var contentSHA256 string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentSHA256 = r.Header.Get("X-Amz-Content-Sha256")
+		w.Header().Set("ETag", `"etag"`)
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(server.URL, "access", "secret", true, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.secure {
+		t.Error("expected an insecure client for an http endpoint")
+	}
+	if transport, ok := c.httpClient.Transport.(*http.Transport); !ok || transport.TLSClientConfig != nil {
+		t.Error("expected a transport without TLS configuration")
+	}
+	if _, err = c.PutObject("bucket", "object", strings.NewReader("data"), 4, PutObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if contentSHA256 != "STREAMING-AWS4-HMAC-SHA256-PAYLOAD" {
+		t.Errorf("expected a streaming signature over http, got %q", contentSHA256)
+	}
+}
+
+// Tests that IPv6 literal endpoints are bracketed in the request URL and
+// the signed Host header.
+func TestIPv6Endpoint(t *testing.T) {
+	l, err := net.Listen("tcp", "[::1]:0")
+	if err != nil {
+		t.Skip("IPv6 loopback is not available:", err)
+	}
+	var host, path string
+	server := &httptest.Server{
+		Listener: l,
+		Config: &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			host, path = r.Host, r.URL.Path
+			if !strings.Contains(r.Header.Get("Authorization"), "SignedHeaders=host;") {
+				t.Errorf("expected the host header to be signed, got %q", r.Header.Get("Authorization"))
+			}
+		})},
+	}
+	server.Start()
+	defer server.Close()
+
+	for _, lookup := range []BucketLookupType{BucketLookupAuto, BucketLookupDNS} {
+		c, err := NewWithOptions(l.Addr().String(), &Options{
+			Creds:        credentials.NewStaticV4("access", "secret", ""),
+			Region:       "us-east-1",
+			BucketLookup: lookup,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = c.BucketExists("bucket"); err != nil {
+			t.Fatal(err)
+		}
+		if host != l.Addr().String() || path != "/bucket/" {
+			t.Errorf("lookup %d: expected path style request to %s, got %s%s", lookup, l.Addr(), host, path)
+		}
+	}
+}
+
+// Tests the signature hook receives the signature of the requests.
+func TestSignatureHook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var signatures []s3signer.SignatureV4
+	c.SetSignatureHook(func(req *http.Request, signature s3signer.SignatureV4) {
+		if !strings.HasSuffix(req.Header.Get("Authorization"), "Signature="+signature.Signature) {
+			t.Errorf("signature %s does not match %s", signature.Signature, req.Header.Get("Authorization"))
+		}
+		signatures = append(signatures, signature)
+	})
+	if _, err = c.BucketExists("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if len(signatures) != 1 || !strings.HasPrefix(signatures[0].CanonicalRequest, "HEAD\n/bucket/\n") {
+		t.Fatalf("unexpected signatures %v", signatures)
+	}
+
+	c.SetSignatureHook(nil)
+	if _, err = c.BucketExists("bucket"); err != nil {
+		t.Fatal(err)
+	}
+	if len(signatures) != 1 {
+		t.Fatalf("expected no signature once the hook is removed, got %d", len(signatures))
+	}
+}
+
+// Tests clones of a client have their own credentials and settings
+// and share the transport and location cache of the client.
+func TestClone(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		requests = append(requests, auth[strings.Index(auth, "Credential=")+len("Credential="):strings.Index(auth, "/")]+" "+r.Header.Get("User-Agent"))
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	clone, err := c.Clone(CloneOptions{Creds: credentials.NewStaticV4("tenant", "secret", "")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clone.SetAppInfo("tenant-app", "1.0")
+
+	for _, client := range []*Client{c, clone} {
+		if _, err = client.StatObject("bucket", "object", StatObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(requests) != 2 || !strings.HasPrefix(requests[0], "access ") || strings.Contains(requests[0], "tenant-app") ||
+		!strings.HasPrefix(requests[1], "tenant ") || !strings.HasSuffix(requests[1], "tenant-app/1.0") {
+		t.Errorf("unexpected requests %q", requests)
+	}
+	if clone.httpClient == c.httpClient || clone.httpClient.Transport != c.httpClient.Transport {
+		t.Errorf("expected a new HTTP client sharing the transport")
+	}
+	if clone.endpointURL == c.endpointURL || clone.bucketLocCache != c.bucketLocCache {
+		t.Errorf("expected a copy of the endpoint and a shared location cache")
+	}
+	if clone.s3ExpressSessions == c.s3ExpressSessions {
+		t.Errorf("expected new S3 Express sessions for other credentials")
+	}
+
+	if _, err = c.Clone(CloneOptions{Region: "EU West"}); ToErrorResponse(err).Code != "InvalidArgument" {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+	clone, err = c.Clone(CloneOptions{Region: "eu-west-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clone.region != "eu-west-1" || c.region != "us-east-1" {
+		t.Errorf("expected the region of the clone only to change, got %q and %q", clone.region, c.region)
+	}
+}
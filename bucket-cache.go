@@ -18,20 +18,25 @@
 package minio
 
 import (
-	"net"
+	"io"
 	"net/http"
 	"net/url"
 	"path"
 	"sync"
+	"sync/atomic"
 
 	"github.com/minio/minio-go/v6/pkg/credentials"
 	"github.com/minio/minio-go/v6/pkg/s3signer"
-	"github.com/minio/minio-go/v6/pkg/s3utils"
 )
 
 // bucketLocationCache - Provides simple mechanism to hold bucket
 // locations in memory.
 type bucketLocationCache struct {
+	// hits and misses count the lookups of bucket locations, first
+	// in the struct to be 64-bit aligned for atomic operations.
+	hits   uint64
+	misses uint64
+
 	// mutex is used for handling the concurrent
 	// read/write requests for cache.
 	sync.RWMutex
@@ -70,31 +75,154 @@ func (r *bucketLocationCache) Delete(bucketName string) {
 	delete(r.items, bucketName)
 }
 
+// Clear - Deletes all bucket names from cache.
+func (r *bucketLocationCache) Clear() {
+	r.Lock()
+	defer r.Unlock()
+	r.items = make(map[string]string)
+}
+
+// Snapshot - Returns a copy of all cached locations.
+func (r *bucketLocationCache) Snapshot() map[string]string {
+	r.RLock()
+	defer r.RUnlock()
+	items := make(map[string]string, len(r.items))
+	for bucketName, location := range r.items {
+		items[bucketName] = location
+	}
+	return items
+}
+
+// Len - Returns the number of cached locations.
+func (r *bucketLocationCache) Len() int {
+	r.RLock()
+	defer r.RUnlock()
+	return len(r.items)
+}
+
+// ClearBucketLocation - removes the location of a bucket from the
+// location cache, it is looked up again by the next request to the
+// bucket, e.g. after the bucket was recreated in another region.
+func (c Client) ClearBucketLocation(bucketName string) {
+	c.bucketLocCache.Delete(bucketName)
+}
+
+// ClearAllBucketLocations - removes the locations of all buckets
+// from the location cache.
+func (c Client) ClearAllBucketLocations() {
+	c.bucketLocCache.Clear()
+}
+
+// BucketLocationCacheStats - counters of the bucket location cache
+// of a client, shared by all its copies.
+type BucketLocationCacheStats struct {
+	// Lookups of bucket locations answered by the cache.
+	Hits uint64
+	// Lookups of bucket locations sent to the server.
+	Misses uint64
+	// Number of cached bucket locations.
+	Size int
+}
+
+// BucketLocationCacheStats - returns the counters of the bucket
+// location cache. Clients with a region never look up locations.
+func (c Client) BucketLocationCacheStats() BucketLocationCacheStats {
+	return BucketLocationCacheStats{
+		Hits:   atomic.LoadUint64(&c.bucketLocCache.hits),
+		Misses: atomic.LoadUint64(&c.bucketLocCache.misses),
+		Size:   c.bucketLocCache.Len(),
+	}
+}
+
+// BucketLocationCacheSnapshot - returns a copy of the bucket location
+// cache, the location of every cached bucket by bucket name.
+func (c Client) BucketLocationCacheSnapshot() map[string]string {
+	return c.bucketLocCache.Snapshot()
+}
+
+// GetBucketLocationOptions represents options specified by user for
+// GetBucketLocationWithOptions call.
+type GetBucketLocationOptions struct {
+	// BypassCache fetches the location from the server even if it is
+	// cached or the client has a region, the cache is then updated.
+	BypassCache bool
+}
+
 // GetBucketLocation - get location for the bucket name from location cache, if not
 // fetch freshly by making a new request.
 func (c Client) GetBucketLocation(bucketName string) (string, error) {
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	return c.GetBucketLocationWithOptions(bucketName, GetBucketLocationOptions{})
+}
+
+// GetBucketLocationWithOptions - get location for the bucket name
+// with the options specified, e.g. to refresh a cached location or to
+// pre-warm the location cache.
+func (c Client) GetBucketLocationWithOptions(bucketName string, opts GetBucketLocationOptions) (string, error) {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return "", err
 	}
+	if opts.BypassCache {
+		return c.fetchBucketLocation(bucketName)
+	}
 	return c.getBucketLocation(bucketName)
 }
 
+// SetBucketLocation - saves the known location of a bucket in the
+// location cache, requests to the bucket are then signed for this
+// location without looking it up. Together with a region set on the
+// client this allows to presign URLs without any network request.
+func (c Client) SetBucketLocation(bucketName, location string) error {
+	if err := c.validateBucketName(bucketName, false); err != nil {
+		return err
+	}
+	if location == "" {
+		return ErrInvalidArgument("Bucket location cannot be empty.")
+	}
+	if !Region(location).IsValid() {
+		return ErrInvalidArgument("Invalid bucket location `" + location + "`.")
+	}
+	c.bucketLocCache.Set(bucketName, location)
+	return nil
+}
+
 // getBucketLocation - Get location for the bucketName from location map cache, if not
 // fetch freshly by making a new request.
 func (c Client) getBucketLocation(bucketName string) (string, error) {
-	if err := s3utils.CheckValidBucketName(bucketName); err != nil {
+	if err := c.validateBucketName(bucketName, false); err != nil {
 		return "", err
 	}
 
+	// Access points are addressed in the region of their ARN.
+	if isAccessPointARN(bucketName) {
+		ap, err := parseAccessPointARN(bucketName)
+		if err != nil {
+			return "", ErrInvalidBucketName(err.Error())
+		}
+		return ap.region, nil
+	}
+
 	// Region set then no need to fetch bucket location.
 	if c.region != "" {
 		return c.region, nil
 	}
 
 	if location, ok := c.bucketLocCache.Get(bucketName); ok {
+		atomic.AddUint64(&c.bucketLocCache.hits, 1)
 		return location, nil
 	}
 
+	// Directory buckets have no location to look up.
+	if c.isS3ExpressBucket(bucketName) {
+		return "", ErrInvalidArgument("The region of the client, or the location of bucket " + bucketName + " set with SetBucketLocation, is required to access S3 Express directory buckets.")
+	}
+
+	atomic.AddUint64(&c.bucketLocCache.misses, 1)
+	return c.fetchBucketLocation(bucketName)
+}
+
+// fetchBucketLocation - fetches the location of bucketName from the
+// server and saves it in the location cache.
+func (c Client) fetchBucketLocation(bucketName string) (string, error) {
 	// Initialize a new request.
 	req, err := c.getBucketLocationRequest(bucketName)
 	if err != nil {
@@ -108,6 +236,10 @@ func (c Client) getBucketLocation(bucketName string) (string, error) {
 		return "", err
 	}
 	location, err := processBucketLocationResponse(resp, bucketName)
+	if err == io.EOF && c.isLegacyGateway(resp.Header) {
+		// Older gateways send no location constraint.
+		location, err = "us-east-1", nil
+	}
 	if err != nil {
 		return "", err
 	}
@@ -173,13 +305,9 @@ func (c Client) getBucketLocationRequest(bucketName string) (*http.Request, erro
 	targetURL := *c.endpointURL
 
 	// as it works in makeTargetURL method from api.go file
-	if h, p, err := net.SplitHostPort(targetURL.Host); err == nil {
-		if targetURL.Scheme == "http" && p == "80" || targetURL.Scheme == "https" && p == "443" {
-			targetURL.Host = h
-		}
-	}
+	targetURL.Host = stripDefaultPort(targetURL.Scheme, targetURL.Host)
 
-	targetURL.Path = path.Join(bucketName, "") + "/"
+	targetURL.Path = path.Join(targetURL.Path, bucketName) + "/"
 	targetURL.RawQuery = urlValues.Encode()
 
 	// Get a new HTTP request for the method.
@@ -233,6 +361,12 @@ func (c Client) getBucketLocationRequest(bucketName string) (*http.Request, erro
 	}
 
 	req.Header.Set("X-Amz-Content-Sha256", contentSha256)
-	req = s3signer.SignV4(*req, accessKeyID, secretAccessKey, sessionToken, "us-east-1")
+
+	// Regional endpoints are signed in their region and the global
+	// endpoint in us-east-1, the region of the client may differ from
+	// the location of the bucket when the cache is bypassed.
+	location := getDefaultLocation(*c.endpointURL, "")
+	req = s3signer.SignV4(*req, accessKeyID, secretAccessKey, sessionToken, location)
+	c.traceSignature(req, secretAccessKey, location)
 	return req, nil
 }
@@ -20,11 +20,14 @@ package minio
 import (
 	"bytes"
 	"encoding/xml"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"path"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/minio/minio-go/v6/pkg/credentials"
@@ -347,3 +350,95 @@ func TestProcessBucketLocationResponse(t *testing.T) {
 		}
 	}
 }
+
+// Tests the counters and snapshot of the bucket location cache.
+func TestBucketLocationCacheStats(t *testing.T) {
+	var lookups int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups++
+		fmt.Fprint(w, `<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">eu-west-1</LocationConstraint>`)
+	}))
+	defer server.Close()
+
+	c, err := New(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, bucketName := range []string{"bucket1", "bucket2", "bucket1", "bucket1"} {
+		if _, err = c.GetBucketLocation(bucketName); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if lookups != 2 {
+		t.Errorf("expected 2 lookups, got %d", lookups)
+	}
+	expected := BucketLocationCacheStats{Hits: 2, Misses: 2, Size: 2}
+	if stats := c.BucketLocationCacheStats(); stats != expected {
+		t.Errorf("expected %+v, got %+v", expected, stats)
+	}
+	snapshot := c.BucketLocationCacheSnapshot()
+	if !reflect.DeepEqual(snapshot, map[string]string{"bucket1": "eu-west-1", "bucket2": "eu-west-1"}) {
+		t.Errorf("unexpected snapshot %v", snapshot)
+	}
+	snapshot["bucket3"] = "us-east-1"
+	if stats := c.BucketLocationCacheStats(); stats.Size != 2 {
+		t.Errorf("snapshot is not a copy of the cache")
+	}
+}
+
+// Tests cached bucket locations are cleared.
+func TestClearBucketLocation(t *testing.T) {
+	c, err := New("localhost:9000", "access", "secret", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, bucketName := range []string{"bucket1", "bucket2", "bucket3"} {
+		if err = c.SetBucketLocation(bucketName, "eu-west-1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.ClearBucketLocation("bucket2")
+	if !reflect.DeepEqual(c.BucketLocationCacheSnapshot(), map[string]string{"bucket1": "eu-west-1", "bucket3": "eu-west-1"}) {
+		t.Errorf("bucket2 not removed from the cache, got %v", c.BucketLocationCacheSnapshot())
+	}
+	c.ClearAllBucketLocations()
+	if size := c.BucketLocationCacheStats().Size; size != 0 {
+		t.Errorf("expected an empty cache, got %d locations", size)
+	}
+}
+
+// Tests bucket locations are fetched from the server when the cache is
+// bypassed, signed in the region of the endpoint rather than the region
+// of the client.
+func TestGetBucketLocationBypassCache(t *testing.T) {
+	var lookups int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lookups++
+		if !strings.Contains(r.Header.Get("Authorization"), "/us-east-1/s3/aws4_request") {
+			t.Errorf("expected a request signed in us-east-1, got %q", r.Header.Get("Authorization"))
+		}
+		fmt.Fprint(w, `<LocationConstraint xmlns="http://s3.amazonaws.com/doc/2006-03-01/">eu-west-1</LocationConstraint>`)
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "ap-south-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if location, err := c.GetBucketLocation("bucket"); err != nil || location != "ap-south-1" || lookups != 0 {
+		t.Errorf("expected the region of the client without lookup, got %q, %v", location, err)
+	}
+	if err = c.SetBucketLocation("bucket", "us-west-2"); err != nil {
+		t.Fatal(err)
+	}
+	location, err := c.GetBucketLocationWithOptions("bucket", GetBucketLocationOptions{BypassCache: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if location != "eu-west-1" || lookups != 1 {
+		t.Errorf("expected eu-west-1 from a lookup, got %q after %d lookups", location, lookups)
+	}
+	if cached := c.BucketLocationCacheSnapshot()["bucket"]; cached != "eu-west-1" {
+		t.Errorf("expected the cache to be updated, got %q", cached)
+	}
+}
@@ -0,0 +1,173 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/minio/minio-go/v6/pkg/encrypt"
+)
+
+// BucketDefaults - defaults applied to the requests of a bucket handle
+// which do not set them explicitly.
+type BucketDefaults struct {
+	// Encryption of uploaded objects. SSE-C keys are also used to
+	// read and stat objects.
+	ServerSideEncryption encrypt.ServerSide
+
+	// Storage class of uploaded objects.
+	StorageClass string
+}
+
+// BucketHandle - a client bound to a bucket, and optionally to a
+// prefix of the object names in the bucket. Object names passed to
+// the handle are relative to the prefix, object names returned by it
+// are full object names.
+type BucketHandle struct {
+	client   *Client
+	bucket   string
+	prefix   string
+	defaults BucketDefaults
+}
+
+// Bucket - returns a handle of the named bucket, with the settings of
+// the client.
+func (c *Client) Bucket(bucketName string) *BucketHandle {
+	return &BucketHandle{client: c, bucket: bucketName}
+}
+
+// WithPrefix - returns a copy of the handle bound to prefix, appended
+// to the prefix of the handle. Pass a prefix ending with '/' to bind
+// the handle to a "directory".
+func (b *BucketHandle) WithPrefix(prefix string) *BucketHandle {
+	handle := *b
+	handle.prefix += prefix
+	return &handle
+}
+
+// WithDefaults - returns a copy of the handle applying the given
+// defaults.
+func (b *BucketHandle) WithDefaults(defaults BucketDefaults) *BucketHandle {
+	handle := *b
+	handle.defaults = defaults
+	return &handle
+}
+
+// Name - returns the name of the bucket.
+func (b *BucketHandle) Name() string {
+	return b.bucket
+}
+
+// Prefix - returns the prefix of object names the handle is bound to.
+func (b *BucketHandle) Prefix() string {
+	return b.prefix
+}
+
+// Exists - checks if the bucket exists.
+func (b *BucketHandle) Exists() (bool, error) {
+	return b.client.BucketExists(b.bucket)
+}
+
+// ListObjects - lists the objects of the bucket under the prefix of
+// the handle, opts.Prefix is relative to it.
+func (b *BucketHandle) ListObjects(opts ListObjectsOptions, doneCh <-chan struct{}) <-chan ObjectInfo {
+	opts.Prefix = b.prefix + opts.Prefix
+	return b.client.ListObjectsV2WithOptions(b.bucket, opts, doneCh)
+}
+
+// Object - returns a handle of the named object, relative to the
+// prefix of the bucket handle.
+func (b *BucketHandle) Object(objectName string) *ObjectHandle {
+	return &ObjectHandle{bucket: b, name: b.prefix + objectName}
+}
+
+// putOptions - applies the defaults of the handle to upload options.
+func (b *BucketHandle) putOptions(opts PutObjectOptions) PutObjectOptions {
+	if opts.ServerSideEncryption == nil {
+		opts.ServerSideEncryption = b.defaults.ServerSideEncryption
+	}
+	if opts.StorageClass == "" {
+		opts.StorageClass = b.defaults.StorageClass
+	}
+	return opts
+}
+
+// getOptions - applies the defaults of the handle to download options,
+// only SSE-C keys are needed to read objects.
+func (b *BucketHandle) getOptions(opts GetObjectOptions) GetObjectOptions {
+	if opts.ServerSideEncryption == nil && b.defaults.ServerSideEncryption != nil && b.defaults.ServerSideEncryption.Type() == encrypt.SSEC {
+		opts.ServerSideEncryption = b.defaults.ServerSideEncryption
+	}
+	return opts
+}
+
+// ObjectHandle - an object of a bucket handle.
+type ObjectHandle struct {
+	bucket *BucketHandle
+	name   string
+}
+
+// Bucket - returns the handle of the bucket of the object.
+func (o *ObjectHandle) Bucket() *BucketHandle {
+	return o.bucket
+}
+
+// Name - returns the full name of the object, including the prefix of
+// the bucket handle.
+func (o *ObjectHandle) Name() string {
+	return o.name
+}
+
+// Put - uploads the object, see PutObject.
+func (o *ObjectHandle) Put(reader io.Reader, objectSize int64, opts PutObjectOptions) (int64, error) {
+	return o.bucket.client.PutObject(o.bucket.bucket, o.name, reader, objectSize, o.bucket.putOptions(opts))
+}
+
+// FPut - uploads the object from a file, see FPutObject.
+func (o *ObjectHandle) FPut(filePath string, opts PutObjectOptions) (int64, error) {
+	return o.bucket.client.FPutObject(o.bucket.bucket, o.name, filePath, o.bucket.putOptions(opts))
+}
+
+// Get - returns a reader of the object, see GetObject.
+func (o *ObjectHandle) Get(opts GetObjectOptions) (*Object, error) {
+	return o.bucket.client.GetObject(o.bucket.bucket, o.name, o.bucket.getOptions(opts))
+}
+
+// FGet - downloads the object to a file, see FGetObject.
+func (o *ObjectHandle) FGet(filePath string, opts GetObjectOptions) error {
+	return o.bucket.client.FGetObject(o.bucket.bucket, o.name, filePath, o.bucket.getOptions(opts))
+}
+
+// Stat - returns the metadata of the object, see StatObject.
+func (o *ObjectHandle) Stat(opts StatObjectOptions) (ObjectInfo, error) {
+	opts.GetObjectOptions = o.bucket.getOptions(opts.GetObjectOptions)
+	return o.bucket.client.StatObject(o.bucket.bucket, o.name, opts)
+}
+
+// Remove - removes the object.
+func (o *ObjectHandle) Remove() error {
+	return o.bucket.client.RemoveObject(o.bucket.bucket, o.name)
+}
+
+// PresignedGet - returns a presigned URL to download the object, see
+// PresignedGetObject.
+func (o *ObjectHandle) PresignedGet(expires time.Duration, reqParams url.Values) (*url.URL, error) {
+	return o.bucket.client.PresignedGetObject(o.bucket.bucket, o.name, expires, reqParams)
+}
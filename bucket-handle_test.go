@@ -0,0 +1,139 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/minio/minio-go/v6/pkg/encrypt"
+)
+
+// Tests that bucket handles apply their prefix and defaults.
+func TestBucketHandle(t *testing.T) {
+	var mutex sync.Mutex
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		mutex.Lock()
+		requests = append(requests, fmt.Sprintf("%s %s sse=%s ssec=%t class=%s", r.Method, r.URL.Path,
+			r.Header.Get("X-Amz-Server-Side-Encryption"), r.Header.Get("X-Amz-Server-Side-Encryption-Customer-Key") != "",
+			r.Header.Get("X-Amz-Storage-Class")))
+		mutex.Unlock()
+		switch r.Method {
+		case http.MethodGet:
+			if prefix := r.URL.Query().Get("prefix"); r.URL.Query().Get("list-type") == "2" {
+				fmt.Fprintf(w, "<ListBucketResult><Name>bucket</Name><Contents><Key>%sfile</Key></Contents></ListBucketResult>", prefix)
+				return
+			}
+			fallthrough
+		case http.MethodHead:
+			w.Header().Set("ETag", `"etag"`)
+			w.Header().Set("Content-Length", "4")
+			w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+			if r.Method == http.MethodGet {
+				w.Write([]byte("data"))
+			}
+		case http.MethodPut:
+			w.Header().Set("ETag", `"etag"`)
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+	takeRequests := func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		r := requests
+		requests = nil
+		return r
+	}
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetExpectContinueTimeout(0)
+
+	photos := c.Bucket("bucket").WithPrefix("photos/").WithDefaults(BucketDefaults{
+		ServerSideEncryption: encrypt.NewSSE(),
+		StorageClass:         "REDUCED_REDUNDANCY",
+	})
+	if photos.Name() != "bucket" || photos.Prefix() != "photos/" {
+		t.Fatalf("unexpected handle %s %s", photos.Name(), photos.Prefix())
+	}
+	object := photos.Object("a.jpg")
+	if object.Name() != "photos/a.jpg" || object.Bucket() != photos {
+		t.Fatalf("unexpected object name %s", object.Name())
+	}
+	if _, err = object.Put(strings.NewReader("data"), 4, PutObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = object.Put(strings.NewReader("data"), 4, PutObjectOptions{StorageClass: "STANDARD"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = object.Stat(StatObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err = object.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for info := range photos.WithPrefix("2019/").ListObjects(ListObjectsOptions{Recursive: true}, nil) {
+		if info.Err != nil {
+			t.Fatal(info.Err)
+		}
+		keys = append(keys, info.Key)
+	}
+	if expected := []string{"photos/2019/file"}; !reflect.DeepEqual(keys, expected) {
+		t.Errorf("expected %v, got %v", expected, keys)
+	}
+	expected := []string{
+		"PUT /bucket/photos/a.jpg sse=AES256 ssec=false class=REDUCED_REDUNDANCY",
+		"PUT /bucket/photos/a.jpg sse=AES256 ssec=false class=STANDARD",
+		"HEAD /bucket/photos/a.jpg sse= ssec=false class=",
+		"DELETE /bucket/photos/a.jpg sse= ssec=false class=",
+		"GET /bucket/ sse= ssec=false class=",
+	}
+	if requests := takeRequests(); !reflect.DeepEqual(requests, expected) {
+		t.Errorf("expected requests %v, got %v", expected, requests)
+	}
+
+	// SSE-C keys are needed to read the objects too.
+	key, _ := encrypt.NewSSEC([]byte(strings.Repeat("k", 32)))
+	object = c.Bucket("bucket").WithDefaults(BucketDefaults{ServerSideEncryption: key}).Object("b")
+	reader, err := object.Get(GetObjectOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := ioutil.ReadAll(reader); err != nil || string(data) != "data" {
+		t.Fatalf("unexpected data %q, %v", data, err)
+	}
+	reader.Close()
+	for _, request := range takeRequests() {
+		if !strings.Contains(request, "ssec=true") {
+			t.Errorf("expected the SSE-C key to be sent, got %s", request)
+		}
+	}
+}
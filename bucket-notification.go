@@ -19,6 +19,7 @@ package minio
 
 import (
 	"encoding/xml"
+	"unicode/utf8"
 
 	"github.com/minio/minio-go/v6/pkg/set"
 )
@@ -43,6 +44,28 @@ const (
 	ObjectReducedRedundancyLostObject                          = "s3:ReducedRedundancyLostObject"
 )
 
+// supportedNotificationEvents - all event types accepted by
+// NotificationEventType.IsValid.
+var supportedNotificationEvents = set.CreateStringSet(
+	string(ObjectCreatedAll),
+	ObjectCreatedPut,
+	ObjectCreatedPost,
+	ObjectCreatedCopy,
+	ObjectCreatedCompleteMultipartUpload,
+	ObjectAccessedGet,
+	ObjectAccessedHead,
+	ObjectAccessedAll,
+	ObjectRemovedAll,
+	ObjectRemovedDelete,
+	ObjectRemovedDeleteMarkerCreated,
+	ObjectReducedRedundancyLostObject,
+)
+
+// IsValid - check whether the event type is a known notification event.
+func (e NotificationEventType) IsValid() bool {
+	return supportedNotificationEvents.Contains(string(e))
+}
+
 // FilterRule - child of S3Key, a tag in the notification xml which
 // carries suffix/prefix filters
 type FilterRule struct {
@@ -137,6 +160,104 @@ func (t *NotificationConfig) AddFilterPrefix(prefix string) {
 	t.Filter.S3Key.FilterRules = append(t.Filter.S3Key.FilterRules, newFilterRule)
 }
 
+// NotificationRules - builds the events and the prefix/suffix filter
+// rules of a notification. The same rules can be applied to a bucket
+// notification configuration or used to listen for notifications.
+type NotificationRules struct {
+	events []NotificationEventType
+	prefix string
+	suffix string
+}
+
+// NewNotificationRules - creates notification rules matching the given events.
+func NewNotificationRules(events ...NotificationEventType) *NotificationRules {
+	return &NotificationRules{events: events}
+}
+
+// WithPrefix - only match objects whose name starts with prefix.
+func (r *NotificationRules) WithPrefix(prefix string) *NotificationRules {
+	r.prefix = prefix
+	return r
+}
+
+// WithSuffix - only match objects whose name ends with suffix.
+func (r *NotificationRules) WithSuffix(suffix string) *NotificationRules {
+	r.suffix = suffix
+	return r
+}
+
+// Events - returns the events matched by the rules.
+func (r *NotificationRules) Events() []NotificationEventType {
+	return r.events
+}
+
+// Prefix - returns the object name prefix filter.
+func (r *NotificationRules) Prefix() string {
+	return r.prefix
+}
+
+// Suffix - returns the object name suffix filter.
+func (r *NotificationRules) Suffix() string {
+	return r.suffix
+}
+
+// Validate - verifies that at least one event is set, that all events
+// are known and that the filters are valid object name fragments.
+func (r *NotificationRules) Validate() error {
+	if len(r.events) == 0 {
+		return ErrInvalidArgument("At least one notification event is required.")
+	}
+	seen := set.NewStringSet()
+	for _, event := range r.events {
+		if !event.IsValid() {
+			return ErrInvalidArgument("Unsupported notification event ‘" + string(event) + "’.")
+		}
+		if seen.Contains(string(event)) {
+			return ErrInvalidArgument("Duplicate notification event ‘" + string(event) + "’.")
+		}
+		seen.Add(string(event))
+	}
+	for _, rule := range []FilterRule{{Name: "prefix", Value: r.prefix}, {Name: "suffix", Value: r.suffix}} {
+		if len(rule.Value) > 1024 {
+			return ErrInvalidArgument("Notification " + rule.Name + " filter cannot be longer than 1024 bytes.")
+		}
+		if !utf8.ValidString(rule.Value) {
+			return ErrInvalidArgument("Notification " + rule.Name + " filter should be a valid UTF-8 string.")
+		}
+	}
+	return nil
+}
+
+// Filter - returns the filter rules in their XML representation, nil
+// when neither a prefix nor a suffix is set.
+func (r *NotificationRules) Filter() *Filter {
+	if r.prefix == "" && r.suffix == "" {
+		return nil
+	}
+	filter := &Filter{}
+	if r.prefix != "" {
+		filter.S3Key.FilterRules = append(filter.S3Key.FilterRules, FilterRule{Name: "prefix", Value: r.prefix})
+	}
+	if r.suffix != "" {
+		filter.S3Key.FilterRules = append(filter.S3Key.FilterRules, FilterRule{Name: "suffix", Value: r.suffix})
+	}
+	return filter
+}
+
+// ApplyTo - validates the rules and sets them on the given notification
+// config, replacing any previously configured events and filters.
+func (r *NotificationRules) ApplyTo(config *NotificationConfig) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	config.Events = append([]NotificationEventType(nil), r.events...)
+	config.Filter = &Filter{}
+	if filter := r.Filter(); filter != nil {
+		config.Filter = filter
+	}
+	return nil
+}
+
 // TopicConfig carries one single topic notification configuration
 type TopicConfig struct {
 	NotificationConfig
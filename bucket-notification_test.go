@@ -0,0 +1,145 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNotificationRulesValidate(t *testing.T) {
+	testCases := []struct {
+		rules   *NotificationRules
+		success bool
+	}{
+		{NewNotificationRules(ObjectCreatedAll), true},
+		{NewNotificationRules(ObjectCreatedPut, ObjectRemovedDelete).WithPrefix("photos/").WithSuffix(".jpg"), true},
+		{NewNotificationRules(), false},
+		{NewNotificationRules("s3:ObjectCreated:Unknown"), false},
+		{NewNotificationRules(ObjectCreatedPut, ObjectCreatedPut), false},
+		{NewNotificationRules(ObjectCreatedPut).WithPrefix(strings.Repeat("a", 1025)), false},
+		{NewNotificationRules(ObjectCreatedPut).WithSuffix("\xff"), false},
+	}
+	for i, testCase := range testCases {
+		err := testCase.rules.Validate()
+		if testCase.success && err != nil {
+			t.Errorf("Test %d: unexpected error %v", i+1, err)
+		}
+		if !testCase.success && err == nil {
+			t.Errorf("Test %d: expected an error", i+1)
+		}
+	}
+}
+
+func TestNotificationRulesApplyTo(t *testing.T) {
+	config := NewNotificationConfig(NewArn("minio", "sqs", "us-east-1", "1", "webhook"))
+	config.AddEvents(ObjectAccessedGet)
+	config.AddFilterSuffix(".txt")
+
+	rules := NewNotificationRules(ObjectCreatedPut, ObjectRemovedAll).WithPrefix("photos/")
+	if err := rules.ApplyTo(&config); err != nil {
+		t.Fatal(err)
+	}
+	expectedEvents := []NotificationEventType{ObjectCreatedPut, ObjectRemovedAll}
+	if !reflect.DeepEqual(config.Events, expectedEvents) {
+		t.Errorf("expected events %v, got %v", expectedEvents, config.Events)
+	}
+	expectedRules := []FilterRule{{Name: "prefix", Value: "photos/"}}
+	if !reflect.DeepEqual(config.Filter.S3Key.FilterRules, expectedRules) {
+		t.Errorf("expected filter rules %v, got %v", expectedRules, config.Filter.S3Key.FilterRules)
+	}
+
+	if err := NewNotificationRules("s3:Unknown").ApplyTo(&config); err == nil {
+		t.Fatal("expected an error")
+	}
+	if !reflect.DeepEqual(config.Events, expectedEvents) {
+		t.Errorf("invalid rules should leave the config unchanged, got %v", config.Events)
+	}
+}
+
+func TestListenBucketNotificationWithRulesInvalid(t *testing.T) {
+	c, err := New("localhost:9000", "access", "secret", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var errs int
+	for info := range c.ListenBucketNotificationWithRules("mybucket", NewNotificationRules(), nil) {
+		if info.Err == nil {
+			t.Fatal("expected an error")
+		}
+		errs++
+	}
+	if errs != 1 {
+		t.Fatalf("expected a single error, got %d", errs)
+	}
+}
+
+func TestParseNotificationEvents(t *testing.T) {
+	body := `{"Records":[{"eventVersion":"2.0","eventSource":"minio:s3","awsRegion":"us-east-1",
+"eventTime":"2019-07-01T10:20:30.123Z","eventName":"s3:ObjectCreated:Put",
+"userIdentity":{"principalId":"minio"},
+"requestParameters":{"sourceIPAddress":"127.0.0.1"},
+"responseElements":{"x-amz-request-id":"15AB"},
+"s3":{"s3SchemaVersion":"1.0","configurationId":"Config",
+"bucket":{"name":"mybucket","ownerIdentity":{"principalId":"minio"},"arn":"arn:aws:s3:::mybucket"},
+"object":{"key":"photos%2Fmy+holiday%25.jpg","size":1024,"eTag":"abc","sequencer":"15AB"}}}]}`
+
+	events, err := ParseNotificationEvents(strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	event := events[0]
+	if event.EventType() != ObjectCreatedPut {
+		t.Errorf("unexpected event type %s", event.EventType())
+	}
+	key, err := event.ObjectKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "photos/my holiday%.jpg" {
+		t.Errorf("unexpected object key %q", key)
+	}
+	eventTime, err := event.Time()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eventTime.Year() != 2019 || eventTime.Nanosecond() != 123000000 {
+		t.Errorf("unexpected event time %v", eventTime)
+	}
+	if event.UserIdentity.PrincipalID != "minio" || event.RequestParameters["sourceIPAddress"] != "127.0.0.1" {
+		t.Errorf("unexpected identity or request parameters %+v", event)
+	}
+	if event.S3.Bucket.Name != "mybucket" || event.S3.Object.Size != 1024 {
+		t.Errorf("unexpected s3 metadata %+v", event.S3)
+	}
+
+	invalid := []string{
+		`{"Records":`,
+		`{"Records":[{"s3":{"object":{"key":"a"}}}]}`,
+		`{"Records":[{"eventName":"s3:ObjectCreated:Put","s3":{"object":{"key":"a%zz"}}}]}`,
+	}
+	for i, body := range invalid {
+		if _, err := ParseNotificationEvents(strings.NewReader(body)); err == nil {
+			t.Errorf("Test %d: expected an error", i+1)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import "sync"
+
+// BufferPool - allocator of the part sized buffers used by multipart
+// uploads of data which cannot be read at an offset, buffers are put
+// back into the pool once the upload is done. Implementations must be
+// safe for concurrent use.
+type BufferPool interface {
+	// Get returns a buffer of length size.
+	Get(size int) []byte
+
+	// Put releases a buffer returned by Get.
+	Put(buf []byte)
+}
+
+// sizedBufferPool - BufferPool keeping a sync.Pool of buffers for
+// each buffer size, such that the buffers of uploads with the same
+// part size are reused instead of being garbage collected.
+type sizedBufferPool struct {
+	pools sync.Map // map[int]*sync.Pool
+}
+
+// defaultBufferPool - buffer pool shared by all clients unless
+// changed with SetBufferPool.
+var defaultBufferPool BufferPool = &sizedBufferPool{}
+
+// Get - returns a buffer of length size.
+func (p *sizedBufferPool) Get(size int) []byte {
+	if pool, ok := p.pools.Load(size); ok {
+		if buf, ok := pool.(*sync.Pool).Get().(*[]byte); ok {
+			return *buf
+		}
+	}
+	return make([]byte, size)
+}
+
+// Put - keeps the buffer for a later Get of the same size.
+func (p *sizedBufferPool) Put(buf []byte) {
+	buf = buf[:cap(buf)]
+	pool, _ := p.pools.LoadOrStore(len(buf), &sync.Pool{})
+	pool.(*sync.Pool).Put(&buf)
+}
@@ -0,0 +1,86 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+)
+
+// Tests the sizes of the buffers returned by the default pool.
+func TestSizedBufferPool(t *testing.T) {
+	pool := &sizedBufferPool{}
+	for _, size := range []int{0, 1, 1024, absMinPartSize} {
+		buf := pool.Get(size)
+		if len(buf) != size {
+			t.Fatalf("expected a buffer of %d bytes, got %d", size, len(buf))
+		}
+		pool.Put(buf[:size/2])
+		if buf = pool.Get(size); len(buf) != size {
+			t.Fatalf("expected a reused buffer of %d bytes, got %d", size, len(buf))
+		}
+	}
+}
+
+// countingBufferPool - BufferPool counting the buffers in use.
+type countingBufferPool struct {
+	mutex sync.Mutex
+	gets  int
+	inUse int
+}
+
+func (p *countingBufferPool) Get(size int) []byte {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.gets++
+	p.inUse++
+	return make([]byte, size)
+}
+
+func (p *countingBufferPool) Put(buf []byte) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.inUse--
+}
+
+// Tests that uploads of unknown size use and release pooled buffers,
+// small uploads do not take parts from the pool.
+func TestSetBufferPool(t *testing.T) {
+	server := newUploadTestServer()
+	defer server.Close()
+	c := server.client(t)
+
+	pool := &countingBufferPool{}
+	c.SetBufferPool(pool)
+	for _, size := range []int{10, 2*absMinPartSize + 1} {
+		reader := struct{ io.Reader }{bytes.NewReader(make([]byte, size))}
+		if _, err := c.PutObject("bucket", "object", reader, -1, PutObjectOptions{PartSize: absMinPartSize}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if pool.gets != 1 || pool.inUse != 0 {
+		t.Errorf("expected 1 buffer used and released, got %d with %d in use", pool.gets, pool.inUse)
+	}
+
+	c.SetBufferPool(nil)
+	if c.getBufferPool() != defaultBufferPool {
+		t.Error("expected the default buffer pool")
+	}
+}
@@ -0,0 +1,160 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"hash/crc32"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// amzChecksumMode is the header used to request the additional
+// checksums of an object in GET and HEAD responses.
+const amzChecksumMode = "X-Amz-Checksum-Mode"
+
+// amzChecksumCRC32C is the header carrying the base64 encoded CRC32C
+// checksum of the payload of an upload.
+const amzChecksumCRC32C = "X-Amz-Checksum-Crc32c"
+
+// amzChecksumAlgorithm is the header choosing the checksum algorithm
+// of the parts of a multipart upload.
+const amzChecksumAlgorithm = "X-Amz-Checksum-Algorithm"
+
+// amzMpPartsCount is the header carrying the number of parts of a
+// multipart object in responses to requests of a part.
+const amzMpPartsCount = "X-Amz-Mp-Parts-Count"
+
+// crc32cTable is the Castagnoli table, hash/crc32 computes checksums
+// with this table using the SSE4.2 CRC32 instruction on amd64 and the
+// CRC32 instructions on arm64, falling back to a slicing-by-8 software
+// implementation on other platforms.
+var crc32cTable = crc32.MakeTable(crc32.Castagnoli)
+
+// newCRC32C - returns a new CRC32C hash.
+func newCRC32C() hash.Hash {
+	return crc32.New(crc32cTable)
+}
+
+// List of additional checksum headers returned by S3, in the order
+// of preference used for verifying downloads.
+var amzChecksumHeaders = []struct {
+	name    string
+	newHash func() hash.Hash
+}{
+	{amzChecksumCRC32C, newCRC32C},
+	{"X-Amz-Checksum-Crc32", func() hash.Hash { return crc32.NewIEEE() }},
+	{"X-Amz-Checksum-Sha1", sha1.New},
+	{"X-Amz-Checksum-Sha256", sha256.New},
+}
+
+// setObjectChecksums - sets the additional checksums and the parts
+// count of the object info from the headers of a response.
+func setObjectChecksums(objectInfo *ObjectInfo, header http.Header) {
+	objectInfo.ChecksumCRC32 = header.Get("X-Amz-Checksum-Crc32")
+	objectInfo.ChecksumCRC32C = header.Get("X-Amz-Checksum-Crc32c")
+	objectInfo.ChecksumSHA1 = header.Get("X-Amz-Checksum-Sha1")
+	objectInfo.ChecksumSHA256 = header.Get("X-Amz-Checksum-Sha256")
+	if count, err := strconv.Atoi(header.Get(amzMpPartsCount)); err == nil {
+		objectInfo.PartsCount = count
+	}
+}
+
+// verifyReader - computes the checksum of the data read from the
+// underlying reader and compares it with the expected checksum of
+// the object once io.EOF is reached or size bytes have been read.
+type verifyReader struct {
+	io.ReadCloser
+	hash       hash.Hash
+	expected   string
+	encode     func([]byte) string
+	size       int64
+	read       int64
+	verified   bool
+	bucketName string
+	objectName string
+}
+
+// Read - reads from the underlying reader, at the end of the object
+// an error is returned if the checksum of the data does not match.
+func (v *verifyReader) Read(p []byte) (n int, err error) {
+	n, err = v.ReadCloser.Read(p)
+	v.hash.Write(p[:n])
+	v.read += int64(n)
+	if !v.verified && (err == io.EOF || (v.size >= 0 && v.read >= v.size)) {
+		v.verified = true
+		if actual := v.encode(v.hash.Sum(nil)); actual != v.expected {
+			return n, ErrObjectCorrupted(v.bucketName, v.objectName, v.expected, actual)
+		}
+	}
+	return n, err
+}
+
+// newVerifyReader - returns a reader verifying the full object body
+// against the x-amz-checksum-* headers of the response, or against
+// the ETag when it is the MD5 sum of the object. The body is returned
+// as is when the response carries no checksum which can be verified.
+// A non-negative size verifies the checksum as soon as size bytes are
+// read, for callers which do not read until io.EOF. The ETag is only
+// verified if verifyETag is true.
+func newVerifyReader(body io.ReadCloser, header http.Header, size int64, verifyETag bool, bucketName, objectName string) io.ReadCloser {
+	for _, checksum := range amzChecksumHeaders {
+		expected := header.Get(checksum.name)
+		// Checksums of multipart objects are computed over the
+		// checksums of the parts, e.g. "<checksum>-<parts>".
+		if expected == "" || strings.Contains(expected, "-") {
+			continue
+		}
+		return &verifyReader{
+			ReadCloser: body,
+			hash:       checksum.newHash(),
+			expected:   expected,
+			encode:     base64.StdEncoding.EncodeToString,
+			size:       size,
+			bucketName: bucketName,
+			objectName: objectName,
+		}
+	}
+
+	// ETag is the MD5 sum of the object only for single part objects
+	// which are not encrypted with SSE-C or SSE-KMS.
+	etag := strings.Trim(header.Get("ETag"), "\"")
+	if !verifyETag || len(etag) != hex.EncodedLen(md5.Size) || strings.Contains(etag, "-") {
+		return body
+	}
+	if header.Get("X-Amz-Server-Side-Encryption-Customer-Algorithm") != "" ||
+		header.Get("X-Amz-Server-Side-Encryption") == "aws:kms" {
+		return body
+	}
+	return &verifyReader{
+		ReadCloser: body,
+		hash:       md5.New(),
+		expected:   strings.ToLower(etag),
+		encode:     hex.EncodeToString,
+		size:       size,
+		bucketName: bucketName,
+		objectName: objectName,
+	}
+}
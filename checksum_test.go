@@ -0,0 +1,193 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Tests verifying downloaded data against the object checksums.
+func TestVerifyReader(t *testing.T) {
+	data := []byte("The quick brown fox jumps over the lazy dog")
+	md5Sum := md5.Sum(data)
+	sha256Sum := sha256.Sum256(data)
+	crc32c := newCRC32C()
+	crc32c.Write(data)
+
+	testCases := []struct {
+		header   http.Header
+		verified bool
+		corrupt  bool
+	}{
+		// Single part object, ETag is the MD5.
+		{http.Header{"Etag": []string{"\"" + hex.EncodeToString(md5Sum[:]) + "\""}}, true, false},
+		{http.Header{"Etag": []string{"\"" + hex.EncodeToString(make([]byte, md5.Size)) + "\""}}, true, true},
+		// Multipart and encrypted objects are not verified by ETag.
+		{http.Header{"Etag": []string{"\"" + hex.EncodeToString(make([]byte, md5.Size)) + "-2\""}}, false, false},
+		{http.Header{
+			"Etag":                         []string{"\"" + hex.EncodeToString(make([]byte, md5.Size)) + "\""},
+			"X-Amz-Server-Side-Encryption": []string{"aws:kms"},
+		}, false, false},
+		// Additional checksums take precedence over the ETag.
+		{http.Header{
+			"Etag":                  []string{"\"" + hex.EncodeToString(make([]byte, md5.Size)) + "\""},
+			"X-Amz-Checksum-Sha256": []string{base64.StdEncoding.EncodeToString(sha256Sum[:])},
+		}, true, false},
+		{http.Header{"X-Amz-Checksum-Crc32c": []string{base64.StdEncoding.EncodeToString(crc32c.Sum(nil))}}, true, false},
+		{http.Header{"X-Amz-Checksum-Crc32c": []string{"AAAAAA=="}}, true, true},
+		// Composite checksums of multipart objects are skipped.
+		{http.Header{"X-Amz-Checksum-Crc32c": []string{"AAAAAA==-3"}}, false, false},
+		{http.Header{}, false, false},
+	}
+
+	for i, testCase := range testCases {
+		body := ioutil.NopCloser(bytes.NewReader(data))
+		reader := newVerifyReader(body, testCase.header, -1, true, "bucket", "object")
+		if _, ok := reader.(*verifyReader); ok != testCase.verified {
+			t.Errorf("Test %d: expected verification to be %v", i+1, testCase.verified)
+		}
+		_, err := ioutil.ReadAll(reader)
+		if testCase.corrupt {
+			if errResp := ToErrorResponse(err); errResp.Code != "ObjectCorrupted" || errResp.Key != "object" {
+				t.Errorf("Test %d: expected ObjectCorrupted error, got %v", i+1, err)
+			}
+		} else if err != nil {
+			t.Errorf("Test %d: unexpected error %v", i+1, err)
+		}
+
+		// Verification with a known size does not depend on io.EOF.
+		body = ioutil.NopCloser(bytes.NewReader(data))
+		reader = newVerifyReader(body, testCase.header, int64(len(data)), true, "bucket", "object")
+		_, err = io.Copy(ioutil.Discard, io.LimitReader(reader, int64(len(data))))
+		if testCase.corrupt != (ToErrorResponse(err).Code == "ObjectCorrupted") {
+			t.Errorf("Test %d: unexpected error %v with known size", i+1, err)
+		}
+	}
+}
+
+// Tests CRC32C against known values, see RFC 3720 section B.4.
+func TestCRC32C(t *testing.T) {
+	testCases := []struct {
+		data     []byte
+		expected uint32
+	}{
+		{make([]byte, 32), 0x8a9136aa},
+		{bytes.Repeat([]byte{0xff}, 32), 0x62a8ab43},
+		{[]byte("123456789"), 0xe3069283},
+	}
+	for i, testCase := range testCases {
+		h := newCRC32C()
+		h.Write(testCase.data)
+		if sum := h.(hash.Hash32).Sum32(); sum != testCase.expected {
+			t.Errorf("Test %d: expected %08x, got %08x", i+1, testCase.expected, sum)
+		}
+	}
+}
+
+// Tests that uploads send the CRC32C checksums of the object and of
+// each part when requested.
+func TestPutObjectCRC32C(t *testing.T) {
+	var (
+		mutex    sync.Mutex
+		requests []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		query := r.URL.Query()
+		mutex.Lock()
+		defer mutex.Unlock()
+		switch {
+		case r.Method == http.MethodPost && r.URL.RawQuery == "uploads=":
+			requests = append(requests, "initiate "+r.Header.Get("X-Amz-Checksum-Algorithm"))
+			fmt.Fprint(w, "<InitiateMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><UploadId>id</UploadId></InitiateMultipartUploadResult>")
+		case r.Method == http.MethodPut && query.Get("partNumber") != "":
+			requests = append(requests, fmt.Sprintf("part %s %s", query.Get("partNumber"), r.Header.Get("X-Amz-Checksum-Crc32c")))
+			w.Header().Set("ETag", `"part"`)
+		case r.Method == http.MethodPost:
+			var complete completeMultipartUpload
+			if err := xml.Unmarshal(body, &complete); err != nil {
+				t.Error(err)
+			}
+			for _, part := range complete.Parts {
+				requests = append(requests, fmt.Sprintf("complete %d %s", part.PartNumber, part.ChecksumCRC32C))
+			}
+			fmt.Fprint(w, "<CompleteMultipartUploadResult><Bucket>bucket</Bucket><Key>object</Key><ETag>\"etag\"</ETag></CompleteMultipartUploadResult>")
+		case r.Method == http.MethodPut:
+			requests = append(requests, "put "+r.Header.Get("X-Amz-Checksum-Crc32c"))
+			w.Header().Set("ETag", `"etag"`)
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const partSize = absMinPartSize
+	data := make([]byte, partSize+9)
+	copy(data[partSize:], "123456789")
+	sum := func(data []byte) string {
+		h := newCRC32C()
+		h.Write(data)
+		return base64.StdEncoding.EncodeToString(h.Sum(nil))
+	}
+	multipart := []string{
+		"initiate CRC32C",
+		"part 1 " + sum(data[:partSize]),
+		"part 2 " + sum(data[partSize:]),
+		"complete 1 " + sum(data[:partSize]),
+		"complete 2 " + sum(data[partSize:]),
+	}
+	testCases := []struct {
+		reader   io.Reader
+		size     int64
+		expected []string
+	}{
+		{bytes.NewReader(data[partSize:]), 9, []string{"put 4waSgw=="}},
+		{struct{ io.Reader }{bytes.NewReader(data[partSize:])}, -1, []string{"put 4waSgw=="}},
+		{bytes.NewReader(data), int64(len(data)), multipart},
+		{struct{ io.Reader }{bytes.NewReader(data)}, -1, multipart},
+		{struct{ io.Reader }{bytes.NewReader(data)}, int64(len(data)), multipart},
+	}
+	for i, testCase := range testCases {
+		requests = nil
+		opts := PutObjectOptions{PartSize: partSize, NumThreads: 1, SendCRC32C: true}
+		if _, err = c.PutObject("bucket", "object", testCase.reader, testCase.size, opts); err != nil {
+			t.Fatalf("Test %d: %v", i+1, err)
+		}
+		if !reflect.DeepEqual(requests, testCase.expected) {
+			t.Errorf("Test %d: expected requests %q, got %q", i+1, testCase.expected, requests)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"compress/gzip"
+	"io"
+)
+
+// Compressor compresses the data of uploads, see the Compression
+// field of PutObjectOptions. Other algorithms, such as zstd, can be
+// used by implementing this interface.
+type Compressor interface {
+	// ContentEncoding returns the Content-Encoding of the compressed
+	// data, e.g. "gzip".
+	ContentEncoding() string
+
+	// NewWriter returns a writer compressing the data written to w,
+	// closing it flushes the compressed data.
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+}
+
+type gzipCompressor struct {
+	level int
+}
+
+// NewGzipCompressor - returns a Compressor compressing with gzip at
+// level, one of the compression levels of compress/gzip.
+func NewGzipCompressor(level int) Compressor {
+	return gzipCompressor{level: level}
+}
+
+func (g gzipCompressor) ContentEncoding() string {
+	return "gzip"
+}
+
+func (g gzipCompressor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriterLevel(w, g.level)
+}
+
+// compressBound - upper bound of the compressed size of size bytes,
+// used to size the parts of compressed uploads.
+func compressBound(size int64) int64 {
+	return size + size/8 + 64*1024
+}
+
+// compressReader - returns a reader of the data of reader compressed
+// by compressor. Closing the returned reader stops the compression.
+func compressReader(reader io.Reader, compressor Compressor) (io.ReadCloser, error) {
+	pipeReader, pipeWriter := io.Pipe()
+	writer, err := compressor.NewWriter(pipeWriter)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		_, err := io.Copy(writer, reader)
+		if cerr := writer.Close(); err == nil {
+			err = cerr
+		}
+		pipeWriter.CloseWithError(err)
+	}()
+	return pipeReader, nil
+}
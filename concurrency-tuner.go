@@ -0,0 +1,99 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"sync"
+	"time"
+)
+
+// maxAutoTunedWorkers - default maximum number of parallel transfers
+// when the concurrency is tuned automatically.
+const maxAutoTunedWorkers = 16
+
+// concurrencyTuner - limits the number of parallel transfers from the
+// measured throughput. Starting with a single transfer, the throughput
+// of all transfers is measured whenever as many transfers as allowed
+// completed. One more transfer is allowed if it grew by more than 10%
+// since the last measurement and one less if it dropped by more than
+// 10%, such that the limit settles where more connections stop adding
+// throughput.
+type concurrencyTuner struct {
+	mutex sync.Mutex
+	cond  *sync.Cond
+	now   func() time.Time
+
+	max    int // Maximum number of parallel transfers.
+	limit  int // Current number of parallel transfers allowed.
+	active int // Number of transfers in progress.
+
+	start time.Time // Start of the current measurement.
+	bytes int64     // Bytes transferred in the current measurement.
+	count int       // Transfers completed in the current measurement.
+	rate  float64   // Throughput of the last measurement.
+}
+
+// newConcurrencyTuner - returns a concurrencyTuner allowing at most
+// max parallel transfers.
+func newConcurrencyTuner(max int) *concurrencyTuner {
+	return newConcurrencyTunerWithClock(max, time.Now)
+}
+
+func newConcurrencyTunerWithClock(max int, now func() time.Time) *concurrencyTuner {
+	if max < 1 {
+		max = 1
+	}
+	t := &concurrencyTuner{now: now, max: max, limit: 1, start: now()}
+	t.cond = sync.NewCond(&t.mutex)
+	return t
+}
+
+// acquire - waits until another transfer is allowed to start.
+func (t *concurrencyTuner) acquire() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for t.active >= t.limit {
+		t.cond.Wait()
+	}
+	t.active++
+}
+
+// release - records a completed transfer of size bytes and adjusts
+// the limit once enough transfers completed.
+func (t *concurrencyTuner) release(size int64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.active--
+	t.bytes += size
+	t.count++
+	if t.count >= t.limit {
+		now := t.now()
+		if elapsed := now.Sub(t.start); elapsed > 0 {
+			rate := float64(t.bytes) / elapsed.Seconds()
+			switch {
+			case rate > t.rate*1.1 && t.limit < t.max:
+				t.limit++
+			case rate < t.rate*0.9 && t.limit > 1:
+				t.limit--
+			}
+			t.rate = rate
+		}
+		t.start, t.bytes, t.count = now, 0, 0
+	}
+	t.cond.Broadcast()
+}
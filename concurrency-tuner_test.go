@@ -0,0 +1,85 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// Tests that the limit settles where more transfers stop adding
+// throughput.
+func TestConcurrencyTuner(t *testing.T) {
+	testCases := []struct {
+		max, saturation int
+		expected        int
+	}{
+		// Throughput scales linearly up to the maximum.
+		{5, 100, 5},
+		// Throughput is flat beyond 3 transfers, a 4th one is tried.
+		{8, 3, 4},
+		// A single transfer saturates the connection.
+		{8, 1, 2},
+		{0, 100, 1},
+	}
+	for i, testCase := range testCases {
+		clock := time.Unix(0, 0)
+		tuner := newConcurrencyTunerWithClock(testCase.max, func() time.Time { return clock })
+		const size = 1 << 20
+		for round := 0; round < 20; round++ {
+			parallel := tuner.limit
+			rate := parallel
+			if rate > testCase.saturation {
+				rate = testCase.saturation
+			}
+			for j := 0; j < parallel; j++ {
+				tuner.acquire()
+			}
+			// Transfers of the round complete together.
+			clock = clock.Add(time.Duration(parallel) * time.Second / time.Duration(rate))
+			for j := 0; j < parallel; j++ {
+				tuner.release(size)
+			}
+		}
+		if tuner.limit != testCase.expected {
+			t.Errorf("Test %d: expected a limit of %d, got %d", i+1, testCase.expected, tuner.limit)
+		}
+	}
+}
+
+// Tests that multipart uploads with automatically tuned concurrency
+// upload all the parts.
+func TestPutObjectAutoTuneThreads(t *testing.T) {
+	server := newUploadTestServer()
+	defer server.Close()
+	c := server.client(t)
+
+	const partSize = absMinPartSize
+	data := make([]byte, 4*partSize)
+	n, err := c.PutObject("bucket", "object", bytes.NewReader(data), int64(len(data)), PutObjectOptions{PartSize: partSize, AutoTuneThreads: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(data)) {
+		t.Errorf("expected %d bytes uploaded, got %d", len(data), n)
+	}
+	if requests := server.takeRequests(); len(requests) != 6 {
+		t.Errorf("expected 4 parts to be uploaded, got requests %v", requests)
+	}
+}
@@ -46,6 +46,11 @@ const maxMultipartPutObjectSize = 1024 * 1024 * 1024 * 1024 * 5
 // we don't want to sign the request payload
 const unsignedPayload = "UNSIGNED-PAYLOAD"
 
+// expectContinueMinSize - minimum size 1MiB of request bodies sent
+// with Expect: 100-continue, smaller bodies are sent right away since
+// waiting for the server costs a round trip.
+const expectContinueMinSize = 1024 * 1024
+
 // Total number of parallel workers used for multipart operation.
 const totalWorkers = 4
 
@@ -60,3 +65,15 @@ const amzStorageClass = "X-Amz-Storage-Class"
 
 // Website redirect location header constant
 const amzWebsiteRedirectLocation = "X-Amz-Website-Redirect-Location"
+
+// Object lock header constants.
+const (
+	amzLockMode            = "X-Amz-Object-Lock-Mode"
+	amzLockRetainUntilDate = "X-Amz-Object-Lock-Retain-Until-Date"
+	amzLockLegalHold       = "X-Amz-Object-Lock-Legal-Hold"
+	amzBypassGovernance    = "X-Amz-Bypass-Governance-Retention"
+)
+
+// MinIO force delete header constant, removes a bucket with all of its
+// objects.
+const minIOForceDelete = "X-Minio-Force-Delete"
@@ -52,7 +52,7 @@ func (c Core) ListObjects(bucket, prefix, marker, delimiter string, maxKeys int)
 // ListObjectsV2 - Lists all the objects at a prefix, similar to ListObjects() but uses
 // continuationToken instead of marker to support iteration over the results.
 func (c Core) ListObjectsV2(bucketName, objectPrefix, continuationToken string, fetchOwner bool, delimiter string, maxkeys int, startAfter string) (ListBucketV2Result, error) {
-	return c.listObjectsV2Query(bucketName, objectPrefix, continuationToken, fetchOwner, delimiter, maxkeys, startAfter)
+	return c.listObjectsV2Query(bucketName, objectPrefix, continuationToken, fetchOwner, delimiter, maxkeys, startAfter, false, nil)
 }
 
 // CopyObject - copies an object from source object to destination object on server side.
@@ -92,7 +92,7 @@ func (c Core) PutObject(bucket, object string, data io.Reader, size int64, md5Ba
 	}
 	opts.UserMetadata = m
 	opts.ServerSideEncryption = sse
-	return c.putObjectDo(context.Background(), bucket, object, data, md5Base64, sha256Hex, size, opts)
+	return c.putObjectDo(context.Background(), bucket, object, data, md5Base64, sha256Hex, "", size, opts)
 }
 
 // NewMultipartUpload - Initiates new multipart upload and returns the new uploadID.
@@ -103,12 +103,12 @@ func (c Core) NewMultipartUpload(bucket, object string, opts PutObjectOptions) (
 
 // ListMultipartUploads - List incomplete uploads.
 func (c Core) ListMultipartUploads(bucket, prefix, keyMarker, uploadIDMarker, delimiter string, maxUploads int) (result ListMultipartUploadsResult, err error) {
-	return c.listMultipartUploadsQuery(bucket, keyMarker, uploadIDMarker, prefix, delimiter, maxUploads)
+	return c.listMultipartUploadsQuery(context.Background(), bucket, keyMarker, uploadIDMarker, prefix, delimiter, maxUploads)
 }
 
 // PutObjectPart - Upload an object part.
 func (c Core) PutObjectPart(bucket, object, uploadID string, partID int, data io.Reader, size int64, md5Base64, sha256Hex string, sse encrypt.ServerSide) (ObjectPart, error) {
-	return c.uploadPart(context.Background(), bucket, object, uploadID, data, partID, md5Base64, sha256Hex, size, sse)
+	return c.uploadPart(context.Background(), bucket, object, uploadID, data, partID, md5Base64, sha256Hex, "", size, PutObjectOptions{ServerSideEncryption: sse})
 }
 
 // ListObjectParts - List uploaded parts of an incomplete upload.x
@@ -120,7 +120,7 @@ func (c Core) ListObjectParts(bucket, object, uploadID string, partNumberMarker
 func (c Core) CompleteMultipartUpload(bucket, object, uploadID string, parts []CompletePart) (string, error) {
 	res, err := c.completeMultipartUpload(context.Background(), bucket, object, uploadID, completeMultipartUpload{
 		Parts: parts,
-	})
+	}, PutObjectOptions{})
 	return res.ETag, err
 }
 
@@ -0,0 +1,177 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+// DNSCacheDialer - dialer resolving host names through a cache, which
+// avoids a DNS lookup for each new connection. Successful lookups are
+// cached for TTL and failed lookups for NegativeTTL. Concurrent lookups
+// of a host share a single query, and the addresses of the last
+// successful lookup are used if a refresh fails.
+//
+// Use its DialContext in the transport of the client, for example
+//
+//   tr, _ := minio.DefaultTransport(true)
+//   tr.(*http.Transport).DialContext = minio.NewDNSCacheDialer(time.Minute, 5*time.Second).DialContext
+//   s3Client.SetCustomTransport(tr)
+//
+type DNSCacheDialer struct {
+	// Dialer used to connect to the resolved addresses.
+	Dialer *net.Dialer
+
+	// Resolver used for the lookups, net.DefaultResolver if nil.
+	Resolver *net.Resolver
+
+	// TTL of successful lookups.
+	TTL time.Duration
+
+	// TTL of failed lookups.
+	NegativeTTL time.Duration
+
+	mutex   sync.Mutex
+	entries map[string]*dnsCacheEntry
+
+	// Used by tests.
+	now        func() time.Time
+	lookupHost func(ctx context.Context, host string) ([]string, error)
+}
+
+// dnsCacheEntry - cached result of a host name lookup.
+type dnsCacheEntry struct {
+	ready   chan struct{} // Closed once the lookup is done.
+	addrs   []string
+	err     error
+	expires time.Time
+}
+
+// NewDNSCacheDialer - returns a DNSCacheDialer caching lookups for
+// ttl, and failed lookups for negativeTTL. It connects with the same
+// timeouts as DefaultTransport.
+func NewDNSCacheDialer(ttl, negativeTTL time.Duration) *DNSCacheDialer {
+	return &DNSCacheDialer{
+		Dialer: &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		},
+		TTL:         ttl,
+		NegativeTTL: negativeTTL,
+	}
+}
+
+// DialContext - connects to address on the named network, resolving the
+// host through the cache. The resolved addresses are tried in order
+// until a connection succeeds.
+func (d *DNSCacheDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	dialer := d.Dialer
+	if dialer == nil {
+		dialer = &net.Dialer{}
+	}
+	if net.ParseIP(host) != nil {
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	addrs, err := d.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	var conn net.Conn
+	for _, addr := range addrs {
+		if conn, err = dialer.DialContext(ctx, network, net.JoinHostPort(addr, port)); err == nil {
+			return conn, nil
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return nil, err
+}
+
+// lookup - returns the addresses of host from the cache, resolving it
+// if it is not cached or has expired.
+func (d *DNSCacheDialer) lookup(ctx context.Context, host string) ([]string, error) {
+	now := d.getNow()
+	d.mutex.Lock()
+	if d.entries == nil {
+		d.entries = make(map[string]*dnsCacheEntry)
+	}
+	entry, ok := d.entries[host]
+	if ok {
+		select {
+		case <-entry.ready:
+			ok = now.Before(entry.expires)
+		default:
+			// A lookup is in progress, wait for it.
+		}
+	}
+	if ok {
+		d.mutex.Unlock()
+		select {
+		case <-entry.ready:
+			return entry.addrs, entry.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	stale := entry
+	entry = &dnsCacheEntry{ready: make(chan struct{})}
+	d.entries[host] = entry
+	d.mutex.Unlock()
+
+	// The lookup is shared, do not cancel it with the context of
+	// the first caller.
+	addrs, err := d.resolve(context.Background(), host)
+	entry.addrs, entry.err = addrs, err
+	entry.expires = d.getNow().Add(d.TTL)
+	if err != nil {
+		entry.expires = d.getNow().Add(d.NegativeTTL)
+		if stale != nil && stale.err == nil {
+			entry.addrs, entry.err = stale.addrs, nil
+		}
+	}
+	close(entry.ready)
+	return entry.addrs, entry.err
+}
+
+func (d *DNSCacheDialer) resolve(ctx context.Context, host string) ([]string, error) {
+	if d.lookupHost != nil {
+		return d.lookupHost(ctx, host)
+	}
+	resolver := d.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	return resolver.LookupHost(ctx, host)
+}
+
+func (d *DNSCacheDialer) getNow() time.Time {
+	if d.now != nil {
+		return d.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,98 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// Tests caching of successful and failed lookups by DNSCacheDialer.
+func TestDNSCacheDialer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	clock := time.Unix(0, 0)
+	lookups := map[string]int{}
+	failing := map[string]bool{"unknown.test": true}
+	d := NewDNSCacheDialer(time.Minute, time.Second)
+	d.now = func() time.Time { return clock }
+	d.lookupHost = func(ctx context.Context, host string) ([]string, error) {
+		lookups[host]++
+		if failing[host] {
+			return nil, errors.New("lookup failed")
+		}
+		return []string{"127.0.0.1"}, nil
+	}
+
+	testCases := []struct {
+		host    string
+		advance time.Duration
+		lookups int
+		fail    bool
+		setFail bool
+	}{
+		{host: "storage.test", lookups: 1},
+		// Cached.
+		{host: "storage.test", advance: 30 * time.Second, lookups: 1},
+		// Expired.
+		{host: "storage.test", advance: 31 * time.Second, lookups: 2},
+		// Failed refresh, the last addresses are used.
+		{host: "storage.test", advance: 2 * time.Minute, lookups: 3, setFail: true},
+		// The failure is cached for the negative TTL.
+		{host: "storage.test", lookups: 3},
+		{host: "storage.test", advance: 2 * time.Second, lookups: 4},
+		{host: "unknown.test", lookups: 1, fail: true},
+		{host: "unknown.test", lookups: 1, fail: true},
+		{host: "unknown.test", advance: 2 * time.Second, lookups: 2, fail: true},
+		// Addresses are not resolved.
+		{host: "127.0.0.1", lookups: 0},
+	}
+	for i, testCase := range testCases {
+		clock = clock.Add(testCase.advance)
+		if testCase.setFail {
+			failing[testCase.host] = true
+		}
+		conn, err := d.DialContext(context.Background(), "tcp", net.JoinHostPort(testCase.host, port))
+		if testCase.fail != (err != nil) {
+			t.Fatalf("Test %d: expected failure %t, got %v", i+1, testCase.fail, err)
+		}
+		if conn != nil {
+			conn.Close()
+		}
+		if lookups[testCase.host] != testCase.lookups {
+			t.Errorf("Test %d: expected %d lookups, got %d", i+1, testCase.lookups, lookups[testCase.host])
+		}
+	}
+}
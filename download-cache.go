@@ -0,0 +1,280 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/minio/minio-go/v6/pkg/encrypt"
+)
+
+// DownloadCache is a size bounded LRU cache of downloaded objects,
+// kept in memory or in files of a directory. Objects are cached by
+// bucket, object name and ETag, repeat downloads of an object are
+// served from the cache once the server confirms with an
+// If-None-Match request that the cached ETag is still current.
+//
+// Only whole objects read to the end are cached, objects larger than
+// the cache are not. A cache should only be shared by clients of the
+// same endpoint.
+type DownloadCache struct {
+	mutex sync.Mutex
+
+	// Directory of the cached files, objects are cached in memory
+	// if empty.
+	dir string
+
+	maxSize int64
+	size    int64
+
+	// Entries by bucket and object name, most recently used first.
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+// downloadCacheEntry - a cached object.
+type downloadCacheEntry struct {
+	key  string
+	info ObjectInfo
+	data []byte
+	path string
+}
+
+// NewMemoryDownloadCache - returns a download cache keeping up to
+// maxSize bytes of objects in memory.
+func NewMemoryDownloadCache(maxSize int64) *DownloadCache {
+	return &DownloadCache{
+		maxSize: maxSize,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// NewDiskDownloadCache - returns a download cache keeping up to
+// maxSize bytes of objects in files of dir, which is created if it
+// does not exist. Cached files are not reused by new caches of the
+// same directory.
+func NewDiskDownloadCache(dir string, maxSize int64) (*DownloadCache, error) {
+	if dir == "" {
+		return nil, ErrInvalidArgument("Download cache directory cannot be empty.")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	cache := NewMemoryDownloadCache(maxSize)
+	cache.dir = dir
+	return cache, nil
+}
+
+// Size - returns the number of bytes of cached objects.
+func (d *DownloadCache) Size() int64 {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.size
+}
+
+// Len - returns the number of cached objects.
+func (d *DownloadCache) Len() int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.lru.Len()
+}
+
+// downloadCacheKey - returns the cache key of an object.
+func downloadCacheKey(bucketName, objectName string) string {
+	return bucketName + "/" + objectName
+}
+
+// open - returns the cached object of key and a reader of its data.
+// Files are opened under the lock so that evictions do not remove
+// them before they are read.
+func (d *DownloadCache) open(key string) (ObjectInfo, io.ReadCloser, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	elem, ok := d.entries[key]
+	if !ok {
+		return ObjectInfo{}, nil, false
+	}
+	entry := elem.Value.(*downloadCacheEntry)
+	if entry.path == "" {
+		d.lru.MoveToFront(elem)
+		return entry.info, ioutil.NopCloser(bytes.NewReader(entry.data)), true
+	}
+	file, err := os.Open(entry.path)
+	if err != nil {
+		d.removeElement(elem)
+		return ObjectInfo{}, nil, false
+	}
+	d.lru.MoveToFront(elem)
+	return entry.info, file, true
+}
+
+// remove - removes the cached object of key.
+func (d *DownloadCache) remove(key string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if elem, ok := d.entries[key]; ok {
+		d.removeElement(elem)
+	}
+}
+
+// removeElement - removes an entry, called with the lock held.
+func (d *DownloadCache) removeElement(elem *list.Element) {
+	entry := d.lru.Remove(elem).(*downloadCacheEntry)
+	delete(d.entries, entry.key)
+	d.size -= entry.info.Size
+	if entry.path != "" {
+		os.Remove(entry.path)
+	}
+}
+
+// add - caches an object replacing any previous version of it, least
+// recently used objects are evicted to make room.
+func (d *DownloadCache) add(entry *downloadCacheEntry) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if elem, ok := d.entries[entry.key]; ok {
+		d.removeElement(elem)
+	}
+	for d.size+entry.info.Size > d.maxSize && d.lru.Len() > 0 {
+		d.removeElement(d.lru.Back())
+	}
+	d.entries[entry.key] = d.lru.PushFront(entry)
+	d.size += entry.info.Size
+}
+
+// newWriter - returns a writer of a new entry, nil if the object
+// cannot be cached.
+func (d *DownloadCache) newWriter(key string, info ObjectInfo) *downloadCacheWriter {
+	if info.ETag == "" || info.Size < 0 || info.Size > d.maxSize {
+		return nil
+	}
+	w := &downloadCacheWriter{cache: d, entry: &downloadCacheEntry{key: key, info: info}}
+	if d.dir == "" {
+		w.buf = bytes.NewBuffer(make([]byte, 0, info.Size))
+		return w
+	}
+	file, err := ioutil.TempFile(d.dir, "minio-cache-")
+	if err != nil {
+		return nil
+	}
+	w.file = file
+	w.entry.path = file.Name()
+	return w
+}
+
+// downloadCacheWriter - stores the data of an object being
+// downloaded.
+type downloadCacheWriter struct {
+	cache   *DownloadCache
+	entry   *downloadCacheEntry
+	buf     *bytes.Buffer
+	file    *os.File
+	written int64
+	err     error
+}
+
+func (w *downloadCacheWriter) write(p []byte) {
+	if w.err != nil {
+		return
+	}
+	if w.file != nil {
+		_, w.err = w.file.Write(p)
+	} else {
+		w.buf.Write(p)
+	}
+	w.written += int64(len(p))
+}
+
+// commit - caches the object if all of it was written.
+func (w *downloadCacheWriter) commit() {
+	if w.file != nil {
+		if err := w.file.Close(); err != nil && w.err == nil {
+			w.err = err
+		}
+	}
+	if w.err != nil || w.written != w.entry.info.Size {
+		w.discard()
+		return
+	}
+	if w.buf != nil {
+		w.entry.data = w.buf.Bytes()
+	}
+	w.cache.add(w.entry)
+}
+
+// discard - drops the data written.
+func (w *downloadCacheWriter) discard() {
+	if w.file != nil {
+		w.file.Close()
+		os.Remove(w.entry.path)
+	}
+}
+
+// downloadCacheReader - caches the body of a download once it is
+// read to the end.
+type downloadCacheReader struct {
+	body   io.ReadCloser
+	writer *downloadCacheWriter
+}
+
+func (r *downloadCacheReader) Read(p []byte) (int, error) {
+	n, err := r.body.Read(p)
+	if r.writer != nil {
+		r.writer.write(p[:n])
+		if err == io.EOF {
+			r.writer.commit()
+			r.writer = nil
+		}
+	}
+	return n, err
+}
+
+// Close discards the data of downloads which were not read to the
+// end.
+func (r *downloadCacheReader) Close() error {
+	if r.writer != nil {
+		r.writer.discard()
+		r.writer = nil
+	}
+	return r.body.Close()
+}
+
+// downloadCacheable - returns true if downloads with the options may
+// be served from the download cache, ranges, conditions, custom
+// query parameters, transformations of the data and SSE-C encrypted
+// objects are not.
+func (o GetObjectOptions) downloadCacheable() bool {
+	for _, key := range []string{"Range", "If-None-Match", "If-Modified-Since"} {
+		if _, ok := o.headers[key]; ok {
+			return false
+		}
+	}
+	if o.NotMatchETag != "" || !o.ModifiedSince.IsZero() {
+		return false
+	}
+	if o.VerifyChecksum || o.Extract || o.Decompress || len(o.CustomQuery) > 0 || o.PartNumber > 0 {
+		return false
+	}
+	return o.ServerSideEncryption == nil || o.ServerSideEncryption.Type() != encrypt.SSEC
+}
@@ -0,0 +1,158 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Tests repeat downloads served from the download cache.
+func TestDownloadCache(t *testing.T) {
+	var (
+		mutex    sync.Mutex
+		data     = []byte("first version")
+		etag     = "etag1"
+		statuses []int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		rec := httptest.NewRecorder()
+		rec.Header().Set("ETag", `"`+etag+`"`)
+		http.ServeContent(rec, r, "", time.Date(2019, time.March, 10, 12, 30, 0, 0, time.UTC), bytes.NewReader(data))
+		statuses = append(statuses, rec.Code)
+		for k, v := range rec.Header() {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(rec.Code)
+		w.Write(rec.Body.Bytes())
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "minio-cache-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	diskCache, err := NewDiskDownloadCache(dir, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cache := range []*DownloadCache{NewMemoryDownloadCache(1024), diskCache} {
+		c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.SetDownloadCache(cache)
+
+		mutex.Lock()
+		data, etag, statuses = []byte("first version"), "etag1", nil
+		mutex.Unlock()
+
+		read := func(opts GetObjectOptions) string {
+			t.Helper()
+			object, err := c.GetObject("bucket", "object", opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer object.Close()
+			content, err := ioutil.ReadAll(object)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return string(content)
+		}
+
+		if content := read(GetObjectOptions{}); content != "first version" {
+			t.Fatalf("unexpected content %q", content)
+		}
+		if cache.Len() != 1 || cache.Size() != int64(len("first version")) {
+			t.Fatalf("expected the object to be cached, got %d objects of %d bytes", cache.Len(), cache.Size())
+		}
+		if content := read(GetObjectOptions{}); content != "first version" {
+			t.Fatalf("unexpected cached content %q", content)
+		}
+
+		// Ranges are not served from the cache.
+		opts := GetObjectOptions{}
+		opts.SetRange(0, 4)
+		if content := read(opts); content != "first" {
+			t.Fatalf("unexpected range content %q", content)
+		}
+
+		mutex.Lock()
+		data, etag = []byte("second version"), "etag2"
+		mutex.Unlock()
+		if content := read(GetObjectOptions{}); content != "second version" {
+			t.Fatalf("unexpected content after update %q", content)
+		}
+		if content := read(GetObjectOptions{}); content != "second version" {
+			t.Fatalf("unexpected cached content after update %q", content)
+		}
+		if cache.Len() != 1 || cache.Size() != int64(len("second version")) {
+			t.Fatalf("expected the new version to replace the cached one, got %d objects of %d bytes", cache.Len(), cache.Size())
+		}
+
+		mutex.Lock()
+		expected := []int{http.StatusOK, http.StatusNotModified, http.StatusPartialContent, http.StatusOK, http.StatusNotModified}
+		if len(statuses) != len(expected) {
+			t.Fatalf("expected statuses %v, got %v", expected, statuses)
+		}
+		for i := range expected {
+			if statuses[i] != expected[i] {
+				t.Fatalf("expected statuses %v, got %v", expected, statuses)
+			}
+		}
+		mutex.Unlock()
+	}
+}
+
+// Tests least recently used objects are evicted from the cache.
+func TestDownloadCacheEviction(t *testing.T) {
+	cache := NewMemoryDownloadCache(10)
+	for _, key := range []string{"a", "b", "c"} {
+		w := cache.newWriter(key, ObjectInfo{ETag: key, Size: 4})
+		w.write([]byte("data"))
+		w.commit()
+		if key == "b" {
+			// Use a so that b is the least recently used.
+			if _, reader, ok := cache.open("a"); !ok {
+				t.Fatal("expected a to be cached")
+			} else {
+				reader.Close()
+			}
+		}
+	}
+	if cache.Len() != 2 || cache.Size() != 8 {
+		t.Fatalf("expected 2 objects of 8 bytes, got %d objects of %d bytes", cache.Len(), cache.Size())
+	}
+	if _, _, ok := cache.open("b"); ok {
+		t.Fatal("expected b to be evicted")
+	}
+	if w := cache.newWriter("d", ObjectInfo{ETag: "d", Size: 11}); w != nil {
+		t.Fatal("expected objects larger than the cache not to be cached")
+	}
+}
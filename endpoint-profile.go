@@ -0,0 +1,187 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/minio/minio-go/v6/pkg/s3utils"
+)
+
+// EndpointProfile is the type of S3 compatible service a client
+// talks to, known deviations of the service from Amazon S3 are
+// handled according to it.
+type EndpointProfile int
+
+// Different endpoint profiles. EndpointProfileAuto determines the
+// profile from the endpoint host, EndpointProfileDefault treats the
+// service as Amazon S3 or MinIO.
+const (
+	EndpointProfileAuto EndpointProfile = iota
+	EndpointProfileDefault
+	EndpointProfileR2
+	EndpointProfileGCS
+	EndpointProfileCeph
+	EndpointProfileB2
+)
+
+// endpointProfile - deviations of an S3 compatible service from
+// Amazon S3.
+type endpointProfile struct {
+	// Name of the service used in error messages.
+	name string
+
+	// Region requests are signed with if the client has no custom
+	// region, buckets are not located when set.
+	region string
+
+	// Matches hosts of the service, the first submatch is the
+	// region of the endpoint used instead of region.
+	hostRegion *regexp.Regexp
+
+	// Buckets are addressed in virtual host style by default.
+	virtualHostStyle bool
+
+	// Conditional writes, If-Match and If-None-Match headers of
+	// PUT and POST requests, are ignored by the service.
+	noConditionalWrites bool
+
+	// Uploads cannot be signed with the streaming signature.
+	noStreamingSignature bool
+
+	// Objects are listed with ListObjects as ListObjectsV2 is not
+	// fully supported.
+	listObjectsV1 bool
+
+	// Parts of multipart uploads cannot be copied from objects.
+	noUploadPartCopy bool
+
+	// Deviations of older gateways are tolerated, bucket locations
+	// may be missing, ETags may not be MD5 sums of the objects and
+	// multipart responses may lack optional elements. Responses of
+	// Ceph RGW are always treated this way.
+	legacyGateway bool
+}
+
+// endpointProfiles - profiles of the supported services.
+var endpointProfiles = map[EndpointProfile]endpointProfile{
+	EndpointProfileDefault: {},
+	// Cloudflare R2 has no bucket locations, requests are signed
+	// with the region 'auto'.
+	EndpointProfileR2: {
+		name:                "Cloudflare R2",
+		region:              "auto",
+		noConditionalWrites: true,
+	},
+	// Google Cloud Storage works with any region in signatures,
+	// bucket locations are multi-region names such as 'US'.
+	EndpointProfileGCS: {
+		name:                 "Google Cloud Storage",
+		region:               "auto",
+		noStreamingSignature: true,
+		listObjectsV1:        true,
+		noUploadPartCopy:     true,
+	},
+	EndpointProfileCeph: {
+		name:          "Ceph RGW",
+		legacyGateway: true,
+	},
+	// Backblaze B2 has no bucket locations, all buckets of an
+	// account are in the region of its endpoint. The part sizes
+	// used by the client meet its minimum part size of 5MB.
+	EndpointProfileB2: {
+		name:             "Backblaze B2",
+		hostRegion:       b2Host,
+		virtualHostStyle: true,
+	},
+}
+
+// b2Host - regular expression used to determine if an arg is a
+// Backblaze B2 host, e.g. 's3.us-west-002.backblazeb2.com'.
+var b2Host = regexp.MustCompile(`^s3\.([a-z0-9-]+)\.backblazeb2\.com$`)
+
+// detectEndpointProfile - returns the profile of the service at
+// endpointURL.
+func detectEndpointProfile(endpointURL url.URL) EndpointProfile {
+	host := endpointURL.Hostname()
+	switch {
+	case strings.HasSuffix(host, ".r2.cloudflarestorage.com"):
+		return EndpointProfileR2
+	case s3utils.IsGoogleEndpoint(endpointURL):
+		return EndpointProfileGCS
+	case b2Host.MatchString(host):
+		return EndpointProfileB2
+	}
+	return EndpointProfileDefault
+}
+
+// applyEndpointProfile - sets the profile of the client, and its
+// region to region, the region of the endpoint or the region of the
+// profile, whichever is set first.
+func (c *Client) applyEndpointProfile(profile EndpointProfile, region string) {
+	if profile == EndpointProfileAuto {
+		profile = detectEndpointProfile(*c.endpointURL)
+	}
+	c.profile = endpointProfiles[profile]
+
+	// Sets custom region, if region is empty bucket location cache is used automatically.
+	if region == "" {
+		region = s3utils.GetRegionFromURL(*c.endpointURL)
+	}
+	if region == "" && c.profile.hostRegion != nil {
+		if parts := c.profile.hostRegion.FindStringSubmatch(c.endpointURL.Hostname()); len(parts) > 1 {
+			region = parts[1]
+		}
+	}
+	if region == "" {
+		region = c.profile.region
+	}
+	c.region = region
+}
+
+// isLegacyGateway - returns true if deviations of older gateways are
+// tolerated for a response with header.
+func (c Client) isLegacyGateway(header http.Header) bool {
+	return c.profile.legacyGateway || isCephResponse(header)
+}
+
+// isCephResponse - returns true if the response with header was sent
+// by Ceph RGW, which identifies itself in the Server header of newer
+// releases and with request ids such as
+// 'tx000000000000000000001-005d2b8f2a-1034-default'.
+func isCephResponse(header http.Header) bool {
+	if strings.HasPrefix(header.Get("Server"), "Ceph") {
+		return true
+	}
+	requestID := header.Get("X-Amz-Request-Id")
+	return strings.HasPrefix(requestID, "tx") && strings.Count(requestID, "-") >= 2
+}
+
+// checkEndpointProfile - verifies that a request can be handled by
+// the service, requests which would not behave as expected fail.
+func (c Client) checkEndpointProfile(method string, metadata requestMetadata) error {
+	if c.profile.noConditionalWrites && (method == "PUT" || method == "POST") {
+		if metadata.customHeader.Get("If-Match") != "" || metadata.customHeader.Get("If-None-Match") != "" {
+			return ErrAPINotSupported("Conditional writes are not supported by " + c.profile.name + ".")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,281 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/v6/pkg/credentials"
+)
+
+// Tests detection of endpoint profiles.
+func TestDetectEndpointProfile(t *testing.T) {
+	testCases := []struct {
+		host    string
+		profile EndpointProfile
+	}{
+		{"account.r2.cloudflarestorage.com", EndpointProfileR2},
+		{"account.eu.r2.cloudflarestorage.com:443", EndpointProfileR2},
+		{"r2.cloudflarestorage.com.example.com", EndpointProfileDefault},
+		{"storage.googleapis.com", EndpointProfileGCS},
+		{"s3.us-west-002.backblazeb2.com", EndpointProfileB2},
+		{"s3.backblazeb2.com", EndpointProfileDefault},
+		{"s3.amazonaws.com", EndpointProfileDefault},
+		{"localhost:9000", EndpointProfileDefault},
+	}
+	for i, testCase := range testCases {
+		if profile := detectEndpointProfile(url.URL{Host: testCase.host}); profile != testCase.profile {
+			t.Errorf("Test %d: expected profile %d, got %d", i+1, testCase.profile, profile)
+		}
+	}
+
+	c, err := New("account.r2.cloudflarestorage.com", "access", "secret", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.region != "auto" {
+		t.Errorf("expected region auto, got %q", c.region)
+	}
+	c, err = NewWithRegion("account.r2.cloudflarestorage.com", "access", "secret", true, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.region != "us-east-1" {
+		t.Errorf("expected custom region, got %q", c.region)
+	}
+}
+
+// Tests requests to Cloudflare R2.
+func TestEndpointProfileR2(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RawQuery)
+		if !strings.Contains(r.Header.Get("Authorization"), "/auto/s3/") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+	}))
+	defer server.Close()
+
+	c, err := NewWithOptions(strings.TrimPrefix(server.URL, "http://"), &Options{
+		Creds:           credentials.NewStaticV4("access", "secret", ""),
+		EndpointProfile: EndpointProfileR2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.StatObject("bucket", "object", StatObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 1 || requests[0] != "HEAD " {
+		t.Errorf("expected a single HEAD request without location lookup, got %q", requests)
+	}
+
+	// Conditional writes fail without a request.
+	requests = nil
+	_, err = c.PutObject("bucket", "object", bytes.NewReader([]byte("data")), 4, PutObjectOptions{CreateOnly: true})
+	if ToErrorResponse(err).Code != "APINotSupported" || len(requests) != 0 {
+		t.Errorf("expected APINotSupported without requests, got %v, %q", err, requests)
+	}
+	if _, err = c.PutObject("bucket", "object", bytes.NewReader([]byte("data")), 4, PutObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Tests requests to Google Cloud Storage.
+func TestEndpointProfileGCS(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, fmt.Sprintf("%s %s %s %d", r.Method, r.URL.RawQuery, r.Header.Get("X-Amz-Content-Sha256"), len(body)))
+		switch {
+		case r.Method == http.MethodGet:
+			fmt.Fprint(w, "<ListBucketResult><Name>bucket</Name><Contents><Key>object</Key></Contents></ListBucketResult>")
+		case r.Method == http.MethodHead:
+			w.Header().Set("ETag", `"etag"`)
+			w.Header().Set("Content-Length", "6291456")
+			w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		default:
+			w.Header().Set("ETag", `"etag"`)
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewWithOptions(strings.TrimPrefix(server.URL, "http://"), &Options{
+		Creds:           credentials.NewStaticV4("access", "secret", ""),
+		EndpointProfile: EndpointProfileGCS,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Buckets are created without location, uploads are not
+	// signed with the streaming signature.
+	if err = c.MakeBucket("bucket", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.PutObject("bucket", "object", bytes.NewReader([]byte("data")), 4, PutObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"PUT  " + unsignedPayload + " 0",
+		"PUT  " + unsignedPayload + " 4",
+	}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Errorf("expected requests %q, got %q", expected, requests)
+	}
+
+	// Objects are listed with version 1 of the listing.
+	requests = nil
+	for object := range c.ListObjectsV2("bucket", "", true, nil) {
+		if object.Err != nil || object.Key != "object" {
+			t.Fatalf("unexpected object %+v", object)
+		}
+	}
+	if len(requests) != 1 || strings.Contains(requests[0], "list-type") {
+		t.Errorf("expected a version 1 listing, got %q", requests)
+	}
+
+	// Composing objects of multiple parts fails.
+	src1, src2 := NewSourceInfo("bucket", "a", nil), NewSourceInfo("bucket", "b", nil)
+	dst, err := NewDestinationInfo("bucket", "c", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = c.ComposeObject(dst, []SourceInfo{src1, src2}); ToErrorResponse(err).Code != "APINotSupported" {
+		t.Errorf("expected APINotSupported, got %v", err)
+	}
+}
+
+// Tests tolerance of Ceph RGW deviations.
+func TestEndpointProfileCeph(t *testing.T) {
+	testCases := []struct {
+		header http.Header
+		ceph   bool
+	}{
+		{http.Header{"Server": []string{"Ceph Object Gateway (quincy)"}}, true},
+		{http.Header{"X-Amz-Request-Id": []string{"tx000000000000000000001-005d2b8f2a-1034-default"}}, true},
+		{http.Header{"X-Amz-Request-Id": []string{"15C9A54C62B4D0A8"}}, false},
+		{http.Header{"Server": []string{"MinIO"}}, false},
+	}
+	for i, testCase := range testCases {
+		if ceph := isCephResponse(testCase.header); ceph != testCase.ceph {
+			t.Errorf("Test %d: expected %v, got %v", i+1, testCase.ceph, ceph)
+		}
+	}
+
+	const data = "data"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Amz-Request-Id", "tx000000000000000000001-005d2b8f2a-1034-default")
+		query := r.URL.Query()
+		switch {
+		case r.URL.RawQuery == "location=":
+			// No location constraint.
+		case r.Method == http.MethodGet && query.Get("uploadId") != "":
+			// No next part number marker.
+			if query.Get("part-number-marker") == "0" {
+				fmt.Fprint(w, "<ListPartsResult><IsTruncated>true</IsTruncated><Part><PartNumber>1</PartNumber><Size>5</Size></Part></ListPartsResult>")
+			} else {
+				fmt.Fprint(w, "<ListPartsResult><Part><PartNumber>2</PartNumber><Size>5</Size></Part></ListPartsResult>")
+			}
+		case r.Method == http.MethodPost:
+			// No bucket in the result.
+			fmt.Fprint(w, "<CompleteMultipartUploadResult><ETag>\"etag\"</ETag></CompleteMultipartUploadResult>")
+		default:
+			// ETag which is not the MD5 sum of the object.
+			w.Header().Set("ETag", `"0123456789abcdef0123456789abcdef"`)
+			w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+			fmt.Fprint(w, data)
+		}
+	}))
+	defer server.Close()
+
+	c, err := New(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := GetObjectOptions{VerifyChecksum: true}
+	reader, err := c.GetObject("bucket", "object", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b, err := ioutil.ReadAll(reader); err != nil || string(b) != data {
+		t.Fatalf("unexpected object %q, %v", b, err)
+	}
+	reader.Close()
+	if location, _ := c.bucketLocCache.Get("bucket"); location != "us-east-1" {
+		t.Errorf("expected location us-east-1, got %q", location)
+	}
+
+	parts, err := c.listObjectParts("bucket", "object", "upload")
+	if err != nil || len(parts) != 2 {
+		t.Fatalf("expected two parts, got %v, %v", parts, err)
+	}
+	if _, err = (Core{c}).CompleteMultipartUpload("bucket", "object", "upload", []CompletePart{{PartNumber: 1, ETag: "etag"}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Tests addressing of Backblaze B2 buckets.
+func TestEndpointProfileB2(t *testing.T) {
+	c, err := New("s3.us-west-002.backblazeb2.com", "access", "secret", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The region is known, buckets are not located.
+	if location, err := c.getBucketLocation("bucket"); err != nil || location != "us-west-002" {
+		t.Fatalf("expected location us-west-002, got %q, %v", location, err)
+	}
+
+	testCases := []struct {
+		bucketName string
+		url        string
+	}{
+		{"bucket", "https://bucket.s3.us-west-002.backblazeb2.com/object"},
+		{"my.bucket", "https://s3.us-west-002.backblazeb2.com/my.bucket/object"},
+	}
+	for i, testCase := range testCases {
+		isVirtualHost := c.isVirtualHostStyleRequest(*c.endpointURL, testCase.bucketName)
+		u, err := c.makeTargetURL(testCase.bucketName, "object", c.region, isVirtualHost, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.String() != testCase.url {
+			t.Errorf("Test %d: expected %s, got %s", i+1, testCase.url, u)
+		}
+	}
+
+	// Path style can still be requested.
+	c, err = NewWithOptions("s3.us-west-002.backblazeb2.com", &Options{Secure: true, BucketLookup: BucketLookupPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.isVirtualHostStyleRequest(*c.endpointURL, "bucket") {
+		t.Error("expected path style requests")
+	}
+}
@@ -20,6 +20,7 @@ package minio
 import (
 	"fmt"
 	"testing"
+	"time"
 )
 
 func TestSetHeader(t *testing.T) {
@@ -55,3 +56,28 @@ func TestSetHeader(t *testing.T) {
 		}
 	}
 }
+
+func TestConditionalHeader(t *testing.T) {
+	modTime := time.Date(2019, time.March, 10, 12, 30, 0, 0, time.FixedZone("IST", 19800))
+	testCases := []struct {
+		opts     GetObjectOptions
+		key      string
+		expected string
+	}{
+		{GetObjectOptions{MatchETag: "abc"}, "If-Match", "\"abc\""},
+		{GetObjectOptions{MatchETag: "\"abc\""}, "If-Match", "\"abc\""},
+		{GetObjectOptions{NotMatchETag: "abc"}, "If-None-Match", "\"abc\""},
+		{GetObjectOptions{NotMatchETag: "*"}, "If-None-Match", "*"},
+		{GetObjectOptions{ModifiedSince: modTime}, "If-Modified-Since", "Sun, 10 Mar 2019 07:00:00 GMT"},
+		{GetObjectOptions{UnmodifiedSince: modTime}, "If-Unmodified-Since", "Sun, 10 Mar 2019 07:00:00 GMT"},
+		{GetObjectOptions{}, "If-Match", ""},
+		{GetObjectOptions{Extract: true}, "X-Minio-Extract", "true"},
+		{GetObjectOptions{}, "X-Minio-Extract", ""},
+	}
+	for i, testCase := range testCases {
+		if value := testCase.opts.Header().Get(testCase.key); value != testCase.expected {
+			t.Errorf("Test %d: Expected %s header '%s', but got '%s'",
+				i+1, testCase.key, testCase.expected, value)
+		}
+	}
+}
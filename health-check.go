@@ -0,0 +1,173 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Results of the health check of the endpoint.
+const (
+	healthUnknown int32 = iota
+	healthOnline
+	healthOffline
+)
+
+// healthStatus - state of the health check, shared by the copies of
+// a client.
+type healthStatus struct {
+	status  int32
+	running int32
+
+	// Index of the endpoint reads are sent to, zero for the endpoint
+	// of the client and i+1 for its i-th read endpoint.
+	readEndpoint int32
+}
+
+// HealthCheck - starts probing the endpoint every interval in the
+// background, IsOnline and IsOffline report the result of the last
+// probe. The endpoint is online as long as it responds, whatever the
+// response. The read endpoints of the client are probed as well, and
+// reads are sent to the online endpoint with the lowest latency. The
+// returned function stops the health check, an error is returned if
+// the health check is already running.
+func (c Client) HealthCheck(interval time.Duration) (context.CancelFunc, error) {
+	if interval < time.Second {
+		return nil, ErrInvalidArgument("Health check interval cannot be less than a second.")
+	}
+	if !atomic.CompareAndSwapInt32(&c.health.running, 0, 1) {
+		return nil, ErrInvalidArgument("Health check is already running.")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		defer func() {
+			atomic.StoreInt32(&c.health.status, healthUnknown)
+			atomic.StoreInt32(&c.health.readEndpoint, 0)
+			atomic.StoreInt32(&c.health.running, 0)
+		}()
+		// Smoothed latencies of the endpoints, negative while offline.
+		latencies := make([]time.Duration, len(c.readEndpoints)+1)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			c.probeHealth(ctx, interval, latencies)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return cancel, nil
+}
+
+// probeHealth - probes all the endpoints concurrently, records the
+// status of the endpoint of the client and selects the endpoint
+// reads are sent to.
+func (c Client) probeHealth(ctx context.Context, timeout time.Duration, latencies []time.Duration) {
+	endpoints := append([]*url.URL{c.endpointURL}, c.readEndpoints...)
+	samples := make([]time.Duration, len(endpoints))
+	var wg sync.WaitGroup
+	for i, endpointURL := range endpoints {
+		wg.Add(1)
+		go func(i int, endpointURL *url.URL) {
+			defer wg.Done()
+			samples[i] = c.probeEndpoint(ctx, endpointURL, timeout)
+		}(i, endpointURL)
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		// The health check was stopped.
+		return
+	}
+
+	for i, sample := range samples {
+		switch {
+		case sample < 0, latencies[i] <= 0:
+			latencies[i] = sample
+		default:
+			latencies[i] = (3*latencies[i] + sample) / 4
+		}
+	}
+
+	if latencies[0] < 0 {
+		atomic.StoreInt32(&c.health.status, healthOffline)
+	} else {
+		atomic.StoreInt32(&c.health.status, healthOnline)
+	}
+
+	// Prefer the endpoint of the client when no endpoint is online.
+	fastest := 0
+	for i, latency := range latencies {
+		if latency > 0 && (latencies[fastest] <= 0 || latency < latencies[fastest]) {
+			fastest = i
+		}
+	}
+	atomic.StoreInt32(&c.health.readEndpoint, int32(fastest))
+}
+
+// probeEndpoint - sends a HEAD request to the root of an endpoint and
+// returns the round-trip time, or -1 if no response was received
+// before the timeout.
+func (c Client) probeEndpoint(ctx context.Context, endpointURL *url.URL, timeout time.Duration) time.Duration {
+	c.endpointURL = endpointURL
+	req, err := c.newRequest("HEAD", requestMetadata{
+		contentSHA256Hex: emptySHA256Hex,
+	})
+	if err != nil {
+		return -1
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	resp, err := c.do(req.WithContext(ctx))
+	closeResponse(resp)
+	if err != nil {
+		return -1
+	}
+	// Zero marks latencies which are not measured yet.
+	if latency := time.Since(start); latency > 0 {
+		return latency
+	}
+	return 1
+}
+
+// readEndpointURL - returns the endpoint reads are sent to.
+func (c Client) readEndpointURL() *url.URL {
+	if i := atomic.LoadInt32(&c.health.readEndpoint); i > 0 && int(i) <= len(c.readEndpoints) {
+		return c.readEndpoints[i-1]
+	}
+	return c.endpointURL
+}
+
+// IsOnline - returns true unless the last probe of the health check
+// failed, it is always true if the health check is not running.
+func (c Client) IsOnline() bool {
+	return !c.IsOffline()
+}
+
+// IsOffline - returns true if the last probe of the health check
+// failed.
+func (c Client) IsOffline() bool {
+	return atomic.LoadInt32(&c.health.status) == healthOffline
+}
@@ -0,0 +1,153 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v6/pkg/credentials"
+)
+
+// Tests the health check following the availability of the endpoint.
+func TestHealthCheck(t *testing.T) {
+	probed := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead || r.URL.Path != "/" {
+			t.Errorf("Expected HEAD /, got %s %s", r.Method, r.URL.Path)
+		}
+		// Any response means that the endpoint is online.
+		w.WriteHeader(http.StatusForbidden)
+		select {
+		case probed <- struct{}{}:
+		default:
+		}
+	}))
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.HealthCheck(time.Millisecond); err == nil {
+		t.Errorf("Expected intervals below a second to be rejected")
+	}
+	if !c.IsOnline() {
+		t.Errorf("Expected the endpoint to be online without health check")
+	}
+
+	stop, err := c.HealthCheck(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+	if _, err = c.HealthCheck(time.Second); err == nil {
+		t.Errorf("Expected a second health check to be rejected")
+	}
+	<-probed
+	if !c.IsOnline() || c.IsOffline() {
+		t.Errorf("Expected the endpoint to be online")
+	}
+
+	server.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	for !c.IsOffline() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if c.IsOnline() || !c.IsOffline() {
+		t.Errorf("Expected the endpoint to be offline")
+	}
+}
+
+// Tests sending reads to the fastest online endpoint.
+func TestReadEndpoints(t *testing.T) {
+	var mutex sync.Mutex
+	var requests []string
+	newServer := func(name string, delay time.Duration) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/" {
+				time.Sleep(delay)
+				return
+			}
+			mutex.Lock()
+			requests = append(requests, name+" "+r.Method)
+			mutex.Unlock()
+			w.Header().Set("ETag", `"etag"`)
+			w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		}))
+	}
+	primary := newServer("primary", 100*time.Millisecond)
+	defer primary.Close()
+	fast := newServer("fast", 0)
+	defer fast.Close()
+	offline := newServer("offline", 0)
+	offline.Close()
+
+	c, err := NewWithOptions(strings.TrimPrefix(primary.URL, "http://"), &Options{
+		Creds:         credentials.NewStaticV4("access", "secret", ""),
+		Region:        "us-east-1",
+		ReadEndpoints: []string{offline.URL, fast.URL},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func() []string {
+		mutex.Lock()
+		requests = nil
+		mutex.Unlock()
+		if _, err := c.StatObject("bucket", "object", StatObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.PutObject("bucket", "object", bytes.NewReader(nil), 0, PutObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		mutex.Lock()
+		defer mutex.Unlock()
+		return requests
+	}
+
+	// All requests are sent to the endpoint of the client without
+	// health check.
+	if requests := do(); strings.Join(requests, ",") != "primary HEAD,primary PUT" {
+		t.Errorf("Expected requests to the endpoint of the client, got %q", requests)
+	}
+
+	stop, err := c.HealthCheck(time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for c.readEndpointURL() == c.endpointURL && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if requests := do(); strings.Join(requests, ",") != "fast HEAD,primary PUT" {
+		t.Errorf("Expected reads to be sent to the fastest endpoint, got %q", requests)
+	}
+
+	stop()
+	for c.readEndpointURL() != c.endpointURL && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if requests := do(); strings.Join(requests, ",") != "primary HEAD,primary PUT" {
+		t.Errorf("Expected reads to be sent to the endpoint of the client once stopped, got %q", requests)
+	}
+}
@@ -0,0 +1,173 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"os"
+)
+
+// minPartBufferSize - size of the buffer first allocated for the parts
+// of an upload, it grows as needed up to the memory size of the parts.
+const minPartBufferSize = 64 * 1024
+
+// partBuffer - buffers one part at a time of an upload of data which
+// cannot be read at an offset. Up to PutObjectOptions.MaxMemoryBuffer
+// bytes of each part are kept in memory, the rest of larger parts is
+// spilled to a temporary file which is reused for all parts of the
+// upload. The buffer grows from minPartBufferSize as data is read, so
+// that small streams do not allocate whole parts, and it is taken from
+// the pool once it reaches its full size.
+type partBuffer struct {
+	pool       BufferPool
+	buf        []byte
+	memorySize int64 // Full size of the buffer.
+	partSize   int64
+	spillDir   string
+	file       *os.File // Created when the first part is spilled.
+	length     int64    // Length of the current part.
+}
+
+// newPartBuffer - returns a partBuffer for parts of partSize bytes,
+// it must be closed once the upload is done.
+func newPartBuffer(pool BufferPool, partSize int64, opts PutObjectOptions) *partBuffer {
+	memorySize := partSize
+	if opts.MaxMemoryBuffer > 0 && int64(opts.MaxMemoryBuffer) < partSize {
+		memorySize = int64(opts.MaxMemoryBuffer)
+	}
+	b := &partBuffer{
+		pool:       pool,
+		memorySize: memorySize,
+		partSize:   partSize,
+		spillDir:   opts.SpillDir,
+	}
+	if memorySize <= minPartBufferSize {
+		b.buf = pool.Get(int(memorySize))
+	} else {
+		b.buf = make([]byte, minPartBufferSize)
+	}
+	return b
+}
+
+// grow - doubles the size of the buffer up to its full size, keeping
+// the data of the current part.
+func (b *partBuffer) grow() {
+	size := 2 * int64(len(b.buf))
+	var buf []byte
+	if size >= b.memorySize {
+		buf = b.pool.Get(int(b.memorySize))
+	} else {
+		buf = make([]byte, size)
+	}
+	copy(buf, b.buf[:b.length])
+	b.buf = buf
+}
+
+// readFull reads the next part from reader. Like io.ReadFull it
+// returns io.EOF if nothing was read and io.ErrUnexpectedEOF if the
+// reader ended within the part.
+func (b *partBuffer) readFull(reader io.Reader) (length int64, err error) {
+	b.length = 0
+	for {
+		var n int
+		n, err = io.ReadFull(reader, b.buf[b.length:])
+		b.length += int64(n)
+		if err == io.EOF && b.length > 0 {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil || b.length == b.partSize {
+			return b.length, err
+		}
+		if int64(len(b.buf)) == b.memorySize {
+			break
+		}
+		b.grow()
+	}
+
+	// The part does not fit in memory, spill it to the file.
+	if b.file == nil {
+		if b.file, err = ioutil.TempFile(b.spillDir, "minio-go-part-"); err != nil {
+			return 0, err
+		}
+	}
+	if _, err = b.file.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	if _, err = b.file.Write(b.buf); err != nil {
+		return 0, err
+	}
+	m, err := io.CopyN(b.file, reader, b.partSize-b.length)
+	b.length += m
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return b.length, err
+}
+
+// reader - returns a reader of the current part.
+func (b *partBuffer) reader() io.ReadSeeker {
+	if b.length <= int64(len(b.buf)) {
+		return bytes.NewReader(b.buf[:b.length])
+	}
+	return io.NewSectionReader(b.file, 0, b.length)
+}
+
+// writeTo - writes the current part to w.
+func (b *partBuffer) writeTo(w io.Writer) error {
+	_, err := io.Copy(w, b.reader())
+	return err
+}
+
+// sumMD5Base64 - returns the base64 encoded MD5 sum of the current part.
+func (b *partBuffer) sumMD5Base64() (string, error) {
+	hash := md5.New()
+	if err := b.writeTo(hash); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(hash.Sum(nil)), nil
+}
+
+// sumCRC32CBase64 - returns the base64 encoded CRC32C checksum of the
+// current part.
+func (b *partBuffer) sumCRC32CBase64() (string, error) {
+	hash := newCRC32C()
+	if err := b.writeTo(hash); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Close - releases the buffer and removes the temporary file.
+func (b *partBuffer) Close() error {
+	// Only buffers of full size were taken from the pool.
+	if int64(len(b.buf)) == b.memorySize {
+		b.pool.Put(b.buf)
+	}
+	if b.file == nil {
+		return nil
+	}
+	err := b.file.Close()
+	if rerr := os.Remove(b.file.Name()); err == nil {
+		err = rerr
+	}
+	return err
+}
@@ -0,0 +1,184 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+// Tests reading parts through a partBuffer with and without spilling.
+func TestPartBuffer(t *testing.T) {
+	data := []byte("0123456789abcdefghijklm")
+	testCases := []struct {
+		maxMemory uint64
+		bufSize   int
+		spilled   bool
+	}{
+		{0, 10, false},
+		{20, 10, false},
+		{4, 4, true},
+	}
+	for i, testCase := range testCases {
+		dir, err := ioutil.TempDir("", "part-buffer-")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		pool := &countingBufferPool{}
+		partBuf := newPartBuffer(pool, 10, PutObjectOptions{MaxMemoryBuffer: testCase.maxMemory, SpillDir: dir})
+		if len(partBuf.buf) != testCase.bufSize {
+			t.Errorf("Test %d: expected a buffer of %d bytes, got %d", i+1, testCase.bufSize, len(partBuf.buf))
+		}
+
+		reader := bytes.NewReader(data)
+		var parts []string
+		for {
+			length, err := partBuf.readFull(reader)
+			if err == io.EOF {
+				break
+			}
+			if err != nil && err != io.ErrUnexpectedEOF {
+				t.Fatalf("Test %d: %v", i+1, err)
+			}
+			part, _ := ioutil.ReadAll(partBuf.reader())
+			if int64(len(part)) != length {
+				t.Errorf("Test %d: expected a part of %d bytes, got %d", i+1, length, len(part))
+			}
+			md5Base64, err := partBuf.sumMD5Base64()
+			if err != nil || md5Base64 != sumMD5Base64(part) {
+				t.Errorf("Test %d: unexpected MD5 sum %q, %v", i+1, md5Base64, err)
+			}
+			parts = append(parts, string(part))
+		}
+		if expected := []string{"0123456789", "abcdefghij", "klm"}; !reflect.DeepEqual(parts, expected) {
+			t.Errorf("Test %d: expected parts %q, got %q", i+1, expected, parts)
+		}
+
+		if spilled := partBuf.file != nil; spilled != testCase.spilled {
+			t.Errorf("Test %d: expected spilled %t, got %t", i+1, testCase.spilled, spilled)
+		}
+		if err = partBuf.Close(); err != nil {
+			t.Errorf("Test %d: %v", i+1, err)
+		}
+		if files, _ := ioutil.ReadDir(dir); len(files) != 0 {
+			t.Errorf("Test %d: expected the temporary file to be removed, found %d files", i+1, len(files))
+		}
+		if pool.inUse != 0 {
+			t.Errorf("Test %d: expected the buffer to be released", i+1)
+		}
+	}
+}
+
+// Tests that uploads of unknown size spill parts beyond the memory limit.
+func TestPutObjectSpill(t *testing.T) {
+	server := newUploadTestServer()
+	defer server.Close()
+	c := server.client(t)
+	pool := &countingBufferPool{}
+	c.SetBufferPool(pool)
+
+	dir, err := ioutil.TempDir("", "put-object-spill-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	const partSize = absMinPartSize
+	data := make([]byte, partSize+10)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	reader := struct{ io.Reader }{bytes.NewReader(data)}
+	// Send the data without chunk signatures to verify it.
+	opts := PutObjectOptions{PartSize: partSize, MaxMemoryBuffer: 1024, SpillDir: dir, DisableContentSha256: true}
+	if _, err = c.PutObject("bucket", "object", reader, -1, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"initiate", fmt.Sprintf("part 1 %d", partSize), "part 2 10", "complete"}
+	if requests := server.takeRequests(); !reflect.DeepEqual(requests, expected) {
+		t.Errorf("expected requests %v, got %v", expected, requests)
+	}
+	if !bytes.Equal(server.takePayload(), data) {
+		t.Error("uploaded data does not match")
+	}
+	if files, _ := ioutil.ReadDir(dir); len(files) != 0 {
+		t.Errorf("expected the temporary file to be removed, found %d files", len(files))
+	}
+	if pool.gets != 1 || pool.inUse != 0 {
+		t.Errorf("expected 1 buffer used and released, got %d with %d in use", pool.gets, pool.inUse)
+	}
+}
+
+// Tests that tiny streams do not allocate whole parts, parts of
+// unknown size uploads are 576MiB by default.
+func TestPartBufferGrow(t *testing.T) {
+	_, partSize, _, err := optimalPartInfo(-1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := &countingBufferPool{}
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	partBuf := newPartBuffer(pool, partSize, PutObjectOptions{})
+	length, err := partBuf.readFull(bytes.NewReader([]byte("tiny")))
+	runtime.ReadMemStats(&after)
+	if length != 4 || err != io.ErrUnexpectedEOF {
+		t.Fatalf("expected a part of 4 bytes, got %d, %v", length, err)
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+		t.Errorf("expected less than 1MiB allocated, got %d bytes", allocated)
+	}
+	if err = partBuf.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if pool.gets != 0 || pool.inUse != 0 {
+		t.Errorf("expected no buffer from the pool, got %d with %d in use", pool.gets, pool.inUse)
+	}
+
+	// Buffers grow up to the full size, taken from the pool.
+	partBuf = newPartBuffer(pool, 1<<20, PutObjectOptions{})
+	data := make([]byte, 1<<20+1)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	reader := bytes.NewReader(data)
+	if length, err = partBuf.readFull(reader); length != 1<<20 || err != nil {
+		t.Fatalf("expected a part of 1MiB, got %d, %v", length, err)
+	}
+	if part, _ := ioutil.ReadAll(partBuf.reader()); !bytes.Equal(part, data[:1<<20]) {
+		t.Error("part data does not match")
+	}
+	if length, err = partBuf.readFull(reader); length != 1 || err != io.ErrUnexpectedEOF {
+		t.Fatalf("expected a part of 1 byte, got %d, %v", length, err)
+	}
+	if err = partBuf.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if pool.gets != 1 || pool.inUse != 0 {
+		t.Errorf("expected 1 buffer used and released, got %d with %d in use", pool.gets, pool.inUse)
+	}
+}
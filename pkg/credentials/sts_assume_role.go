@@ -0,0 +1,246 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package credentials
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v6/pkg/s3signer"
+)
+
+// Limits of the session tags and managed policies of AssumeRole.
+const (
+	maxAssumeRoleTags       = 50
+	maxAssumeRolePolicyARNs = 10
+)
+
+// AssumeRoleResponse contains the result of successful AssumeRole request.
+type AssumeRoleResponse struct {
+	XMLName          xml.Name         `xml:"https://sts.amazonaws.com/doc/2011-06-15/ AssumeRoleResponse" json:"-"`
+	Result           AssumeRoleResult `xml:"AssumeRoleResult"`
+	ResponseMetadata struct {
+		RequestID string `xml:"RequestId,omitempty"`
+	} `xml:"ResponseMetadata,omitempty"`
+}
+
+// AssumeRoleResult - Contains the response to a successful AssumeRole
+// request, including temporary credentials that can be used to make
+// S3 API requests.
+type AssumeRoleResult struct {
+	AssumedRoleUser AssumedRoleUser `xml:",omitempty"`
+	Credentials     struct {
+		AccessKey    string    `xml:"AccessKeyId" json:"accessKey,omitempty"`
+		SecretKey    string    `xml:"SecretAccessKey" json:"secretKey,omitempty"`
+		Expiration   time.Time `xml:"Expiration" json:"expiration,omitempty"`
+		SessionToken string    `xml:"SessionToken" json:"sessionToken,omitempty"`
+	} `xml:",omitempty"`
+	PackedPolicySize int    `xml:",omitempty"`
+	SourceIdentity   string `xml:",omitempty"`
+}
+
+// STSAssumeRoleOptions - the credentials the role is assumed with and
+// the scope of the session.
+type STSAssumeRoleOptions struct {
+	// Long lived credentials, or temporary credentials with their
+	// session token, of the caller.
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+
+	// Region the request is signed for, 'us-east-1' by default.
+	Location string
+
+	RoleARN         string
+	RoleSessionName string
+	// Duration of the session, the default of the service if zero.
+	DurationSeconds int
+
+	// ExternalID - identifier the trust policy of a role of another
+	// account may require, https://docs.aws.amazon.com/IAM/latest/UserGuide/id_roles_create_for-user_externalid.html
+	ExternalID string
+	// SourceIdentity - identity of the caller recorded with all the
+	// actions of the session and of roles assumed from it.
+	SourceIdentity string
+
+	// Tags - session tags, passed to roles assumed from the session
+	// if their keys are listed in TransitiveTagKeys.
+	Tags              map[string]string
+	TransitiveTagKeys []string
+
+	// Policy - inline JSON policy and PolicyARNs - managed policies,
+	// both limiting the permissions of the session to those also
+	// granted by the role.
+	Policy     string
+	PolicyARNs []string
+}
+
+// A STSAssumeRole retrieves credentials from the AssumeRole API of an
+// STS service, and keeps track if those credentials are expired.
+type STSAssumeRole struct {
+	Expiry
+
+	// Required http Client to use when connecting to the STS service.
+	Client *http.Client
+
+	// STS endpoint to fetch credentials from.
+	STSEndpoint string
+
+	// Options of the role and the session.
+	Options STSAssumeRoleOptions
+}
+
+// NewSTSAssumeRole returns a pointer to a new
+// Credentials object wrapping the STSAssumeRole.
+func NewSTSAssumeRole(stsEndpoint string, opts STSAssumeRoleOptions) (*Credentials, error) {
+	if stsEndpoint == "" {
+		return nil, errors.New("STS endpoint cannot be empty")
+	}
+	if opts.AccessKey == "" || opts.SecretKey == "" {
+		return nil, errors.New("AssumeRole credentials access/secretkey is mandatory")
+	}
+	if len(opts.Tags) > maxAssumeRoleTags {
+		return nil, errors.New("AssumeRole sessions cannot have more than 50 tags")
+	}
+	for _, key := range opts.TransitiveTagKeys {
+		if _, ok := opts.Tags[key]; !ok {
+			return nil, errors.New("AssumeRole transitive tag key " + key + " is not a session tag")
+		}
+	}
+	if len(opts.PolicyARNs) > maxAssumeRolePolicyARNs {
+		return nil, errors.New("AssumeRole sessions cannot have more than 10 managed policies")
+	}
+	return New(&STSAssumeRole{
+		Client: &http.Client{
+			Transport: http.DefaultTransport,
+		},
+		STSEndpoint: stsEndpoint,
+		Options:     opts,
+	}), nil
+}
+
+// getAssumeRoleValues - returns the form values of the AssumeRole
+// request of the options.
+func getAssumeRoleValues(opts STSAssumeRoleOptions) url.Values {
+	v := url.Values{}
+	v.Set("Action", "AssumeRole")
+	v.Set("Version", "2011-06-15")
+	if opts.RoleARN != "" {
+		v.Set("RoleArn", opts.RoleARN)
+	}
+	if opts.RoleSessionName != "" {
+		v.Set("RoleSessionName", opts.RoleSessionName)
+	}
+	if opts.DurationSeconds != 0 {
+		v.Set("DurationSeconds", strconv.Itoa(opts.DurationSeconds))
+	}
+	if opts.ExternalID != "" {
+		v.Set("ExternalId", opts.ExternalID)
+	}
+	if opts.SourceIdentity != "" {
+		v.Set("SourceIdentity", opts.SourceIdentity)
+	}
+	if opts.Policy != "" {
+		v.Set("Policy", opts.Policy)
+	}
+	for i, arn := range opts.PolicyARNs {
+		v.Set("PolicyArns.member."+strconv.Itoa(i+1)+".arn", arn)
+	}
+
+	// Tags are sent in the order of their keys.
+	keys := make([]string, 0, len(opts.Tags))
+	for key := range opts.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for i, key := range keys {
+		member := "Tags.member." + strconv.Itoa(i+1)
+		v.Set(member+".Key", key)
+		v.Set(member+".Value", opts.Tags[key])
+	}
+	for i, key := range opts.TransitiveTagKeys {
+		v.Set("TransitiveTagKeys.member."+strconv.Itoa(i+1), key)
+	}
+	return v
+}
+
+func getAssumeRoleCredentials(clnt *http.Client, endpoint string, opts STSAssumeRoleOptions) (AssumeRoleResponse, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return AssumeRoleResponse{}, err
+	}
+
+	body := getAssumeRoleValues(opts).Encode()
+	req, err := http.NewRequest("POST", u.String(), strings.NewReader(body))
+	if err != nil {
+		return AssumeRoleResponse{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	sum := sha256.Sum256([]byte(body))
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(sum[:]))
+
+	location := opts.Location
+	if location == "" {
+		location = "us-east-1"
+	}
+	req = s3signer.SignV4Service(*req, opts.AccessKey, opts.SecretKey, opts.SessionToken, location, "sts")
+
+	resp, err := clnt.Do(req)
+	if err != nil {
+		return AssumeRoleResponse{}, err
+	}
+
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return AssumeRoleResponse{}, errors.New(resp.Status)
+	}
+
+	a := AssumeRoleResponse{}
+	if err = xml.NewDecoder(resp.Body).Decode(&a); err != nil {
+		return AssumeRoleResponse{}, err
+	}
+
+	return a, nil
+}
+
+// Retrieve retrieves credentials from the STS service.
+// Error will be returned if the request fails.
+func (m *STSAssumeRole) Retrieve() (Value, error) {
+	a, err := getAssumeRoleCredentials(m.Client, m.STSEndpoint, m.Options)
+	if err != nil {
+		return Value{}, err
+	}
+
+	// Expiry window is set to 10secs.
+	m.SetExpiration(a.Result.Credentials.Expiration, DefaultExpiryWindow)
+
+	return Value{
+		AccessKeyID:     a.Result.Credentials.AccessKey,
+		SecretAccessKey: a.Result.Credentials.SecretKey,
+		SessionToken:    a.Result.Credentials.SessionToken,
+		SignerType:      SignatureV4,
+	}, nil
+}
@@ -34,7 +34,9 @@ const (
 	// sseKmsKeyID is the AWS SSE-KMS key id.
 	sseKmsKeyID = sseGenericHeader + "-Aws-Kms-Key-Id"
 	// sseEncryptionContext is the AWS SSE-KMS Encryption Context data.
-	sseEncryptionContext = sseGenericHeader + "-Encryption-Context"
+	sseEncryptionContext = sseGenericHeader + "-Context"
+	// sseBucketKeyEnabled is the AWS SSE-KMS S3 Bucket Key flag.
+	sseBucketKeyEnabled = sseGenericHeader + "-Bucket-Key-Enabled"
 
 	// sseCustomerAlgorithm is the AWS SSE-C algorithm HTTP header key.
 	sseCustomerAlgorithm = sseGenericHeader + "-Customer-Algorithm"
@@ -97,6 +99,9 @@ type ServerSide interface {
 func NewSSE() ServerSide { return s3{} }
 
 // NewSSEKMS returns a new server-side-encryption using SSE-KMS and the provided Key Id and context.
+// The default KMS key of the account is used if the Key Id is empty. The context, usually a map of
+// strings, is sent JSON and base64 encoded in the X-Amz-Server-Side-Encryption-Context header and
+// recorded with every use of the key, e.g. in AWS CloudTrail.
 func NewSSEKMS(keyID string, context interface{}) (ServerSide, error) {
 	if context == nil {
 		return kms{key: keyID, hasContext: false}, nil
@@ -150,6 +155,21 @@ func SSECopy(sse ServerSide) ServerSide {
 	return sse
 }
 
+// BucketKey transforms a SSE-KMS encryption into a SSE-KMS encryption
+// with an S3 Bucket Key, which encrypts the objects with data keys of
+// a key of the bucket derived from the KMS key, such that far fewer
+// requests are sent to KMS.
+//
+// If the provided sse is no SSE-KMS encryption BucketKey returns
+// sse unmodified.
+func BucketKey(sse ServerSide) ServerSide {
+	if sse, ok := sse.(kms); ok {
+		sse.bucketKey = true
+		return sse
+	}
+	return sse
+}
+
 type ssec [32]byte
 
 func (s ssec) Type() Type { return SSEC }
@@ -182,14 +202,20 @@ type kms struct {
 	key        string
 	context    []byte
 	hasContext bool
+	bucketKey  bool
 }
 
 func (s kms) Type() Type { return KMS }
 
 func (s kms) Marshal(h http.Header) {
 	h.Set(sseGenericHeader, "aws:kms")
-	h.Set(sseKmsKeyID, s.key)
+	if s.key != "" {
+		h.Set(sseKmsKeyID, s.key)
+	}
 	if s.hasContext {
 		h.Set(sseEncryptionContext, base64.StdEncoding.EncodeToString(s.context))
 	}
+	if s.bucketKey {
+		h.Set(sseBucketKeyEnabled, "true")
+	}
 }
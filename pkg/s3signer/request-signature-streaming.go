@@ -47,6 +47,7 @@ var ignoredStreamingHeaders = map[string]bool{
 	"Authorization": true,
 	"User-Agent":    true,
 	"Content-Type":  true,
+	"Expect":        true,
 }
 
 // getSignedChunkLength - calculates the length of chunk metadata
@@ -82,7 +83,7 @@ func buildChunkStringToSign(t time.Time, region, previousSig string, chunkData [
 	stringToSignParts := []string{
 		streamingPayloadHdr,
 		t.Format(iso8601DateFormat),
-		getScope(region, t),
+		getScope(region, serviceS3, t),
 		previousSig,
 		emptySHA256,
 		hex.EncodeToString(sum256(chunkData)),
@@ -118,7 +119,7 @@ func buildChunkSignature(chunkData []byte, reqTime time.Time, region,
 
 	chunkStringToSign := buildChunkStringToSign(reqTime, region,
 		previousSignature, chunkData)
-	signingKey := getSigningKey(secretAccessKey, region, reqTime)
+	signingKey := getSigningKey(secretAccessKey, region, serviceS3, reqTime)
 	return getSignature(signingKey, chunkStringToSign)
 }
 
@@ -128,9 +129,9 @@ func (s *StreamingReader) setSeedSignature(req *http.Request) {
 	canonicalRequest := getCanonicalRequest(*req, ignoredStreamingHeaders)
 
 	// Get string to sign from canonical request.
-	stringToSign := getStringToSignV4(s.reqTime, s.region, canonicalRequest)
+	stringToSign := getStringToSignV4(s.reqTime, s.region, serviceS3, canonicalRequest)
 
-	signingKey := getSigningKey(s.secretAccessKey, s.region, s.reqTime)
+	signingKey := getSigningKey(s.secretAccessKey, s.region, serviceS3, s.reqTime)
 
 	// Calculate signature.
 	s.seedSignature = getSignature(signingKey, stringToSign)
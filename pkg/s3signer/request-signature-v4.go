@@ -18,8 +18,10 @@
 package s3signer
 
 import (
-	"bytes"
+	"crypto/sha256"
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"net/http"
 	"sort"
 	"strconv"
@@ -36,6 +38,24 @@ const (
 	yyyymmdd          = "20060102"
 )
 
+// Signing names of the services signed with signature V4.
+const (
+	serviceS3 = "s3"
+
+	// ServiceS3Express - signing name of S3 Express One Zone, its
+	// directory buckets are accessed with the credentials of
+	// sessions created by the CreateSession API.
+	ServiceS3Express = "s3express"
+
+	// ServiceS3ObjectLambda - signing name of S3 Object Lambda, its
+	// access points transform the objects read through them.
+	ServiceS3ObjectLambda = "s3-object-lambda"
+
+	// ServiceS3Outposts - signing name of S3 on Outposts, its
+	// buckets are accessed through access points on the Outpost.
+	ServiceS3Outposts = "s3-outposts"
+)
+
 ///
 /// Excerpts from @lsegal -
 /// https://github.com/aws/aws-sdk-js/issues/659#issuecomment-120477258.
@@ -71,18 +91,24 @@ const (
 ///
 ///      Is skipped for obvious reasons
 ///
+///  Expect:
+///
+///      Is a hop-by-hop expectation which proxies may handle and
+///      remove, the request is valid with or without it.
+///
 var v4IgnoredHeaders = map[string]bool{
 	"Authorization":  true,
 	"Content-Type":   true,
 	"Content-Length": true,
 	"User-Agent":     true,
+	"Expect":         true,
 }
 
 // getSigningKey hmac seed to calculate final signature.
-func getSigningKey(secret, loc string, t time.Time) []byte {
+func getSigningKey(secret, loc, serviceName string, t time.Time) []byte {
 	date := sumHMAC([]byte("AWS4"+secret), []byte(t.Format(yyyymmdd)))
 	location := sumHMAC(date, []byte(loc))
-	service := sumHMAC(location, []byte("s3"))
+	service := sumHMAC(location, []byte(serviceName))
 	signingKey := sumHMAC(service, []byte("aws4_request"))
 	return signingKey
 }
@@ -94,20 +120,18 @@ func getSignature(signingKey []byte, stringToSign string) string {
 
 // getScope generate a string of a specific date, an AWS region, and a
 // service.
-func getScope(location string, t time.Time) string {
-	scope := strings.Join([]string{
-		t.Format(yyyymmdd),
-		location,
-		"s3",
-		"aws4_request",
-	}, "/")
-	return scope
+func getScope(location, serviceName string, t time.Time) string {
+	return t.Format(yyyymmdd) + "/" + location + "/" + serviceName + "/aws4_request"
 }
 
 // GetCredential generate a credential string.
 func GetCredential(accessKeyID, location string, t time.Time) string {
-	scope := getScope(location, t)
-	return accessKeyID + "/" + scope
+	return getCredential(accessKeyID, location, serviceS3, t)
+}
+
+// getCredential generate a credential string of a service.
+func getCredential(accessKeyID, location, serviceName string, t time.Time) string {
+	return accessKeyID + "/" + getScope(location, serviceName, t)
 }
 
 // getHashedPayload get the hexadecimal value of the SHA256 hash of
@@ -121,58 +145,81 @@ func getHashedPayload(req http.Request) string {
 	return hashedPayload
 }
 
-// getCanonicalHeaders generate a list of request headers for
-// signature.
-func getCanonicalHeaders(req http.Request, ignoredHeaders map[string]bool) string {
-	var headers []string
-	vals := make(map[string][]string)
-	for k, vv := range req.Header {
+// signedHeaderKey - name of a request header included in the signature.
+type signedHeaderKey struct {
+	lower string // Lowercase name, headers are sorted by it.
+	key   string // Key in the request headers, empty for host.
+}
+
+// signedHeaderKeys - sorts signed headers lexically by lowercase name.
+type signedHeaderKeys []signedHeaderKey
+
+func (h signedHeaderKeys) Len() int           { return len(h) }
+func (h signedHeaderKeys) Less(i, j int) bool { return h[i].lower < h[j].lower }
+func (h signedHeaderKeys) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+// getSignedHeaderKeys returns the lexically sorted request headers
+// included in the signature, including host.
+func getSignedHeaderKeys(req http.Request, ignoredHeaders map[string]bool) signedHeaderKeys {
+	keys := make(signedHeaderKeys, 0, len(req.Header)+1)
+	for k := range req.Header {
 		if _, ok := ignoredHeaders[http.CanonicalHeaderKey(k)]; ok {
 			continue // ignored header
 		}
-		headers = append(headers, strings.ToLower(k))
-		vals[strings.ToLower(k)] = vv
+		keys = append(keys, signedHeaderKey{lower: strings.ToLower(k), key: k})
 	}
-	headers = append(headers, "host")
-	sort.Strings(headers)
-
-	var buf bytes.Buffer
-	// Save all the headers in canonical form <header>:<value> newline
-	// separated for each header.
-	for _, k := range headers {
-		buf.WriteString(k)
-		buf.WriteByte(':')
-		switch {
-		case k == "host":
-			buf.WriteString(getHostAddr(&req))
-			fallthrough
-		default:
-			for idx, v := range vals[k] {
+	keys = append(keys, signedHeaderKey{lower: "host"})
+	sort.Sort(keys)
+	return keys
+}
+
+// writeCanonicalHeaders writes the headers in canonical form
+// <header>:<value> newline separated for each header.
+func writeCanonicalHeaders(b *strings.Builder, req http.Request, keys signedHeaderKeys) {
+	for _, k := range keys {
+		b.WriteString(k.lower)
+		b.WriteByte(':')
+		if k.lower == "host" {
+			b.WriteString(getHostAddr(&req))
+		}
+		if k.key != "" {
+			for idx, v := range req.Header[k.key] {
 				if idx > 0 {
-					buf.WriteByte(',')
+					b.WriteByte(',')
 				}
-				buf.WriteString(signV4TrimAll(v))
+				b.WriteString(signV4TrimAll(v))
 			}
-			buf.WriteByte('\n')
 		}
+		b.WriteByte('\n')
+	}
+}
+
+// writeSignedHeaders writes the semicolon-separated list of lowercase
+// header names.
+func writeSignedHeaders(b *strings.Builder, keys signedHeaderKeys) {
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(';')
+		}
+		b.WriteString(k.lower)
 	}
-	return buf.String()
+}
+
+// getCanonicalHeaders generate a list of request headers for
+// signature.
+func getCanonicalHeaders(req http.Request, ignoredHeaders map[string]bool) string {
+	var b strings.Builder
+	writeCanonicalHeaders(&b, req, getSignedHeaderKeys(req, ignoredHeaders))
+	return b.String()
 }
 
 // getSignedHeaders generate all signed request headers.
 // i.e lexically sorted, semicolon-separated list of lowercase
 // request header names.
 func getSignedHeaders(req http.Request, ignoredHeaders map[string]bool) string {
-	var headers []string
-	for k := range req.Header {
-		if _, ok := ignoredHeaders[http.CanonicalHeaderKey(k)]; ok {
-			continue // Ignored header found continue.
-		}
-		headers = append(headers, strings.ToLower(k))
-	}
-	headers = append(headers, "host")
-	sort.Strings(headers)
-	return strings.Join(headers, ";")
+	var b strings.Builder
+	writeSignedHeaders(&b, getSignedHeaderKeys(req, ignoredHeaders))
+	return b.String()
 }
 
 // getCanonicalRequest generate a canonical request of style.
@@ -185,29 +232,79 @@ func getSignedHeaders(req http.Request, ignoredHeaders map[string]bool) string {
 //  <SignedHeaders>\n
 //  <HashedPayload>
 func getCanonicalRequest(req http.Request, ignoredHeaders map[string]bool) string {
-	req.URL.RawQuery = strings.Replace(req.URL.Query().Encode(), "+", "%20", -1)
-	canonicalRequest := strings.Join([]string{
-		req.Method,
-		s3utils.EncodePath(req.URL.Path),
-		req.URL.RawQuery,
-		getCanonicalHeaders(req, ignoredHeaders),
-		getSignedHeaders(req, ignoredHeaders),
-		getHashedPayload(req),
-	}, "\n")
+	canonicalRequest, _ := getCanonicalRequestAndSignedHeaders(req, ignoredHeaders)
 	return canonicalRequest
 }
 
+// getCanonicalRequestAndSignedHeaders generate the canonical request and
+// the signed headers, which are part of it, in a single buffer such
+// that the headers are only collected and sorted once.
+func getCanonicalRequestAndSignedHeaders(req http.Request, ignoredHeaders map[string]bool) (canonicalRequest, signedHeaders string) {
+	if req.URL.RawQuery != "" {
+		req.URL.RawQuery = strings.Replace(req.URL.Query().Encode(), "+", "%20", -1)
+	}
+	keys := getSignedHeaderKeys(req, ignoredHeaders)
+	hashedPayload := getHashedPayload(req)
+	encodedPath := s3utils.EncodePath(req.URL.Path)
+
+	var b strings.Builder
+	size := len(req.Method) + len(encodedPath) + len(req.URL.RawQuery) + len(hashedPayload) + 5
+	for _, k := range keys {
+		size += 2*len(k.lower) + 3
+		for _, v := range req.Header[k.key] {
+			size += len(v) + 1
+		}
+	}
+	b.Grow(size + len(getHostAddr(&req)))
+
+	b.WriteString(req.Method)
+	b.WriteByte('\n')
+	b.WriteString(encodedPath)
+	b.WriteByte('\n')
+	b.WriteString(req.URL.RawQuery)
+	b.WriteByte('\n')
+	writeCanonicalHeaders(&b, req, keys)
+	b.WriteByte('\n')
+	start := b.Len()
+	writeSignedHeaders(&b, keys)
+	end := b.Len()
+	b.WriteByte('\n')
+	b.WriteString(hashedPayload)
+
+	// Strings returned by a strings.Builder are not modified by later
+	// writes, so the signed headers may refer to its buffer.
+	canonicalRequest = b.String()
+	return canonicalRequest, canonicalRequest[start:end]
+}
+
 // getStringToSign a string based on selected query values.
-func getStringToSignV4(t time.Time, location, canonicalRequest string) string {
-	stringToSign := signV4Algorithm + "\n" + t.Format(iso8601DateFormat) + "\n"
-	stringToSign = stringToSign + getScope(location, t) + "\n"
-	stringToSign = stringToSign + hex.EncodeToString(sum256([]byte(canonicalRequest)))
-	return stringToSign
+func getStringToSignV4(t time.Time, location, serviceName, canonicalRequest string) string {
+	sum := sha256.Sum256([]byte(canonicalRequest))
+	scope := getScope(location, serviceName, t)
+
+	var b strings.Builder
+	b.Grow(len(signV4Algorithm) + len(iso8601DateFormat) + len(scope) + 2*sha256.Size + 3)
+	b.WriteString(signV4Algorithm)
+	b.WriteByte('\n')
+	b.WriteString(t.Format(iso8601DateFormat))
+	b.WriteByte('\n')
+	b.WriteString(scope)
+	b.WriteByte('\n')
+	var hexSum [2 * sha256.Size]byte
+	hex.Encode(hexSum[:], sum[:])
+	b.Write(hexSum[:])
+	return b.String()
 }
 
 // PreSignV4 presign the request, in accordance with
 // http://docs.aws.amazon.com/AmazonS3/latest/API/sigv4-query-string-auth.html.
 func PreSignV4(req http.Request, accessKeyID, secretAccessKey, sessionToken, location string, expires int64) *http.Request {
+	return PreSignV4Service(req, accessKeyID, secretAccessKey, sessionToken, location, serviceS3, expires)
+}
+
+// PreSignV4Service presign the request like PreSignV4, for the
+// service of the given signing name such as ServiceS3ObjectLambda.
+func PreSignV4Service(req http.Request, accessKeyID, secretAccessKey, sessionToken, location, serviceName string, expires int64) *http.Request {
 	// Presign is not needed for anonymous credentials.
 	if accessKeyID == "" || secretAccessKey == "" {
 		return &req
@@ -217,7 +314,7 @@ func PreSignV4(req http.Request, accessKeyID, secretAccessKey, sessionToken, loc
 	t := time.Now().UTC()
 
 	// Get credential string.
-	credential := GetCredential(accessKeyID, location, t)
+	credential := getCredential(accessKeyID, location, serviceName, t)
 
 	// Get all signed headers.
 	signedHeaders := getSignedHeaders(req, v4IgnoredHeaders)
@@ -239,10 +336,10 @@ func PreSignV4(req http.Request, accessKeyID, secretAccessKey, sessionToken, loc
 	canonicalRequest := getCanonicalRequest(req, v4IgnoredHeaders)
 
 	// Get string to sign from canonical request.
-	stringToSign := getStringToSignV4(t, location, canonicalRequest)
+	stringToSign := getStringToSignV4(t, location, serviceName, canonicalRequest)
 
 	// Gext hmac signing key.
-	signingKey := getSigningKey(secretAccessKey, location, t)
+	signingKey := getSigningKey(secretAccessKey, location, serviceName, t)
 
 	// Calculate signature.
 	signature := getSignature(signingKey, stringToSign)
@@ -257,7 +354,7 @@ func PreSignV4(req http.Request, accessKeyID, secretAccessKey, sessionToken, loc
 // requests.
 func PostPresignSignatureV4(policyBase64 string, t time.Time, secretAccessKey, location string) string {
 	// Get signining key.
-	signingkey := getSigningKey(secretAccessKey, location, t)
+	signingkey := getSigningKey(secretAccessKey, location, serviceS3, t)
 	// Calculate signature.
 	signature := getSignature(signingkey, policyBase64)
 	return signature
@@ -266,6 +363,26 @@ func PostPresignSignatureV4(policyBase64 string, t time.Time, secretAccessKey, l
 // SignV4 sign the request before Do(), in accordance with
 // http://docs.aws.amazon.com/AmazonS3/latest/API/sig-v4-authenticating-requests.html.
 func SignV4(req http.Request, accessKeyID, secretAccessKey, sessionToken, location string) *http.Request {
+	return signV4(req, accessKeyID, secretAccessKey, sessionToken, location, serviceS3, "X-Amz-Security-Token")
+}
+
+// SignV4Service sign the request before Do() like SignV4, for the
+// service of the given signing name such as ServiceS3Express.
+func SignV4Service(req http.Request, accessKeyID, secretAccessKey, sessionToken, location, serviceName string) *http.Request {
+	return signV4(req, accessKeyID, secretAccessKey, sessionToken, location, serviceName, "X-Amz-Security-Token")
+}
+
+// SignV4Express sign requests to S3 Express One Zone directory
+// buckets before Do() with the credentials of a session created by
+// the CreateSession API, the session token is sent in the
+// X-Amz-S3session-Token header.
+func SignV4Express(req http.Request, accessKeyID, secretAccessKey, sessionToken, location string) *http.Request {
+	return signV4(req, accessKeyID, secretAccessKey, sessionToken, location, ServiceS3Express, "X-Amz-S3session-Token")
+}
+
+// signV4 - signs the request for a service, the session token is
+// sent in tokenHeader.
+func signV4(req http.Request, accessKeyID, secretAccessKey, sessionToken, location, serviceName, tokenHeader string) *http.Request {
 	// Signature calculation is not needed for anonymous credentials.
 	if accessKeyID == "" || secretAccessKey == "" {
 		return &req
@@ -279,37 +396,88 @@ func SignV4(req http.Request, accessKeyID, secretAccessKey, sessionToken, locati
 
 	// Set session token if available.
 	if sessionToken != "" {
-		req.Header.Set("X-Amz-Security-Token", sessionToken)
+		req.Header.Set(tokenHeader, sessionToken)
 	}
 
-	// Get canonical request.
-	canonicalRequest := getCanonicalRequest(req, v4IgnoredHeaders)
+	// Get canonical request and all signed headers.
+	canonicalRequest, signedHeaders := getCanonicalRequestAndSignedHeaders(req, v4IgnoredHeaders)
 
 	// Get string to sign from canonical request.
-	stringToSign := getStringToSignV4(t, location, canonicalRequest)
+	stringToSign := getStringToSignV4(t, location, serviceName, canonicalRequest)
 
 	// Get hmac signing key.
-	signingKey := getSigningKey(secretAccessKey, location, t)
+	signingKey := getSigningKey(secretAccessKey, location, serviceName, t)
 
 	// Get credential string.
-	credential := GetCredential(accessKeyID, location, t)
-
-	// Get all signed headers.
-	signedHeaders := getSignedHeaders(req, v4IgnoredHeaders)
+	credential := getCredential(accessKeyID, location, serviceName, t)
 
 	// Calculate signature.
 	signature := getSignature(signingKey, stringToSign)
 
 	// If regular request, construct the final authorization header.
-	parts := []string{
-		signV4Algorithm + " Credential=" + credential,
-		"SignedHeaders=" + signedHeaders,
-		"Signature=" + signature,
-	}
+	auth := signV4Algorithm + " Credential=" + credential +
+		", SignedHeaders=" + signedHeaders +
+		", Signature=" + signature
 
 	// Set authorization header.
-	auth := strings.Join(parts, ", ")
 	req.Header.Set("Authorization", auth)
 
 	return &req
 }
+
+// SignatureV4 - the canonical request and string to sign a signature
+// V4 is computed from, which S3 servers also report along with
+// SignatureDoesNotMatch errors.
+type SignatureV4 struct {
+	CanonicalRequest string
+	StringToSign     string
+	Signature        string
+}
+
+// GetSignatureV4 computes the signature V4 of a request signed by
+// SignV4, SignV4Service, SignV4Express, PreSignV4, PreSignV4Service or
+// StreamingSignV4 at the time of its X-Amz-Date header, or query
+// parameter for presigned requests, to diagnose
+// SignatureDoesNotMatch errors. The request is not modified. Signatures
+// V4A are randomized and cannot be computed again.
+func GetSignatureV4(req http.Request, secretAccessKey, location string) (SignatureV4, error) {
+	// The query of the canonical request is normalized in place.
+	u := *req.URL
+	req.URL = &u
+
+	ignoredHeaders := v4IgnoredHeaders
+	date := req.Header.Get("X-Amz-Date")
+	credential := req.Header.Get("Authorization")
+	if query := req.URL.Query(); query.Get("X-Amz-Signature") != "" {
+		date = query.Get("X-Amz-Date")
+		credential = query.Get("X-Amz-Credential")
+		query.Del("X-Amz-Signature")
+		req.URL.RawQuery = query.Encode()
+	} else if req.Header.Get("X-Amz-Content-Sha256") == streamingSignAlgorithm {
+		ignoredHeaders = ignoredStreamingHeaders
+	}
+	if strings.HasPrefix(credential, signV4AAlgorithm) || req.URL.Query().Get("X-Amz-Algorithm") == signV4AAlgorithm {
+		return SignatureV4{}, errors.New("signature: signatures V4A cannot be computed again")
+	}
+	t, err := time.Parse(iso8601DateFormat, date)
+	if err != nil {
+		return SignatureV4{}, fmt.Errorf("signature: X-Amz-Date %q of the request is not valid", date)
+	}
+	// The signing name of the service is part of the credential
+	// scope, e.g. 'access/20190501/us-east-1/s3/aws4_request'.
+	serviceName := serviceS3
+	for _, name := range []string{ServiceS3Express, ServiceS3ObjectLambda, ServiceS3Outposts} {
+		if strings.Contains(credential, "/"+name+"/aws4_request") {
+			serviceName = name
+		}
+	}
+
+	canonicalRequest := getCanonicalRequest(req, ignoredHeaders)
+	stringToSign := getStringToSignV4(t, location, serviceName, canonicalRequest)
+	signingKey := getSigningKey(secretAccessKey, location, serviceName, t)
+	return SignatureV4{
+		CanonicalRequest: canonicalRequest,
+		StringToSign:     stringToSign,
+		Signature:        getSignature(signingKey, stringToSign),
+	}, nil
+}
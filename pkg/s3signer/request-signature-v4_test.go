@@ -22,6 +22,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestRequestHost(t *testing.T) {
@@ -35,6 +36,47 @@ func TestRequestHost(t *testing.T) {
 	}
 }
 
+func TestCanonicalRequest(t *testing.T) {
+	req, _ := http.NewRequest("PUT", "https://s3.amazonaws.com/bucket/my%20object?uploadId=1&partNumber=2", nil)
+	req.Header.Set("X-Amz-Meta-B", "  two   words ")
+	req.Header.Add("X-Amz-Meta-B", "second")
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	req.Header.Set("X-Amz-Date", "20190501T100000Z")
+	req.Header.Set("Content-Type", "ignored")
+	req.Header.Set("Expect", "100-continue")
+
+	expected := strings.Join([]string{
+		"PUT",
+		"/bucket/my%20object",
+		"partNumber=2&uploadId=1",
+		"host:s3.amazonaws.com",
+		"x-amz-content-sha256:" + unsignedPayload,
+		"x-amz-date:20190501T100000Z",
+		"x-amz-meta-b:two words,second",
+		"",
+		"host;x-amz-content-sha256;x-amz-date;x-amz-meta-b",
+		unsignedPayload,
+	}, "\n")
+	canonicalRequest, signedHeaders := getCanonicalRequestAndSignedHeaders(*req, v4IgnoredHeaders)
+	if canonicalRequest != expected {
+		t.Errorf("Expected canonical request %q, got %q", expected, canonicalRequest)
+	}
+	if signedHeaders != getSignedHeaders(*req, v4IgnoredHeaders) {
+		t.Errorf("Signed headers %q do not match %q", signedHeaders, getSignedHeaders(*req, v4IgnoredHeaders))
+	}
+}
+
+func BenchmarkSignV4(b *testing.B) {
+	req, _ := http.NewRequest("PUT", "https://s3.amazonaws.com/bucket/object?uploadId=1&partNumber=2", nil)
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("X-Amz-Meta-Key", "value")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		SignV4(*req, "ACCESS-KEY", "SECRET-KEY", "", "us-east-1")
+	}
+}
+
 func buildRequest(serviceName, region, body string) (*http.Request, io.ReadSeeker) {
 	endpoint := "https://" + serviceName + "." + region + ".amazonaws.com"
 	reader := strings.NewReader(body)
@@ -48,3 +90,74 @@ func buildRequest(serviceName, region, body string) (*http.Request, io.ReadSeeke
 	req.Header.Add("X-amz-Meta-Other-Header_With_Underscore", "some-value=!@#$%^&* (+)")
 	return req, reader
 }
+
+// Tests the signature of signed requests is computed again from
+// their canonical request.
+func TestGetSignatureV4(t *testing.T) {
+	newRequest := func() *http.Request {
+		req, _ := http.NewRequest("PUT", "https://s3.amazonaws.com/bucket/object?x-minio-extension=value", nil)
+		req.Header.Set("X-Amz-Meta-A", "value")
+		return req
+	}
+
+	req := newRequest()
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	req = SignV4(*req, "access", "secret", "", "us-east-1")
+	signature, err := GetSignatureV4(*req, "secret", "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(req.Header.Get("Authorization"), "Signature="+signature.Signature) {
+		t.Errorf("signature %s does not match %s", signature.Signature, req.Header.Get("Authorization"))
+	}
+	if !strings.HasPrefix(signature.CanonicalRequest, "PUT\n/bucket/object\nx-minio-extension=value\n") {
+		t.Errorf("unexpected canonical request %q", signature.CanonicalRequest)
+	}
+	if !strings.HasPrefix(signature.StringToSign, signV4Algorithm+"\n"+req.Header.Get("X-Amz-Date")+"\n") {
+		t.Errorf("unexpected string to sign %q", signature.StringToSign)
+	}
+
+	req = PreSignV4(*newRequest(), "access", "secret", "", "us-east-1", 3600)
+	signature, err = GetSignatureV4(*req, "secret", "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.URL.Query().Get("X-Amz-Signature") != signature.Signature {
+		t.Errorf("signature %s does not match %s", signature.Signature, req.URL.RawQuery)
+	}
+
+	req = StreamingSignV4(newRequest(), "access", "secret", "", "us-east-1", 10, time.Now().UTC())
+	signature, err = GetSignatureV4(*req, "secret", "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(req.Header.Get("Authorization"), "Signature="+signature.Signature) {
+		t.Errorf("signature %s does not match %s", signature.Signature, req.Header.Get("Authorization"))
+	}
+
+	if _, err = GetSignatureV4(*newRequest(), "secret", "us-east-1"); err == nil {
+		t.Error("expected an error for an unsigned request")
+	}
+}
+
+// Tests requests to S3 Express directory buckets are signed with the
+// s3express signing name and session token header.
+func TestSignV4Express(t *testing.T) {
+	req, _ := http.NewRequest("GET", "https://bucket--usw2-az1--x-s3.s3express-usw2-az1.us-west-2.amazonaws.com/object", nil)
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	req = SignV4Express(*req, "access", "secret", "token", "us-west-2")
+	if req.Header.Get("X-Amz-S3session-Token") != "token" || req.Header.Get("X-Amz-Security-Token") != "" {
+		t.Fatalf("unexpected session token headers %v", req.Header)
+	}
+	auth := req.Header.Get("Authorization")
+	if !strings.Contains(auth, "/us-west-2/s3express/aws4_request") || !strings.Contains(auth, "x-amz-s3session-token") {
+		t.Fatalf("unexpected authorization %s", auth)
+	}
+	signature, err := GetSignatureV4(*req, "secret", "us-west-2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(auth, "Signature="+signature.Signature) {
+		t.Errorf("signature %s does not match %s", signature.Signature, auth)
+	}
+}
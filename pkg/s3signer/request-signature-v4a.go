@@ -0,0 +1,181 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3signer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// signV4AAlgorithm - algorithm of signature V4A, the asymmetric
+// variant of signature V4 whose signatures are valid in a set of
+// regions, e.g. for Multi-Region Access Points.
+const signV4AAlgorithm = "AWS4-ECDSA-P256-SHA256"
+
+// deriveV4AKey - derives the ECDSA P-256 key of signature V4A from
+// the access key, in accordance with the NIST SP 800-108 HMAC-SHA256
+// KDF in counter mode used by AWS. The first candidate below the
+// order of the curve minus two, plus one, is the private key.
+func deriveV4AKey(accessKeyID, secretAccessKey string) (*ecdsa.PrivateKey, error) {
+	curve := elliptic.P256()
+	nMinusTwo := new(big.Int).Sub(curve.Params().N, big.NewInt(2))
+	inputKey := []byte("AWS4A" + secretAccessKey)
+
+	for counter := 1; counter <= 0xff; counter++ {
+		// Fixed input: i || label || 0x00 || context || L, with
+		// a single iteration for the 256 bits of the key.
+		var fixedInput []byte
+		fixedInput = append(fixedInput, 0, 0, 0, 1)
+		fixedInput = append(fixedInput, signV4AAlgorithm...)
+		fixedInput = append(fixedInput, 0)
+		fixedInput = append(fixedInput, accessKeyID...)
+		fixedInput = append(fixedInput, byte(counter))
+		var length [4]byte
+		binary.BigEndian.PutUint32(length[:], 256)
+		fixedInput = append(fixedInput, length[:]...)
+
+		mac := hmac.New(sha256.New, inputKey)
+		mac.Write(fixedInput)
+		candidate := new(big.Int).SetBytes(mac.Sum(nil))
+		if candidate.Cmp(nMinusTwo) >= 0 {
+			continue
+		}
+
+		d := candidate.Add(candidate, big.NewInt(1))
+		scalar := make([]byte, 32)
+		b := d.Bytes()
+		copy(scalar[len(scalar)-len(b):], b)
+
+		key := &ecdsa.PrivateKey{D: d}
+		key.PublicKey.Curve = curve
+		key.PublicKey.X, key.PublicKey.Y = curve.ScalarBaseMult(scalar)
+		return key, nil
+	}
+	return nil, errors.New("signature: no signature V4A key could be derived from the access key")
+}
+
+// getScopeV4A - returns the credential scope of signature V4A, which
+// has no region, e.g. '20190501/s3/aws4_request'.
+func getScopeV4A(t time.Time) string {
+	return t.Format(yyyymmdd) + "/" + serviceS3 + "/aws4_request"
+}
+
+// getStringToSignV4A - returns the string to sign of signature V4A.
+func getStringToSignV4A(t time.Time, canonicalRequest string) string {
+	sum := sha256.Sum256([]byte(canonicalRequest))
+	return signV4AAlgorithm + "\n" + t.Format(iso8601DateFormat) + "\n" +
+		getScopeV4A(t) + "\n" + hex.EncodeToString(sum[:])
+}
+
+// getSignatureV4A - returns the hex encoded ASN.1 DER ECDSA signature
+// of the SHA256 sum of the string to sign.
+func getSignatureV4A(key *ecdsa.PrivateKey, stringToSign string) (string, error) {
+	sum := sha256.Sum256([]byte(stringToSign))
+	r, s, err := ecdsa.Sign(rand.Reader, key, sum[:])
+	if err != nil {
+		return "", err
+	}
+	signature, err := asn1.Marshal(struct{ R, S *big.Int }{r, s})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(signature), nil
+}
+
+// SignV4A sign the request before Do() with signature V4A, for the
+// regions of regionSet, e.g. '*' for all regions as required by
+// Multi-Region Access Points. The request is returned unsigned if
+// the signature cannot be computed.
+func SignV4A(req http.Request, accessKeyID, secretAccessKey, sessionToken string, regionSet []string) *http.Request {
+	// Signature calculation is not needed for anonymous credentials.
+	if accessKeyID == "" || secretAccessKey == "" {
+		return &req
+	}
+	key, err := deriveV4AKey(accessKeyID, secretAccessKey)
+	if err != nil {
+		return &req
+	}
+
+	// Initial time.
+	t := time.Now().UTC()
+
+	req.Header.Set("X-Amz-Date", t.Format(iso8601DateFormat))
+	req.Header.Set("X-Amz-Region-Set", strings.Join(regionSet, ","))
+	if sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", sessionToken)
+	}
+
+	canonicalRequest, signedHeaders := getCanonicalRequestAndSignedHeaders(req, v4IgnoredHeaders)
+	signature, err := getSignatureV4A(key, getStringToSignV4A(t, canonicalRequest))
+	if err != nil {
+		return &req
+	}
+
+	req.Header.Set("Authorization", signV4AAlgorithm+" Credential="+accessKeyID+"/"+getScopeV4A(t)+
+		", SignedHeaders="+signedHeaders+
+		", Signature="+signature)
+	return &req
+}
+
+// PreSignV4A presign the request like PreSignV4 with signature V4A,
+// for the regions of regionSet. The request is returned unsigned if
+// the signature cannot be computed.
+func PreSignV4A(req http.Request, accessKeyID, secretAccessKey, sessionToken string, regionSet []string, expires int64) *http.Request {
+	// Presign is not needed for anonymous credentials.
+	if accessKeyID == "" || secretAccessKey == "" {
+		return &req
+	}
+	key, err := deriveV4AKey(accessKeyID, secretAccessKey)
+	if err != nil {
+		return &req
+	}
+
+	// Initial time.
+	t := time.Now().UTC()
+
+	query := req.URL.Query()
+	query.Set("X-Amz-Algorithm", signV4AAlgorithm)
+	query.Set("X-Amz-Date", t.Format(iso8601DateFormat))
+	query.Set("X-Amz-Expires", strconv.FormatInt(expires, 10))
+	query.Set("X-Amz-SignedHeaders", getSignedHeaders(req, v4IgnoredHeaders))
+	query.Set("X-Amz-Credential", accessKeyID+"/"+getScopeV4A(t))
+	query.Set("X-Amz-Region-Set", strings.Join(regionSet, ","))
+	if sessionToken != "" {
+		query.Set("X-Amz-Security-Token", sessionToken)
+	}
+	req.URL.RawQuery = query.Encode()
+
+	signature, err := getSignatureV4A(key, getStringToSignV4A(t, getCanonicalRequest(req, v4IgnoredHeaders)))
+	if err != nil {
+		return &req
+	}
+	req.URL.RawQuery += "&X-Amz-Signature=" + signature
+	return &req
+}
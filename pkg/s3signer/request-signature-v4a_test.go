@@ -0,0 +1,106 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package s3signer
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Tests the signature V4A key is derived like the AWS SDKs do.
+func TestDeriveV4AKey(t *testing.T) {
+	key, err := deriveV4AKey("AKISORANDOMAASORANDOM", "q+jcrXGc+0zWN6uzclKVhvMmUsIfRPa4rlRandom")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := fmt.Sprintf("%x", key.D); d != "7fd3bd010c0d9c292141c2b77bfbde1042c92e6836fff749d1269ec890fca1bd" {
+		t.Errorf("unexpected private key %s", d)
+	}
+	if !key.PublicKey.Curve.IsOnCurve(key.PublicKey.X, key.PublicKey.Y) {
+		t.Error("public key is not on the curve")
+	}
+}
+
+// Tests requests signed with signature V4A verify with the public key
+// of the access key.
+func TestSignV4A(t *testing.T) {
+	req, err := http.NewRequest("GET", "https://mfzwi23gnjvgw.mrap.accesspoint.s3-global.amazonaws.com/object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	req = SignV4A(*req, "access", "secret", "token", []string{"*"})
+
+	auth := req.Header.Get("Authorization")
+	credential := "Credential=access/" + time.Now().UTC().Format(yyyymmdd) + "/s3/aws4_request"
+	if !strings.HasPrefix(auth, signV4AAlgorithm+" "+credential+", SignedHeaders=") ||
+		!strings.Contains(auth, "x-amz-region-set") || !strings.Contains(auth, "x-amz-security-token") {
+		t.Fatalf("unexpected authorization %s", auth)
+	}
+	if req.Header.Get("X-Amz-Region-Set") != "*" {
+		t.Errorf("expected region set *, got %q", req.Header.Get("X-Amz-Region-Set"))
+	}
+
+	date, err := time.Parse(iso8601DateFormat, req.Header.Get("X-Amz-Date"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature, err := hex.DecodeString(auth[strings.LastIndex(auth, "=")+1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rs struct{ R, S *big.Int }
+	if _, err = asn1.Unmarshal(signature, &rs); err != nil {
+		t.Fatal(err)
+	}
+	key, err := deriveV4AKey("access", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte(getStringToSignV4A(date, getCanonicalRequest(*req, v4IgnoredHeaders))))
+	if !ecdsa.Verify(&key.PublicKey, sum[:], rs.R, rs.S) {
+		t.Error("signature V4A does not verify")
+	}
+
+	if _, err = GetSignatureV4(*req, "secret", "us-east-1"); err == nil {
+		t.Error("expected signatures V4A not to be computed again")
+	}
+}
+
+// Tests requests presigned with signature V4A carry the region set.
+func TestPreSignV4A(t *testing.T) {
+	req, err := http.NewRequest("GET", "https://mfzwi23gnjvgw.mrap.accesspoint.s3-global.amazonaws.com/object", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = PreSignV4A(*req, "access", "secret", "", []string{"*"}, 3600)
+	query := req.URL.Query()
+	if query.Get("X-Amz-Algorithm") != signV4AAlgorithm || query.Get("X-Amz-Region-Set") != "*" ||
+		query.Get("X-Amz-Expires") != "3600" || query.Get("X-Amz-Signature") == "" ||
+		!strings.HasSuffix(query.Get("X-Amz-Credential"), "/s3/aws4_request") {
+		t.Errorf("unexpected presigned query %s", req.URL.RawQuery)
+	}
+}
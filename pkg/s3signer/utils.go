@@ -22,6 +22,7 @@ import (
 	"crypto/sha256"
 	"net/http"
 	"strings"
+	"unicode/utf8"
 )
 
 // unsignedPayload - value to be set to X-Amz-Content-Sha256 header when
@@ -52,7 +53,32 @@ func getHostAddr(req *http.Request) string {
 // Trim leading and trailing spaces and replace sequential spaces with one space, following Trimall()
 // in http://docs.aws.amazon.com/general/latest/gr/sigv4-create-canonical-request.html
 func signV4TrimAll(input string) string {
+	// Most values have nothing to trim, return them as is
+	// without allocating.
+	if !needsTrimAll(input) {
+		return input
+	}
 	// Compress adjacent spaces (a space is determined by
 	// unicode.IsSpace() internally here) to one space and return
 	return strings.Join(strings.Fields(input), " ")
 }
+
+// needsTrimAll returns false if signV4TrimAll would return the input
+// unchanged, i.e. it has no leading, trailing or adjacent spaces and
+// no whitespace other than single spaces. Non ASCII input is always
+// trimmed since it may contain unicode spaces.
+func needsTrimAll(input string) bool {
+	for i := 0; i < len(input); i++ {
+		switch c := input[i]; {
+		case c >= utf8.RuneSelf:
+			return true
+		case c == ' ':
+			if i == 0 || i == len(input)-1 || input[i-1] == ' ' {
+				return true
+			}
+		case c == '\t' || c == '\n' || c == '\v' || c == '\f' || c == '\r':
+			return true
+		}
+	}
+	return false
+}
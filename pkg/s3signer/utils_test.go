@@ -103,6 +103,11 @@ func TestSignV4TrimAll(t *testing.T) {
 		{"a \t b  c   ", "a b c"},
 		{"\"a \t b  c   ", "\"a b c"},
 		{" \t\n\u000b\r\fa \t\n\u000b\r\f b \t\n\u000b\r\f c \t\n\u000b\r\f", "a b c"},
+		{"", ""},
+		{" ", ""},
+		{"abc", "abc"},
+		{"a b c", "a b c"},
+		{"a\u00a0 b", "a b"},
 	}
 
 	// Tests generated values from url encoded name.
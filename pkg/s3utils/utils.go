@@ -19,7 +19,6 @@ package s3utils
 
 import (
 	"bytes"
-	"encoding/hex"
 	"errors"
 	"net"
 	"net/url"
@@ -93,7 +92,10 @@ var amazonS3HostDualStack = regexp.MustCompile(`^s3\.dualstack\.(.*?)\.amazonaws
 var amazonS3HostDot = regexp.MustCompile(`^s3\.(.*?)\.amazonaws\.com$`)
 
 // amazonS3ChinaHost - regular expression used to determine if the arg is s3 china host.
-var amazonS3ChinaHost = regexp.MustCompile(`^s3\.(cn.*?)\.amazonaws\.com\.cn$`)
+var amazonS3ChinaHost = regexp.MustCompile(`^s3\.(?:dualstack\.)?(cn.*?)\.amazonaws\.com\.cn$`)
+
+// amazonS3HostFIPS - regular expression used to determine if an arg is s3 FIPS host.
+var amazonS3HostFIPS = regexp.MustCompile(`^s3-fips[.-](?:dualstack\.)?(.*?)\.amazonaws\.com$`)
 
 // GetRegionFromURL - returns a region from url host.
 func GetRegionFromURL(endpointURL url.URL) string {
@@ -103,10 +105,11 @@ func GetRegionFromURL(endpointURL url.URL) string {
 	if endpointURL.Host == "s3-external-1.amazonaws.com" {
 		return ""
 	}
-	if IsAmazonGovCloudEndpoint(endpointURL) {
-		return "us-gov-west-1"
+	parts := amazonS3HostFIPS.FindStringSubmatch(endpointURL.Host)
+	if len(parts) > 1 {
+		return parts[1]
 	}
-	parts := amazonS3HostDualStack.FindStringSubmatch(endpointURL.Host)
+	parts = amazonS3HostDualStack.FindStringSubmatch(endpointURL.Host)
 	if len(parts) > 1 {
 		return parts[1]
 	}
@@ -135,11 +138,45 @@ func IsAmazonEndpoint(endpointURL url.URL) bool {
 
 // IsAmazonGovCloudEndpoint - Match if it is exactly Amazon S3 GovCloud endpoint.
 func IsAmazonGovCloudEndpoint(endpointURL url.URL) bool {
-	if endpointURL == sentinelURL {
-		return false
+	return GetPartitionFromURL(endpointURL) == PartitionAWSGovCloud
+}
+
+// IsAmazonChinaEndpoint - Match if it is exactly Amazon S3 China endpoint.
+func IsAmazonChinaEndpoint(endpointURL url.URL) bool {
+	return GetPartitionFromURL(endpointURL) == PartitionAWSChina
+}
+
+// AWS partitions, regions of different partitions are isolated
+// from each other and need separate credentials.
+const (
+	PartitionAWS         = "aws"
+	PartitionAWSChina    = "aws-cn"
+	PartitionAWSGovCloud = "aws-us-gov"
+)
+
+// GetPartition - returns the AWS partition of a region.
+func GetPartition(region string) string {
+	switch {
+	case region == "":
+		return ""
+	case strings.HasPrefix(region, "cn-"):
+		return PartitionAWSChina
+	case strings.HasPrefix(region, "us-gov-"):
+		return PartitionAWSGovCloud
+	}
+	return PartitionAWS
+}
+
+// GetPartitionFromURL - returns the AWS partition of an Amazon S3
+// endpoint, empty for all other endpoints.
+func GetPartitionFromURL(endpointURL url.URL) string {
+	if !IsAmazonEndpoint(endpointURL) {
+		return ""
 	}
-	return (endpointURL.Host == "s3-us-gov-west-1.amazonaws.com" ||
-		IsAmazonFIPSGovCloudEndpoint(endpointURL))
+	if partition := GetPartition(GetRegionFromURL(endpointURL)); partition != "" {
+		return partition
+	}
+	return PartitionAWS
 }
 
 // IsAmazonFIPSGovCloudEndpoint - Match if it is exactly Amazon S3 FIPS GovCloud endpoint.
@@ -219,45 +256,55 @@ func QueryEncode(v url.Values) string {
 	return buf.String()
 }
 
-// if object matches reserved string, no need to encode them
-var reservedObjectNames = regexp.MustCompile("^[a-zA-Z0-9-_.~/]+$")
-
 // EncodePath encode the strings from UTF-8 byte representations to HTML hex escape sequences
 //
 // This is necessary since regular url.Parse() and url.Encode() functions do not support UTF-8
 // non english characters cannot be parsed due to the nature in which url.Encode() is written
 //
 // This function on the other hand is a direct replacement for url.Encode() technique to support
-// pretty much every UTF-8 character.
+// pretty much every UTF-8 character. All bytes except the unreserved characters of RFC 3986
+// and '/' are percent encoded, as required by S3 for object names in request URIs, signatures
+// and copy source headers. Consecutive slashes are kept as is.
 func EncodePath(pathName string) string {
-	if reservedObjectNames.MatchString(pathName) {
+	// Most object names need no encoding, return them as is.
+	i := 0
+	for i < len(pathName) && !shouldEscape(pathName[i]) {
+		i++
+	}
+	if i == len(pathName) {
 		return pathName
 	}
-	var encodedPathname string
-	for _, s := range pathName {
-		if 'A' <= s && s <= 'Z' || 'a' <= s && s <= 'z' || '0' <= s && s <= '9' { // §2.3 Unreserved characters (mark)
-			encodedPathname = encodedPathname + string(s)
-			continue
-		}
-		switch s {
-		case '-', '_', '.', '~', '/': // §2.3 Unreserved characters (mark)
-			encodedPathname = encodedPathname + string(s)
+
+	var encodedPathname strings.Builder
+	encodedPathname.Grow(len(pathName) + 2*(len(pathName)-i))
+	encodedPathname.WriteString(pathName[:i])
+	for ; i < len(pathName); i++ {
+		c := pathName[i]
+		if !shouldEscape(c) {
+			encodedPathname.WriteByte(c)
 			continue
-		default:
-			len := utf8.RuneLen(s)
-			if len < 0 {
-				// if utf8 cannot convert return the same string as is
-				return pathName
-			}
-			u := make([]byte, len)
-			utf8.EncodeRune(u, s)
-			for _, r := range u {
-				hex := hex.EncodeToString([]byte{r})
-				encodedPathname = encodedPathname + "%" + strings.ToUpper(hex)
-			}
 		}
+		encodedPathname.WriteByte('%')
+		encodedPathname.WriteByte(upperHex[c>>4])
+		encodedPathname.WriteByte(upperHex[c&15])
 	}
-	return encodedPathname
+	return encodedPathname.String()
+}
+
+const upperHex = "0123456789ABCDEF"
+
+// shouldEscape - returns false for the §2.3 unreserved characters
+// (mark) and '/'.
+func shouldEscape(c byte) bool {
+	switch {
+	case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9':
+		return false
+	}
+	switch c {
+	case '-', '_', '.', '~', '/':
+		return false
+	}
+	return true
 }
 
 // We support '.' with bucket names but we fallback to using path
@@ -73,6 +73,22 @@ func TestGetRegionFromURL(t *testing.T) {
 			u:              url.URL{Host: "s3-external-1.amazonaws.com"},
 			expectedRegion: "",
 		},
+		{
+			u:              url.URL{Host: "s3.dualstack.cn-north-1.amazonaws.com.cn"},
+			expectedRegion: "cn-north-1",
+		},
+		{
+			u:              url.URL{Host: "s3-fips.us-east-1.amazonaws.com"},
+			expectedRegion: "us-east-1",
+		},
+		{
+			u:              url.URL{Host: "s3-fips.dualstack.us-gov-west-1.amazonaws.com"},
+			expectedRegion: "us-gov-west-1",
+		},
+		{
+			u:              url.URL{Host: "s3.us-gov-east-1.amazonaws.com"},
+			expectedRegion: "us-gov-east-1",
+		},
 	}
 
 	for i, testCase := range testCases {
@@ -208,6 +224,41 @@ func TestIsAmazonEndpoint(t *testing.T) {
 
 }
 
+// Tests partitions of regions and endpoints.
+func TestGetPartition(t *testing.T) {
+	testCases := []struct {
+		host      string
+		region    string
+		partition string
+	}{
+		{"s3.amazonaws.com", "", PartitionAWS},
+		{"s3-external-1.amazonaws.com", "", PartitionAWS},
+		{"s3.eu-west-1.amazonaws.com", "eu-west-1", PartitionAWS},
+		{"s3.cn-north-1.amazonaws.com.cn", "cn-north-1", PartitionAWSChina},
+		{"s3.dualstack.cn-northwest-1.amazonaws.com.cn", "cn-northwest-1", PartitionAWSChina},
+		{"s3-us-gov-west-1.amazonaws.com", "us-gov-west-1", PartitionAWSGovCloud},
+		{"s3-fips-us-gov-west-1.amazonaws.com", "us-gov-west-1", PartitionAWSGovCloud},
+		{"s3.us-gov-east-1.amazonaws.com", "us-gov-east-1", PartitionAWSGovCloud},
+		{"storage.googleapis.com", "", ""},
+		{"localhost:9000", "", ""},
+	}
+	for i, testCase := range testCases {
+		u := url.URL{Host: testCase.host}
+		if partition := GetPartitionFromURL(u); partition != testCase.partition {
+			t.Errorf("Test %d: Expected partition %q, got %q", i+1, testCase.partition, partition)
+		}
+		if testCase.region != "" && GetPartition(testCase.region) != testCase.partition {
+			t.Errorf("Test %d: Expected partition %q of region %q, got %q", i+1, testCase.partition, testCase.region, GetPartition(testCase.region))
+		}
+		if IsAmazonGovCloudEndpoint(u) != (testCase.partition == PartitionAWSGovCloud) {
+			t.Errorf("Test %d: Unexpected GovCloud endpoint result", i+1)
+		}
+		if IsAmazonChinaEndpoint(u) != (testCase.partition == PartitionAWSChina) {
+			t.Errorf("Test %d: Unexpected China endpoint result", i+1)
+		}
+	}
+}
+
 // Tests validate Google Cloud end point validator.
 func TestIsGoogleEndpoint(t *testing.T) {
 	testCases := []struct {
@@ -310,6 +361,13 @@ func TestEncodePath(t *testing.T) {
 		{"myurl#link", "myurl%23link"},
 		{"space in url", "space%20in%20url"},
 		{"url+path", "url%2Bpath"},
+		{"a?b", "a%3Fb"},
+		{"a;b=c", "a%3Bb%3Dc"},
+		{"[x]@y:z", "%5Bx%5D%40y%3Az"},
+		{"a\\b^c", "a%5Cb%5Ec"},
+		{"a//b/", "a//b/"},
+		{"-_.~/AZaz09", "-_.~/AZaz09"},
+		{"\xff", "%FF"},
 	}
 
 	for i, testCase := range testCases {
@@ -0,0 +1,54 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/minio/minio-go/v6/pkg/s3utils"
+)
+
+// getRegionEndpoint - returns the endpoint of region for multi-region
+// clients of endpointURL. Clients of FIPS endpoints are kept on FIPS
+// endpoints, which Amazon S3 offers in the US and Canada regions only,
+// requests to other regions are not supported.
+func getRegionEndpoint(endpointURL url.URL, region string) (string, error) {
+	if !s3utils.IsAmazonFIPSEndpoint(endpointURL) {
+		return getS3Endpoint(region), nil
+	}
+	if region == "" {
+		return endpointURL.Host, nil
+	}
+	if !strings.HasPrefix(region, "us-") && !strings.HasPrefix(region, "ca-") {
+		return "", ErrAPINotSupported("Amazon S3 has no FIPS endpoint in region ‘" + region + "’.")
+	}
+	if strings.HasPrefix(endpointURL.Host, "s3-fips.dualstack.") {
+		return "s3-fips.dualstack." + region + ".amazonaws.com", nil
+	}
+	return "s3-fips." + region + ".amazonaws.com", nil
+}
+
+// regionOverride - returns the region of all requests of the client,
+// or the region of requests without a bucket of multi-region clients.
+func (c Client) regionOverride() string {
+	if c.region != "" {
+		return c.region
+	}
+	return c.defaultRegion
+}
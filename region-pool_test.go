@@ -0,0 +1,107 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/minio/minio-go/v6/pkg/credentials"
+)
+
+// Tests requests of multi-region clients are sent to the endpoint of
+// the region of their bucket and signed in that region.
+func TestMultiRegion(t *testing.T) {
+	testCases := []struct {
+		endpoint string
+		region   string
+		hosts    map[string]string
+	}{
+		{"s3.eu-west-1.amazonaws.com", "", map[string]string{
+			"":          "s3.dualstack.eu-west-1.amazonaws.com",
+			"us-west-2": "s3.dualstack.us-west-2.amazonaws.com",
+			"eu-west-1": "s3.dualstack.eu-west-1.amazonaws.com",
+		}},
+		{"s3-fips.us-east-1.amazonaws.com", "", map[string]string{
+			"":             "s3-fips.us-east-1.amazonaws.com",
+			"us-west-2":    "s3-fips.us-west-2.amazonaws.com",
+			"ca-central-1": "s3-fips.ca-central-1.amazonaws.com",
+		}},
+		{"s3-fips.dualstack.us-east-2.amazonaws.com", "", map[string]string{
+			"us-west-1": "s3-fips.dualstack.us-west-1.amazonaws.com",
+		}},
+		{"s3.amazonaws.com", "ap-south-1", map[string]string{
+			"":          "s3.dualstack.ap-south-1.amazonaws.com",
+			"us-east-2": "s3.dualstack.us-east-2.amazonaws.com",
+		}},
+	}
+	for _, testCase := range testCases {
+		c, err := NewWithOptions(testCase.endpoint, &Options{
+			Creds:       credentials.NewStaticV4("access", "secret", ""),
+			Secure:      true,
+			Region:      testCase.region,
+			MultiRegion: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for region, host := range testCase.hosts {
+			metadata := requestMetadata{}
+			if region != "" {
+				metadata.bucketName = "bucket-" + region
+				if err = c.SetBucketLocation(metadata.bucketName, region); err != nil {
+					t.Fatal(err)
+				}
+			} else if region = testCase.region; region == "" {
+				region = strings.Split(testCase.endpoint, ".")[1]
+			}
+			if metadata.bucketName != "" {
+				host = metadata.bucketName + "." + host
+			}
+			req, err := c.newRequest("GET", metadata)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if req.URL.Host != host {
+				t.Errorf("%s: expected host %s for %s, got %s", testCase.endpoint, host, region, req.URL.Host)
+			}
+			if scope := "/" + region + "/s3/aws4_request"; !strings.Contains(req.Header.Get("Authorization"), scope) {
+				t.Errorf("%s: expected scope %s, got %s", testCase.endpoint, scope, req.Header.Get("Authorization"))
+			}
+		}
+	}
+}
+
+// Tests that FIPS clients do not send requests of buckets in regions
+// without FIPS endpoints to other endpoints.
+func TestMultiRegionFIPS(t *testing.T) {
+	c, err := NewWithOptions("s3-fips.us-east-1.amazonaws.com", &Options{
+		Creds:       credentials.NewStaticV4("access", "secret", ""),
+		Secure:      true,
+		MultiRegion: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = c.SetBucketLocation("bucket", "eu-west-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.newRequest("GET", requestMetadata{bucketName: "bucket"}); ToErrorResponse(err).Code != "APINotSupported" {
+		t.Errorf("expected APINotSupported, got %v", err)
+	}
+}
@@ -18,10 +18,12 @@
 package minio
 
 import (
+	"context"
 	"net"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -43,8 +45,9 @@ const DefaultRetryUnit = time.Second
 const DefaultRetryCap = time.Second * 30
 
 // newRetryTimer creates a timer with exponentially increasing
-// delays until the maximum retry attempts are reached.
-func (c Client) newRetryTimer(maxRetry int, unit time.Duration, cap time.Duration, jitter float64, doneCh chan struct{}) <-chan int {
+// delays until the maximum retry attempts are reached, or until the
+// next delay would end after deadline if deadline is not zero.
+func (c Client) newRetryTimer(maxRetry int, unit time.Duration, cap time.Duration, jitter float64, deadline time.Time, doneCh chan struct{}) <-chan int {
 	attemptCh := make(chan int)
 
 	// computes the exponential backoff duration according to
@@ -79,12 +82,98 @@ func (c Client) newRetryTimer(maxRetry int, unit time.Duration, cap time.Duratio
 				// Stop the routine.
 				return
 			}
-			time.Sleep(exponentialBackoffWait(i))
+			sleep := exponentialBackoffWait(i)
+			if !deadline.IsZero() && time.Now().Add(sleep).After(deadline) {
+				return
+			}
+			time.Sleep(sleep)
 		}
 	}()
 	return attemptCh
 }
 
+// RetryBudget limits the retries of all requests sharing the budget
+// to a fraction of the requests made, so that an outage of the server
+// is not amplified by retries. A budget can be shared by clients.
+type RetryBudget struct {
+	mu        sync.Mutex
+	tokens    float64
+	maxTokens float64
+	ratio     float64
+}
+
+// NewRetryBudget - creates a retry budget allowing ratio retries per
+// request made, e.g. 0.1 allows one retry every ten requests. The
+// budget starts with, and saves up to, burst retries.
+func NewRetryBudget(ratio float64, burst int) *RetryBudget {
+	if ratio < 0 {
+		ratio = 0
+	}
+	if burst < 0 {
+		burst = 0
+	}
+	return &RetryBudget{
+		tokens:    float64(burst),
+		maxTokens: float64(burst),
+		ratio:     ratio,
+	}
+}
+
+// deposit - credits the budget for a new request.
+func (b *RetryBudget) deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens += b.ratio
+	if b.tokens > b.maxTokens {
+		b.tokens = b.maxTokens
+	}
+}
+
+// withdraw - takes one retry from the budget, returns false if the
+// budget is exhausted.
+func (b *RetryBudget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// retryDeadline - returns the time after which a request started at
+// start must not be retried, the earliest of the retry deadline of the
+// client and the deadline of ctx. Returns zero if there is none.
+func (c Client) retryDeadline(ctx context.Context, start time.Time) time.Time {
+	var deadline time.Time
+	if c.maxRetryElapsed > 0 {
+		deadline = start.Add(c.maxRetryElapsed)
+	}
+	if ctxDeadline, ok := ctx.Deadline(); ok && (deadline.IsZero() || ctxDeadline.Before(deadline)) {
+		deadline = ctxDeadline
+	}
+	return deadline
+}
+
+// retryAttemptKey is the context key under which the attempt
+// number of an outgoing request is saved.
+type retryAttemptKey struct{}
+
+// withRetryAttempt returns a copy of ctx carrying the attempt number.
+func withRetryAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, retryAttemptKey{}, attempt)
+}
+
+// retryAttempt returns the attempt number saved on the request, if
+// the request was not sent by executeMethod returns 0.
+func retryAttempt(req *http.Request) int {
+	if req == nil {
+		return 0
+	}
+	attempt, _ := req.Context().Value(retryAttemptKey{}).(int)
+	return attempt
+}
+
 // isHTTPReqErrorRetryable - is http requests error retryable, such
 // as i/o timeout, connection broken etc..
 func isHTTPReqErrorRetryable(err error) bool {
@@ -0,0 +1,102 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newUnavailableServer(requests *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+}
+
+func TestRetryBudget(t *testing.T) {
+	var requests int32
+	server := newUnavailableServer(&requests)
+	defer server.Close()
+
+	budget := NewRetryBudget(0, 1)
+	for i := 0; i < 2; i++ {
+		c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.SetRetryBudget(budget)
+		if _, err = c.StatObject("bucket", "object", StatObjectOptions{}); err == nil {
+			t.Fatal("expected an error")
+		}
+	}
+	// The only retry of the budget is used by the first client.
+	if n := atomic.LoadInt32(&requests); n != 3 {
+		t.Fatalf("expected 3 requests, got %d", n)
+	}
+
+	budget = NewRetryBudget(0.5, 2)
+	budget.tokens = 0
+	budget.deposit()
+	if budget.withdraw() {
+		t.Fatal("expected half a retry not to be enough")
+	}
+	budget.deposit()
+	budget.deposit()
+	budget.deposit()
+	budget.deposit()
+	if !budget.withdraw() || !budget.withdraw() || budget.withdraw() {
+		t.Fatal("expected the budget to save up to 2 retries")
+	}
+}
+
+func TestRetryDeadline(t *testing.T) {
+	var requests int32
+	server := newUnavailableServer(&requests)
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetRetryDeadline(500 * time.Millisecond)
+
+	start := time.Now()
+	if _, err = c.StatObject("bucket", "object", StatObjectOptions{}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("retries should stop at the deadline, took %v", elapsed)
+	}
+
+	c.SetRetryDeadline(0)
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	if _, err = c.PutObjectWithContext(ctx, "bucket", "object", strings.NewReader("data"), 4, PutObjectOptions{}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("retries should stop at the context deadline, took %v", elapsed)
+	}
+}
@@ -17,6 +17,55 @@
 
 package minio
 
+import (
+	"regexp"
+
+	"github.com/minio/minio-go/v6/pkg/s3utils"
+)
+
+// Region - name of a region, e.g. the location of a bucket.
+type Region string
+
+// Regions of Amazon S3.
+const (
+	RegionUSEast1      Region = "us-east-1"
+	RegionUSEast2      Region = "us-east-2"
+	RegionUSWest1      Region = "us-west-1"
+	RegionUSWest2      Region = "us-west-2"
+	RegionCACentral1   Region = "ca-central-1"
+	RegionEUWest1      Region = "eu-west-1"
+	RegionEUWest2      Region = "eu-west-2"
+	RegionEUWest3      Region = "eu-west-3"
+	RegionEUCentral1   Region = "eu-central-1"
+	RegionEUNorth1     Region = "eu-north-1"
+	RegionAPEast1      Region = "ap-east-1"
+	RegionAPSouth1     Region = "ap-south-1"
+	RegionAPSoutheast1 Region = "ap-southeast-1"
+	RegionAPSoutheast2 Region = "ap-southeast-2"
+	RegionAPNortheast1 Region = "ap-northeast-1"
+	RegionAPNortheast2 Region = "ap-northeast-2"
+	RegionSAEast1      Region = "sa-east-1"
+	RegionUSGovWest1   Region = "us-gov-west-1"
+	RegionUSGovEast1   Region = "us-gov-east-1"
+	RegionCNNorth1     Region = "cn-north-1"
+	RegionCNNorthwest1 Region = "cn-northwest-1"
+)
+
+// validRegionName - names of regions start with a letter followed by
+// letters, digits, hyphens and underscores, like the region names
+// accepted by MinIO.
+var validRegionName = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*$`)
+
+func (r Region) String() string {
+	return string(r)
+}
+
+// IsValid - check whether this region name is well formed, regions of
+// other S3 compatible services than Amazon S3 are valid too.
+func (r Region) IsValid() bool {
+	return validRegionName.MatchString(string(r))
+}
+
 // awsS3EndpointMap Amazon S3 endpoint map.
 var awsS3EndpointMap = map[string]string{
 	"us-east-1":      "s3.dualstack.us-east-1.amazonaws.com",
@@ -45,6 +94,10 @@ var awsS3EndpointMap = map[string]string{
 // getS3Endpoint get Amazon S3 endpoint based on the bucket location.
 func getS3Endpoint(bucketLocation string) (s3Endpoint string) {
 	s3Endpoint, ok := awsS3EndpointMap[bucketLocation]
+	if !ok && s3utils.GetPartition(bucketLocation) == s3utils.PartitionAWSChina {
+		// Endpoints of China regions have their own domain.
+		return "s3." + bucketLocation + ".amazonaws.com.cn"
+	}
 	if !ok {
 		// Default to 's3.dualstack.us-east-1.amazonaws.com' endpoint.
 		s3Endpoint = "s3.dualstack.us-east-1.amazonaws.com"
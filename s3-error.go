@@ -39,6 +39,7 @@ var s3ErrorResponseMap = map[string]string{
 	"NoSuchKey":                         "The specified key does not exist.",
 	"NoSuchUpload":                      "The specified multipart upload does not exist. The upload ID may be invalid, or the upload may have been aborted or completed.",
 	"NotImplemented":                    "A header you provided implies functionality that is not implemented",
+	"NotModified":                       "The object was not modified since the time or the ETag specified in the request.",
 	"PreconditionFailed":                "At least one of the pre-conditions you specified did not hold",
 	"RequestTimeTooSkewed":              "The difference between the request time and the server's time is too large.",
 	"SignatureDoesNotMatch":             "The request signature we calculated does not match the signature you provided. Check your key and signing method.",
@@ -46,6 +47,7 @@ var s3ErrorResponseMap = map[string]string{
 	"InvalidPart":                       "One or more of the specified parts could not be found.",
 	"InvalidPartOrder":                  "The list of parts was not in ascending order. The parts list must be specified in order by part number.",
 	"InvalidObjectState":                "The operation is not valid for the current state of the object.",
+	"InvalidWriteOffset":                "The write offset does not match the current size of the object.",
 	"AuthorizationHeaderMalformed":      "The authorization header is malformed; the region is wrong.",
 	"MalformedPOSTRequest":              "The body of your POST request is not well-formed multipart/form-data.",
 	"BucketNotEmpty":                    "The bucket you tried to delete is not empty",
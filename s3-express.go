@@ -0,0 +1,167 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// s3ExpressBucketSuffix - suffix of the names of S3 Express One Zone
+// directory buckets, which end with the id of their availability
+// zone, e.g. 'bucket--usw2-az1--x-s3'.
+const s3ExpressBucketSuffix = "--x-s3"
+
+// s3ExpressSessionRefresh - sessions of directory buckets are
+// replaced this long before they expire.
+const s3ExpressSessionRefresh = time.Minute
+
+// s3ExpressZone - returns the availability zone id in the name of a
+// directory bucket, empty for other buckets.
+func s3ExpressZone(bucketName string) string {
+	if !strings.HasSuffix(bucketName, s3ExpressBucketSuffix) {
+		return ""
+	}
+	name := strings.TrimSuffix(bucketName, s3ExpressBucketSuffix)
+	i := strings.LastIndex(name, "--")
+	if i <= 0 {
+		return ""
+	}
+	return name[i+2:]
+}
+
+// isS3ExpressBucket - returns true if requests to the bucket are
+// signed with the credentials of S3 Express sessions.
+func (c Client) isS3ExpressBucket(bucketName string) bool {
+	return c.s3Express && s3ExpressZone(bucketName) != ""
+}
+
+// getS3ExpressEndpoint - returns the zonal endpoint of the directory
+// buckets of an availability zone, e.g.
+// 's3express-usw2-az1.us-west-2.amazonaws.com'.
+func getS3ExpressEndpoint(zone, bucketLocation string) string {
+	return "s3express-" + zone + "." + bucketLocation + ".amazonaws.com"
+}
+
+// createSessionResult - container of the CreateSession response.
+type createSessionResult struct {
+	XMLName     xml.Name `xml:"CreateSessionResult"`
+	Credentials struct {
+		AccessKeyID     string    `xml:"AccessKeyId"`
+		SecretAccessKey string    `xml:"SecretAccessKey"`
+		SessionToken    string    `xml:"SessionToken"`
+		Expiration      time.Time `xml:"Expiration"`
+	} `xml:"Credentials"`
+}
+
+// s3ExpressSession - temporary credentials of a directory bucket.
+type s3ExpressSession struct {
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	expiration      time.Time
+}
+
+// s3ExpressSessions - sessions of the directory buckets of a client,
+// shared by its copies.
+type s3ExpressSessions struct {
+	sync.Mutex
+	sessions map[string]s3ExpressSession
+}
+
+// newS3ExpressSessions - returns an empty set of sessions.
+func newS3ExpressSessions() *s3ExpressSessions {
+	return &s3ExpressSessions{sessions: make(map[string]s3ExpressSession)}
+}
+
+// get - returns the session of a bucket unless it expires soon.
+func (s *s3ExpressSessions) get(bucketName string) (s3ExpressSession, bool) {
+	s.Lock()
+	defer s.Unlock()
+	session, ok := s.sessions[bucketName]
+	if !ok || time.Now().Add(s3ExpressSessionRefresh).After(session.expiration) {
+		return s3ExpressSession{}, false
+	}
+	return session, true
+}
+
+// set - saves the session of a bucket.
+func (s *s3ExpressSessions) set(bucketName string, session s3ExpressSession) {
+	s.Lock()
+	defer s.Unlock()
+	s.sessions[bucketName] = session
+}
+
+// remove - drops the session of a bucket, returns false if there was
+// none.
+func (s *s3ExpressSessions) remove(bucketName string) bool {
+	s.Lock()
+	defer s.Unlock()
+	_, ok := s.sessions[bucketName]
+	delete(s.sessions, bucketName)
+	return ok
+}
+
+// getS3ExpressSession - returns the session of a directory bucket,
+// a new session is created with CreateSession when there is none or
+// it expires soon.
+func (c Client) getS3ExpressSession(bucketName string) (s3ExpressSession, error) {
+	if session, ok := c.s3ExpressSessions.get(bucketName); ok {
+		return session, nil
+	}
+
+	// Execute GET on the bucket to create a session, the request is
+	// signed with the credentials of the client.
+	resp, err := c.executeMethod(context.Background(), "GET", requestMetadata{
+		bucketName:       bucketName,
+		queryValues:      url.Values{"session": []string{""}},
+		contentSHA256Hex: emptySHA256Hex,
+		createSession:    true,
+	})
+	defer closeResponse(resp)
+	if err != nil {
+		return s3ExpressSession{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return s3ExpressSession{}, httpRespToErrorResponse(resp, bucketName, "")
+	}
+	var result createSessionResult
+	if err = xmlDecoder(resp.Body, &result); err != nil {
+		return s3ExpressSession{}, err
+	}
+	session := s3ExpressSession{
+		accessKeyID:     result.Credentials.AccessKeyID,
+		secretAccessKey: result.Credentials.SecretAccessKey,
+		sessionToken:    result.Credentials.SessionToken,
+		expiration:      result.Credentials.Expiration,
+	}
+	if session.accessKeyID == "" || session.secretAccessKey == "" || session.sessionToken == "" {
+		return s3ExpressSession{}, ErrorResponse{
+			Code:       "InvalidSession",
+			Message:    "CreateSession response of bucket " + bucketName + " has no credentials.",
+			BucketName: bucketName,
+		}
+	}
+	c.s3ExpressSessions.set(bucketName, session)
+	return session, nil
+}
@@ -0,0 +1,192 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v6/pkg/credentials"
+	"github.com/minio/minio-go/v6/pkg/s3signer"
+)
+
+// Tests the zone of directory bucket names.
+func TestS3ExpressZone(t *testing.T) {
+	testCases := []struct {
+		bucketName string
+		zone       string
+	}{
+		{"bucket--usw2-az1--x-s3", "usw2-az1"},
+		{"my--bucket--use1-az4--x-s3", "use1-az4"},
+		{"bucket--x-s3", ""},
+		{"bucket", ""},
+	}
+	for _, testCase := range testCases {
+		if zone := s3ExpressZone(testCase.bucketName); zone != testCase.zone {
+			t.Errorf("%s: expected zone %q, got %q", testCase.bucketName, testCase.zone, zone)
+		}
+	}
+
+	c, err := New("s3.us-west-2.amazonaws.com", "access", "secret", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := c.makeTargetURL("bucket--usw2-az1--x-s3", "object", "us-west-2", false, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "https://bucket--usw2-az1--x-s3.s3express-usw2-az1.us-west-2.amazonaws.com/object"; u.String() != expected {
+		t.Errorf("expected %s, got %s", expected, u)
+	}
+}
+
+// Tests requests to directory buckets are signed with the credentials
+// of S3 Express sessions.
+func TestS3ExpressSession(t *testing.T) {
+	var (
+		mutex         sync.Mutex
+		sessions      int
+		expireSession bool
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		auth := r.Header.Get("Authorization")
+		if _, ok := r.URL.Query()["session"]; ok {
+			if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=access/") || !strings.Contains(auth, "/us-east-1/s3express/aws4_request") {
+				t.Errorf("unexpected CreateSession authorization %s", auth)
+			}
+			sessions++
+			fmt.Fprintf(w, `<CreateSessionResult><Credentials><SessionToken>token%d</SessionToken><SecretAccessKey>sessionsecret</SecretAccessKey><AccessKeyId>sessionaccess</AccessKeyId><Expiration>%s</Expiration></Credentials></CreateSessionResult>`,
+				sessions, time.Now().Add(5*time.Minute).UTC().Format(time.RFC3339))
+			return
+		}
+		if token := r.Header.Get("X-Amz-S3session-Token"); token != fmt.Sprintf("token%d", sessions) {
+			t.Errorf("unexpected session token %q", token)
+		}
+		if r.Header.Get("X-Amz-Security-Token") != "" {
+			t.Error("unexpected security token")
+		}
+		r.URL.Host = r.Host
+		signature, err := s3signer.GetSignatureV4(*r, "sessionsecret", "us-east-1")
+		if err != nil || !strings.HasSuffix(auth, "Signature="+signature.Signature) || !strings.Contains(auth, "Credential=sessionaccess/") {
+			t.Errorf("request is not signed with the session credentials: %s %v", auth, err)
+		}
+		if expireSession {
+			expireSession = false
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `<Error><Code>ExpiredToken</Code><Message>The provided token has expired.</Message></Error>`)
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+	}))
+	defer server.Close()
+
+	c, err := NewWithOptions(strings.TrimPrefix(server.URL, "http://"), &Options{
+		Creds:     credentials.NewStaticV4("access", "secret", "stsToken"),
+		Region:    "us-east-1",
+		S3Express: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err = c.PutObject("bucket--use1-az4--x-s3", "object", strings.NewReader("data"), 4, PutObjectOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if sessions != 1 {
+		t.Fatalf("expected a single session, got %d", sessions)
+	}
+
+	// Sessions ending early are replaced.
+	mutex.Lock()
+	expireSession = true
+	mutex.Unlock()
+	if _, err = c.PutObject("bucket--use1-az4--x-s3", "object", strings.NewReader("data"), 4, PutObjectOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if sessions != 2 {
+		t.Fatalf("expected a new session, got %d sessions", sessions)
+	}
+
+	if _, err = c.PresignedGetObject("bucket--use1-az4--x-s3", "object", time.Hour, nil); ToErrorResponse(err).Code != "APINotSupported" {
+		t.Errorf("expected presigning to be rejected, got %v", err)
+	}
+}
+
+// Tests listings of directory buckets use version 2 of the listing
+// and meet the restrictions of directory buckets.
+func TestS3ExpressListing(t *testing.T) {
+	var listTypes []string
+	list := newListTestHandler([]string{"dir/a", "dir/b", "file"})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.URL.Query()["session"]; ok {
+			fmt.Fprintf(w, `<CreateSessionResult><Credentials><SessionToken>token</SessionToken><SecretAccessKey>sessionsecret</SecretAccessKey><AccessKeyId>sessionaccess</AccessKeyId><Expiration>%s</Expiration></Credentials></CreateSessionResult>`,
+				time.Now().Add(5*time.Minute).UTC().Format(time.RFC3339))
+			return
+		}
+		listTypes = append(listTypes, r.URL.Query().Get("list-type"))
+		list(w, r)
+	}))
+	defer server.Close()
+
+	c, err := NewWithOptions(strings.TrimPrefix(server.URL, "http://"), &Options{
+		Creds:     credentials.NewStaticV4("access", "secret", ""),
+		Region:    "us-east-1",
+		S3Express: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const bucketName = "bucket--use1-az4--x-s3"
+
+	var names []string
+	for object := range c.ListObjects(bucketName, "dir/", true, nil) {
+		if object.Err != nil {
+			t.Fatal(object.Err)
+		}
+		names = append(names, object.Key)
+	}
+	if strings.Join(names, ",") != "dir/a,dir/b" || len(listTypes) != 1 || listTypes[0] != "2" {
+		t.Fatalf("expected a version 2 listing of dir/a and dir/b, got %v with list types %v", names, listTypes)
+	}
+
+	for _, opts := range []ListObjectsOptions{
+		{Prefix: "dir"},
+		{Prefix: "dir/", Delimiter: "-"},
+		{StartAfter: "dir/a", Recursive: true},
+	} {
+		if _, err = c.ListObjectsV2Page(bucketName, opts); ToErrorResponse(err).Code != "InvalidArgument" {
+			t.Errorf("%+v: expected an InvalidArgument error, got %v", opts, err)
+		}
+		for object := range c.ListObjectsV2WithOptions(bucketName, opts, nil) {
+			if ToErrorResponse(object.Err).Code != "InvalidArgument" {
+				t.Errorf("%+v: expected an InvalidArgument error, got %v", opts, object.Err)
+			}
+		}
+	}
+	if len(listTypes) != 1 {
+		t.Errorf("expected invalid listings not to be sent, got %d listings", len(listTypes))
+	}
+}
@@ -0,0 +1,76 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"strings"
+)
+
+// s3URIScheme is the scheme prefix of an S3 URI.
+const s3URIScheme = "s3://"
+
+// ParseURI - parses an S3 URI of the form "s3://bucket/prefix/key" and
+// returns the bucket name, the object name and the optional version id
+// passed as "?versionId=" query. The object name is returned as is,
+// it may be empty or end with a '/' when the URI addresses a bucket
+// or a prefix.
+//
+// For example:
+//
+//   bucketName, objectName, versionID, err := minio.ParseURI("s3://mybucket/photos/2019/pic.jpg?versionId=123")
+func ParseURI(uri string) (bucketName, objectName, versionID string, err error) {
+	if !strings.HasPrefix(uri, s3URIScheme) {
+		return "", "", "", ErrInvalidArgument("S3 URI ‘" + uri + "’ must start with ‘" + s3URIScheme + "’.")
+	}
+	path := strings.TrimPrefix(uri, s3URIScheme)
+
+	// Only a trailing versionId query is recognized, '?' is
+	// otherwise a valid character in object names.
+	if i := strings.LastIndex(path, "?versionId="); i >= 0 {
+		versionID = path[i+len("?versionId="):]
+		path = path[:i]
+		if versionID == "" {
+			return "", "", "", ErrInvalidArgument("S3 URI ‘" + uri + "’ has an empty version id.")
+		}
+	}
+
+	bucketName = path
+	if i := strings.Index(path, "/"); i >= 0 {
+		bucketName, objectName = path[:i], path[i+1:]
+	}
+	if err = ValidateBucketName(bucketName, false); err != nil {
+		return "", "", "", err
+	}
+	if versionID != "" && objectName == "" {
+		return "", "", "", ErrInvalidArgument("S3 URI ‘" + uri + "’ has a version id but no object name.")
+	}
+	return bucketName, objectName, versionID, nil
+}
+
+// BuildURI - returns the S3 URI for a bucket, an optional object name
+// and an optional version id, this is the inverse of ParseURI.
+func BuildURI(bucketName, objectName, versionID string) string {
+	uri := s3URIScheme + bucketName
+	if objectName != "" {
+		uri += "/" + objectName
+	}
+	if versionID != "" {
+		uri += "?versionId=" + versionID
+	}
+	return uri
+}
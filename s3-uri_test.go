@@ -0,0 +1,70 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"testing"
+)
+
+// Tests parsing and building S3 URIs.
+func TestParseURI(t *testing.T) {
+	testCases := []struct {
+		uri        string
+		bucketName string
+		objectName string
+		versionID  string
+		shouldPass bool
+	}{
+		{"s3://mybucket", "mybucket", "", "", true},
+		{"s3://mybucket/", "mybucket", "", "", true},
+		{"s3://mybucket/prefix/", "mybucket", "prefix/", "", true},
+		{"s3://mybucket/prefix/key", "mybucket", "prefix/key", "", true},
+		{"s3://mybucket/a?b#c d+e", "mybucket", "a?b#c d+e", "", true},
+		{"s3://mybucket/key?versionId=3HL4kqtJlcpXroDTDmJ+rmSpXd3dIbrHY", "mybucket", "key", "3HL4kqtJlcpXroDTDmJ+rmSpXd3dIbrHY", true},
+		{"s3://mybucket?versionId=123", "", "", "", false},
+		{"s3://mybucket/key?versionId=", "", "", "", false},
+		{"https://mybucket/key", "", "", "", false},
+		{"s3://", "", "", "", false},
+		{"s3://my/key", "", "", "", false},
+	}
+
+	for i, testCase := range testCases {
+		bucketName, objectName, versionID, err := ParseURI(testCase.uri)
+		if err != nil && testCase.shouldPass {
+			t.Errorf("Test %d: Expected to pass, but failed with: %v", i+1, err)
+			continue
+		}
+		if err == nil && !testCase.shouldPass {
+			t.Errorf("Test %d: Expected to fail, but passed instead", i+1)
+			continue
+		}
+		if !testCase.shouldPass {
+			continue
+		}
+		if bucketName != testCase.bucketName || objectName != testCase.objectName || versionID != testCase.versionID {
+			t.Errorf("Test %d: Expected (%s, %s, %s), got (%s, %s, %s)", i+1,
+				testCase.bucketName, testCase.objectName, testCase.versionID,
+				bucketName, objectName, versionID)
+		}
+		uri := BuildURI(bucketName, objectName, versionID)
+		if b, o, v, err := ParseURI(uri); err != nil || b != bucketName || o != objectName || v != versionID {
+			t.Errorf("Test %d: Expected %s to parse back to (%s, %s, %s), got (%s, %s, %s, %v)", i+1,
+				uri, bucketName, objectName, versionID, b, o, v, err)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+// StorageClass - class of storage of an object, the storage class
+// fields of options and results are strings set to and compared with
+// StorageClass.String() so that classes unknown to the client can be
+// used.
+type StorageClass string
+
+// Storage classes of Amazon S3, MinIO and Google Cloud Storage.
+const (
+	StorageClassStandard           StorageClass = "STANDARD"
+	StorageClassReducedRedundancy  StorageClass = "REDUCED_REDUNDANCY"
+	StorageClassStandardIA         StorageClass = "STANDARD_IA"
+	StorageClassOneZoneIA          StorageClass = "ONEZONE_IA"
+	StorageClassIntelligentTiering StorageClass = "INTELLIGENT_TIERING"
+	StorageClassGlacier            StorageClass = "GLACIER"
+	StorageClassGlacierIR          StorageClass = "GLACIER_IR"
+	StorageClassDeepArchive        StorageClass = "DEEP_ARCHIVE"
+	StorageClassOutposts           StorageClass = "OUTPOSTS"
+	StorageClassExpressOneZone     StorageClass = "EXPRESS_ONEZONE"
+	StorageClassNearline           StorageClass = "NEARLINE"
+	StorageClassColdline           StorageClass = "COLDLINE"
+	StorageClassArchive            StorageClass = "ARCHIVE"
+)
+
+func (s StorageClass) String() string {
+	return string(s)
+}
+
+// IsValid - check whether this storage class is a known storage class.
+func (s StorageClass) IsValid() bool {
+	switch s {
+	case StorageClassStandard, StorageClassReducedRedundancy, StorageClassStandardIA,
+		StorageClassOneZoneIA, StorageClassIntelligentTiering, StorageClassGlacier,
+		StorageClassGlacierIR, StorageClassDeepArchive, StorageClassOutposts,
+		StorageClassExpressOneZone, StorageClassNearline, StorageClassColdline,
+		StorageClassArchive:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+/*
+ * MinIO Go Library for Amazon S3 Compatible Cloud Storage
+ * Copyright 2019 MinIO, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package minio
+
+import (
+	"testing"
+)
+
+// Tests storage classes, regions and event types are validated, regions
+// before any request is sent. Unknown storage classes are left to the
+// server to validate.
+func TestTypedConstants(t *testing.T) {
+	storageClasses := []struct {
+		storageClass StorageClass
+		valid        bool
+	}{
+		{StorageClassStandard, true},
+		{StorageClassGlacierIR, true},
+		{"NEARLINE", true},
+		{"standard", false},
+		{"INVALID_STORAGE_CLASS", false},
+	}
+	for _, testCase := range storageClasses {
+		if valid := testCase.storageClass.IsValid(); valid != testCase.valid {
+			t.Errorf("%s: expected valid %t, got %t", testCase.storageClass, testCase.valid, valid)
+		}
+		if err := (PutObjectOptions{StorageClass: testCase.storageClass.String()}).validate(); err != nil {
+			t.Errorf("%s: expected the storage class to be sent, got %v", testCase.storageClass, err)
+		}
+	}
+
+	regions := []struct {
+		region Region
+		valid  bool
+	}{
+		{RegionEUWest1, true},
+		{RegionUSGovWest1, true},
+		{"auto", true},
+		{"my_region", true},
+		{"eu-west-1 ", false},
+		{"s3.eu-west-1.amazonaws.com", false},
+		{"1region", false},
+	}
+	for _, testCase := range regions {
+		if valid := testCase.region.IsValid(); valid != testCase.valid {
+			t.Errorf("%s: expected valid %t, got %t", testCase.region, testCase.valid, valid)
+		}
+		_, err := NewWithRegion("localhost:9000", "access", "secret", false, testCase.region.String())
+		if testCase.valid != (err == nil) {
+			t.Errorf("%s: expected valid %t, got %v", testCase.region, testCase.valid, err)
+		}
+	}
+
+	c, err := New("localhost:9000", "access", "secret", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = c.MakeBucket("bucket", "EU West"); ToErrorResponse(err).Code != "InvalidArgument" {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+	if err = c.SetBucketLocation("bucket", "EU West"); ToErrorResponse(err).Code != "InvalidArgument" {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+
+	for _, event := range []NotificationEventType{ObjectCreatedPut, ObjectRemovedDeleteMarkerCreated} {
+		if !event.IsValid() {
+			t.Errorf("%s: expected a valid event type", event)
+		}
+	}
+	if NotificationEventType("s3:ObjectCreated:Get").IsValid() {
+		t.Errorf("expected an invalid event type")
+	}
+}
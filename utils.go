@@ -18,21 +18,27 @@
 package minio
 
 import (
+	"bytes"
 	"crypto/md5"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"encoding/xml"
+	"hash"
 	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
 	"net/url"
+	"path"
 	"regexp"
 	"strings"
 	"time"
 
+	"github.com/minio/minio-go/v6/pkg/credentials"
 	"github.com/minio/minio-go/v6/pkg/s3utils"
+	"golang.org/x/net/http/httpguts"
 )
 
 // xmlDecoder provide decoded value in xml.
@@ -55,28 +61,98 @@ func sumMD5Base64(data []byte) string {
 	return base64.StdEncoding.EncodeToString(hash.Sum(nil))
 }
 
+// contentMD5 - computes the base64 encoded MD5 sum of the next size
+// bytes of the reader. Seekable readers are rewound once hashed while
+// other readers are buffered in memory, the returned reader must be
+// used in place of the input reader.
+func contentMD5(reader io.Reader, size int64) (md5Base64 string, r io.Reader, err error) {
+	return contentSum(md5.New(), reader, size)
+}
+
+// contentCRC32C - computes the base64 encoded CRC32C checksum of the
+// next size bytes of the reader, like contentMD5.
+func contentCRC32C(reader io.Reader, size int64) (crc32cBase64 string, r io.Reader, err error) {
+	return contentSum(newCRC32C(), reader, size)
+}
+
+// contentSum - computes the base64 encoded sum of the next size bytes
+// of the reader with the given hash.
+func contentSum(hash hash.Hash, reader io.Reader, size int64) (sumBase64 string, r io.Reader, err error) {
+	if seeker, ok := reader.(io.ReadSeeker); ok {
+		if offset, serr := seeker.Seek(0, io.SeekCurrent); serr == nil {
+			if _, err = io.CopyN(hash, seeker, size); err != nil {
+				return "", nil, err
+			}
+			if _, err = seeker.Seek(offset, io.SeekStart); err != nil {
+				return "", nil, err
+			}
+			return base64.StdEncoding.EncodeToString(hash.Sum(nil)), reader, nil
+		}
+	}
+	buf := make([]byte, size)
+	if _, err = io.ReadFull(reader, buf); err != nil {
+		return "", nil, err
+	}
+	hash.Write(buf)
+	return base64.StdEncoding.EncodeToString(hash.Sum(nil)), bytes.NewReader(buf), nil
+}
+
 // getEndpointURL - construct a new endpoint.
+//
+// The endpoint is a host optionally followed by a port and a base path,
+// e.g. "proxy.example.com:8443/minio" for a server behind a reverse
+// proxy. An explicit "http://" or "https://" scheme takes precedence
+// over secure.
 func getEndpointURL(endpoint string, secure bool) (*url.URL, error) {
-	if strings.Contains(endpoint, ":") {
+	// If secure is false, use 'http' scheme.
+	scheme := "https"
+	if !secure {
+		scheme = "http"
+	}
+	if i := strings.Index(endpoint, "://"); i >= 0 {
+		scheme = strings.ToLower(endpoint[:i])
+		if scheme != "http" && scheme != "https" {
+			msg := "Endpoint: " + endpoint + " has an unsupported scheme, only 'http' and 'https' are allowed."
+			return nil, ErrInvalidArgument(msg)
+		}
+		endpoint = endpoint[i+len("://"):]
+	}
+
+	// Split off the base path, if any.
+	var basePath string
+	if i := strings.Index(endpoint, "/"); i >= 0 {
+		endpoint, basePath = endpoint[:i], path.Clean(endpoint[i:])
+		if basePath == "/" {
+			basePath = ""
+		}
+	}
+
+	msg := "Endpoint: " + endpoint + " does not follow ip address or domain name standards."
+	switch {
+	case strings.HasPrefix(endpoint, "[") && strings.HasSuffix(endpoint, "]"):
+		// IPv6 literal without a port, e.g. "[::1]".
+		if ip := endpoint[1 : len(endpoint)-1]; !strings.Contains(ip, ":") || !s3utils.IsValidIP(ip) {
+			return nil, ErrInvalidArgument(msg)
+		}
+	case strings.Count(endpoint, ":") > 1 && !strings.HasPrefix(endpoint, "["):
+		// Unbracketed IPv6 literal, e.g. "::1", cannot carry a port.
+		if !s3utils.IsValidIP(endpoint) {
+			return nil, ErrInvalidArgument(msg)
+		}
+		endpoint = "[" + endpoint + "]"
+	case strings.Contains(endpoint, ":"):
 		host, _, err := net.SplitHostPort(endpoint)
 		if err != nil {
 			return nil, err
 		}
 		if !s3utils.IsValidIP(host) && !s3utils.IsValidDomain(host) {
-			msg := "Endpoint: " + endpoint + " does not follow ip address or domain name standards."
 			return nil, ErrInvalidArgument(msg)
 		}
-	} else {
+	default:
 		if !s3utils.IsValidIP(endpoint) && !s3utils.IsValidDomain(endpoint) {
-			msg := "Endpoint: " + endpoint + " does not follow ip address or domain name standards."
 			return nil, ErrInvalidArgument(msg)
 		}
 	}
-	// If secure is false, use 'http' scheme.
-	scheme := "https"
-	if !secure {
-		scheme = "http"
-	}
 
 	// Construct a secured endpoint URL.
 	endpointURLStr := scheme + "://" + endpoint
@@ -89,6 +165,15 @@ func getEndpointURL(endpoint string, secure bool) (*url.URL, error) {
 	if err := isValidEndpointURL(*endpointURL); err != nil {
 		return nil, err
 	}
+
+	// Amazon S3 and Google Cloud Storage are never proxied under a
+	// path, a path there is a bucket or object name.
+	if basePath != "" {
+		if s3utils.IsAmazonEndpoint(*endpointURL) || s3utils.IsGoogleEndpoint(*endpointURL) {
+			return nil, ErrInvalidArgument("Endpoint url cannot have fully qualified paths.")
+		}
+		endpointURL.Path = basePath
+	}
 	return endpointURL, nil
 }
 
@@ -120,6 +205,22 @@ var (
 	sentinelURL = url.URL{}
 )
 
+// stripDefaultPort strips port 80 for http and port 443 for https from
+// host, keeping IPv6 literals bracketed.
+func stripDefaultPort(scheme, host string) string {
+	h, p, err := net.SplitHostPort(host)
+	if err != nil {
+		return host
+	}
+	if scheme == "http" && p == "80" || scheme == "https" && p == "443" {
+		if strings.Contains(h, ":") {
+			return "[" + h + "]"
+		}
+		return h
+	}
+	return host
+}
+
 // Verify if input endpoint URL is valid.
 func isValidEndpointURL(endpointURL url.URL) error {
 	if endpointURL == sentinelURL {
@@ -153,6 +254,49 @@ func isValidExpiry(expires time.Duration) error {
 	return nil
 }
 
+// isValidPresignExpiry - checks the expiry of a URL presigned with
+// signerType. Signature V4 limits the expiry to 7 days, signature V2
+// URLs carry an absolute expiry time and only need to expire in the
+// future.
+func isValidPresignExpiry(signerType credentials.SignatureType, expires time.Duration) error {
+	if signerType.IsV2() {
+		if int64(expires/time.Second) < 1 {
+			return ErrInvalidArgument("Expires cannot be lesser than 1 second.")
+		}
+		return nil
+	}
+	return isValidExpiry(expires)
+}
+
+// ValidateBucketName - checks if the bucket name is valid. When strict
+// is true the name must follow the AWS S3 DNS compatible naming rules,
+// otherwise the relaxed rules accepted by MinIO are used. ARNs of
+// access points are accepted in place of bucket names.
+//   - http://docs.aws.amazon.com/AmazonS3/latest/dev/BucketRestrictions.html
+func ValidateBucketName(bucketName string, strict bool) error {
+	var err error
+	if isAccessPointARN(bucketName) {
+		_, err = parseAccessPointARN(bucketName)
+	} else if strict {
+		err = s3utils.CheckValidBucketNameStrict(bucketName)
+	} else {
+		err = s3utils.CheckValidBucketName(bucketName)
+	}
+	if err != nil {
+		return ErrInvalidBucketName(err.Error())
+	}
+	return nil
+}
+
+// ValidateObjectKey - checks if the object key is valid.
+//   - http://docs.aws.amazon.com/AmazonS3/latest/dev/UsingMetadata.html
+func ValidateObjectKey(objectName string) error {
+	if err := s3utils.CheckValidObjectName(objectName); err != nil {
+		return ErrInvalidObjectName(err.Error())
+	}
+	return nil
+}
+
 // make a copy of http.Header
 func cloneHeader(h http.Header) http.Header {
 	h2 := make(http.Header, len(h))
@@ -248,6 +392,7 @@ var sseHeaders = []string{
 	"x-amz-server-side-encryption",
 	"x-amz-server-side-encryption-aws-kms-key-id",
 	"x-amz-server-side-encryption-context",
+	"x-amz-server-side-encryption-bucket-key-enabled",
 	"x-amz-server-side-encryption-customer-algorithm",
 	"x-amz-server-side-encryption-customer-key",
 	"x-amz-server-side-encryption-customer-key-MD5",
@@ -264,9 +409,111 @@ func isSSEHeader(headerKey string) bool {
 	return false
 }
 
+// setObjectEncryption - sets the server-side encryption of the object
+// info from the headers of a response, the encryption context of
+// SSE-KMS is base64 encoded JSON and left unset if malformed.
+func setObjectEncryption(objectInfo *ObjectInfo, header http.Header) {
+	objectInfo.ServerSideEncryption = header.Get("X-Amz-Server-Side-Encryption")
+	objectInfo.SSEKMSKeyID = header.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id")
+	objectInfo.SSEKMSBucketKeyEnabled = strings.EqualFold(header.Get("X-Amz-Server-Side-Encryption-Bucket-Key-Enabled"), "true")
+	if context := header.Get("X-Amz-Server-Side-Encryption-Context"); context != "" {
+		data, err := base64.StdEncoding.DecodeString(context)
+		if err != nil {
+			return
+		}
+		var encryptionContext map[string]string
+		if json.Unmarshal(data, &encryptionContext) == nil {
+			objectInfo.SSEKMSEncryptionContext = encryptionContext
+		}
+	}
+}
+
 // isAmzHeader returns true if header is a x-amz-meta-* or x-amz-acl header.
 func isAmzHeader(headerKey string) bool {
 	key := strings.ToLower(headerKey)
 
 	return strings.HasPrefix(key, "x-amz-meta-") || strings.HasPrefix(key, "x-amz-grant-") || key == "x-amz-acl" || isSSEHeader(headerKey)
 }
+
+// reservedHeaders - headers set by the client and the signers of
+// requests, which cannot be sent as custom headers.
+var reservedHeaders = map[string]bool{
+	"Authorization":                true,
+	"Host":                         true,
+	"Content-Length":               true,
+	"Expect":                       true,
+	"Transfer-Encoding":            true,
+	"Range":                        true,
+	"X-Amz-Date":                   true,
+	"X-Amz-Content-Sha256":         true,
+	"X-Amz-Security-Token":         true,
+	"X-Amz-Decoded-Content-Length": true,
+}
+
+// validateCustomHeaders - checks custom headers of requests are valid
+// and are not set by the client or its signers.
+func validateCustomHeaders(header http.Header) error {
+	for k, values := range header {
+		if !httpguts.ValidHeaderFieldName(k) {
+			return ErrInvalidArgument(k + " is not a valid header name.")
+		}
+		if reservedHeaders[http.CanonicalHeaderKey(k)] {
+			return ErrInvalidArgument(k + " is set by the client and cannot be a custom header.")
+		}
+		for _, v := range values {
+			if !httpguts.ValidHeaderFieldValue(v) {
+				return ErrInvalidArgument(v + " is not a valid value of header " + k + ".")
+			}
+		}
+	}
+	return nil
+}
+
+// setCustomHeaders - copies custom headers to the headers of a
+// request.
+func setCustomHeaders(header, custom http.Header) {
+	for k, values := range custom {
+		header[http.CanonicalHeaderKey(k)] = append([]string(nil), values...)
+	}
+}
+
+// reservedQueryParams - query parameters set by the client and the
+// signers of requests, in lower case, which cannot be sent as custom
+// query parameters.
+var reservedQueryParams = map[string]bool{
+	"x-amz-algorithm":      true,
+	"x-amz-credential":     true,
+	"x-amz-date":           true,
+	"x-amz-expires":        true,
+	"x-amz-signedheaders":  true,
+	"x-amz-signature":      true,
+	"x-amz-security-token": true,
+	"awsaccesskeyid":       true,
+	"signature":            true,
+	"expires":              true,
+	"uploads":              true,
+	"uploadid":             true,
+	"partnumber":           true,
+}
+
+// validateCustomQuery - checks custom query parameters of requests
+// are not set by the client or its signers.
+func validateCustomQuery(query url.Values) error {
+	for k := range query {
+		if k == "" {
+			return ErrInvalidArgument("Query parameter name cannot be empty.")
+		}
+		if reservedQueryParams[strings.ToLower(k)] {
+			return ErrInvalidArgument(k + " is set by the client and cannot be a custom query parameter.")
+		}
+	}
+	return nil
+}
+
+// setCustomQuery - copies custom query parameters to the query of a
+// request, they are signed along with it.
+func setCustomQuery(query, custom url.Values) {
+	for k, values := range custom {
+		query[k] = append([]string(nil), values...)
+	}
+}
@@ -19,8 +19,13 @@ package minio
 
 import (
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -89,6 +94,18 @@ func TestGetEndpointURL(t *testing.T) {
 		{"192.168.1.1:9000", false, "http://192.168.1.1:9000", nil, true},
 		{"192.168.1.1:9000", true, "https://192.168.1.1:9000", nil, true},
 		{"s3.amazonaws.com:443", true, "https://s3.amazonaws.com:443", nil, true},
+		{"http://192.168.1.1:9000", true, "http://192.168.1.1:9000", nil, true},
+		{"HTTPS://localhost:9000/", false, "https://localhost:9000", nil, true},
+		{"localhost:9000/minio", false, "http://localhost:9000/minio", nil, true},
+		{"https://proxy.example.com/s3//minio/", false, "https://proxy.example.com/s3/minio", nil, true},
+		{"ftp://localhost:9000", false, "", ErrInvalidArgument("Endpoint: ftp://localhost:9000 has an unsupported scheme, only 'http' and 'https' are allowed."), false},
+		{"s3.amazonaws.com/bucket", true, "", ErrInvalidArgument("Endpoint url cannot have fully qualified paths."), false},
+		{"[::1]:9000", false, "http://[::1]:9000", nil, true},
+		{"[2001:db8::1]", true, "https://[2001:db8::1]", nil, true},
+		{"2001:db8::1", true, "https://[2001:db8::1]", nil, true},
+		{"http://[::1]:9000/minio", true, "http://[::1]:9000/minio", nil, true},
+		{"[192.168.1.1]", true, "", ErrInvalidArgument("Endpoint: [192.168.1.1] does not follow ip address or domain name standards."), false},
+		{"2001:db8::zz", true, "", ErrInvalidArgument("Endpoint: 2001:db8::zz does not follow ip address or domain name standards."), false},
 		{"13333.123123.-", true, "", ErrInvalidArgument(fmt.Sprintf("Endpoint: %s does not follow ip address or domain name standards.", "13333.123123.-")), false},
 		{"13333.123123.-", true, "", ErrInvalidArgument(fmt.Sprintf("Endpoint: %s does not follow ip address or domain name standards.", "13333.123123.-")), false},
 		{"storage.googleapis.com:4000", true, "", ErrInvalidArgument("Google Cloud Storage endpoint should be 'storage.googleapis.com'."), false},
@@ -376,3 +393,216 @@ func TestIsAmzHeader(t *testing.T) {
 	}
 
 }
+
+// Tests the exported bucket name and object key validators.
+func TestValidateBucketNameAndObjectKey(t *testing.T) {
+	testCases := []struct {
+		bucketName string
+		strict     bool
+		objectName string
+		bucketErr  error
+		objectErr  error
+	}{
+		{"my-bucket", true, "object", nil, nil},
+		{"My_Bucket", false, "dir/object", nil, nil},
+		{"My_Bucket", true, "object", ErrInvalidBucketName("Bucket name contains invalid characters"), nil},
+		{"my", false, "object", ErrInvalidBucketName("Bucket name cannot be smaller than 3 characters"), nil},
+		{"192.168.1.1", true, "object", ErrInvalidBucketName("Bucket name cannot be an ip address"), nil},
+		{"my-bucket", false, " ", nil, ErrInvalidObjectName("Object name cannot be empty")},
+		{"my-bucket", false, string([]byte{0xff, 0xfe}), nil, ErrInvalidObjectName("Object name with non UTF-8 strings are not supported")},
+	}
+
+	for i, testCase := range testCases {
+		if err := ValidateBucketName(testCase.bucketName, testCase.strict); !reflect.DeepEqual(err, testCase.bucketErr) {
+			t.Errorf("Test %d: Expected bucket name error %v, got %v", i+1, testCase.bucketErr, err)
+		}
+		if err := ValidateObjectKey(testCase.objectName); !reflect.DeepEqual(err, testCase.objectErr) {
+			t.Errorf("Test %d: Expected object key error %v, got %v", i+1, testCase.objectErr, err)
+		}
+	}
+}
+
+// Tests computing the Content-MD5 of a reader.
+func TestContentMD5(t *testing.T) {
+	data := "The quick brown fox jumps over the lazy dog"
+	expected := sumMD5Base64([]byte(data[:20]))
+	testCases := []struct {
+		reader io.Reader
+	}{
+		{strings.NewReader(data)},
+		{ioutil.NopCloser(strings.NewReader(data))},
+	}
+	for i, testCase := range testCases {
+		md5Base64, reader, err := contentMD5(testCase.reader, 20)
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		if md5Base64 != expected {
+			t.Errorf("Test %d: expected %s, got %s", i+1, expected, md5Base64)
+		}
+		b, err := ioutil.ReadAll(io.LimitReader(reader, 20))
+		if err != nil {
+			t.Fatalf("Test %d: unexpected error %v", i+1, err)
+		}
+		if string(b) != data[:20] {
+			t.Errorf("Test %d: expected reader to replay %q, got %q", i+1, data[:20], string(b))
+		}
+	}
+	if _, _, err := contentMD5(strings.NewReader(data), int64(len(data)+1)); err == nil {
+		t.Errorf("Expected an error for a short reader")
+	}
+}
+
+// Tests the hash algorithms selected for uploads.
+func TestHashMaterials(t *testing.T) {
+	testCases := []struct {
+		secure           bool
+		isMd5Requested   bool
+		isMd5Disabled    bool
+		isSha256Disabled bool
+		md5, sha256      bool
+	}{
+		{false, false, false, false, false, true},
+		{false, true, false, false, true, true},
+		{false, false, false, true, false, false},
+		{true, false, false, false, true, false},
+		{true, false, false, true, true, false},
+		{true, false, true, false, false, false},
+		{true, true, true, false, true, false},
+		{false, false, true, true, false, false},
+	}
+	for i, testCase := range testCases {
+		c, err := New("localhost:9000", "access", "secret", testCase.secure)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hashAlgos, _ := c.hashMaterials(testCase.isMd5Requested, testCase.isMd5Disabled, testCase.isSha256Disabled)
+		if _, ok := hashAlgos["md5"]; ok != testCase.md5 {
+			t.Errorf("Test %d: expected md5 to be computed %v", i+1, testCase.md5)
+		}
+		if _, ok := hashAlgos["sha256"]; ok != testCase.sha256 {
+			t.Errorf("Test %d: expected sha256 to be computed %v", i+1, testCase.sha256)
+		}
+	}
+}
+
+// Tests custom headers are sent with requests and signed.
+func TestCustomHeaders(t *testing.T) {
+	received := make(map[string]http.Header)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received[r.Method] = r.Header
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", time.Date(2019, time.March, 10, 12, 30, 0, 0, time.UTC).Format(http.TimeFormat))
+		switch r.Method {
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodHead:
+			w.Header().Set("Content-Length", "0")
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	custom := http.Header{"x-minio-extension": []string{"value"}}
+
+	if _, err = c.PutObject("bucket", "object", strings.NewReader("data"), 4, PutObjectOptions{CustomHeaders: custom}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.StatObject("bucket", "object", StatObjectOptions{GetObjectOptions{CustomHeaders: custom}}); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.RemoveObjectWithOptions("bucket", "object", RemoveObjectOptions{CustomHeaders: custom}); err != nil {
+		t.Fatal(err)
+	}
+	for _, method := range []string{http.MethodPut, http.MethodHead, http.MethodDelete} {
+		header := received[method]
+		if header.Get("X-Minio-Extension") != "value" {
+			t.Errorf("%s: expected the custom header to be sent, got %v", method, header)
+		}
+		if !strings.Contains(header.Get("Authorization"), "x-minio-extension") {
+			t.Errorf("%s: expected the custom header to be signed, got %s", method, header.Get("Authorization"))
+		}
+	}
+
+	// Options take precedence over custom headers.
+	opts := PutObjectOptions{ContentType: "text/plain", CustomHeaders: http.Header{"Content-Type": []string{"image/png"}}}
+	if contentType := opts.Header().Get("Content-Type"); contentType != "text/plain" {
+		t.Errorf("expected the content type of the options, got %s", contentType)
+	}
+
+	for _, header := range []http.Header{
+		{"Authorization": []string{"AWS4-HMAC-SHA256"}},
+		{"x-amz-date": []string{"20190310T123000Z"}},
+		{"Range": []string{"bytes=0-1"}},
+		{"Bad Name": []string{"value"}},
+		{"X-Minio-Extension": []string{"bad\nvalue"}},
+	} {
+		if _, err = c.PutObject("bucket", "object", strings.NewReader("data"), 4, PutObjectOptions{CustomHeaders: header}); ToErrorResponse(err).Code != "InvalidArgument" {
+			t.Errorf("%v: expected an InvalidArgument error, got %v", header, err)
+		}
+		if _, err = c.StatObject("bucket", "object", StatObjectOptions{GetObjectOptions{CustomHeaders: header}}); ToErrorResponse(err).Code != "InvalidArgument" {
+			t.Errorf("%v: expected an InvalidArgument error, got %v", header, err)
+		}
+		if err = c.RemoveObjectWithOptions("bucket", "object", RemoveObjectOptions{CustomHeaders: header}); ToErrorResponse(err).Code != "InvalidArgument" {
+			t.Errorf("%v: expected an InvalidArgument error, got %v", header, err)
+		}
+	}
+}
+
+// Tests custom query parameters are sent with requests.
+func TestCustomQuery(t *testing.T) {
+	received := make(map[string]*http.Request)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received[r.Method] = r
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", time.Date(2019, time.March, 10, 12, 30, 0, 0, time.UTC).Format(http.TimeFormat))
+		switch r.Method {
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodHead:
+			w.Header().Set("Content-Length", "0")
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewWithRegion(strings.TrimPrefix(server.URL, "http://"), "access", "secret", false, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	custom := url.Values{"x-minio-extension": []string{"value"}}
+
+	if _, err = c.PutObject("bucket", "object", strings.NewReader("data"), 4, PutObjectOptions{CustomQuery: custom}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.StatObject("bucket", "object", StatObjectOptions{GetObjectOptions{CustomQuery: custom}}); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.RemoveObjectWithOptions("bucket", "object", RemoveObjectOptions{CustomQuery: custom}); err != nil {
+		t.Fatal(err)
+	}
+	for _, method := range []string{http.MethodPut, http.MethodHead, http.MethodDelete} {
+		r := received[method]
+		if r.URL.Query().Get("x-minio-extension") != "value" {
+			t.Errorf("%s: expected the custom query parameter to be sent, got %s", method, r.URL.RawQuery)
+		}
+	}
+
+	for _, query := range []url.Values{
+		{"X-Amz-Signature": []string{"signature"}},
+		{"uploadId": []string{"id"}},
+		{"": []string{"value"}},
+	} {
+		if _, err = c.PutObject("bucket", "object", strings.NewReader("data"), 4, PutObjectOptions{CustomQuery: query}); ToErrorResponse(err).Code != "InvalidArgument" {
+			t.Errorf("%v: expected an InvalidArgument error, got %v", query, err)
+		}
+		if _, err = c.StatObject("bucket", "object", StatObjectOptions{GetObjectOptions{CustomQuery: query}}); ToErrorResponse(err).Code != "InvalidArgument" {
+			t.Errorf("%v: expected an InvalidArgument error, got %v", query, err)
+		}
+		if err = c.RemoveObjectWithOptions("bucket", "object", RemoveObjectOptions{CustomQuery: query}); ToErrorResponse(err).Code != "InvalidArgument" {
+			t.Errorf("%v: expected an InvalidArgument error, got %v", query, err)
+		}
+	}
+}